@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/state"
+)
+
+// ackUsage describes the ack subcommand.
+const ackUsage = "usage: ccbell ack"
+
+// runAck handles "ccbell ack": marking the last notification as seen, so
+// it isn't escalated.
+func runAck(args []string, homeDir string, now time.Time, stdout io.Writer) error {
+	if len(args) != 0 {
+		return cliLocale.Errorf(ackUsage)
+	}
+	u, err := state.NewManager(homeDir).Ack()
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		fmt.Fprintln(stdout, "No notification to acknowledge")
+		return nil
+	}
+	ago := now.Sub(time.Unix(u.Sent, 0)).Round(time.Second)
+	fmt.Fprintf(stdout, "Acknowledged %s notification from %s ago\n", u.Event, ago)
+	return nil
+}
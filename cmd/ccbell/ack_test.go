@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/state"
+)
+
+func TestRunAck(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "ccbell-ack-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(homeDir)
+
+	var out bytes.Buffer
+	if err := runAck(nil, homeDir, time.Now(), &out); err != nil || out.String() != "No notification to acknowledge\n" {
+		t.Errorf("runAck() = %q, %v", out.String(), err)
+	}
+
+	now := time.Unix(time.Now().Unix(), 0)
+	if err := state.NewManager(homeDir).SetUnacked(&state.Unacked{Event: "stop", Sent: now.Add(-3 * time.Minute).Unix()}); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := runAck(nil, homeDir, now, &out); err != nil || out.String() != "Acknowledged stop notification from 3m0s ago\n" {
+		t.Errorf("runAck() = %q, %v", out.String(), err)
+	}
+
+	if err := runAck([]string{"--all"}, homeDir, now, &out); err == nil {
+		t.Error("runAck() with arguments should fail")
+	}
+}
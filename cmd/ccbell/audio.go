@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/audio"
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/state"
+)
+
+// audioUsage describes the audio subcommand.
+const audioUsage = "usage: ccbell audio check"
+
+// playerRankArg marks the detached invocation that ranks the Linux players.
+const playerRankArg = "rank"
+
+// spawnPlayerRank starts a detached "ccbell audio rank" that times the Linux
+// players and caches their ranking, so the hook doesn't wait for it.
+func spawnPlayerRank(configFile string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return cliLocale.Errorf("cannot locate ccbell binary: %w", err)
+	}
+
+	args := []string{"audio", playerRankArg}
+	if configFile != "" {
+		args = append(args, "--config", configFile)
+	}
+	cmd := execCommand(self, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true} // Outlive the hook
+	if err := cmd.Start(); err != nil {
+		return cliLocale.Errorf("failed to start player ranking: %w", err)
+	}
+	return cmd.Process.Release()
+}
+
+// runAudio handles "ccbell audio check": playing a test tone with each
+// audio player and reporting their startup latency, so the fastest can be
+// preferred with "players". Without "players", the Linux players' ranking
+// by latency is updated in the state file.
+func runAudio(args []string, configFile, pluginRoot, homeDir string, stdout io.Writer) error {
+	if len(args) == 1 && args[0] == playerRankArg {
+		return rankAudioPlayers(configFile, pluginRoot, homeDir)
+	}
+	if len(args) != 1 || args[0] != "check" {
+		return cliLocale.Errorf(audioUsage)
+	}
+	cfg, _, err := config.LoadFile(configFile)
+	if err != nil {
+		return err
+	}
+
+	player := audio.NewPlayer(pluginRoot)
+	m := state.NewManager(homeDir)
+	ranked := player.Platform() == audio.PlatformLinux && len(cfg.Players) == 0
+	if ranked {
+		order, err := m.RankedPlayers()
+		if err != nil {
+			return err
+		}
+		player.SetPlayerOrder(order)
+	} else {
+		player.SetPlayerOrder(cfg.Players)
+	}
+	if cfg.MaxVolume != nil {
+		player.SetMaxVolume(*cfg.MaxVolume)
+	}
+	fmt.Fprintf(stdout, "Playing a test tone with each %s player...\n", player.Platform())
+	checks, err := player.CheckPlayers(context.Background())
+	if err != nil {
+		return err
+	}
+	printPlayerChecks(stdout, player.Platform(), checks)
+	if order := audio.RankChecks(checks); ranked && len(order) > 0 && homeDir != "" {
+		return m.RankPlayers(order)
+	}
+	return nil
+}
+
+// rankAudioPlayers handles "ccbell audio rank", started by a notification
+// without a fresh player ranking: it times the Linux players playing
+// silence and caches the ranking. Another process may have cached one
+// meanwhile, and a "players" setting leaves nothing to rank.
+func rankAudioPlayers(configFile, pluginRoot, homeDir string) error {
+	cfg, _, err := config.LoadFile(configFile)
+	if err != nil {
+		return err
+	}
+	player := audio.NewPlayer(pluginRoot)
+	if player.Platform() != audio.PlatformLinux || len(cfg.Players) > 0 || homeDir == "" {
+		return nil
+	}
+	m := state.NewManager(homeDir)
+	if order, err := m.RankedPlayers(); err != nil || order != nil {
+		return err
+	}
+	if cfg.MaxVolume != nil {
+		player.SetMaxVolume(*cfg.MaxVolume)
+	}
+	order, err := player.RankPlayers(context.Background())
+	if err != nil || len(order) == 0 {
+		return err
+	}
+	return m.RankPlayers(order)
+}
+
+// printPlayerChecks prints each player's result, marking the one ccbell
+// uses, and on Linux and the BSDs the "players" setting that orders the
+// working players fastest first.
+func printPlayerChecks(stdout io.Writer, platform audio.Platform, checks []audio.PlayerCheck) {
+	working := 0
+	for _, c := range checks {
+		switch {
+		case !c.Installed:
+			fmt.Fprintf(stdout, "%-8s not installed\n", c.Name)
+		case c.Err != nil:
+			fmt.Fprintf(stdout, "%-8s failed: %v\n", c.Name, c.Err)
+		default:
+			inUse := ""
+			if working == 0 {
+				inUse = " (in use)"
+			}
+			fmt.Fprintf(stdout, "%-8s %s startup latency%s\n", c.Name, c.Latency.Round(time.Millisecond), inUse)
+			working++
+		}
+	}
+	if working == 0 {
+		fmt.Fprintln(stdout, "No player could play the test tone")
+		return
+	}
+	if platform == audio.PlatformMacOS || working < 2 {
+		return
+	}
+	names := audio.RankChecks(checks)
+	for i, name := range names {
+		names[i] = fmt.Sprintf("%q", name)
+	}
+	fmt.Fprintf(stdout, "Fastest first: \"players\": [%s]\n", strings.Join(names, ", "))
+}
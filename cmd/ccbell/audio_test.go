@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/audio"
+)
+
+func TestRunAudio(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-audio-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	configFile := filepath.Join(tmpDir, "ccbell.config.json")
+	if err := os.WriteFile(configFile, []byte(`{"enabled": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(audio.BackendEnvVar, "mock") // Every player is installed
+
+	var stdout bytes.Buffer
+	if err := runAudio([]string{"check"}, configFile, tmpDir, tmpDir, &stdout); err != nil {
+		t.Fatalf("runAudio() error = %v", err)
+	}
+	if out := stdout.String(); !strings.Contains(out, "(in use)") {
+		t.Errorf("output doesn't mark the player in use:\n%s", out)
+	}
+
+	// Mock playback isn't timed, so ranking caches nothing
+	if err := runAudio([]string{"rank"}, configFile, tmpDir, tmpDir, &stdout); err != nil {
+		t.Errorf("runAudio(rank) error = %v", err)
+	}
+
+	for _, args := range [][]string{nil, {"play"}, {"check", "mpv"}} {
+		if err := runAudio(args, configFile, tmpDir, tmpDir, &stdout); err == nil || err.Error() != audioUsage {
+			t.Errorf("runAudio(%q) error = %v, want usage", args, err)
+		}
+	}
+}
+
+func TestSpawnPlayerRank(t *testing.T) {
+	var got []string
+	orig := execCommand
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		got = args
+		cmd := exec.Command(os.Args[0], "-test.run=TestHelperProcess")
+		cmd.Env = append(os.Environ(), "GO_WANT_HELPER_PROCESS=1")
+		return cmd
+	}
+	defer func() { execCommand = orig }()
+
+	if err := spawnPlayerRank("/tmp/ccbell.json"); err != nil {
+		t.Fatalf("spawnPlayerRank() error = %v", err)
+	}
+	if want := []string{"audio", "rank", "--config", "/tmp/ccbell.json"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("args = %v, want %v", got, want)
+	}
+}
+
+func TestPrintPlayerChecks(t *testing.T) {
+	checks := []audio.PlayerCheck{
+		{Name: "mpv", Installed: true, Latency: 180 * time.Millisecond},
+		{Name: "paplay", Installed: true, Latency: 40 * time.Millisecond},
+		{Name: "aplay"},
+		{Name: "ffplay", Installed: true, Err: errors.New("exit status 1")},
+	}
+
+	var stdout bytes.Buffer
+	printPlayerChecks(&stdout, audio.PlatformLinux, checks)
+	want := `mpv      180ms startup latency (in use)
+paplay   40ms startup latency
+aplay    not installed
+ffplay   failed: exit status 1
+Fastest first: "players": ["paplay", "mpv"]
+`
+	if got := stdout.String(); got != want {
+		t.Errorf("output =\n%s\nwant\n%s", got, want)
+	}
+
+	stdout.Reset()
+	printPlayerChecks(&stdout, audio.PlatformMacOS, checks[:1])
+	if got := stdout.String(); strings.Contains(got, "players") {
+		t.Errorf("macOS output suggests a player order:\n%s", got)
+	}
+
+	stdout.Reset()
+	printPlayerChecks(&stdout, audio.PlatformLinux, checks[2:])
+	if got := stdout.String(); !strings.Contains(got, "No player could play") {
+		t.Errorf("output without working players =\n%s", got)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+)
+
+// subagentFlushArg marks the detached invocation that emits a batch summary.
+const subagentFlushArg = "flush"
+
+// execCommand is the command constructor, replaceable in tests.
+var execCommand = exec.Command
+
+// spawnSubagentFlush starts a detached "ccbell subagent flush <seq>" that
+// emits the summary once the quiet period passes without newer completions.
+func spawnSubagentFlush(configFile string, seq int64) error {
+	self, err := os.Executable()
+	if err != nil {
+		return cliLocale.Errorf("cannot locate ccbell binary: %w", err)
+	}
+
+	args := []string{"subagent", subagentFlushArg, strconv.FormatInt(seq, 10)}
+	if configFile != "" {
+		args = append(args, "--config", configFile)
+	}
+	cmd := execCommand(self, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true} // Outlive the hook
+	if err := cmd.Start(); err != nil {
+		return cliLocale.Errorf("failed to start batch flush: %w", err)
+	}
+	return cmd.Process.Release()
+}
+
+// parseFlushArgs parses the sequence argument of "ccbell subagent flush <seq>".
+func parseFlushArgs(args []string) (int64, error) {
+	if len(args) != 1 {
+		return 0, cliLocale.Errorf("usage: ccbell subagent flush <seq>")
+	}
+	seq, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || seq <= 0 {
+		return 0, cliLocale.Errorf("invalid batch sequence: %s", args[0])
+	}
+	return seq, nil
+}
+
+// eventCountUsage describes invoking ccbell with several events.
+const eventCountUsage = "usage: ccbell <event> [<event>...] | ccbell <event> --count N"
+
+// eventCount is an event type and how many times it occurred.
+type eventCount struct {
+	event string
+	count int
+}
+
+// parseEventCounts parses the arguments after the event type: more event
+// types ("ccbell subagent subagent stop") or a --count for the one event,
+// so wrappers that coalesce events can pass the aggregate. Repeated events
+// are grouped, in the order they first appear.
+func parseEventCounts(eventType string, args []string) ([]eventCount, error) {
+	events := []string{eventType}
+	count := 0
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--count" && i+1 < len(args):
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n <= 0 {
+				return nil, cliLocale.Errorf("invalid count: %s", args[i+1])
+			}
+			count = n
+			i++
+		case strings.HasPrefix(args[i], "-"):
+			return nil, cliLocale.Errorf(eventCountUsage)
+		default:
+			if err := config.ValidateEventType(args[i]); err != nil {
+				return nil, err
+			}
+			events = append(events, args[i])
+		}
+	}
+	if count > 0 && len(events) > 1 {
+		return nil, cliLocale.Errorf("--count can't be combined with several event types")
+	}
+
+	var counts []eventCount
+	index := make(map[string]int)
+	for _, event := range events {
+		if i, ok := index[event]; ok {
+			counts[i].count++
+			continue
+		}
+		index[event] = len(counts)
+		counts = append(counts, eventCount{event: event, count: 1})
+	}
+	if count > 0 {
+		counts[0].count = count
+	}
+	return counts, nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	if path := os.Getenv("GO_HELPER_OUTPUT"); path != "" {
+		os.WriteFile(path, make([]byte, 1024), 0644)
+	}
+	os.Exit(0)
+}
+
+func TestSpawnSubagentFlush(t *testing.T) {
+	var got []string
+	orig := execCommand
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		got = args
+		cmd := exec.Command(os.Args[0], "-test.run=TestHelperProcess")
+		cmd.Env = append(os.Environ(), "GO_WANT_HELPER_PROCESS=1")
+		return cmd
+	}
+	defer func() { execCommand = orig }()
+
+	if err := spawnSubagentFlush("/tmp/ccbell.json", 7); err != nil {
+		t.Fatalf("spawnSubagentFlush() error = %v", err)
+	}
+
+	want := []string{"subagent", "flush", "7", "--config", "/tmp/ccbell.json"}
+	if len(got) != len(want) {
+		t.Fatalf("args = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("args[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseFlushArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    int64
+		wantErr bool
+	}{
+		{name: "valid", args: []string{"12"}, want: 12},
+		{name: "missing", args: nil, wantErr: true},
+		{name: "not a number", args: []string{"abc"}, wantErr: true},
+		{name: "zero", args: []string{"0"}, wantErr: true},
+		{name: "extra", args: []string{"1", "2"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFlushArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFlushArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseFlushArgs() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEventCounts(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   string
+		args    []string
+		want    []eventCount
+		wantErr bool
+	}{
+		{name: "single", event: "stop", want: []eventCount{{"stop", 1}}},
+		{
+			name:  "repeated events grouped in order",
+			event: "subagent",
+			args:  []string{"subagent", "stop", "subagent"},
+			want:  []eventCount{{"subagent", 3}, {"stop", 1}},
+		},
+		{name: "count", event: "subagent", args: []string{"--count", "5"}, want: []eventCount{{"subagent", 5}}},
+		{name: "zero count", event: "stop", args: []string{"--count", "0"}, wantErr: true},
+		{name: "missing count", event: "stop", args: []string{"--count"}, wantErr: true},
+		{name: "count with several events", event: "stop", args: []string{"subagent", "--count", "2"}, wantErr: true},
+		{name: "unknown event", event: "stop", args: []string{"build_done"}, wantErr: true},
+		{name: "unknown flag", event: "stop", args: []string{"--loud"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEventCounts(tt.event, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseEventCounts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseEventCounts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// command runs a subcommand with the parsed global options; opts.args holds
+// the arguments after the subcommand name.
+type command func(opts *cliOptions) error
+
+// commands are the ccbell subcommands. Any other first argument is an event
+// type, so "ccbell stop" is short for "ccbell notify stop".
+var commands = map[string]command{
+	"notify":    runNotifyCommand,
+	"replay":    runReplay,
+	"listen":    cmdListen,
+	"daemon":    cmdDaemon,
+	"status":    cmdStatus,
+	"pause":     cmdPause,
+	"resume":    cmdResume,
+	"ack":       cmdAck,
+	"config":    cmdConfig,
+	"profile":   cmdProfile,
+	"theme":     cmdTheme,
+	"pack":      cmdPack,
+	"sounds":    cmdSounds,
+	"record":    cmdRecord,
+	"secret":    cmdSecret,
+	"logs":      cmdLogs,
+	"stats":     cmdStats,
+	"state":     cmdState,
+	"ui":        cmdUI,
+	"doctor":    cmdDoctor,
+	"audio":     cmdAudio,
+	"diagnose":  cmdDiagnose,
+	"docs":      cmdDocs,
+	"version":   cmdVersion,
+	"--version": cmdVersion,
+	"-v":        cmdVersion,
+	"help":      cmdHelp,
+	"--help":    cmdHelp,
+	"-h":        cmdHelp,
+}
+
+func cmdVersion(*cliOptions) error {
+	fmt.Printf("ccbell %s (commit: %s, built: %s)\n", version, commit, buildDate)
+	return nil
+}
+
+func cmdHelp(*cliOptions) error {
+	printUsage()
+	return nil
+}
+
+func cmdListen(opts *cliOptions) error {
+	return runListen(context.Background(), opts.args, newPipeline(opts), os.Stdin, os.Stderr)
+}
+
+func cmdDaemon(opts *cliOptions) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return runDaemon(ctx, opts.args, newPipeline(opts), os.Stdout)
+}
+
+func cmdStatus(opts *cliOptions) error {
+	return runStatus(resolveConfigFile(opts), os.Getenv("HOME"), time.Now(), os.Stdout)
+}
+
+func cmdPause(opts *cliOptions) error {
+	return runPause(opts.args, os.Getenv("HOME"), time.Now(), os.Stdout)
+}
+
+func cmdResume(*cliOptions) error {
+	return runResume(os.Getenv("HOME"), os.Stdout)
+}
+
+func cmdAck(opts *cliOptions) error {
+	return runAck(opts.args, os.Getenv("HOME"), time.Now(), os.Stdout)
+}
+
+func cmdConfig(opts *cliOptions) error {
+	return runConfig(opts.args, resolveConfigFile(opts), os.Getenv("HOME"), opts.strict, os.Stdout)
+}
+
+func cmdProfile(opts *cliOptions) error {
+	return runProfile(opts.args, resolveConfigFile(opts), os.Stdout)
+}
+
+func cmdTheme(opts *cliOptions) error {
+	return runTheme(opts.args, resolveConfigFile(opts), os.Stdout)
+}
+
+func cmdPack(opts *cliOptions) error {
+	return runPack(opts.args, resolveConfigFile(opts), os.Getenv("HOME"), os.Stdout)
+}
+
+func cmdSounds(opts *cliOptions) error {
+	homeDir := os.Getenv("HOME")
+	return runSounds(context.Background(), opts.args, resolveConfigFile(opts), homeDir, resolvePluginRoot(homeDir), os.Stdout)
+}
+
+func cmdRecord(opts *cliOptions) error {
+	return runRecord(opts.args, resolveConfigFile(opts), os.Getenv("HOME"), os.Stdout)
+}
+
+func cmdSecret(opts *cliOptions) error {
+	return runSecret(opts.args, os.Stdin, os.Stdout)
+}
+
+func cmdLogs(opts *cliOptions) error {
+	color := isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+	return runLogs(context.Background(), opts.args, os.Getenv("HOME"), time.Now(), color, os.Stdout)
+}
+
+func cmdStats(opts *cliOptions) error {
+	return runStats(opts.args, resolveConfigFile(opts), os.Getenv("HOME"), time.Now(), os.Stdout)
+}
+
+func cmdState(opts *cliOptions) error {
+	return runState(opts.args, resolveConfigFile(opts), os.Getenv("HOME"), time.Now(), os.Stdout)
+}
+
+func cmdUI(opts *cliOptions) error {
+	return runUI(context.Background(), opts.args, resolveConfigFile(opts), os.Getenv("HOME"), os.Stdin, os.Stdout)
+}
+
+func cmdDoctor(opts *cliOptions) error {
+	return runDoctor(opts.args, resolveConfigFile(opts), resolvePluginRoot(os.Getenv("HOME")), os.Stdout)
+}
+
+func cmdAudio(opts *cliOptions) error {
+	homeDir := os.Getenv("HOME")
+	return runAudio(opts.args, resolveConfigFile(opts), resolvePluginRoot(homeDir), homeDir, os.Stdout)
+}
+
+func cmdDocs(opts *cliOptions) error {
+	return runDocs(opts.args, os.Stdout)
+}
+
+func cmdDiagnose(opts *cliOptions) error {
+	homeDir := os.Getenv("HOME")
+	return runDiagnose(opts.args, resolveConfigFile(opts), homeDir, resolvePluginRoot(homeDir), time.Now(), os.Stdout)
+}
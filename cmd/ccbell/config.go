@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+)
+
+// configUsage describes the config subcommand.
+const configUsage = "usage: ccbell config validate [--strict] | push|pull [--sounds] [--repo <url> | --gist <id>]"
+
+// runConfig handles "ccbell config": checking the configuration, or syncing
+// it with push and pull. Unknown keys are reported as warnings; validation
+// errors fail the command, as do all strict checks in strict mode.
+func runConfig(args []string, configFile, homeDir string, strict bool, stdout io.Writer) error {
+	if len(args) > 0 && (args[0] == "push" || args[0] == "pull") {
+		return runConfigSync(args[0], args[1:], configFile, homeDir, stdout)
+	}
+	if len(args) != 1 || args[0] != "validate" {
+		return cliLocale.Errorf(configUsage)
+	}
+
+	load := config.LoadFile
+	if strict {
+		load = config.LoadFileStrict
+	}
+	cfg, configPath, err := load(configFile)
+	if err != nil {
+		return err
+	}
+	for _, key := range cfg.UnknownKeys {
+		fmt.Fprintf(stdout, "warning: unknown key %s\n", key)
+	}
+
+	if configPath == "" {
+		fmt.Fprintln(stdout, "No config file found, using defaults")
+		return nil
+	}
+	if n := len(cfg.UnknownKeys); n > 0 {
+		fmt.Fprintf(stdout, "%s: valid, %d unknown keys ignored\n", configPath, n)
+		return nil
+	}
+	fmt.Fprintf(stdout, "%s: valid\n", configPath)
+	return nil
+}
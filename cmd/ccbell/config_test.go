@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+)
+
+func TestRunConfigValidate(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-config-cmd-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	configFile := filepath.Join(tempDir, "ccbell.config.json")
+	if err := os.WriteFile(configFile, []byte(`{"enabled": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := runConfig([]string{"validate"}, configFile, "", false, &out); err != nil {
+		t.Fatalf("runConfig() error = %v", err)
+	}
+	if want := configFile + ": valid\n"; out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+
+	data := `{"enabled": true, "quitHours": {"start": "22:00", "end": "07:00"}}`
+	if err := os.WriteFile(configFile, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := runConfig([]string{"validate"}, configFile, "", false, &out); err != nil {
+		t.Fatalf("runConfig() error = %v", err)
+	}
+	if !strings.Contains(out.String(), `quitHours (did you mean "quietHours"?)`) {
+		t.Errorf("output = %q, want unknown key warning", out.String())
+	}
+
+	if err := runConfig([]string{"validate"}, configFile, "", true, &out); !errors.Is(err, config.ErrStrict) {
+		t.Errorf("runConfig(strict) error = %v, want ErrStrict", err)
+	}
+
+	if err := os.WriteFile(configFile, []byte(`{"enabled": true, "quietHours": {"start": "25:00"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runConfig([]string{"validate"}, configFile, "", false, &out); err == nil {
+		t.Error("runConfig() should fail for an invalid config")
+	}
+	if err := runConfig(nil, configFile, "", false, &out); err == nil {
+		t.Error("runConfig() without args should fail")
+	}
+}
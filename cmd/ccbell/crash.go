@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// crashReportGlob matches crash report file names in ~/.claude.
+const crashReportGlob = "ccbell-crash-*.log"
+
+// crashReportPath returns the crash report path for a crash at now.
+func crashReportPath(homeDir string, now time.Time) string {
+	return filepath.Join(homeDir, ".claude", "ccbell-crash-"+now.Format("20060102-150405.000")+".log")
+}
+
+// writeCrashReport records a recovered panic with its stack trace, the
+// command-line arguments and an environment summary, so hook crashes can be
+// investigated after the fact. It returns the report's path.
+func writeCrashReport(homeDir string, r any, stack []byte, args []string, now time.Time) (string, error) {
+	if homeDir == "" {
+		return "", cliLocale.Errorf("no home directory")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "ccbell crash at %s\n\n", now.Format(time.RFC3339))
+	fmt.Fprintf(&buf, "Panic:     %v\n", r)
+	fmt.Fprintf(&buf, "Version:   %s (commit: %s, built: %s)\n", version, commit, buildDate)
+	fmt.Fprintf(&buf, "Platform:  %s/%s, %s\n", runtime.GOOS, runtime.GOARCH, runtime.Version())
+	fmt.Fprintf(&buf, "PID:       %d\n", os.Getpid())
+	fmt.Fprintf(&buf, "Args:      %q\n", args)
+	if wd, err := os.Getwd(); err == nil {
+		fmt.Fprintf(&buf, "Directory: %s\n", wd)
+	}
+	fmt.Fprintln(&buf, "\nEnvironment:")
+	for _, name := range diagnoseEnv {
+		if value, ok := os.LookupEnv(name); ok {
+			fmt.Fprintf(&buf, "  %s=%s\n", name, value)
+		}
+	}
+	fmt.Fprintf(&buf, "\nStack:\n%s", stack)
+
+	path := crashReportPath(homeDir, now)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteCrashReport(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "ccbell-crash-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(homeDir)
+
+	now := time.Date(2025, 3, 10, 14, 5, 6, 0, time.Local)
+	path, err := writeCrashReport(homeDir, "boom", []byte("goroutine 1 [running]:\nmain.run()\n"), []string{"ccbell", "stop"}, now)
+	if err != nil {
+		t.Fatalf("writeCrashReport() error = %v", err)
+	}
+	if want := filepath.Join(homeDir, ".claude", "ccbell-crash-20250310-140506.000.log"); path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+	if matched, _ := filepath.Match(crashReportGlob, filepath.Base(path)); !matched {
+		t.Errorf("%s does not match %s", path, crashReportGlob)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Panic:     boom", `Args:      ["ccbell" "stop"]`, "main.run()"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("report missing %q:\n%s", want, data)
+		}
+	}
+
+	if _, err := writeCrashReport("", "boom", nil, nil, now); err == nil {
+		t.Error("writeCrashReport() without a home directory should fail")
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/daemon"
+	"github.com/mpolatcan/ccbell/pkg/ccbell"
+)
+
+// daemonUsage describes the daemon subcommand.
+const daemonUsage = "usage: ccbell daemon [--listen <host:port>]"
+
+// daemonShutdownTimeout bounds waiting for in-flight API requests on exit.
+const daemonShutdownTimeout = 5 * time.Second
+
+// backgroundInterval is how often the daemon redelivers queued remote
+// notifications and checks for due escalations.
+const backgroundInterval = time.Minute
+
+// runDaemon handles "ccbell daemon": serving the local status, control and
+// trigger API until ctx is canceled. The address and access token are
+// written to ~/.claude/ccbell-daemon.json for companion apps and scripts.
+// Event names written to ~/.claude/ccbell.fifo trigger notifications too,
+// queued remote notifications are redelivered every minute, and
+// unacknowledged notifications are escalated when due.
+func runDaemon(ctx context.Context, args []string, p *pipeline, stdout io.Writer) error {
+	addr := daemon.DefaultAddr
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--listen" && i+1 < len(args):
+			addr = args[i+1]
+			i++
+		default:
+			return cliLocale.Errorf(daemonUsage)
+		}
+	}
+	if err := daemon.CheckLoopback(addr); err != nil {
+		return err
+	}
+	infoPath := daemon.InfoPath(p.homeDir)
+	if infoPath == "" {
+		return cliLocale.Errorf("HOME is not set")
+	}
+
+	token, err := daemon.NewToken()
+	if err != nil {
+		return cliLocale.Errorf("failed to create token: %w", err)
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	info := daemon.Info{Addr: ln.Addr().String(), Token: token, PID: os.Getpid()}
+	if err := daemon.WriteInfo(infoPath, info); err != nil {
+		ln.Close()
+		return err
+	}
+	defer os.Remove(infoPath)
+
+	notify := func(ctx context.Context, event string, payload []byte) error {
+		return p.notify(ctx, ccbell.Request{Event: event, Payload: payload})
+	}
+
+	// The pipe is a convenience; the HTTP API keeps running without it
+	bgCtx, stopBackground := context.WithCancel(ctx)
+	fifoDone := make(chan struct{})
+	go func() {
+		defer close(fifoDone)
+		if err := daemon.ServeFIFO(bgCtx, daemon.FIFOPath(p.homeDir), notify, p.stderr); err != nil {
+			fmt.Fprintf(p.stderr, "ccbell: Warning: FIFO unavailable: %v\n", err)
+		}
+	}()
+	defer func() {
+		stopBackground()
+		<-fifoDone
+	}()
+
+	go runPeriodically(bgCtx, p)
+
+	srv := &http.Server{
+		Handler: daemon.New(daemon.Options{
+			ConfigFile: p.configFile,
+			HomeDir:    p.homeDir,
+			Token:      token,
+			Version:    version,
+			Notify:     notify,
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+	fmt.Fprintf(stdout, "ccbell daemon listening on http://%s (token in %s)\n", info.Addr, infoPath)
+	fmt.Fprintf(stdout, "Write event names to %s to trigger notifications\n", daemon.FIFOPath(p.homeDir))
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), daemonShutdownTimeout)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
+
+// runPeriodically redelivers queued remote notifications and escalates
+// unacknowledged ones until ctx is canceled.
+func runPeriodically(ctx context.Context, p *pipeline) {
+	ticker := time.NewTicker(backgroundInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.flushQueue(ctx); err != nil {
+				fmt.Fprintf(p.stderr, "ccbell: Warning: queue flush failed: %v\n", err)
+			}
+			if err := p.escalate(ctx); err != nil {
+				fmt.Fprintf(p.stderr, "ccbell: Warning: escalation failed: %v\n", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/daemon"
+)
+
+func TestRunDaemon(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "ccbell-daemon-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(homeDir)
+	configFile := filepath.Join(homeDir, "ccbell.config.json")
+	if err := os.WriteFile(configFile, []byte(`{"enabled": false}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	var stdout bytes.Buffer
+	p := &pipeline{configFile: configFile, homeDir: homeDir, stderr: &bytes.Buffer{}}
+	go func() { done <- runDaemon(ctx, []string{"--listen", "127.0.0.1:0"}, p, &stdout) }()
+
+	// Wait for the info file
+	infoPath := daemon.InfoPath(homeDir)
+	var info *daemon.Info
+	for i := 0; i < 100 && info == nil; i++ {
+		info, _ = daemon.ReadInfo(infoPath)
+		time.Sleep(10 * time.Millisecond)
+	}
+	if info == nil {
+		cancel()
+		t.Fatalf("daemon info not written: %v", <-done)
+	}
+
+	fifoPath := daemon.FIFOPath(homeDir)
+	for i := 0; i < 100; i++ {
+		if _, err := os.Lstat(fifoPath); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if fi, err := os.Lstat(fifoPath); err != nil || fi.Mode()&os.ModeNamedPipe == 0 {
+		t.Errorf("FIFO not created: %v", err)
+	}
+
+	resp, err := http.Get("http://" + info.Addr + "/v1/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st daemon.Status
+	err = json.NewDecoder(resp.Body).Decode(&st)
+	resp.Body.Close()
+	if err != nil || st.Enabled {
+		t.Errorf("GET /v1/status = %+v, %v", st, err)
+	}
+
+	req, _ := http.NewRequest("POST", "http://"+info.Addr+"/v1/mute", strings.NewReader(`{"muted": true}`))
+	req.Header.Set("Authorization", "Bearer "+info.Token)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("POST /v1/mute = %d", resp.StatusCode)
+	}
+
+	// Notifications are disabled, so the pipeline stops before playing
+	req, _ = http.NewRequest("POST", "http://"+info.Addr+"/v1/notify", strings.NewReader(`{"event": "stop", "payload": {"message": "Tests passed"}}`))
+	req.Header.Set("Authorization", "Bearer "+info.Token)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("POST /v1/notify = %d", resp.StatusCode)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("runDaemon() error = %v", err)
+	}
+	if _, err := os.Stat(infoPath); !os.IsNotExist(err) {
+		t.Error("info file should be removed on exit")
+	}
+	if _, err := os.Lstat(fifoPath); !os.IsNotExist(err) {
+		t.Error("FIFO should be removed on exit")
+	}
+}
+
+func TestRunDaemonArgs(t *testing.T) {
+	for _, args := range [][]string{{"--listen"}, {"--bogus"}, {"--listen", "0.0.0.0:7337"}} {
+		if err := runDaemon(context.Background(), args, &pipeline{homeDir: "/nonexistent"}, &bytes.Buffer{}); err == nil {
+			t.Errorf("runDaemon(%v) should fail", args)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+)
+
+// docsUsage describes the docs subcommand.
+const docsUsage = "usage: ccbell docs man|markdown"
+
+// usageHeading matches a section heading in the help text, e.g. "COMMANDS:".
+var usageHeading = regexp.MustCompile(`^[A-Z][A-Z -]*:$`)
+
+// usageSection is one headed section of the help text.
+type usageSection struct {
+	name  string
+	lines []string // Without the help text's four-space indent
+}
+
+// runDocs handles "ccbell docs": printing the manual page or a Markdown
+// reference built from the help text and the config schema.
+func runDocs(args []string, stdout io.Writer) error {
+	if len(args) != 1 {
+		return cliLocale.Errorf(docsUsage)
+	}
+	summary, sections := parseUsage(usageText)
+	switch args[0] {
+	case "man":
+		writeManPage(stdout, summary, sections, config.Keys())
+	case "markdown":
+		writeMarkdown(stdout, summary, sections, config.Keys())
+	default:
+		return cliLocale.Errorf(docsUsage)
+	}
+	return nil
+}
+
+// parseUsage splits help text into its one-line summary and its sections.
+func parseUsage(text string) (string, []usageSection) {
+	lines := strings.Split(text, "\n")
+	var sections []usageSection
+	for _, line := range lines[1:] {
+		switch {
+		case usageHeading.MatchString(line):
+			sections = append(sections, usageSection{name: strings.TrimSuffix(line, ":")})
+		case len(sections) > 0:
+			s := &sections[len(sections)-1]
+			s.lines = append(s.lines, strings.TrimPrefix(line, "    "))
+		}
+	}
+	for i := range sections {
+		lines := sections[i].lines
+		for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+			lines = lines[:len(lines)-1]
+		}
+		sections[i].lines = lines
+	}
+	_, summary, _ := strings.Cut(lines[0], " - ")
+	return summary, sections
+}
+
+// writeManPage writes a roff manual page for section 1.
+func writeManPage(w io.Writer, summary string, sections []usageSection, keys []config.Key) {
+	fmt.Fprintf(w, ".TH CCBELL 1 \"\" \"ccbell %s\" \"User Commands\"\n", roffEscape(version))
+	fmt.Fprintln(w, ".SH NAME")
+	fmt.Fprintf(w, "ccbell \\- %s\n", roffEscape(summary))
+	for _, s := range sections {
+		fmt.Fprintf(w, ".SH %q\n", s.name)
+		fmt.Fprintln(w, ".nf")
+		for _, line := range s.lines {
+			fmt.Fprintln(w, roffLine(line))
+		}
+		fmt.Fprintln(w, ".fi")
+	}
+	fmt.Fprintln(w, `.SH "CONFIGURATION KEYS"`)
+	for _, key := range keys {
+		fmt.Fprintln(w, ".TP")
+		fmt.Fprintf(w, ".B %s\n", roffEscape(key.Path))
+		fmt.Fprintln(w, roffLine(key.Type))
+	}
+}
+
+// roffEscape escapes backslashes, which start roff escapes.
+func roffEscape(s string) string {
+	return strings.ReplaceAll(s, `\`, `\e`)
+}
+
+// roffLine escapes a line of text, including a leading . or ' that roff
+// would read as a request.
+func roffLine(s string) string {
+	s = roffEscape(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+// writeMarkdown writes a Markdown CLI and config reference.
+func writeMarkdown(w io.Writer, summary string, sections []usageSection, keys []config.Key) {
+	fmt.Fprintf(w, "# ccbell\n\n%s.\n", summary)
+	for _, s := range sections {
+		fmt.Fprintf(w, "\n## %s\n\n```text\n", markdownTitle(s.name))
+		for _, line := range s.lines {
+			fmt.Fprintln(w, line)
+		}
+		fmt.Fprintln(w, "```")
+	}
+	fmt.Fprint(w, "\n## Configuration keys\n\n| Key | Type |\n|-----|------|\n")
+	for _, key := range keys {
+		fmt.Fprintf(w, "| `%s` | %s |\n", key.Path, key.Type)
+	}
+}
+
+// markdownTitle turns a help heading such as "EVENT TYPES" into "Event types".
+func markdownTitle(name string) string {
+	return name[:1] + strings.ToLower(name[1:])
+}
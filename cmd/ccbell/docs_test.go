@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunDocs(t *testing.T) {
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"man", []string{
+			".TH CCBELL 1",
+			"ccbell \\- Sound notifications for Claude Code\n",
+			".SH \"EVENT TYPES\"\n.nf\nstop ",
+			".SH \"CONFIGURATION KEYS\"",
+			".B events.<event>.sound\nstring or object\n",
+		}},
+		{"markdown", []string{
+			"# ccbell\n\nSound notifications for Claude Code.\n",
+			"## Event types\n\n```text\nstop ",
+			"docs man|markdown",
+			"| `quietHours.start` | string |\n",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := runDocs([]string{tt.format}, &out); err != nil {
+				t.Fatalf("runDocs() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output missing %q", want)
+				}
+			}
+		})
+	}
+
+	for _, args := range [][]string{nil, {"html"}, {"man", "extra"}} {
+		if err := runDocs(args, &bytes.Buffer{}); err == nil {
+			t.Errorf("runDocs(%q) error = nil", args)
+		}
+	}
+}
+
+// TestUsageListsCommands keeps the help text, and so the generated docs,
+// in step with the command table.
+func TestUsageListsCommands(t *testing.T) {
+	_, sections := parseUsage(usageText)
+	var listed string
+	for _, s := range sections {
+		if s.name == "COMMANDS" {
+			listed = "\n" + strings.Join(s.lines, "\n")
+		}
+	}
+	for name := range commands {
+		if strings.HasPrefix(name, "-") || name == "help" || name == "version" {
+			continue
+		}
+		if !strings.Contains(listed, "\n"+name+" ") {
+			t.Errorf("COMMANDS section doesn't describe %q", name)
+		}
+	}
+}
+
+func TestRoffLine(t *testing.T) {
+	tests := map[string]string{
+		"plain":          "plain",
+		".starts a dot":  `\&.starts a dot`,
+		"'quoted":        `\&'quoted`,
+		`C:\path`:        `C:\epath`,
+		"mid .dot stays": "mid .dot stays",
+	}
+	for in, want := range tests {
+		if got := roffLine(in); got != want {
+			t.Errorf("roffLine(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/mpolatcan/ccbell/internal/audio"
+	"github.com/mpolatcan/ccbell/internal/config"
+)
+
+// doctorUsage describes the doctor subcommand.
+const doctorUsage = "usage: ccbell doctor"
+
+// runDoctor handles "ccbell doctor": checking that the config loads and an
+// audio player is available. When a Linux player is missing it shows the
+// detected package manager and the install command, without running it.
+func runDoctor(args []string, configFile, pluginRoot string, stdout io.Writer) error {
+	if len(args) != 0 {
+		return cliLocale.Errorf(doctorUsage)
+	}
+
+	cfg, _, err := config.LoadFile(configFile)
+	if err != nil {
+		fmt.Fprintf(stdout, "Config:          %s (%v)\n", configFile, err)
+		cfg = config.Default()
+	} else {
+		fmt.Fprintf(stdout, "Config:          %s (ok)\n", configFile)
+	}
+
+	player := audio.NewPlayer(pluginRoot)
+	fmt.Fprintf(stdout, "Platform:        %s\n", player.Platform())
+	if player.Platform() != audio.PlatformLinux {
+		if player.HasAudioPlayer() {
+			fmt.Fprintln(stdout, "Audio player:    ok")
+		} else {
+			fmt.Fprintln(stdout, "Audio player:    none found")
+		}
+		return nil
+	}
+
+	var install bytes.Buffer
+	player.SetInstallDryRun(&install)
+	name, err := player.EnsureAudioPlayer()
+	if err == nil {
+		fmt.Fprintf(stdout, "Audio player:    %s\n", name)
+		return nil
+	}
+	fmt.Fprintln(stdout, "Audio player:    none found")
+	stdout.Write(install.Bytes())
+	if cfg.AutoInstallsPlayer() {
+		fmt.Fprintln(stdout, "Auto-install:    on (asks when ccbell runs in a terminal)")
+	} else {
+		fmt.Fprintln(stdout, `Auto-install:    off ("autoInstallPlayer": true to be asked)`)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mpolatcan/ccbell/internal/audio"
+)
+
+func TestRunDoctor(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-doctor-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	configFile := filepath.Join(tmpDir, "ccbell.config.json")
+	if err := os.WriteFile(configFile, []byte(`{"enabled": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(audio.BackendEnvVar, "mock") // Every player is installed
+
+	var stdout bytes.Buffer
+	if err := runDoctor(nil, configFile, tmpDir, &stdout); err != nil {
+		t.Fatalf("runDoctor() error = %v", err)
+	}
+	out := stdout.String()
+	for _, want := range []string{configFile + " (ok)", "Platform:", "Audio player:"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Would run:") {
+		t.Errorf("nothing should need installing:\n%s", out)
+	}
+
+	if err := runDoctor([]string{"--bogus"}, configFile, tmpDir, &stdout); err == nil {
+		t.Error("runDoctor(--bogus) should fail")
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/hook"
+	"github.com/mpolatcan/ccbell/pkg/ccbell"
+)
+
+// listenUsage describes the listen subcommand.
+const listenUsage = "usage: ccbell listen"
+
+// listenEvent is one line of "ccbell listen" input, in the same shape as
+// the daemon's POST /v1/notify body.
+type listenEvent struct {
+	Event   string          `json:"event"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// runListen handles "ccbell listen": reading newline-delimited JSON events
+// from stdin until EOF and running each through the pipeline, so a wrapper
+// can keep one process instead of starting ccbell per event. Bad lines and
+// failed events are reported to stderr without stopping the loop.
+func runListen(ctx context.Context, args []string, p *pipeline, stdin io.Reader, stderr io.Writer) error {
+	if len(args) != 0 {
+		return cliLocale.Errorf(listenUsage)
+	}
+
+	scanner := bufio.NewScanner(stdin)
+	scanner.Buffer(make([]byte, 0, 64<<10), hook.MaxPayloadSize+4096)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		req, err := parseListenEvent(data)
+		if err == nil {
+			err = p.notify(ctx, req)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "ccbell: line %d: %v\n", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return cliLocale.Errorf("failed to read events: %w", err)
+	}
+	return nil
+}
+
+// parseListenEvent decodes and validates one input line.
+func parseListenEvent(data []byte) (ccbell.Request, error) {
+	var e listenEvent
+	if err := json.Unmarshal(data, &e); err != nil {
+		return ccbell.Request{}, cliLocale.Errorf("invalid JSON: %w", err)
+	}
+	if err := config.ValidateEventType(e.Event); err != nil {
+		return ccbell.Request{}, err
+	}
+	return ccbell.Request{Event: e.Event, Payload: e.Payload}, nil
+}
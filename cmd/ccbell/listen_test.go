@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mpolatcan/ccbell/internal/logger"
+)
+
+func TestRunListen(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "ccbell-listen-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(homeDir)
+	if err := os.MkdirAll(filepath.Join(homeDir, ".claude"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	configFile := filepath.Join(homeDir, "ccbell.config.json")
+	if err := os.WriteFile(configFile, []byte(`{"enabled": false, "debug": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	input := strings.Join([]string{
+		`{"event": "stop", "payload": {"message": "done"}}`,
+		``,
+		`{"event":`,
+		`{"event": "build_done"}`,
+		`{"event": "subagent"}`,
+	}, "\n")
+	var stderr bytes.Buffer
+	p := &pipeline{configFile: configFile, homeDir: homeDir, stderr: &stderr}
+	if err := runListen(context.Background(), nil, p, strings.NewReader(input), &stderr); err != nil {
+		t.Fatalf("runListen() error = %v", err)
+	}
+
+	for _, want := range []string{"line 3: invalid JSON", "line 4: unknown event type: build_done"} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("stderr = %q, want %q", stderr.String(), want)
+		}
+	}
+	log, err := os.ReadFile(logger.Path(homeDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range []string{"stop", "subagent"} {
+		if !strings.Contains(string(log), "ccbell triggered: event="+event) {
+			t.Errorf("log doesn't show the %s event:\n%s", event, log)
+		}
+	}
+
+	if err := runListen(context.Background(), []string{"extra"}, p, strings.NewReader(""), &stderr); err == nil {
+		t.Error("runListen() with arguments should fail")
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/locale"
+	"github.com/mpolatcan/ccbell/internal/pack"
+)
+
+// cliLocale translates the help text and error messages. run sets it; it
+// is English until then, as in tests.
+var cliLocale locale.Locale
+
+// setCLILanguage makes the CLI speak the config's "language", or the
+// system locale's language when the config sets none.
+func setCLILanguage(configFile string) {
+	language := config.Language(configFile)
+	if language == "" {
+		language = pack.SystemLanguage()
+	}
+	cliLocale = locale.New(language)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mpolatcan/ccbell/internal/locale"
+)
+
+// cliMessages returns the help and error formats the CLI passes to
+// cliLocale, resolving constants; a message that isn't a constant fails t.
+func cliMessages(t *testing.T) []string {
+	t.Helper()
+	fset := token.NewFileSet()
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed []*ast.File
+	consts := map[string]ast.Expr{}
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") || name == "locale_tr.go" {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed = append(parsed, f)
+		for _, decl := range f.Decls {
+			if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.CONST {
+				for _, spec := range gen.Specs {
+					value := spec.(*ast.ValueSpec)
+					for i, ident := range value.Names {
+						if i < len(value.Values) {
+							consts[ident.Name] = value.Values[i]
+						}
+					}
+				}
+			}
+		}
+	}
+
+	var resolve func(ast.Expr) (string, bool)
+	resolve = func(expr ast.Expr) (string, bool) {
+		switch e := expr.(type) {
+		case *ast.BasicLit:
+			if s, err := strconv.Unquote(e.Value); err == nil {
+				return s, true
+			}
+		case *ast.Ident:
+			if value, ok := consts[e.Name]; ok {
+				return resolve(value)
+			}
+		case *ast.BinaryExpr:
+			left, ok1 := resolve(e.X)
+			right, ok2 := resolve(e.Y)
+			return left + right, ok1 && ok2 && e.Op == token.ADD
+		}
+		return "", false
+	}
+
+	var messages []string
+	for _, f := range parsed {
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if recv, ok := sel.X.(*ast.Ident); !ok || recv.Name != "cliLocale" {
+				return true
+			}
+			text, ok := resolve(call.Args[0])
+			if !ok {
+				t.Errorf("%s: message is not a constant", fset.Position(call.Pos()))
+				return true
+			}
+			messages = append(messages, text)
+			return true
+		})
+	}
+	if len(messages) == 0 {
+		t.Fatal("found no messages")
+	}
+	return messages
+}
+
+var formatVerb = regexp.MustCompile(`%[-+# 0]*[0-9]*[a-zA-Z%]`)
+
+func TestCLIMessagesTranslated(t *testing.T) {
+	tr := locale.New("tr")
+	for _, text := range cliMessages(t) {
+		if !tr.Translates(text) {
+			t.Errorf("no Turkish translation of %q", text)
+			continue
+		}
+		translated := tr.Sprintf(text)
+		if got, want := formatVerb.FindAllString(translated, -1), formatVerb.FindAllString(text, -1); !slices.Equal(got, want) {
+			t.Errorf("Turkish translation of %q has verbs %q, want %q", text, got, want)
+		}
+	}
+}
+
+// usageTokens returns the flags, config keys and commands in help text,
+// which a translation must keep as they are.
+var usageTokens = regexp.MustCompile(`--[a-z][a-z-]*|"[a-zA-Z0-9]+":|\bccbell [a-z]+\b|\$\{?[A-Z_]+\}?`)
+
+func TestTurkishUsageKeepsTokens(t *testing.T) {
+	collect := func(text string) map[string]bool {
+		tokens := map[string]bool{}
+		for _, token := range usageTokens.FindAllString(text, -1) {
+			if command, ok := strings.CutPrefix(token, "ccbell "); ok && commands[command] == nil {
+				continue
+			}
+			tokens[token] = true
+		}
+		return tokens
+	}
+	english, turkish := collect(usageText), collect(usageTextTR)
+	for token := range english {
+		if !turkish[token] {
+			t.Errorf("Turkish help lacks %s", token)
+		}
+	}
+	for token := range turkish {
+		if !english[token] {
+			t.Errorf("Turkish help has %s, which the English help lacks", token)
+		}
+	}
+}
+
+func TestRunSpeaksConfiguredLanguage(t *testing.T) {
+	oldArgs := os.Args
+	defer func() {
+		os.Args = oldArgs
+		cliLocale = locale.Locale{}
+	}()
+
+	tmpDir, err := os.MkdirTemp("", "ccbell-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	t.Setenv("CCBELL_SYSTEM_CONFIG", filepath.Join(tmpDir, "missing.json"))
+
+	tests := []struct {
+		name     string
+		config   string
+		lang     string
+		wantText string
+	}{
+		{"config language", `{"language": "tr"}`, "en_US.UTF-8", "kullanım: ccbell pause"},
+		{"system locale", `{}`, "tr_TR.UTF-8", "kullanım: ccbell pause"},
+		{"config overrides locale", `{"language": "en"}`, "tr_TR.UTF-8", "usage: ccbell pause"},
+		{"untranslated language", `{"language": "de"}`, "", "usage: ccbell pause"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(tmpDir, "config.json")
+			if err := os.WriteFile(configPath, []byte(tt.config), 0644); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("LC_ALL", "")
+			t.Setenv("LC_MESSAGES", "")
+			t.Setenv("LANG", tt.lang)
+
+			os.Args = []string{"ccbell", "pause", "--config", configPath}
+			err := run()
+			if err == nil || !strings.Contains(err.Error(), tt.wantText) {
+				t.Errorf("run() error = %v, want %q", err, tt.wantText)
+			}
+		})
+	}
+}
@@ -0,0 +1,471 @@
+package main
+
+import "github.com/mpolatcan/ccbell/internal/locale"
+
+// The CLI's help and error messages in Turkish.
+func init() {
+	locale.Register("tr", map[string]string{
+		usageText:   usageTextTR,
+		"ERROR: %v": "HATA: %v",
+
+		ackUsage:        "kullanım: ccbell ack",
+		audioUsage:      "kullanım: ccbell audio check",
+		configUsage:     "kullanım: ccbell config validate [--strict] | push|pull [--sounds] [--repo <url> | --gist <id>]",
+		daemonUsage:     "kullanım: ccbell daemon [--listen <host:port>]",
+		diagnoseUsage:   "kullanım: ccbell diagnose [--output <dosya.tar.gz>]",
+		docsUsage:       "kullanım: ccbell docs man|markdown",
+		doctorUsage:     "kullanım: ccbell doctor",
+		eventCountUsage: "kullanım: ccbell <olay> [<olay>...] | ccbell <olay> --count N",
+		listenUsage:     "kullanım: ccbell listen",
+		logsUsage:       "kullanım: ccbell logs [--follow] [--since <süre>] [--event <tür>] [--errors-only]",
+		notifyUsage:     "kullanım: ccbell notify <olay_türü> [<olay_türü>... | --count N]",
+		packUsage:       "kullanım: ccbell pack <list|uninstall <id> [--fix]>",
+		pauseUsage:      "kullanım: ccbell pause --until SS:DD | --for <süre>",
+		profileUsage:    "kullanım: ccbell profile <list|use <ad>>",
+		soundsUsage:     "kullanım: ccbell sounds <search <sorgu>|preview <id>|use <id> --event <olay>>",
+		stateUsage:      "kullanım: ccbell state gc | show [olay] | clear [olay]",
+		statsUsage:      "kullanım: ccbell stats [--since <süre>] [--event <tür>]",
+		syncUsage:       "kullanım: ccbell config push|pull [--sounds] [--repo <url> | --gist <id>]",
+		themeUsage:      "kullanım: ccbell theme <list|use <ad>>",
+		uiUsage:         "kullanım: ccbell ui",
+
+		"usage: ccbell record <event> [--seconds N]":   "kullanım: ccbell record <olay> [--seconds N]",
+		"usage: ccbell replay [--last|--id N]":         "kullanım: ccbell replay [--last|--id N]",
+		"usage: ccbell secret <set|get|delete> <name>": "kullanım: ccbell secret <set|get|delete> <ad>",
+		"usage: ccbell subagent flush <seq>":           "kullanım: ccbell subagent flush <sıra>",
+
+		"--config requires a path argument":                        "--config bir yol bekliyor",
+		"--count can't be combined with several event types":       "--count birden çok olay türüyle birlikte kullanılamaz",
+		"--seconds must be 1-%d":                                   "--seconds 1-%d arasında olmalı",
+		"%s has no %s; run ccbell config push first":               "%s içinde %s yok; önce ccbell config push çalıştırın",
+		"HOME is not set":                                          "HOME tanımlı değil",
+		"cannot locate ccbell binary: %w":                          "ccbell programı bulunamadı: %w",
+		"cannot locate log file: HOME is not set":                  "günlük dosyası bulunamadı: HOME tanımlı değil",
+		"ccbell ui needs an interactive terminal":                  "ccbell ui etkileşimli bir terminal gerektirir",
+		"failed to back up config: %w":                             "yapılandırma yedeklenemedi: %w",
+		"failed to copy sound %s: %w":                              "%s sesi kopyalanamadı: %w",
+		"failed to create bundle: %w":                              "paket oluşturulamadı: %w",
+		"failed to create sounds directory: %w":                    "ses dizini oluşturulamadı: %w",
+		"failed to create token: %w":                               "belirteç oluşturulamadı: %w",
+		"failed to read config: %w":                                "yapılandırma okunamadı: %w",
+		"failed to read events: %w":                                "olaylar okunamadı: %w",
+		"failed to read secret value: %w":                          "gizli değer okunamadı: %w",
+		"failed to save recording: %w":                             "kayıt kaydedilemedi: %w",
+		"failed to set up terminal: %w":                            "terminal hazırlanamadı: %w",
+		"failed to start batch flush: %w":                          "toplu özet başlatılamadı: %w",
+		"failed to start player ranking: %w":                       "oynatıcı sıralaması başlatılamadı: %w",
+		"failed to write bundle: %w":                               "paket yazılamadı: %w",
+		"freesound token: %w":                                      "freesound belirteci: %w",
+		"git %s failed: %w: %s":                                    "git %s başarısız oldu: %w: %s",
+		"invalid --since %q, expected e.g. 30m, 1h or 2d":          "geçersiz --since %q; örneğin 30m, 1h veya 2d bekleniyor",
+		"invalid JSON: %w":                                         "geçersiz JSON: %w",
+		"invalid batch sequence: %s":                               "geçersiz toplu özet sırası: %s",
+		"invalid config path: %w":                                  "geçersiz yapılandırma yolu: %w",
+		"invalid count: %s":                                        "geçersiz sayı: %s",
+		"invalid duration %q, expected e.g. 45m or 2h":             "geçersiz süre %q; örneğin 45m veya 2h bekleniyor",
+		"invalid sound id: %s":                                     "geçersiz ses kimliği: %s",
+		"invalid time %q, expected HH:MM":                          "geçersiz saat %q; SS:DD bekleniyor",
+		"no home directory":                                        "ev dizini yok",
+		"no log at %s (set \"debug\": true in the config)":         "%s konumunda günlük yok (yapılandırmada \"debug\": true ayarlayın)",
+		"no sound captured; check your microphone and try again":   "ses yakalanamadı; mikrofonunuzu kontrol edip yeniden deneyin",
+		"pulled config is invalid, keeping the current one: %w":    "çekilen yapılandırma geçersiz, mevcut olan korunuyor: %w",
+		"recording failed: %w: %s":                                 "kayıt başarısız oldu: %w: %s",
+		"recording needs sox or ffmpeg; install one and try again": "kayıt için sox veya ffmpeg gerekli; birini kurup yeniden deneyin",
+		"replay: HOME is not set":                                  "replay: HOME tanımlı değil",
+		"replay: invalid id: %s":                                   "replay: geçersiz kimlik: %s",
+		"saved %s but could not update config: %w":                 "%s kaydedildi ama yapılandırma güncellenemedi: %w",
+		"secret value cannot be empty":                             "gizli değer boş olamaz",
+		"unknown profile: %s":                                      "bilinmeyen profil: %s",
+		"unknown secret action: %s (valid: set, get, delete)":      "bilinmeyen secret işlemi: %s (geçerli: set, get, delete)",
+		"unknown sounds action: %s (valid: search, preview, use)":  "bilinmeyen sounds işlemi: %s (geçerli: search, preview, use)",
+		"unknown theme: %s":                                        "bilinmeyen tema: %s",
+
+		`no history recorded (set "history": {"enabled": true} in the config)`:                            `kayıtlı geçmiş yok (yapılandırmada "history": {"enabled": true} ayarlayın)`,
+		`no sync target: set "sync": {"repo": "<git url>"} or {"gist": "<id>"}, or pass --repo or --gist`: `eşitleme hedefi yok: "sync": {"repo": "<git url>"} veya {"gist": "<id>"} ayarlayın ya da --repo veya --gist verin`,
+	})
+}
+
+// usageTextTR is usageText in Turkish. Commands, options, config keys and
+// values stay as they are typed.
+const usageTextTR = `ccbell - Claude Code için sesli bildirimler
+
+KULLANIM:
+    ccbell <olay_türü> [--config <yol>]
+    ccbell <olay_türü> <olay_türü>...  Birden çok olay birden, örneğin onları
+                          birleştiren bir sarmalayıcıdan (subagent subagent stop)
+    ccbell <olay_türü> --count N        N kez yaşanmış tek bir olay
+    ccbell notify <olay_türü> ...       ccbell <olay_türü> ... ile aynı
+    ccbell <komut> [argümanlar] [SEÇENEKLER]
+    ccbell [SEÇENEKLER]
+
+OLAY TÜRLERİ:
+    stop              Claude yanıtını tamamladı
+    permission_prompt Claude izninizi bekliyor
+    idle_prompt       Claude girdinizi bekliyor
+    subagent          Bir arka plan ajanı tamamlandı
+
+KOMUTLAR:
+    notify <olay_türü>    Bir olayı bildirim hattından geçirir
+    secret set <ad>       İşletim sistemi anahtar zincirine bir gizli değer kaydeder
+                          (değer stdin'den okunur)
+    secret get <ad>       Kayıtlı bir gizli değeri yazdırır
+    secret delete <ad>    Kayıtlı bir gizli değeri siler
+    replay [--last|--id N]  Günlüğe yazılmış bir olay için hattı yeniden çalıştırır
+    record <olay> [--seconds N]  Mikrofondan ses kaydeder (sox veya ffmpeg),
+                          sessizliği kırpar ve olay için kullanır
+    sounds search <sorgu>           Freesound'da kısa sesler arar
+    sounds preview <id>             Bir Freesound sonucunu çalar
+    sounds use <id> --event <olay>  Bir sonucu indirip olay için kullanır
+                          ("freesound": {"token": "secret:freesound"} gerekir)
+    logs [--follow] [--event <tür>]  Hata ayıklama günlüğünü (~/.claude/ccbell.log)
+                          yazdırır; isteğe bağlı olarak kancalar çalıştıkça
+                          yeni satırları izler
+    logs --since 1h --event <tür> --errors-only
+                          Döndürülmüş günlükler dahil eşleşen kayıtları yazdırır
+    pause --until SS:DD   Bildirimleri bir saate kadar duraklatır (kendiliğinden sürer)
+    pause --for <süre>    Bildirimleri bir süre duraklatır, örneğin 45m veya 2h
+    resume                Duraklatmayı erken bitirir
+    ack                   Son bildirimi görüldü sayar, böylece yükseltilmez
+    status                Bildirimlerin açık, duraklatılmış ya da sessiz olduğunu gösterir
+    stats [--since 7d] [--event <tür>]
+                          Günlük bildirim sayıları ("history" gerekir)
+    state gc              Bekleme süresi durum dosyasından eski kayıtları siler
+    state show [olay]     Son tetiklenme zamanlarını, kalan bekleme sürelerini,
+                          duraklatmayı, bekletilen alt ajanları, kuyruktaki
+                          gönderimleri, açık devreleri ve oynatıcı sıralamasını yazdırır
+    state clear [olay]    Bir olayın bekleme süresini, olay verilmezse tüm durumu sıfırlar
+    ui                    Terminal paneli: durum, olay başına açma/kapama ve ses
+                          düzeyi kaydırıcıları (yapılandırmaya kaydedilir), son bildirimler
+    daemon [--listen <host:port>]  Menü çubuğu ve tepsi uygulamaları ile betikler
+                          için yerel bir HTTP API sunar (varsayılan 127.0.0.1:7337):
+                          GET /v1/status, POST /v1/mute, GET /v1/events ve
+                          bildirim tetiklemek için
+                          POST /v1/notify {"event": "stop", "payload": {...}}.
+                          Adres ve POST isteklerinin gerektirdiği belirteç
+                          ~/.claude/ccbell-daemon.json dosyasındadır.
+                          Olay adlarını ~/.claude/ccbell.fifo'dan da okur:
+                          echo stop > ~/.claude/ccbell.fifo
+    listen                EOF'a kadar stdin'den satır satır JSON olaylar okur,
+                          her satırda bir tane: {"event": "stop", "payload": {...}}
+    config validate [--strict]  Yapılandırmayı denetler, bilinmeyen (yanlış
+                          yazılmış) anahtarlar için uyarır
+    config push [--sounds]  Yapılandırmayı (ve ~/.claude/ccbell/sounds içindeki özel
+                          sesleri) "sync" git deposuna veya gist'e işler
+    config pull [--sounds]  Yapılandırmayı (ve sesleri) eşitlenmiş kopyayla
+                          değiştirir, önceki yapılandırmayı <yapılandırma>.bak
+                          olarak saklar
+    profile list          Yerleşik silent ve minimal dahil profilleri listeler
+    profile use <ad>      Etkin profili değiştirir
+    theme list            Ses temalarını listeler (* etkin olanı gösterir)
+    theme use <ad>        Etkin ses temasını değiştirir
+    pack list             ~/.claude/ccbell/packs içindeki ses paketlerini listeler
+    pack uninstall <id> [--fix]  Bir paketi kaldırır ve onu hâlâ kullanan
+                          yapılandırma için uyarır; --fix bu ayarları
+                          varsayılan seslere döndürür
+    diagnose [--output <dosya>]  Yapılandırmayı (gizli değerler gizlenmiş),
+                          günlükleri (URL'ler ve belirteçler temizlenmiş), durumu,
+                          platform bilgisini ve kullanılabilir oynatıcıları hata
+                          raporları için bir tar.gz dosyasında toplar; varsa çökme
+                          raporlarını da (~/.claude/ccbell-crash-<zaman>.log) ekler
+    doctor                Yapılandırmayı ve ses oynatıcısını denetler; eksik bir
+                          Linux oynatıcısı, bulunan paket yöneticisi ve kurulum
+                          komutuyla gösterilir, komut çalıştırılmaz
+    audio check           Kurulu her ses oynatıcısıyla bir deneme sesi çalar ve
+                          açılış gecikmesini bildirir, bir "players" sırası önerir
+                          (ve önbellekteki Linux oynatıcı sıralamasını günceller)
+    docs man|markdown     Bu yardımdan ve yapılandırma şemasından üretilen kılavuz
+                          sayfasını (roff) veya Markdown CLI ve yapılandırma
+                          başvurusunu (İngilizce) yazdırır
+
+SEÇENEKLER:
+    Her komut kabul eder, komut satırının herhangi bir yerinde.
+    -h, --help        Bu yardım iletisini gösterir (ayrıca: ccbell help)
+    -v, --version     Sürüm bilgisini gösterir (ayrıca: ccbell version)
+    --config <yol>    Başka bir yapılandırma dosyası kullanır
+    --strict          Bilinmeyen yapılandırma anahtarlarında, eksik ses
+                      dosyalarında ve ulaşılamayan profillerde uyarmak ya da
+                      varsayılanları kullanmak yerine başarısız olur
+                      (yapılandırmadaki "strict": true ile aynı)
+
+YAPILANDIRMA:
+    Sistem yapılandırması:  /etc/ccbell/config.json (temel katman)
+    Genel yapılandırma:     ~/.claude/ccbell.config.json
+    Geçersiz kılma:         --config <yol> veya $CCBELL_CONFIG
+    Profiller:              ~/.claude/ccbell/profiles/<ad>.json
+
+SES BİÇİMLERİ:
+    bundled:stop         Eklentiyle gelir
+    bundled:permission_prompt
+    bundled:idle_prompt
+    bundled:subagent
+    bundled:<ad>         "soundPaths" dizinlerinde de aranır
+    system:Glass         İşletim sistemi sesi (macOS /System/Library/Sounds,
+                         Linux freedesktop)
+    custom:/path/to.mp3  Özel ses dosyası
+    pack:<id>:<olay>     ~/.claude/ccbell/packs/<id> içindeki bir paketten ses
+    pack:<id>            Hangi olay tetiklenirse paketin o olaya ait sesi;
+                         böylece her olay aynı tanımı kullanabilir
+    url:https://host/ding.mp3  Bir kez indirilip ~/.claude/ccbell/cache/downloads
+                         içinde önbelleğe alınır; yalnızca https, boyutu
+                         soundLimits ile sınırlı; sabitlemek için sonuna
+                         #sha256=<hex> ekleyin
+    "soundPermissions": "not-world-writable" herkesin yazabildiği özel ve paket
+                         ses dosyalarını reddeder; "owner" ayrıca size veya
+                         root'a ait olmalarını ister (varsayılan: "off")
+    "allowedSoundDirs": ["${HOME}/.claude/ccbell"] özel ve paket seslerini
+                         yalnızca bu dizinlerin içindeyse çalar (sembolik
+                         bağlantılar çözülür)
+    "allowSymlinks": "deny" sembolik bağlantılı ses dosyalarını reddeder; "allow"
+                         onları her yere izler. Varsayılan "resolve-within-root",
+                         onları sesi içeren ses dizini, arama yolu ya da
+                         allowedSoundDirs girdisi içinde tutar; diğer özel ses
+                         bağlantıları izlenir
+
+GİZLİ DEĞERLER:
+    Bir anahtar zinciri gizli değerine başvurmak için yapılandırma değerlerinde
+    "secret:<ad>" kullanın.
+
+YİNELENENLERİ ENGELLEME:
+    "dedupeWindow": 30    olay başına; 30 saniye içinde yeniden gönderilen aynı
+                          kanca verilerini bastırır (bekleme süresinden ayrı)
+
+TAZE BAŞLANGIÇ:
+    "freshStartSeconds": 1800  30 dakika hiç olay olmadıktan sonraki ilk olay
+                          bekleme süresini atlar ve tam ses düzeyinde çalar
+                          (yine maxVolume ile sınırlı); uyku ya da saat
+                          sıçraması durum dosyasında eski zamanlar bıraktığında da
+
+ALT AJAN TOPLAMA:
+    "subagentBatch": {"enabled": true, "quietPeriod": 5}
+    Alt ajan tamamlanmalarını, her biri için bir çan yerine, quietPeriod saniye
+    boyunca yenisi gelmediğinde özetler ("4 alt ajan tamamlandı").
+
+ALT AJANLARI BEKLEME:
+    "waitForSubagents": {"enabled": true, "sound": "system:Glass"}
+    Alt ajan bildirimlerini oturumun stop olayına kadar bekletir; stop olayı
+    ardından ayrı bir "her şey bitti" sesi çalar.
+
+OLAY GÜNLÜĞÜ:
+    "journal": {"enabled": true, "maxSizeKB": 1024}
+    Her kanca verisini (hassas değerler gizlenmiş) ~/.claude/ccbell.events.jsonl
+    dosyasına ekler; dosya maxSizeKB'yi aşınca döndürülür.
+
+GEÇMİŞ:
+    "history": {"enabled": true, "retentionDays": 90}
+    "ccbell stats" için her bildirimi ~/.claude/ccbell/history.db içine kaydeder.
+    retentionDays'ten eski kayıtlar silinir.
+
+KANALLAR:
+    "channels": ["sound", "desktop", "terminal", "uservar", "webhook", "bark",
+                 "led", "text"]
+                      olay başına, varsayılan ["sound"]
+    Kanallara aynı anda bildirilir; "webhook" için "webhook": {"url": ...} gerekir
+    "webhook": {"url": ..., "preset": "ifttt"}  IFTTT Webhooks value1-3 (başlık,
+    ileti, olay) ya da Zapier catch hook için "zapier" düz alanları gönderir
+    "webhook": {"url": ..., "template": "{\"text\": {{json .Message}}}"}  tam
+    olarak yazdığınız JSON'u gönderir; .Event, .Title, .Message, .Project,
+    .Branch, .Hostname, .Timestamp ve kanca verisinin alanlarını
+    (.Payload.session_id) içeren bir Go şablonu olarak; {{json ...}} değerleri
+    JSON için tırnaklar
+    "desktop": {"focusApp": "auto"}   bir masaüstü bildirimine tıklamak terminale
+    ("auto") ya da adı verilen uygulamaya odaklanır (macOS'ta terminal-notifier,
+    Linux'ta wmctrl veya xdotool)
+    "desktop": {"group": 300}  bir olayın 300 saniye içindeki tekrarları,
+    bildirimleri üst üste yığmak yerine masaüstü bildirimini değiştirir
+    ("3 alt ajan tamamlandı") (Linux'ta notify-send 0.7.10+; macOS'ta olay
+    başına terminal-notifier grupları)
+    "desktop": {"bypassDnd": false}  kritik olayların rahatsız etmeyin modunu
+    aşmasını engeller (varsayılan olarak Linux'ta urgency=critical, macOS'ta
+    kuruluysa terminal-notifier'ın -ignoreDnD seçeneğini kullanırlar)
+    "terminal": {"sequence": "osc9"}  "terminal" terminale bir OSC 9 (iTerm2,
+    kitty, WezTerm) ya da "osc777" (foot, Ghostty, VTE) bildirimi yazar; SSH
+    üzerinden de çalışır
+    "terminal": {"userVar": "ccbell_event"}  "uservar" bir terminal kullanıcı
+    değişkenini olay türüne ayarlar (WezTerm/iTerm2 için OSC 1337, kitty için
+    kitten @)
+    "bark": {"deviceKey": "secret:bark"}  "bark" Bark iOS uygulamasına gönderir
+    (isteğe bağlı "server", varsayılan https://api.day.app; "body" webhook'taki
+    gibi bildirim metni için bir şablondur)
+    "led": {"colors": {"stop": "#00ff00"}, "blinks": 3}  "led" sessiz geri
+    bildirim için blink1-tool ile bir blink(1) USB LED'ini yakıp söndürür
+    "text": {"speak": true}  "text" ekran okuyucular için terminale düz bir
+    durum satırı yazar; isteğe bağlı olarak say (macOS) ya da spd-say (Linux)
+    ile seslendirir
+    Başlıklar, kancanın çalışma dizinindeki proje dizinini ve git dalını
+    içerir ("Claude Code · api (main)"); webhook'lar ayrıca "project" ve
+    "branch" alanlarını alır. Kendi başlığınızı şöyle ayarlayın:
+    "notificationTitle": "{project}@{branch}: {event}"
+    Webhook verileri ve Bark alt başlıkları makinenin adını içerir
+    (başlıklarda {hostname}); "sendHostname": false bunu dışarıda bırakır.
+    Başarısız "webhook" ve "bark" gönderimleri üstel geri çekilmeyle yeniden
+    denenir; "retry": {"attempts": 3, "budget": 10} toplam deneme sayısını ve
+    bunlar için izin verilen saniyeyi ayarlar ("attempts": 1 yeniden denemeyi
+    kapatır).
+    Yine başarısız olan gönderimler kuyruğa alınır ve bir gün içinde bir sonraki
+    bildirimle (onları en çok 2 saniye bekler) ya da çalışan bir
+    "ccbell daemon" ile gönderilir; "retry": {"queue": false} onları atar.
+    Art arda 3 başarısız gönderimden sonra bir uzak kanal 5 dakika atlanır
+    (bu arada gönderimler kuyruğa alınır); bunu
+    "circuitBreaker": {"failures": 3, "cooldown": 300} ile değiştirin
+    ("failures": 0 kapatır).
+
+METİN BİÇİMİ:
+    "format": {"icon": "🚨", "severity": "critical", "body": "{icon} {message}"}
+    olay başına; "body" masaüstü, terminal, metin ve anlık bildirimlerin
+    metnini {message}, {icon}, {severity}, {project}, {branch}, {hostname} ve
+    {event} ile oluşturur ({icon} notificationTitle içinde de çalışır).
+    Yerleşik simgeler: stop ✅, permission_prompt 🔐, idle_prompt ⏳, subagent 🤖,
+    diğerleri 🔔. Önem derecesi "info" (varsayılan), "warning"
+    (permission_prompt'un varsayılanı; sarı metin satırı) ya da "critical"
+    (kırmızı metin satırı, acil Linux masaüstü bildirimi) olur. Webhook
+    şablonları .Icon ve .Severity alır.
+
+MASAÜSTÜ KALICILIĞI:
+    "desktop": {"timeout": 0, "urgency": "critical"} olay başına, Linux masaüstü
+    bildiriminin ne kadar açık kalacağını (saniye; 0 kapatılana kadar) ve
+    aciliyetini ("low", "normal" ya da "critical"; varsayılan önem
+    derecesinden) ayarlar; örneğin kalıcı izin istemleri ve süresi dolan stop
+    bildirimleri.
+
+SES PAKETLERİ:
+    "language": "tr"      Ses paketlerinin dili (varsayılan: $LANG'den); paketin
+                          ilk listelenen diline geri döner. Ayrıca yerleşik
+                          bildirim metninin, CLI yardımının ve hata iletilerinin
+                          dili (İngilizce, Türkçe "tr")
+
+SES YEDEĞİ:
+    Bir ses çalınamadığında (oynatıcı yok, dosya eksik) onun yerine bir masaüstü
+    bildirimi gösterilir. "soundFallback": "bell" terminal zilini çaldırır;
+    "none" yalnızca hatayı bildirir.
+    Linux'ta eksik bir ses oynatıcısı, onu kuran komutla birlikte bildirilir.
+    "autoInstallPlayer": true ile terminalden çalıştırılan ccbell kurmadan önce
+    sorar; kancalar asla sudo çalıştırmaz.
+
+OYNATICILAR:
+    "players": ["paplay", "mpv"]
+    Önce denenecek Linux ve BSD ses oynatıcıları, sırayla; kalanlar varsayılan
+    sırayla gelir (Linux: mpv, paplay, aplay, ffplay; BSD: mpv, ffplay, aucat).
+    ccbell audio check hangisinin en hızlı açıldığını ölçer.
+    "players" olmadan, ilk Linux bildirimi kurulu her oynatıcının sessizlik
+    çalma süresini ölçen bir arka plan süreci başlatır ve sonraki bildirimler
+    önce en hızlısını dener; sıralama durum dosyasında bir hafta saklanır ve
+    ccbell audio check onu yeniler.
+
+EN YÜKSEK SES:
+    "maxVolume": 0.6 profiller, kurallar, paketler ve kazanç uygulandıktan sonra
+    her sesi sınırlar; böylece hiçbir ayar daha yüksek sesle çalamaz.
+
+KAZANÇ:
+    "gain": -6            olay başına; ses dosyalarını eşitlemek için ses düzeyinin
+                          üstüne uygulanan dB cinsinden ayar (-40 ile +20 arası)
+
+ÇEŞİTLEME:
+    "variation": {"mode": "pitch", "amount": 0.05}
+    olay başına; her çalışı ±%5'e kadar rastgele değiştirir ("pitch" mpv gerektirir,
+    "tempo" mpv, ffplay ve afplay ile çalışır)
+    "perSession": true bunun yerine kancanın oturum kimliğinden 5 sabit
+    adımdan birini seçer; böylece paralel oturumlar farklı ama her biri hep
+    aynı duyulur.
+
+AFPLAY SEÇENEKLERİ:
+    "afplay": {"rate": 1.5, "quality": 1} olay başına, dosyayı düzenlemeden
+    macOS'ta çalmayı perdeyi koruyarak hızlandırır (ya da yavaşlatır, 0.25-4);
+    "quality" 1, afplay'in daha kaliteli hız ölçeklemesidir (-q 1).
+
+OTURUM SESLERİ:
+    "sessionSounds": ["system:Glass", "system:Hero", "system:Pop"]
+    olay başına; her Claude oturumu, paralel oturumları kulaktan ayırt etmek
+    için "sound" yerine oturum kimliğine göre seçilen çeşidi çalar.
+
+YANIT UZUNLUĞU:
+    "responseLength": {"shortSeconds": 10, "longSeconds": 120,
+                       "short": {"sound": "system:Tink", "volume": 0.3},
+                       "long": {"sound": "system:Hero", "volume": 0.8}}
+    olay başına; kanca verisi yanıtın süresini (ya da shortTokens/longTokens
+    ile çıktı belirteçlerini) bildirdiğinde kısa ve uzun yanıtlar bu ayarları
+    kullanır.
+
+PROFİL ZAMANLAMASI:
+    "activeProfile": {"weekday": "work", "weekend": "home"}
+    yapılandırma yüklendiğinde profili güne göre seçer; eksik bir gün türü
+    varsayılan profili kullanır.
+
+TATİLLER:
+    "holidays": {"dates": ["2025-12-25"], "ics": "${HOME}/holidays.ics"}
+    listelenen günleri ve takvimin etkinliklerini profil zamanlamaları ve hafta
+    sonu sessiz saatleri için hafta sonu gibi sayar. "ics" ayarını ülkenizin
+    resmi tatil takvimine yöneltin. Hafta sonu sessiz saatleri:
+    "quietHours": {"start": "22:00", "end": "07:00",
+                   "weekend": {"start": "23:00", "end": "10:00"}}
+
+GÜN BATIMI SESSİZ SAATLERİ:
+    "quietHours": {"start": "sunset+30m", "end": "sunrise"}
+    gün ışığını izler: start ve end "HH:MM", "sunrise" ya da "sunset" alır,
+    isteğe bağlı olarak "sunrise-1h" gibi bir farkla. Konum varsayılan olarak
+    saat diliminizin ana şehridir; kesin olmak için "latitude" ve "longitude"
+    (derece, doğu pozitif) ayarlayın. Gün doğumu ya da gün batımı olmayan
+    günlerde sessiz saatler uygulanmaz.
+
+DAL KURALLARI:
+    "branches": [{"branch": "release/*", "events": {"permission_prompt": {"volume": 1.0}}},
+                 {"worktree": "/home/me/src/app-*", "events": {"stop": {"enabled": false}}}]
+    kancanın çalışma dizini eşleşen bir dalda ya da çalışma ağacı yolunda
+    (glob kalıpları) bir git kopyası olduğunda olayları geçersiz kılar.
+    Eşleşen kurallar etkin profilden sonra sırayla uygulanır.
+
+TEMALAR:
+    "theme": "system" her olayın varsayılan yerleşik sesini temanın sesiyle
+    değiştirir. Yerleşik temalar: bundled, system. Kendi temanızı şöyle tanımlayın:
+    "themes": {"retro": {"stop": "pack:retro:stop", "subagent": "system:Pop"}}
+    Başka bir sese ayarlanmış olaylar o sesi korur.
+    "activePack": "retro" varsayılan yerleşik sesler yerine, temadan önce
+    paketin seslerini çalar. Paketin sesi olmayan olaylar yerleşik sesi korur.
+
+YAPILANDIRMA EŞİTLEME:
+    "sync": {"repo": "git@github.com:me/ccbell-config.git"} ya da
+    "sync": {"gist": "<gist id>"} "config push" ve "config pull" komutlarının
+    yapılandırmayı nerede tutacağını ayarlar (--repo ve --gist bunu geçersiz
+    kılar). Ev dizininizin altındaki özel ses yolları ${HOME} olarak yazılır,
+    böylece her makinede çalışırlar.
+
+ERİŞİLEBİLİRLİK:
+    İşletim sistemi uygulamalardan sesi azaltmalarını istediğinde (macOS'ta
+    "Kullanıcı arayüzü ses efektlerini çal" kapalı, GNOME olay sesleri kapalı)
+    sesli bildirimler masaüstü bildirimlerine dönüşür.
+    "accessibility": {"mode": "quiet", "volume": 0.2} onları bunun yerine kısık
+    sesle çalar; {"followSystem": false} işletim sistemi ayarını yok sayar.
+
+EKRAN KİLİDİ:
+    "screenLock": {} ekran kilitliyken, kimsenin duymayacağı sesleri çalmak
+    yerine bildirimleri yalnızca olayın uzak kanallarına (webhook, bark)
+    gönderir. "screenLock": {"channels": ["bark"]} kanalları seçer.
+
+ETKİN GÖRÜŞMELER:
+    "activeCall": {} mikrofon ya da kamera kullanımdayken ses çalmak yerine
+    bir masaüstü bildirimi gösterir. "activeCall": {"mode": "mute"} sesi
+    atar ve olayın diğer kanallarını korur.
+
+YÜKSELTME:
+    "escalate": {"after": 300, "channels": ["bark"]} bir olay 300 saniye
+    içinde "ccbell ack" ile ya da sonraki olayla onaylanmazsa onu bir kez
+    yeniden gönderir. Kanallar varsayılan olarak yapılandırılmış webhook ve
+    bark'tır. Yükseltmeleri "ccbell daemon" gönderir.
+
+PLATFORMA ÖZEL SESLER:
+    "sound": {"macos": "system:Glass", "linux": "bundled:stop", "default": "bundled:stop"}
+    Platform anahtarları: macos, linux, bsd (FreeBSD, OpenBSD, NetBSD).
+
+DEĞİŞKENLER:
+    ${VAR} şu ayarlarda ortam değerine genişletilir: ses tanımları (platform,
+    oturum ve responseLength sesleri ile waitForSubagents.sound dahil),
+    soundPaths, allowedSoundDirs, branches[].worktree, webhook.url,
+    webhook.headers, bark.server, bark.deviceKey, freesound.token ve
+    holidays.ics. Düz bir ${VAR} için $${VAR} yazın.
+
+ORTAM:
+    CLAUDE_PLUGIN_ROOT   Eklenti kurulum dizini
+    CCBELL_CONFIG        Başka bir yapılandırma dosyası yolu
+    CCBELL_SYSTEM_CONFIG Başka bir sistem geneli temel yapılandırma yolu
+    CCBELL_AUDIO_BACKEND Çalmak yerine oynatıcı komutlarını yazdırmak için "mock"
+
+Daha fazla bilgi için: https://github.com/mpolatcan/ccbell`
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/logger"
+)
+
+// logsUsage describes the logs subcommand.
+const logsUsage = "usage: ccbell logs [--follow] [--since <duration>] [--event <type>] [--errors-only]"
+
+// logPollInterval is how often --follow checks the log for new lines.
+var logPollInterval = 250 * time.Millisecond
+
+// ANSI colors for log output.
+const (
+	colorReset  = "\033[0m"
+	colorDim    = "\033[2m"
+	colorRed    = "\033[31m"
+	colorYellow = "\033[33m"
+	colorCyan   = "\033[36m"
+)
+
+// logsOptions are the parsed arguments of "ccbell logs".
+type logsOptions struct {
+	follow     bool
+	event      string
+	since      time.Time
+	errorsOnly bool
+}
+
+// parseLogsArgs parses the arguments of "ccbell logs". --since is relative
+// to now.
+func parseLogsArgs(args []string, now time.Time) (logsOptions, error) {
+	var opts logsOptions
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--follow", "-f":
+			opts.follow = true
+		case "--event":
+			if i+1 >= len(args) {
+				return opts, cliLocale.Errorf(logsUsage)
+			}
+			i++
+			if err := config.ValidateEventType(args[i]); err != nil {
+				return opts, err
+			}
+			opts.event = args[i]
+		case "--since":
+			if i+1 >= len(args) {
+				return opts, cliLocale.Errorf(logsUsage)
+			}
+			i++
+			d, err := parseSince(args[i])
+			if err != nil {
+				return opts, err
+			}
+			opts.since = now.Add(-d)
+		case "--errors-only":
+			opts.errorsOnly = true
+		default:
+			return opts, cliLocale.Errorf(logsUsage)
+		}
+	}
+	return opts, nil
+}
+
+// parseSince parses a --since duration. Besides Go durations such as 90m
+// or 1h30m, whole days are accepted as e.g. 2d.
+func parseSince(s string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil || d <= 0 {
+		return 0, cliLocale.Errorf("invalid --since %q, expected e.g. 30m, 1h or 2d", s)
+	}
+	return d, nil
+}
+
+// match reports whether an entry passes the filters.
+func (o logsOptions) match(e logger.Entry) bool {
+	if o.event != "" && e.Event != o.event {
+		return false
+	}
+	if o.errorsOnly && e.Level != logger.LevelError {
+		return false
+	}
+	return o.since.IsZero() || !e.Time.Before(o.since)
+}
+
+// runLogs handles "ccbell logs": printing matching debug log entries,
+// including rotated logs, or following the log as hooks append to it.
+func runLogs(ctx context.Context, args []string, homeDir string, now time.Time, color bool, stdout io.Writer) error {
+	opts, err := parseLogsArgs(args, now)
+	if err != nil {
+		return err
+	}
+	path := logger.Path(homeDir)
+	if path == "" {
+		return cliLocale.Errorf("cannot locate log file: HOME is not set")
+	}
+
+	w := &logWriter{opts: opts, color: color, out: stdout}
+	if !opts.follow {
+		if err := printRotatedLogs(path, w); err != nil {
+			return err
+		}
+	}
+	f, err := os.Open(path)
+	if err != nil && !(os.IsNotExist(err) && opts.follow) {
+		if os.IsNotExist(err) {
+			return cliLocale.Errorf("no log at %s (set \"debug\": true in the config)", path)
+		}
+		return err
+	}
+	var offset int64
+	if f != nil {
+		offset, err = w.copy(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if !opts.follow {
+		w.flush()
+		return nil
+	}
+	return followLog(ctx, path, offset, w)
+}
+
+// printRotatedLogs prints the rotated copies of the log, oldest first.
+func printRotatedLogs(path string, w *logWriter) error {
+	for i := logger.RotateCount - 1; i >= 0; i-- {
+		f, err := os.Open(fmt.Sprintf("%s.%d", path, i))
+		if err != nil {
+			continue
+		}
+		_, err = w.copy(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		w.flush()
+	}
+	return nil
+}
+
+// followLog prints lines appended to path after offset until ctx is done,
+// starting over when the log is rotated or truncated.
+func followLog(ctx context.Context, path string, offset int64, w *logWriter) error {
+	var last os.FileInfo
+	ticker := time.NewTicker(logPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue // Rotated away; the next write recreates it
+		}
+		if (last != nil && !os.SameFile(last, info)) || info.Size() < offset {
+			offset = 0
+			w.partial = ""
+		}
+		last = info
+		if info.Size() == offset {
+			continue
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		if _, err := f.Seek(offset, io.SeekStart); err == nil {
+			n, err := w.copy(f)
+			offset += n
+			if err != nil {
+				f.Close()
+				return err
+			}
+		}
+		f.Close()
+	}
+}
+
+// logWriter filters and colorizes log lines.
+type logWriter struct {
+	opts    logsOptions
+	color   bool
+	out     io.Writer
+	partial string // Incomplete last line, awaiting the rest
+	matched bool   // Whether the last entry passed the filters
+}
+
+// copy writes the matching complete lines of r and returns the number of
+// bytes read.
+func (w *logWriter) copy(r io.Reader) (int64, error) {
+	var n int64
+	reader := bufio.NewReader(r)
+	for {
+		chunk, err := reader.ReadString('\n')
+		n += int64(len(chunk))
+		if err != nil {
+			w.partial += chunk
+			if err == io.EOF {
+				return n, nil
+			}
+			return n, err
+		}
+		line := w.partial + strings.TrimSuffix(chunk, "\n")
+		w.partial = ""
+		w.writeLine(line)
+	}
+}
+
+// flush prints a final line that lacks its newline.
+func (w *logWriter) flush() {
+	if w.partial != "" {
+		w.writeLine(w.partial)
+		w.partial = ""
+	}
+}
+
+// writeLine prints one line if it passes the filters. Lines that are not
+// entries continue the previous entry and follow its match.
+func (w *logWriter) writeLine(line string) {
+	entry, ok := logger.ParseLine(line)
+	if ok {
+		w.matched = w.opts.match(entry)
+	}
+	if !w.matched {
+		return
+	}
+	if ok && w.color {
+		line = colorizeEntry(entry)
+	}
+	fmt.Fprintln(w.out, line)
+}
+
+// colorizeEntry formats an entry with its level and event highlighted.
+func colorizeEntry(e logger.Entry) string {
+	levelColor := colorDim
+	switch e.Level {
+	case logger.LevelWarn:
+		levelColor = colorYellow
+	case logger.LevelError:
+		levelColor = colorRed
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%s [%d]%s %s%-5s%s ", colorDim, e.Time.Format(logger.TimeFormat), e.PID, colorReset, levelColor, e.Level, colorReset)
+	if e.Event != "" {
+		fmt.Fprintf(&b, "%s%s%s ", colorCyan, e.Event, colorReset)
+	}
+	if e.Level == logger.LevelError {
+		b.WriteString(colorRed + e.Message + colorReset)
+	} else {
+		b.WriteString(e.Message)
+	}
+	return b.String()
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+const testLog = `[2025-03-10 14:05:09] [1] [DEBUG] === ccbell triggered: event=stop ===
+[2025-03-10 14:05:09] [1] [DEBUG] [stop] Event config: enabled=true
+[2025-03-10 14:05:09] [1] [ERROR] [stop] Sound playback failed: boom
+[2025-03-10 14:05:10] [2] [DEBUG] [subagent] Event config: enabled=true
+`
+
+// writeTestLog creates ~/.claude/ccbell.log under homeDir.
+func writeTestLog(t *testing.T, homeDir, content string) string {
+	t.Helper()
+	path := filepath.Join(homeDir, ".claude", "ccbell.log")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseLogsArgs(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		args    []string
+		want    logsOptions
+		wantErr bool
+	}{
+		{"none", nil, logsOptions{}, false},
+		{"follow", []string{"-f", "--event", "stop"}, logsOptions{follow: true, event: "stop"}, false},
+		{"bad event", []string{"--event", "Bad-Event"}, logsOptions{}, true},
+		{"missing event", []string{"--event"}, logsOptions{}, true},
+		{"since", []string{"--since", "1h", "--errors-only"}, logsOptions{since: now.Add(-time.Hour), errorsOnly: true}, false},
+		{"since days", []string{"--since", "2d"}, logsOptions{since: now.Add(-48 * time.Hour)}, false},
+		{"bad since", []string{"--since", "yesterday"}, logsOptions{}, true},
+		{"negative since", []string{"--since", "-1h"}, logsOptions{}, true},
+		{"unknown flag", []string{"--tail"}, logsOptions{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLogsArgs(tt.args, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseLogsArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parseLogsArgs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunLogs(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "ccbell-logs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(homeDir)
+
+	var out bytes.Buffer
+	if err := runLogs(context.Background(), nil, homeDir, time.Now(), false, &out); err == nil {
+		t.Error("runLogs() without a log should fail")
+	}
+
+	writeTestLog(t, homeDir, testLog)
+	if err := runLogs(context.Background(), []string{"--event", "stop"}, homeDir, time.Now(), false, &out); err != nil {
+		t.Fatalf("runLogs() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], "Sound playback failed") {
+		t.Errorf("filtered output = %q", out.String())
+	}
+
+	out.Reset()
+	if err := runLogs(context.Background(), nil, homeDir, time.Now(), true, &out); err != nil {
+		t.Fatalf("runLogs() error = %v", err)
+	}
+	if !strings.Contains(out.String(), colorRed+"ERROR"+colorReset) || !strings.Contains(out.String(), colorCyan+"subagent"+colorReset) {
+		t.Errorf("colorized output = %q", out.String())
+	}
+}
+
+func TestRunLogsQuery(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "ccbell-logs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(homeDir)
+
+	path := writeTestLog(t, homeDir, testLog+"[2025-03-10 16:00:00] [5] [ERROR] [permission_prompt] Notification failed: late\n")
+	old := "[2025-03-09 10:00:00] [9] [ERROR] [permission_prompt] Notification failed: old\n"
+	if err := os.WriteFile(path+".1", []byte(old), 0600); err != nil {
+		t.Fatal(err)
+	}
+	older := "[2025-03-08 10:00:00] [8] [ERROR] [permission_prompt] Notification failed: older\n"
+	if err := os.WriteFile(path+".2", []byte(older), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2025, 3, 10, 16, 30, 0, 0, time.Local)
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"errors only", []string{"--errors-only"}, []string{"older", "old", "boom", "late"}},
+		{"event and since", []string{"--since", "2d", "--event", "permission_prompt", "--errors-only"}, []string{"old", "late"}},
+		{"since", []string{"--since", "1h"}, []string{"late"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := runLogs(context.Background(), tt.args, homeDir, now, false, &out); err != nil {
+				t.Fatalf("runLogs() error = %v", err)
+			}
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("got %d lines, want %d: %q", len(lines), len(tt.want), out.String())
+			}
+			for i, want := range tt.want {
+				if !strings.HasSuffix(lines[i], ": "+want) {
+					t.Errorf("line %d = %q, want suffix %q", i, lines[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRunLogsFollow(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "ccbell-logs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(homeDir)
+
+	orig := logPollInterval
+	logPollInterval = 10 * time.Millisecond
+	defer func() { logPollInterval = orig }()
+
+	path := writeTestLog(t, homeDir, testLog)
+	ctx, cancel := context.WithCancel(context.Background())
+	out := &syncBuffer{}
+	done := make(chan error, 1)
+	go func() {
+		done <- runLogs(ctx, []string{"--follow", "--event", "subagent"}, homeDir, time.Now(), false, out)
+	}()
+
+	waitFor := func(want string) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for !strings.Contains(out.String(), want) {
+			if time.Now().After(deadline) {
+				t.Fatalf("output %q never contained %q", out.String(), want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	waitFor("[subagent] Event config")
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A partial line is held back until it is complete
+	f.WriteString("[2025-03-10 14:06:00] [3] [DEBUG] [stop] ignored\n[2025-03-10 14:06:00] [3] [WARN] [subagent] appended")
+	time.Sleep(50 * time.Millisecond)
+	f.WriteString(" line\n")
+	f.Close()
+	waitFor("[subagent] appended line\n")
+
+	// Rotation starts over at the beginning of the new file
+	if err := os.Rename(path, path+".0"); err != nil {
+		t.Fatal(err)
+	}
+	writeTestLog(t, homeDir, "[2025-03-10 14:07:00] [4] [DEBUG] [subagent] after rotation\n")
+	waitFor("after rotation")
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("runLogs() error = %v", err)
+	}
+	if strings.Contains(out.String(), "ignored") {
+		t.Errorf("follow output not filtered: %q", out.String())
+	}
+}
@@ -1,43 +1,21 @@
 // ccbell - Sound notification hook for Claude Code
 //
-// Usage: ccbell <event_type>
+// Usage: ccbell <event_type> | ccbell <command> [args]
 // Event types: stop, permission_prompt, idle_prompt, subagent
+// Commands: see commands.go
 package main
 
 import (
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
+	"runtime/debug"
 	"strings"
+	"time"
 
-	"github.com/mpolatcan/ccbell/internal/audio"
 	"github.com/mpolatcan/ccbell/internal/config"
-	"github.com/mpolatcan/ccbell/internal/logger"
-	"github.com/mpolatcan/ccbell/internal/state"
 )
 
-func derefBool(ptr *bool, defaultVal bool) bool {
-	if ptr == nil {
-		return defaultVal
-	}
-	return *ptr
-}
-
-func derefFloat(ptr *float64, defaultVal float64) float64 {
-	if ptr == nil {
-		return defaultVal
-	}
-	return *ptr
-}
-
-func derefInt(ptr *int, defaultVal int) int {
-	if ptr == nil {
-		return defaultVal
-	}
-	return *ptr
-}
-
 // Build-time variables (set via -ldflags).
 var (
 	version   = "dev"
@@ -98,162 +76,115 @@ func findPluginRoot(homeDir string) string {
 	return ccbellPath
 }
 
+// resolvePluginRoot returns $CLAUDE_PLUGIN_ROOT or the installed plugin
+// directory.
+func resolvePluginRoot(homeDir string) string {
+	if pluginRoot := os.Getenv("CLAUDE_PLUGIN_ROOT"); pluginRoot != "" {
+		return pluginRoot
+	}
+	return findPluginRoot(homeDir)
+}
+
+// resolveConfigFile returns the config file to use: --config, then
+// $CCBELL_CONFIG, then the default path.
+func resolveConfigFile(opts *cliOptions) string {
+	if opts.configPath != "" {
+		return opts.configPath
+	}
+	return config.Path(os.Getenv("HOME"))
+}
+
+// cliOptions holds parsed command-line arguments. The global flags apply to
+// every command.
+type cliOptions struct {
+	command    string // Subcommand or event type
+	configPath string
+	strict     bool     // Fail on config problems instead of using defaults
+	args       []string // Positional arguments after the event type
+}
+
+// parseArgs parses command-line arguments. The first positional argument is
+// the subcommand or event type (defaults to "stop"); the global flags may
+// appear anywhere: --config <path> selects a config file; --strict turns
+// config problems into errors.
+func parseArgs(args []string) (*cliOptions, error) {
+	opts := &cliOptions{command: "stop"}
+	positional := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--config":
+			if i+1 >= len(args) {
+				return nil, cliLocale.Errorf("--config requires a path argument")
+			}
+			i++
+			opts.configPath = args[i]
+		case strings.HasPrefix(arg, "--config="):
+			opts.configPath = strings.TrimPrefix(arg, "--config=")
+		case arg == "--strict":
+			opts.strict = true
+		case !positional:
+			opts.command = arg
+			positional = true
+		default:
+			opts.args = append(opts.args, arg)
+		}
+	}
+	if opts.configPath != "" && !filepath.IsAbs(opts.configPath) {
+		abs, err := filepath.Abs(opts.configPath)
+		if err != nil {
+			return nil, cliLocale.Errorf("invalid config path: %w", err)
+		}
+		opts.configPath = abs
+	}
+	return opts, nil
+}
+
 func main() {
 	var exitCode int
 	defer func() {
 		if r := recover(); r != nil {
 			fmt.Fprintf(os.Stderr, "PANIC: %v\n", r)
+			if path, err := writeCrashReport(os.Getenv("HOME"), r, debug.Stack(), os.Args, time.Now()); err == nil {
+				fmt.Fprintf(os.Stderr, "ccbell: crash report written to %s\n", path)
+			}
 			exitCode = 2
 		}
 		os.Exit(exitCode)
 	}()
 
 	if err := run(); err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		fmt.Fprintln(os.Stderr, cliLocale.Sprintf("ERROR: %v", err))
 		exitCode = 1
 	}
 }
 
+// run dispatches to the subcommand named by the first argument, or runs
+// the notify command when it is an event type.
 func run() error {
-	// === Get event type from args ===
-	eventType := "stop"
-	if len(os.Args) > 1 {
-		eventType = os.Args[1]
-	}
-
-	// Handle special commands
-	if eventType == "--version" || eventType == "-v" {
-		fmt.Printf("ccbell %s (commit: %s, built: %s)\n", version, commit, buildDate)
-		return nil
-	}
-	if eventType == "--help" || eventType == "-h" {
-		printUsage()
-		return nil
-	}
-
-	// === Validate event type ===
-	if err := config.ValidateEventType(eventType); err != nil {
-		return err
-	}
-
-	// === Drain stdin (hooks may send data) ===
-	// Non-blocking read to prevent hanging. The stdin is drained in a separate
-	// goroutine since this is a short-lived process.
-	go func() {
-		_, _ = io.Copy(io.Discard, os.Stdin)
-	}()
-
-	// === Environment setup ===
-	homeDir := os.Getenv("HOME")
-	pluginRoot := os.Getenv("CLAUDE_PLUGIN_ROOT")
-	if pluginRoot == "" {
-		pluginRoot = findPluginRoot(homeDir)
-	}
-
-	// === Ensure config exists ===
-	if err := config.EnsureConfig(homeDir); err != nil {
-		fmt.Fprintf(os.Stderr, "ccbell: Warning: could not create config: %v\n", err)
-	}
-
-	// === Load configuration ===
-	cfg, configPath, configErr := config.Load(homeDir)
-	if configErr != nil {
-		// Config error shouldn't be fatal - use defaults
-		cfg = config.Default()
-		configPath = "(default - config load failed)"
-	}
-
-	// === Initialize logger ===
-	log := logger.New(cfg.Debug, homeDir)
-	log.Debug("=== ccbell triggered: event=%s ===", eventType)
-	log.Debug("Version: %s, Config: %s", version, configPath)
-
-	// Log config error if any (after logger is initialized)
-	if configErr != nil {
-		log.Debug("Config load error (using defaults): %v", configErr)
-		// Also warn to stderr so user knows their config is broken
-		fmt.Fprintf(os.Stderr, "ccbell: config error, using defaults: %v\n", configErr)
-	}
-	log.Debug("Plugin root: %s", pluginRoot)
-
-	// === Check global enable ===
-	if !cfg.Enabled {
-		log.Debug("Plugin disabled globally, exiting")
-		return nil
-	}
-
-	// === Get event configuration ===
-	eventCfg := cfg.GetEventConfig(eventType)
-	log.Debug("Active profile: %s", cfg.ActiveProfile)
-	log.Debug("Event config: enabled=%v, sound=%s, volume=%.2f, cooldown=%d",
-		derefBool(eventCfg.Enabled, true), eventCfg.Sound, derefFloat(eventCfg.Volume, 0.5), derefInt(eventCfg.Cooldown, 0))
-
-	// === Check event enable ===
-	if !derefBool(eventCfg.Enabled, true) {
-		log.Debug("Event '%s' is disabled, exiting", eventType)
-		return nil
-	}
-
-	// === Check quiet hours ===
-	if cfg.IsInQuietHours() {
-		log.Debug("In quiet hours (%s-%s), suppressing notification",
-			cfg.QuietHours.Start, cfg.QuietHours.End)
-		return nil
-	}
-
-	// === Check cooldown ===
-	stateManager := state.NewManager(homeDir)
-	inCooldown, err := stateManager.CheckCooldown(eventType, derefInt(eventCfg.Cooldown, 0))
+	setCLILanguage("")
+	opts, err := parseArgs(os.Args[1:])
 	if err != nil {
-		log.Debug("Cooldown check error: %v, proceeding with notification", err)
-	} else if inCooldown {
-		log.Debug("In cooldown period (%ds), suppressing notification", derefInt(eventCfg.Cooldown, 0))
-		return nil
-	}
-
-	log.Debug("All checks passed, proceeding to play sound")
-
-	// === Resolve sound path ===
-	player := audio.NewPlayer(pluginRoot)
-	log.Debug("Detected platform: %s", player.Platform())
-
-	// === Ensure audio player is available ===
-	if player.Platform() == audio.PlatformLinux {
-		audioPlayer, err := player.EnsureAudioPlayer()
-		if err != nil {
-			log.Debug("Audio player check failed: %v", err)
-			return fmt.Errorf("no audio player available: %w", err)
-		}
-		log.Debug("Using audio player: %s", audioPlayer)
-	}
-
-	soundPath, err := player.ResolveSoundPath(eventCfg.Sound, eventType)
-	if err != nil {
-		log.Debug("Sound resolution failed: %v, trying fallbacks", err)
-		soundPath = player.GetFallbackPath(eventType)
-		if soundPath == "" {
-			return fmt.Errorf("no playable sound found")
-		}
+		return err
 	}
-	log.Debug("Final sound path: %s", soundPath)
-
-	// === Play sound ===
-	if err := player.Play(soundPath, derefFloat(eventCfg.Volume, 0.5)); err != nil {
-		log.Debug("Sound playback failed: %v", err)
-		return fmt.Errorf("sound playback failed: %w", err)
+	setCLILanguage(resolveConfigFile(opts))
+	if cmd, ok := commands[opts.command]; ok {
+		return cmd(opts)
 	}
-
-	log.Debug("Sound playback initiated successfully")
-	log.Debug("=== ccbell completed ===")
-
-	return nil
+	return runNotify(opts, nil)
 }
 
-func printUsage() {
-	fmt.Println(`ccbell - Sound notifications for Claude Code
+// usageText is the help text. "ccbell docs" builds the manual page and the
+// CLI reference from it, so the three stay in step.
+const usageText = `ccbell - Sound notifications for Claude Code
 
 USAGE:
-    ccbell <event_type>
+    ccbell <event_type> [--config <path>]
+    ccbell <event_type> <event_type>...  Several events at once, e.g. from a
+                          wrapper that coalesces them (subagent subagent stop)
+    ccbell <event_type> --count N        One event standing for N occurrences
+    ccbell notify <event_type> ...       Same as ccbell <event_type> ...
+    ccbell <command> [args] [OPTIONS]
     ccbell [OPTIONS]
 
 EVENT TYPES:
@@ -262,22 +193,352 @@ EVENT TYPES:
     idle_prompt       Claude is waiting for input
     subagent          A background agent completed
 
+COMMANDS:
+    notify <event_type>   Run an event through the notification pipeline
+    secret set <name>     Store a secret in the OS keychain (value read from stdin)
+    secret get <name>     Print a stored secret
+    secret delete <name>  Remove a stored secret
+    replay [--last|--id N]  Re-run the pipeline for a journaled event
+    record <event> [--seconds N]  Record a sound from the microphone (sox or
+                          ffmpeg), trim silence, and use it for the event
+    sounds search <query>           Search Freesound for short sounds
+    sounds preview <id>             Play a Freesound result
+    sounds use <id> --event <event> Download a result and use it for the event
+                          (needs "freesound": {"token": "secret:freesound"})
+    logs [--follow] [--event <type>]  Print the debug log (~/.claude/ccbell.log),
+                          optionally following new lines as hooks run
+    logs --since 1h --event <type> --errors-only
+                          Print matching entries, including rotated logs
+    pause --until HH:MM   Pause notifications until a time (resumes automatically)
+    pause --for <dur>     Pause notifications for a duration, e.g. 45m or 2h
+    resume                End a pause early
+    ack                   Mark the last notification as seen, so it isn't escalated
+    status                Show whether notifications are enabled, paused or quiet
+    stats [--since 7d] [--event <type>]
+                          Per-day notification counts (needs "history")
+    state gc              Remove stale entries from the cooldown state file
+    state show [event]    Print last trigger times, remaining cooldowns, the
+                          pause, held subagents, queued deliveries, open circuits
+                          and the player ranking
+    state clear [event]   Reset one event's cooldown, or all state without an event
+    ui                    Terminal dashboard: status, per-event toggles and volume
+                          sliders (saved to the config), recent notifications
+    daemon [--listen <host:port>]  Serve a local HTTP API for menu-bar and tray
+                          apps and scripts (default 127.0.0.1:7337):
+                          GET /v1/status, POST /v1/mute, GET /v1/events, and
+                          POST /v1/notify {"event": "stop", "payload": {...}}
+                          to trigger a notification. The address and the
+                          token POST requests need are in ~/.claude/ccbell-daemon.json.
+                          Also reads event names from ~/.claude/ccbell.fifo:
+                          echo stop > ~/.claude/ccbell.fifo
+    listen                Read newline-delimited JSON events from stdin until EOF,
+                          one per line: {"event": "stop", "payload": {...}}
+    config validate [--strict]  Check the config, warning about unknown
+                          (misspelled) keys
+    config push [--sounds]  Commit the config (and custom sounds from
+                          ~/.claude/ccbell/sounds) to the "sync" git repo or gist
+    config pull [--sounds]  Replace the config (and sounds) with the synced copy,
+                          keeping the previous config as <config>.bak
+    profile list          List profiles, including built-in silent and minimal
+    profile use <name>    Switch the active profile
+    theme list            List sound themes (* marks the active one)
+    theme use <name>      Switch the active sound theme
+    pack list             List the sound packs in ~/.claude/ccbell/packs
+    pack uninstall <id> [--fix]  Remove a pack and warn about config still
+                          using it; --fix resets those settings to the
+                          default sounds
+    diagnose [--output <file>]  Bundle the config (secrets redacted), logs
+                          (URLs and tokens scrubbed), state, platform info and
+                          available players into a tar.gz for bug reports,
+                          with any crash reports (~/.claude/ccbell-crash-<time>.log)
+    doctor                Check the config and audio player; a missing Linux
+                          player is shown with the detected package manager
+                          and the install command, which isn't run
+    audio check           Play a test tone with each installed audio player and
+                          report its startup latency, suggesting a "players" order
+                          (and updating the cached Linux player ranking)
+    docs man|markdown     Print the manual page (roff) or a Markdown CLI and
+                          config reference, generated from this help and the
+                          config schema
+
 OPTIONS:
-    -h, --help        Show this help message
-    -v, --version     Show version information
+    Accepted by every command, anywhere on the command line.
+    -h, --help        Show this help message (also: ccbell help)
+    -v, --version     Show version information (also: ccbell version)
+    --config <path>   Use an alternate config file
+    --strict          Fail on unknown config keys, missing sound files and
+                      unreachable profiles instead of warning or using defaults
+                      (same as "strict": true in the config)
 
 CONFIGURATION:
+    System config:  /etc/ccbell/config.json (base layer)
     Global config:  ~/.claude/ccbell.config.json
+    Override:       --config <path> or $CCBELL_CONFIG
+    Profiles:       ~/.claude/ccbell/profiles/<name>.json
 
 SOUND FORMATS:
     bundled:stop         Bundled with plugin
     bundled:permission_prompt
     bundled:idle_prompt
     bundled:subagent
+    bundled:<name>       Also looked up in "soundPaths" directories
+    system:Glass         OS sound (macOS /System/Library/Sounds, Linux freedesktop)
     custom:/path/to.mp3  Custom audio file
+    pack:<id>:<event>    Sound from a pack in ~/.claude/ccbell/packs/<id>
+    pack:<id>            The pack's sound for whichever event fires, so
+                         every event can use the same spec
+    url:https://host/ding.mp3  Downloaded once and cached in
+                         ~/.claude/ccbell/cache/downloads; https only, size
+                         capped by soundLimits; append #sha256=<hex> to pin
+    "soundPermissions": "not-world-writable" refuses custom and pack sound
+                         files anyone can write; "owner" also requires them
+                         to belong to you or root (default: "off")
+    "allowedSoundDirs": ["${HOME}/.claude/ccbell"] only plays custom and pack
+                         sounds inside these directories (symlinks resolved)
+    "allowSymlinks": "deny" refuses symlinked sound files; "allow" follows
+                         them anywhere. The default, "resolve-within-root",
+                         keeps them inside the sounds directory, search path
+                         or allowedSoundDirs entry holding the sound; other
+                         custom sound symlinks are followed
+
+SECRETS:
+    Use "secret:<name>" in config values to reference a keychain secret.
+
+DEDUPLICATION:
+    "dedupeWindow": 30    per event; suppress identical hook payloads re-fired
+                          within 30s (separate from cooldown)
+
+FRESH START:
+    "freshStartSeconds": 1800  the first event after 30 minutes without any
+                          skips its cooldown and plays at full volume (still
+                          capped by maxVolume), also after sleep or a clock
+                          jump left stale times in the state file
+
+SUBAGENT BATCHING:
+    "subagentBatch": {"enabled": true, "quietPeriod": 5}
+    Summarize subagent completions ("4 subagents finished") once none have
+    arrived for quietPeriod seconds, instead of one chime each.
+
+WAIT FOR SUBAGENTS:
+    "waitForSubagents": {"enabled": true, "sound": "system:Glass"}
+    Hold subagent notifications until the session's stop event, which then
+    plays a distinct "everything done" sound.
+
+JOURNAL:
+    "journal": {"enabled": true, "maxSizeKB": 1024}
+    Append each hook payload (sensitive values redacted) to
+    ~/.claude/ccbell.events.jsonl, rotating when it grows past maxSizeKB.
+
+HISTORY:
+    "history": {"enabled": true, "retentionDays": 90}
+    Record each notification in ~/.claude/ccbell/history.db for
+    "ccbell stats". Records older than retentionDays are pruned.
+
+CHANNELS:
+    "channels": ["sound", "desktop", "terminal", "uservar", "webhook", "bark",
+                 "led", "text"]
+                      per event, default ["sound"]
+    Channels are notified concurrently; "webhook" needs "webhook": {"url": ...}
+    "webhook": {"url": ..., "preset": "ifttt"}  send IFTTT Webhooks value1-3
+    (title, message, event) or "zapier" flat fields for a Zapier catch hook
+    "webhook": {"url": ..., "template": "{\"text\": {{json .Message}}}"}  sends
+    exactly the JSON you write, as a Go template with .Event, .Title,
+    .Message, .Project, .Branch, .Hostname, .Timestamp and the hook payload's
+    fields (.Payload.session_id); {{json ...}} quotes values for JSON
+    "desktop": {"focusApp": "auto"}   clicking a desktop notification focuses
+    the terminal ("auto") or the named app (terminal-notifier on macOS,
+    wmctrl or xdotool on Linux)
+    "desktop": {"group": 300}  repeats of an event within 300 seconds replace
+    its desktop notification ("3 subagents finished") instead of stacking
+    (notify-send 0.7.10+ on Linux; per-event terminal-notifier groups on macOS)
+    "desktop": {"bypassDnd": false}  keep critical events from breaking through
+    do-not-disturb (by default they use urgency=critical on Linux and
+    terminal-notifier's -ignoreDnD on macOS, when it is installed)
+    "terminal": {"sequence": "osc9"}  "terminal" writes an OSC 9 (iTerm2, kitty,
+    WezTerm) or "osc777" (foot, Ghostty, VTE) notification to the terminal,
+    which also works over SSH
+    "terminal": {"userVar": "ccbell_event"}  "uservar" sets a terminal user
+    variable to the event type (OSC 1337 for WezTerm/iTerm2, kitten @ for kitty)
+    "bark": {"deviceKey": "secret:bark"}  "bark" pushes to the Bark iOS app
+    (optional "server", default https://api.day.app; "body" is a template
+    for the push text, like webhook's)
+    "led": {"colors": {"stop": "#00ff00"}, "blinks": 3}  "led" blinks a
+    blink(1) USB LED via blink1-tool for silent feedback
+    "text": {"speak": true}  "text" writes a plain status line to the terminal
+    for screen readers, optionally spoken with say (macOS) or spd-say (Linux)
+    Titles name the project directory and git branch of the hook's working
+    directory ("Claude Code · api (main)"); webhooks also get "project" and
+    "branch" fields. Set your own with
+    "notificationTitle": "{project}@{branch}: {event}"
+    Webhook payloads and Bark subtitles include the machine's hostname
+    ({hostname} in titles); "sendHostname": false leaves it out.
+    Failed "webhook" and "bark" deliveries are retried with exponential
+    backoff; "retry": {"attempts": 3, "budget": 10} sets the total attempts
+    and the seconds allowed for them ("attempts": 1 disables retries).
+    Deliveries that still fail are queued and sent by the next notification
+    (which waits at most 2 seconds for them) or a running "ccbell daemon"
+    within a day; "retry": {"queue": false} drops them instead.
+    After 3 failed deliveries in a row a remote channel is skipped for 5
+    minutes (deliveries are queued meanwhile); change it with
+    "circuitBreaker": {"failures": 3, "cooldown": 300} ("failures": 0 disables).
+
+TEXT FORMAT:
+    "format": {"icon": "🚨", "severity": "critical", "body": "{icon} {message}"}
+    per event; "body" renders the text of desktop, terminal, text and push
+    notifications with {message}, {icon}, {severity}, {project}, {branch},
+    {hostname} and {event} ({icon} works in notificationTitle too).
+    Built-in icons: stop ✅, permission_prompt 🔐, idle_prompt ⏳, subagent 🤖,
+    others 🔔. Severity is "info" (default), "warning" (permission_prompt's
+    default; yellow text line) or "critical" (red text line, urgent Linux
+    desktop notification). Webhook templates get .Icon and .Severity.
+
+DESKTOP PERSISTENCE:
+    "desktop": {"timeout": 0, "urgency": "critical"} per event sets how long
+    its Linux desktop notification stays up (seconds; 0 until dismissed) and
+    its urgency ("low", "normal" or "critical"; default from the severity),
+    e.g. sticky permission prompts and stop notifications that expire.
+
+VOICE PACKS:
+    "language": "tr"      Language for voice packs (default: from $LANG);
+                          falls back to the pack's first listed language.
+                          Also the language of built-in notification text,
+                          CLI help and error messages (English, Turkish "tr")
+
+SOUND FALLBACK:
+    When a sound can't be played (no player, missing file), a desktop
+    notification is shown instead. "soundFallback": "bell" rings the terminal
+    bell; "none" only reports the error.
+    On Linux a missing audio player is reported with the command that installs
+    one. With "autoInstallPlayer": true, ccbell run from a terminal asks
+    before installing it; hooks never run sudo.
+
+PLAYERS:
+    "players": ["paplay", "mpv"]
+    Linux and BSD audio players to try first, in order; the rest follow in
+    the default order (Linux: mpv, paplay, aplay, ffplay; BSD: mpv, ffplay,
+    aucat). ccbell audio check measures which starts fastest.
+    Without "players", the first Linux notification starts a background
+    process that times each installed player playing silence, and later ones
+    try the fastest first; the ranking is kept in the state file for a week,
+    and ccbell audio check refreshes it.
+
+MAX VOLUME:
+    "maxVolume": 0.6 caps every sound after profiles, rules, packs and gain
+    are applied, so no setting can play louder.
+
+GAIN:
+    "gain": -6            per event; loudness adjustment in dB (-40 to +20)
+                          applied on top of volume, to even out sound files
+
+VARIATION:
+    "variation": {"mode": "pitch", "amount": 0.05}
+    per event; randomize each playback by up to ±5% ("pitch" needs mpv,
+    "tempo" works with mpv, ffplay and afplay)
+    "perSession": true picks one of 5 fixed steps from the hook's session ID
+    instead, so parallel sessions sound different but each stays the same.
+
+AFPLAY OPTIONS:
+    "afplay": {"rate": 1.5, "quality": 1} per event speeds up (or slows down,
+    0.25-4) playback on macOS, keeping pitch, without editing the file;
+    "quality" 1 is afplay's higher-quality rate scaling (-q 1).
+
+SESSION SOUNDS:
+    "sessionSounds": ["system:Glass", "system:Hero", "system:Pop"]
+    per event; each Claude session plays the variant picked by its session
+    ID instead of "sound", to tell parallel sessions apart by ear.
+
+RESPONSE LENGTH:
+    "responseLength": {"shortSeconds": 10, "longSeconds": 120,
+                       "short": {"sound": "system:Tink", "volume": 0.3},
+                       "long": {"sound": "system:Hero", "volume": 0.8}}
+    per event; when the hook payload reports the response's duration (or,
+    with shortTokens/longTokens, its output tokens), short and long responses
+    use the overrides.
+
+PROFILE SCHEDULE:
+    "activeProfile": {"weekday": "work", "weekend": "home"}
+    picks the profile by day when the config is loaded; a missing day type
+    uses the default profile.
+
+HOLIDAYS:
+    "holidays": {"dates": ["2025-12-25"], "ics": "${HOME}/holidays.ics"}
+    treats the listed days and the calendar's events like weekends for
+    profile schedules and weekend quiet hours. Point "ics" at your
+    country's public holiday calendar. Weekend quiet hours:
+    "quietHours": {"start": "22:00", "end": "07:00",
+                   "weekend": {"start": "23:00", "end": "10:00"}}
+
+SUNSET QUIET HOURS:
+    "quietHours": {"start": "sunset+30m", "end": "sunrise"}
+    follows daylight: start and end take "HH:MM", "sunrise" or "sunset",
+    optionally with an offset like "sunrise-1h". The location defaults to
+    your time zone's main city; set "latitude" and "longitude" (degrees,
+    east positive) to be exact. Quiet hours don't apply on days without a
+    sunrise or sunset.
+
+BRANCH RULES:
+    "branches": [{"branch": "release/*", "events": {"permission_prompt": {"volume": 1.0}}},
+                 {"worktree": "/home/me/src/app-*", "events": {"stop": {"enabled": false}}}]
+    overrides events when the hook's working directory is a git checkout on
+    a matching branch or worktree path (glob patterns). Matching rules apply
+    in order after the active profile.
+
+THEMES:
+    "theme": "system" replaces each event's default bundled sound with the
+    theme's sound. Built-in themes: bundled, system. Define your own with
+    "themes": {"retro": {"stop": "pack:retro:stop", "subagent": "system:Pop"}}
+    Events set to any other sound keep it.
+    "activePack": "retro" plays the pack's sounds instead of the default
+    bundled ones, ahead of the theme. Events the pack has no sound for keep
+    the bundled sound.
+
+CONFIG SYNC:
+    "sync": {"repo": "git@github.com:me/ccbell-config.git"} or
+    "sync": {"gist": "<gist id>"} sets where "config push" and "config pull"
+    keep the config (--repo and --gist override it). Custom sound paths under
+    your home directory are written as ${HOME}, so they work on every machine.
+
+ACCESSIBILITY:
+    When the OS asks apps to reduce sound (macOS "Play user interface sound
+    effects" off, GNOME event sounds off), sound notifications become desktop
+    notifications. "accessibility": {"mode": "quiet", "volume": 0.2} plays
+    them quietly instead; {"followSystem": false} ignores the OS setting.
+
+SCREEN LOCK:
+    "screenLock": {} sends notifications only to an event's remote channels
+    (webhook, bark) while the screen is locked, instead of playing sounds
+    nobody hears. "screenLock": {"channels": ["bark"]} picks the channels.
+
+ACTIVE CALLS:
+    "activeCall": {} shows a desktop notification instead of playing a sound
+    while the microphone or camera is in use. "activeCall": {"mode": "mute"}
+    drops the sound and keeps the event's other channels.
+
+ESCALATION:
+    "escalate": {"after": 300, "channels": ["bark"]} on an event re-sends it
+    once if it isn't acknowledged within 300 seconds, with "ccbell ack" or
+    by the next event. Channels default to the configured webhook and bark.
+    Escalations are sent by "ccbell daemon".
+
+PER-PLATFORM SOUNDS:
+    "sound": {"macos": "system:Glass", "linux": "bundled:stop", "default": "bundled:stop"}
+    Platform keys: macos, linux, bsd (FreeBSD, OpenBSD, NetBSD).
+
+VARIABLES:
+    ${VAR} expands to the environment value in sound specs (including
+    platform, session and responseLength sounds and waitForSubagents.sound),
+    soundPaths, allowedSoundDirs, branches[].worktree, webhook.url,
+    webhook.headers, bark.server, bark.deviceKey, freesound.token and
+    holidays.ics. Write $${VAR} for a literal ${VAR}.
 
 ENVIRONMENT:
     CLAUDE_PLUGIN_ROOT   Plugin installation directory
+    CCBELL_CONFIG        Alternate config file path
+    CCBELL_SYSTEM_CONFIG Alternate system-wide base config path
+    CCBELL_AUDIO_BACKEND Set to "mock" to print player commands instead of playing
 
-For more information, visit: https://github.com/mpolatcan/ccbell`)
+For more information, visit: https://github.com/mpolatcan/ccbell`
+
+func printUsage() {
+	fmt.Println(cliLocale.Sprintf(usageText))
 }
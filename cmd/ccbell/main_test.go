@@ -5,8 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/logger"
 )
 
 // testConfigDisabledPlugin is the JSON config content used in tests.
@@ -884,39 +888,6 @@ func TestRunWithZeroCooldown(t *testing.T) {
 	}
 }
 
-func TestDerefFunctions(t *testing.T) {
-	// Test derefBool
-	trueVal := true
-	falseVal := false
-	if derefBool(nil, true) != true {
-		t.Error("derefBool with nil should return default")
-	}
-	if derefBool(&trueVal, false) != true {
-		t.Error("derefBool with true pointer should return true")
-	}
-	if derefBool(&falseVal, true) != false {
-		t.Error("derefBool with false pointer should return false")
-	}
-
-	// Test derefFloat
-	fval := 0.75
-	if derefFloat(nil, 0.5) != 0.5 {
-		t.Error("derefFloat with nil should return default")
-	}
-	if derefFloat(&fval, 0.1) != 0.75 {
-		t.Error("derefFloat with pointer should return value")
-	}
-
-	// Test derefInt
-	ival := 42
-	if derefInt(nil, 10) != 10 {
-		t.Error("derefInt with nil should return default")
-	}
-	if derefInt(&ival, 0) != 42 {
-		t.Error("derefInt with pointer should return value")
-	}
-}
-
 func TestFindPluginRoot(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -1170,3 +1141,167 @@ func TestRunWithInvalidCooldown(t *testing.T) {
 		t.Errorf("run() with valid config should not error, got: %v", err)
 	}
 }
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantEvent  string
+		wantConfig string
+		wantErr    bool
+	}{
+		{"no args", nil, "stop", "", false},
+		{"event only", []string{"subagent"}, "subagent", "", false},
+		{"config flag after event", []string{"stop", "--config", "/tmp/a.json"}, "stop", "/tmp/a.json", false},
+		{"config flag before event", []string{"--config", "/tmp/a.json", "idle_prompt"}, "idle_prompt", "/tmp/a.json", false},
+		{"config equals form", []string{"--config=/tmp/b.json", "stop"}, "stop", "/tmp/b.json", false},
+		{"config missing value", []string{"stop", "--config"}, "", "", true},
+		{"strict flag", []string{"--strict", "stop"}, "stop", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := parseArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseArgs(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if opts.command != tt.wantEvent {
+				t.Errorf("eventType = %q, want %q", opts.command, tt.wantEvent)
+			}
+			if opts.configPath != tt.wantConfig {
+				t.Errorf("configPath = %q, want %q", opts.configPath, tt.wantConfig)
+			}
+			if wantStrict := slices.Contains(tt.args, "--strict"); opts.strict != wantStrict {
+				t.Errorf("strict = %v, want %v", opts.strict, wantStrict)
+			}
+		})
+	}
+}
+
+func TestRunWithConfigFlag(t *testing.T) {
+	// Save original args and env
+	oldArgs := os.Args
+	oldHome := os.Getenv("HOME")
+	oldPluginRoot := os.Getenv("CLAUDE_PLUGIN_ROOT")
+	defer func() {
+		os.Args = oldArgs
+		os.Setenv("HOME", oldHome)
+		if oldPluginRoot != "" {
+			os.Setenv("CLAUDE_PLUGIN_ROOT", oldPluginRoot)
+		} else {
+			os.Unsetenv("CLAUDE_PLUGIN_ROOT")
+		}
+	}()
+
+	// Create temp directory
+	tmpDir, err := os.MkdirTemp("", "ccbell-config-flag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Alternate config disables the plugin; the default location is left empty
+	altConfig := filepath.Join(tmpDir, "alt.config.json")
+	if err := os.WriteFile(altConfig, []byte(testConfigDisabledPlugin), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Set environment - no sounds, so only a disabled config avoids an error
+	os.Setenv("HOME", tmpDir)
+	os.Setenv("CLAUDE_PLUGIN_ROOT", tmpDir)
+
+	os.Args = []string{"ccbell", "stop", "--config", altConfig}
+	if err := run(); err != nil {
+		t.Errorf("run() with --config should use alternate config, got: %v", err)
+	}
+
+	// Default config must not have been created
+	defaultConfig := filepath.Join(tmpDir, ".claude", "ccbell.config.json")
+	if _, err := os.Stat(defaultConfig); !os.IsNotExist(err) {
+		t.Error("run() with --config should not create the default config")
+	}
+}
+
+func TestConfirmPrompt(t *testing.T) {
+	var out bytes.Buffer
+	confirm := confirmPrompt(strings.NewReader("y\nno\n YES \n"), &out)
+	for i, want := range []bool{true, false, true, false} {
+		if got := confirm("Install mpv via apt-get?"); got != want {
+			t.Errorf("answer %d = %v, want %v", i, got, want)
+		}
+	}
+	if !strings.HasPrefix(out.String(), "Install mpv via apt-get? [y/N] ") {
+		t.Errorf("prompt = %q", out.String())
+	}
+}
+
+func TestRunDisabledFastPath(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-main-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	configPath := filepath.Join(tmpDir, ".claude", "ccbell.config.json")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte(`{"enabled": false, "debug": true}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", tmpDir)
+	t.Setenv(config.SystemConfigEnvVar, filepath.Join(tmpDir, "missing.json"))
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	// A full run would log the event before finding notifications disabled
+	os.Args = []string{"ccbell", "--config", configPath, "stop"}
+	if err := run(); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if _, err := os.Stat(logger.Path(tmpDir)); !os.IsNotExist(err) {
+		t.Errorf("disabled run should exit before logging: %v", err)
+	}
+
+	os.Args = []string{"ccbell", "--config", configPath, "--strict", "stop"}
+	if err := run(); err != nil {
+		t.Fatalf("run() --strict error = %v", err)
+	}
+	if _, err := os.Stat(logger.Path(tmpDir)); err != nil {
+		t.Errorf("--strict should take the full path: %v", err)
+	}
+}
+
+func TestRunNotifyCommand(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-main-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	configPath := filepath.Join(tmpDir, "ccbell.config.json")
+	if err := os.WriteFile(configPath, []byte(testConfigDisabledPlugin), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", tmpDir)
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	tests := []struct {
+		args    []string
+		wantErr bool
+	}{
+		{[]string{"notify"}, true},
+		{[]string{"notify", "bogus"}, true},
+		{[]string{"notify", "stop"}, false},
+		{[]string{"--config", configPath, "notify", "subagent", "subagent", "stop"}, false},
+		{[]string{"notify", "stop", "--count", "x"}, true},
+	}
+	for _, tt := range tests {
+		os.Args = append([]string{"ccbell", "--config", configPath}, tt.args...)
+		if err := run(); (err != nil) != tt.wantErr {
+			t.Errorf("run(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+		}
+	}
+}
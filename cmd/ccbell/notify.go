@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/hook"
+	"github.com/mpolatcan/ccbell/internal/journal"
+	"github.com/mpolatcan/ccbell/pkg/ccbell"
+)
+
+// notifyUsage describes the notify command.
+const notifyUsage = "usage: ccbell notify <event_type> [<event_type>... | --count N]"
+
+// runNotifyCommand handles "ccbell notify <event_type> ...", the explicit
+// form of "ccbell <event_type> ...".
+func runNotifyCommand(opts *cliOptions) error {
+	if len(opts.args) == 0 {
+		return cliLocale.Errorf(notifyUsage)
+	}
+	event := *opts
+	event.command, event.args = opts.args[0], opts.args[1:]
+	return runNotify(&event, nil)
+}
+
+// runNotify runs the event in opts.command through the notification
+// pipeline, as the Claude Code hooks do. The remaining arguments batch more
+// events. A replayed entry supplies the payload instead of stdin.
+func runNotify(opts *cliOptions, replayEntry *journal.Entry) error {
+	eventType := opts.command
+
+	// === Validate event type ===
+	if err := config.ValidateEventType(eventType); err != nil {
+		return err
+	}
+
+	// === Parse batched events (subagent subagent stop, or --count N) ===
+	counts := []eventCount{{event: eventType, count: 1}}
+	var flushSeq int64
+	if replayEntry == nil && len(opts.args) > 0 {
+		var err error
+		if opts.args[0] == subagentFlushArg {
+			flushSeq, err = parseFlushArgs(opts.args[1:])
+		} else {
+			counts, err = parseEventCounts(eventType, opts.args)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// === Read hook payload from stdin ===
+	// Bounded by a timeout so an unclosed stdin can't hang the hook; the rest
+	// is drained in the background. Skipped when run from a terminal.
+	var payloadData []byte
+	var payloadErr error
+	if replayEntry != nil {
+		payloadData = replayEntry.Payload
+	} else if !isTerminal(os.Stdin) {
+		payloadData, payloadErr = hook.Read(os.Stdin, hook.DefaultReadTimeout)
+	}
+
+	// === Fast path when disabled ===
+	// Skips plugin root discovery, config creation and state entirely
+	if !opts.strict && config.Disabled(resolveConfigFile(opts)) {
+		return nil
+	}
+
+	// === Run the notification pipeline ===
+	p := newPipeline(opts)
+	p.payloadErr = payloadErr
+	if isTerminal(os.Stdin) && isTerminal(os.Stderr) {
+		p.prompt = confirmPrompt(os.Stdin, os.Stderr)
+	}
+	var errs []error
+	for _, c := range counts {
+		err := p.notify(context.Background(), ccbell.Request{
+			Event:      c.event,
+			Payload:    payloadData,
+			NoJournal:  replayEntry != nil,
+			Count:      c.count,
+			FlushBatch: flushSeq,
+		})
+		if errors.Is(err, config.ErrStrict) {
+			return err
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
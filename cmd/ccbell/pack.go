@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/pack"
+)
+
+// packUsage describes the pack subcommand.
+const packUsage = "usage: ccbell pack <list|uninstall <id> [--fix]>"
+
+// runPack handles "ccbell pack": listing installed packs and uninstalling
+// them. Uninstalling warns about config settings still using the pack, and
+// with --fix removes them so the events play their default sounds, even if
+// the pack was already removed.
+func runPack(args []string, configFile, homeDir string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return cliLocale.Errorf(packUsage)
+	}
+	packsDir := pack.Dir(homeDir)
+
+	switch {
+	case args[0] == "list" && len(args) == 1:
+		packs, err := pack.List(packsDir)
+		if err != nil {
+			return err
+		}
+		if len(packs) == 0 {
+			fmt.Fprintf(stdout, "No packs installed in %s\n", packsDir)
+		}
+		for _, p := range packs {
+			fmt.Fprintf(stdout, "%-16s %s", p.ID, p.Name)
+			if p.Version != "" {
+				fmt.Fprintf(stdout, " %s", p.Version)
+			}
+			fmt.Fprintln(stdout)
+		}
+		return nil
+	case args[0] == "uninstall" && (len(args) == 2 || len(args) == 3 && args[2] == "--fix"):
+		id, fix := args[1], len(args) == 3
+		// --fix still cleans up the config after the pack is gone
+		err := pack.Remove(packsDir, id)
+		switch {
+		case err == nil:
+			fmt.Fprintf(stdout, "Uninstalled pack %s\n", id)
+		case !fix || !errors.Is(err, pack.ErrNotFound):
+			return err
+		}
+
+		if fix {
+			refs, err := config.RemovePackReferences(configFile, id)
+			if err != nil {
+				return err
+			}
+			if len(refs) > 0 {
+				fmt.Fprintf(stdout, "Reset to default sounds: %s\n", strings.Join(refs, ", "))
+			}
+			return nil
+		}
+		refs, err := config.PackReferences(configFile, id)
+		if err != nil {
+			return err
+		}
+		if len(refs) > 0 {
+			fmt.Fprintf(stdout, "Warning: config still uses pack %s: %s\n", id, strings.Join(refs, ", "))
+			fmt.Fprintf(stdout, "Run \"ccbell pack uninstall %s --fix\" to reset them to the default sounds\n", id)
+		}
+		return nil
+	default:
+		return cliLocale.Errorf(packUsage)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/pack"
+)
+
+func TestRunPack(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "ccbell-pack-cmd-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(homeDir)
+
+	packDir := filepath.Join(pack.Dir(homeDir), "retro")
+	if err := os.MkdirAll(packDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := `{"name": "Retro", "version": "1.0", "sounds": {"stop": "stop.wav"}}`
+	if err := os.WriteFile(filepath.Join(packDir, pack.ManifestFile), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	configFile := filepath.Join(homeDir, "ccbell.config.json")
+	content := `{"activePack": "retro", "events": {"stop": {"sound": "pack:retro:stop", "volume": 0.8},
+		"subagent": {"sound": {"macos": "pack:retro", "linux": "bundled:subagent"}}}}`
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runPack([]string{"list"}, configFile, homeDir, &out); err != nil {
+		t.Fatalf("runPack(list) error = %v", err)
+	}
+	if want := "retro            Retro 1.0\n"; out.String() != want {
+		t.Errorf("list output = %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	if err := runPack([]string{"uninstall", "retro"}, configFile, homeDir, &out); err != nil {
+		t.Fatalf("runPack(uninstall) error = %v", err)
+	}
+	if _, err := os.Stat(packDir); !os.IsNotExist(err) {
+		t.Error("pack directory should be removed")
+	}
+	if want := "config still uses pack retro: activePack, events.stop.sound, events.subagent.sound.macos"; !strings.Contains(out.String(), want) {
+		t.Errorf("uninstall output = %q, want warning %q", out.String(), want)
+	}
+
+	out.Reset()
+	if err := runPack([]string{"uninstall", "retro", "--fix"}, configFile, homeDir, &out); err != nil {
+		t.Fatalf("runPack(uninstall --fix) error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Reset to default sounds: activePack, events.stop.sound") {
+		t.Errorf("uninstall --fix output = %q", out.String())
+	}
+	cfg, _, err := config.LoadFile(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stop := cfg.GetEventConfig("stop"); cfg.ActivePack != "" || stop.Sound != "bundled:stop" || *stop.Volume != 0.8 {
+		t.Errorf("after --fix: activePack %q, stop %+v", cfg.ActivePack, stop)
+	}
+	if sounds := cfg.Events["subagent"].PlatformSounds; sounds["macos"] != "" || sounds["linux"] != "bundled:subagent" {
+		t.Errorf("subagent sounds = %v, want only linux kept", sounds)
+	}
+
+	if err := runPack([]string{"uninstall", "retro"}, configFile, homeDir, &out); err == nil {
+		t.Error("uninstalling a missing pack should fail")
+	}
+	for _, args := range [][]string{nil, {"uninstall"}, {"uninstall", "retro", "--force"}} {
+		if err := runPack(args, configFile, homeDir, &out); err == nil {
+			t.Errorf("runPack(%v) should fail", args)
+		}
+	}
+}
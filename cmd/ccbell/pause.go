@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/state"
+)
+
+// pauseUsage describes the pause subcommand.
+const pauseUsage = "usage: ccbell pause --until HH:MM | --for <duration>"
+
+// pauseTimeFormat is how pause end times are shown.
+const pauseTimeFormat = "Mon 15:04"
+
+// runPause handles "ccbell pause": suppressing notifications until a time.
+func runPause(args []string, homeDir string, now time.Time, stdout io.Writer) error {
+	until, err := parsePauseArgs(args, now)
+	if err != nil {
+		return err
+	}
+	if err := state.NewManager(homeDir).Pause(until); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Notifications paused until %s\n", until.Format(pauseTimeFormat))
+	return nil
+}
+
+// parsePauseArgs returns the end of the pause. An --until time that has
+// already passed today refers to tomorrow.
+func parsePauseArgs(args []string, now time.Time) (time.Time, error) {
+	if len(args) != 2 {
+		return time.Time{}, cliLocale.Errorf(pauseUsage)
+	}
+	switch args[0] {
+	case "--until":
+		t, err := time.ParseInLocation("15:04", args[1], now.Location())
+		if err != nil {
+			return time.Time{}, cliLocale.Errorf("invalid time %q, expected HH:MM", args[1])
+		}
+		until := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !until.After(now) {
+			until = until.AddDate(0, 0, 1)
+		}
+		return until, nil
+	case "--for":
+		d, err := time.ParseDuration(args[1])
+		if err != nil || d <= 0 {
+			return time.Time{}, cliLocale.Errorf("invalid duration %q, expected e.g. 45m or 2h", args[1])
+		}
+		return now.Add(d), nil
+	default:
+		return time.Time{}, cliLocale.Errorf(pauseUsage)
+	}
+}
+
+// runResume handles "ccbell resume": ending a pause early.
+func runResume(homeDir string, stdout io.Writer) error {
+	if err := state.NewManager(homeDir).Resume(); err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, "Notifications resumed")
+	return nil
+}
+
+// runStatus handles "ccbell status": summarizing whether and how
+// notifications will be delivered right now.
+func runStatus(configFile, homeDir string, now time.Time, stdout io.Writer) error {
+	cfg, _, err := config.LoadFile(configFile)
+	if err != nil {
+		return err
+	}
+
+	enabled := "yes"
+	if !cfg.Enabled {
+		enabled = "no"
+	}
+	fmt.Fprintf(stdout, "Enabled:     %s\n", enabled)
+
+	profile := cfg.ActiveProfile
+	if profile == "" {
+		profile = "default"
+	}
+	fmt.Fprintf(stdout, "Profile:     %s\n", profile)
+	if cfg.Theme != "" {
+		fmt.Fprintf(stdout, "Theme:       %s\n", cfg.Theme)
+	}
+
+	if qh := cfg.QuietHoursFor(now); qh != nil && qh.Start != "" && qh.End != "" {
+		active := ""
+		if cfg.IsInQuietHours() {
+			active = " (active)"
+		}
+		fmt.Fprintf(stdout, "Quiet hours: %s-%s%s\n", qh.Start, qh.End, active)
+	}
+
+	until, err := state.NewManager(homeDir).PausedUntil()
+	if err != nil {
+		return err
+	}
+	if !until.IsZero() && now.Before(until) {
+		fmt.Fprintf(stdout, "Paused:      until %s\n", until.Format(pauseTimeFormat))
+	} else {
+		fmt.Fprintln(stdout, "Paused:      no")
+	}
+	return nil
+}
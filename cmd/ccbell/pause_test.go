@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePauseArgs(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		args    []string
+		want    time.Time
+		wantErr bool
+	}{
+		{"until later today", []string{"--until", "15:30"}, time.Date(2025, 3, 10, 15, 30, 0, 0, time.UTC), false},
+		{"until tomorrow", []string{"--until", "09:00"}, time.Date(2025, 3, 11, 9, 0, 0, 0, time.UTC), false},
+		{"until now", []string{"--until", "14:00"}, time.Date(2025, 3, 11, 14, 0, 0, 0, time.UTC), false},
+		{"for duration", []string{"--for", "45m"}, now.Add(45 * time.Minute), false},
+		{"bad time", []string{"--until", "25:00"}, time.Time{}, true},
+		{"bad duration", []string{"--for", "soon"}, time.Time{}, true},
+		{"negative duration", []string{"--for", "-5m"}, time.Time{}, true},
+		{"unknown flag", []string{"--at", "15:30"}, time.Time{}, true},
+		{"no args", nil, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePauseArgs(tt.args, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePauseArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parsePauseArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunPauseAndStatus(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "ccbell-pause-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(homeDir)
+
+	configFile := filepath.Join(homeDir, "ccbell.config.json")
+	if err := os.WriteFile(configFile, []byte(`{"enabled": true, "activeProfile": "minimal"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	var out bytes.Buffer
+	if err := runPause([]string{"--for", "45m"}, homeDir, now, &out); err != nil {
+		t.Fatalf("runPause() error = %v", err)
+	}
+
+	out.Reset()
+	if err := runStatus(configFile, homeDir, now, &out); err != nil {
+		t.Fatalf("runStatus() error = %v", err)
+	}
+	want := "Paused:      until " + now.Add(45*time.Minute).Format(pauseTimeFormat)
+	if !strings.Contains(out.String(), want) || !strings.Contains(out.String(), "Profile:     minimal") {
+		t.Errorf("status = %q, want %q", out.String(), want)
+	}
+
+	if err := runResume(homeDir, &out); err != nil {
+		t.Fatalf("runResume() error = %v", err)
+	}
+	out.Reset()
+	if err := runStatus(configFile, homeDir, now, &out); err != nil {
+		t.Fatalf("runStatus() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Paused:      no") {
+		t.Errorf("status after resume = %q", out.String())
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/logger"
+	"github.com/mpolatcan/ccbell/pkg/ccbell"
+)
+
+// pipeline runs events through the notification pipeline. The config is
+// loaded for every event, so long-running commands pick up edits.
+type pipeline struct {
+	configFile string
+	homeDir    string
+	pluginRoot string
+	strict     bool      // Fail on config problems instead of using defaults
+	stderr     io.Writer // User-facing warnings
+	payloadErr error     // Why the hook payload couldn't be read, for the log
+
+	// prompt asks the user a yes/no question; nil when not interactive, so
+	// hooks never wait for an answer.
+	prompt func(question string) bool
+}
+
+// newPipeline creates a pipeline for the config selected by opts.
+func newPipeline(opts *cliOptions) *pipeline {
+	homeDir := os.Getenv("HOME")
+	return &pipeline{
+		configFile: resolveConfigFile(opts),
+		homeDir:    homeDir,
+		pluginRoot: resolvePluginRoot(homeDir),
+		strict:     opts.strict,
+		stderr:     os.Stderr,
+	}
+}
+
+// notify loads the config and runs one event through the pipeline. A broken
+// config falls back to the defaults unless strict mode is on.
+func (p *pipeline) notify(ctx context.Context, req ccbell.Request) error {
+	// === Ensure config exists ===
+	if p.configFile != "" {
+		if err := config.EnsureConfigFile(p.configFile); err != nil {
+			fmt.Fprintf(p.stderr, "ccbell: Warning: could not create config: %v\n", err)
+		}
+	}
+
+	// === Load configuration ===
+	load := config.LoadFile
+	if p.strict {
+		load = config.LoadFileStrict
+	}
+	cfg, configPath, configErr := load(p.configFile)
+	if errors.Is(configErr, config.ErrStrict) {
+		return configErr
+	}
+	if configErr != nil {
+		// Config error shouldn't be fatal - use defaults
+		cfg = config.Default()
+		configPath = "(default - config load failed)"
+	}
+
+	// === Initialize logger ===
+	log := logger.New(cfg.Debug, p.homeDir)
+	log.SetEvent(req.Event)
+	log.Debug("=== ccbell triggered: event=%s ===", req.Event)
+	log.Debug("Version: %s, Config: %s", version, configPath)
+
+	// Log config error if any (after logger is initialized)
+	if configErr != nil {
+		log.Error("Config load error (using defaults): %v", configErr)
+		// Also warn to stderr so user knows their config is broken
+		fmt.Fprintf(p.stderr, "ccbell: config error, using defaults: %v\n", configErr)
+	}
+	for _, key := range cfg.UnknownKeys {
+		log.Warn("Unknown config key ignored: %s", key)
+	}
+	log.Debug("Plugin root: %s", p.pluginRoot)
+	if p.payloadErr != nil {
+		log.Debug("Hook payload unavailable: %v", p.payloadErr)
+	}
+
+	// === Run the notification pipeline ===
+	notifier := ccbell.New(cfg, ccbell.Options{
+		HomeDir:    p.homeDir,
+		PluginRoot: p.pluginRoot,
+		Logger:     log,
+		Warn:       p.stderr,
+		Prompt:     p.prompt,
+		SpawnFlush: func(seq int64) error {
+			return spawnSubagentFlush(p.configFile, seq)
+		},
+		SpawnRank: func() error {
+			return spawnPlayerRank(p.configFile)
+		},
+	})
+	if err := notifier.Notify(ctx, req); err != nil {
+		return err
+	}
+
+	log.Debug("=== ccbell completed ===")
+
+	return nil
+}
+
+// flushQueue loads the config and redelivers queued remote notifications.
+func (p *pipeline) flushQueue(ctx context.Context) error {
+	cfg, _, err := config.LoadFile(p.configFile)
+	if err != nil {
+		return err
+	}
+	log := logger.New(cfg.Debug, p.homeDir)
+	notifier := ccbell.New(cfg, ccbell.Options{
+		HomeDir:    p.homeDir,
+		PluginRoot: p.pluginRoot,
+		Logger:     log,
+		Warn:       p.stderr,
+	})
+	_, err = notifier.FlushQueue(ctx)
+	return err
+}
+
+// escalate loads the config and re-sends the last notification if its
+// escalation is due and it is still unacknowledged.
+func (p *pipeline) escalate(ctx context.Context) error {
+	cfg, _, err := config.LoadFile(p.configFile)
+	if err != nil {
+		return err
+	}
+	log := logger.New(cfg.Debug, p.homeDir)
+	notifier := ccbell.New(cfg, ccbell.Options{
+		HomeDir:    p.homeDir,
+		PluginRoot: p.pluginRoot,
+		Logger:     log,
+		Warn:       p.stderr,
+	})
+	_, err = notifier.Escalate(ctx)
+	return err
+}
+
+// confirmPrompt returns a function that asks a yes/no question on out and
+// reads the answer from in. Anything but "y" or "yes" is no.
+func confirmPrompt(in io.Reader, out io.Writer) func(question string) bool {
+	reader := bufio.NewReader(in)
+	return func(question string) bool {
+		fmt.Fprintf(out, "%s [y/N] ", question)
+		answer, _ := reader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return true
+		}
+		return false
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+)
+
+// profileUsage describes the profile subcommand.
+const profileUsage = "usage: ccbell profile <list|use <name>>"
+
+// runProfile handles "ccbell profile": listing profiles and switching the
+// active one.
+func runProfile(args []string, configFile string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return cliLocale.Errorf(profileUsage)
+	}
+
+	cfg, _, err := config.LoadFile(configFile)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "list":
+		active := cfg.ActiveProfile
+		if active == "" {
+			active = "default"
+		}
+		for _, name := range cfg.ProfileNames() {
+			marker := " "
+			if name == active {
+				marker = "*"
+			}
+			fmt.Fprintf(stdout, "%s %s\n", marker, name)
+		}
+		return nil
+	case "use":
+		if len(args) != 2 {
+			return cliLocale.Errorf(profileUsage)
+		}
+		name := args[1]
+		if _, ok := cfg.GetProfile(name); !ok && name != "default" {
+			return cliLocale.Errorf("unknown profile: %s", name)
+		}
+		if err := config.SetKey(configFile, "activeProfile", name); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Using profile %s\n", name)
+		return nil
+	default:
+		return cliLocale.Errorf(profileUsage)
+	}
+}
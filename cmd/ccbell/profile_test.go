@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+)
+
+func TestRunProfile(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-profile-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	configFile := filepath.Join(tempDir, "ccbell.config.json")
+	if err := os.WriteFile(configFile, []byte(`{"enabled": true, "profiles": {"work": {}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runProfile([]string{"list"}, configFile, &out); err != nil {
+		t.Fatalf("runProfile(list) error = %v", err)
+	}
+	if want := "* default\n  minimal\n  silent\n  work\n"; out.String() != want {
+		t.Errorf("list output = %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	if err := runProfile([]string{"use", "silent"}, configFile, &out); err != nil {
+		t.Fatalf("runProfile(use) error = %v", err)
+	}
+	cfg, _, err := config.LoadFile(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ActiveProfile != "silent" {
+		t.Errorf("activeProfile = %q, want silent", cfg.ActiveProfile)
+	}
+	if *cfg.GetEventConfig("permission_prompt").Enabled {
+		t.Error("silent profile should disable permission_prompt")
+	}
+
+	if err := runProfile([]string{"use", "nope"}, configFile, &out); err == nil || !strings.Contains(err.Error(), "unknown profile") {
+		t.Errorf("runProfile(use nope) error = %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+)
+
+// Recording length limits in seconds.
+const (
+	defaultRecordSeconds = 3
+	maxRecordSeconds     = 10
+)
+
+// lookPath is replaceable in tests.
+var lookPath = exec.LookPath
+
+// silenceTrim is the sox effect chain trimming leading and trailing silence.
+var silenceTrim = []string{"silence", "1", "0.1", "1%", "reverse", "silence", "1", "0.1", "1%", "reverse"}
+
+// ffmpegSilenceTrim is the ffmpeg equivalent of silenceTrim.
+const ffmpegSilenceTrim = "silenceremove=start_periods=1:start_threshold=-50dB,areverse," +
+	"silenceremove=start_periods=1:start_threshold=-50dB,areverse"
+
+// runRecord handles "ccbell record <event> [--seconds N]": it records a clip
+// from the microphone, trims silence, and assigns it as the event's sound.
+func runRecord(args []string, configFile, homeDir string, stdout io.Writer) error {
+	eventType, seconds, err := parseRecordArgs(args)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(homeDir, ".claude", "ccbell", "sounds")
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return cliLocale.Errorf("failed to create sounds directory: %w", err)
+	}
+	dest := filepath.Join(dir, eventType+".wav")
+	// Record to a temp file so a failed take keeps the previous sound
+	tmp := filepath.Join(dir, "."+eventType+".recording.wav")
+	defer os.Remove(tmp)
+
+	cmd, err := recordCommand(tmp, seconds)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Recording %ds for %s with %s... speak now\n", seconds, eventType, filepath.Base(cmd.Path))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return cliLocale.Errorf("recording failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	// A WAV header alone means only silence was captured
+	if info, err := os.Stat(tmp); err != nil || info.Size() <= 44 {
+		return cliLocale.Errorf("no sound captured; check your microphone and try again")
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		return cliLocale.Errorf("failed to save recording: %w", err)
+	}
+
+	spec := "custom:" + dest
+	if err := config.SetEventSound(configFile, eventType, spec); err != nil {
+		return cliLocale.Errorf("saved %s but could not update config: %w", dest, err)
+	}
+	fmt.Fprintf(stdout, "Saved %s and set it as the %s sound\n", dest, eventType)
+	return nil
+}
+
+// parseRecordArgs parses "<event> [--seconds N]".
+func parseRecordArgs(args []string) (string, int, error) {
+	usage := cliLocale.Errorf("usage: ccbell record <event> [--seconds N]")
+	var eventType string
+	seconds := defaultRecordSeconds
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--seconds" || strings.HasPrefix(arg, "--seconds="):
+			value, found := strings.CutPrefix(arg, "--seconds=")
+			if !found {
+				if i+1 >= len(args) {
+					return "", 0, usage
+				}
+				i++
+				value = args[i]
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > maxRecordSeconds {
+				return "", 0, cliLocale.Errorf("--seconds must be 1-%d", maxRecordSeconds)
+			}
+			seconds = n
+		case eventType == "":
+			eventType = arg
+		default:
+			return "", 0, usage
+		}
+	}
+	if eventType == "" {
+		return "", 0, usage
+	}
+	if err := config.ValidateEventType(eventType); err != nil {
+		return "", 0, err
+	}
+	return eventType, seconds, nil
+}
+
+// recordCommand builds a command recording seconds of mono audio from the
+// default input to dest, preferring sox over ffmpeg.
+func recordCommand(dest string, seconds int) (*exec.Cmd, error) {
+	duration := strconv.Itoa(seconds)
+	if path, err := lookPath("sox"); err == nil {
+		args := []string{"-q", "-d", "-c", "1", "-r", "44100", dest, "trim", "0", duration}
+		return execCommand(path, append(args, silenceTrim...)...), nil
+	}
+	if path, err := lookPath("ffmpeg"); err == nil {
+		input := []string{"-f", "pulse", "-i", "default"}
+		if runtime.GOOS == "darwin" {
+			input = []string{"-f", "avfoundation", "-i", ":0"}
+		}
+		args := append([]string{"-nostdin", "-y", "-loglevel", "error"}, input...)
+		args = append(args, "-t", duration, "-ac", "1", "-af", ffmpegSilenceTrim, dest)
+		return execCommand(path, args...), nil
+	}
+	return nil, cliLocale.Errorf("recording needs sox or ffmpeg; install one and try again")
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+)
+
+func TestParseRecordArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantEvent   string
+		wantSeconds int
+		wantErr     bool
+	}{
+		{name: "default seconds", args: []string{"stop"}, wantEvent: "stop", wantSeconds: 3},
+		{name: "seconds flag", args: []string{"stop", "--seconds", "5"}, wantEvent: "stop", wantSeconds: 5},
+		{name: "seconds equals", args: []string{"--seconds=2", "subagent"}, wantEvent: "subagent", wantSeconds: 2},
+		{name: "missing event", args: nil, wantErr: true},
+		{name: "unknown event", args: []string{"done"}, wantErr: true},
+		{name: "too long", args: []string{"stop", "--seconds", "60"}, wantErr: true},
+		{name: "missing seconds", args: []string{"stop", "--seconds"}, wantErr: true},
+		{name: "extra", args: []string{"stop", "idle_prompt"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, seconds, err := parseRecordArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRecordArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (event != tt.wantEvent || seconds != tt.wantSeconds) {
+				t.Errorf("parseRecordArgs() = %q, %d, want %q, %d", event, seconds, tt.wantEvent, tt.wantSeconds)
+			}
+		})
+	}
+}
+
+func TestRunRecord(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "ccbell-record-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(homeDir)
+	configFile := filepath.Join(homeDir, ".claude", "ccbell.config.json")
+
+	origLookPath, origExec := lookPath, execCommand
+	defer func() { lookPath, execCommand = origLookPath, origExec }()
+	lookPath = func(file string) (string, error) {
+		if file == "sox" {
+			return "/usr/bin/sox", nil
+		}
+		return "", exec.ErrNotFound
+	}
+	var got []string
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		got = append([]string{name}, args...)
+		cmd := exec.Command(os.Args[0], "-test.run=TestHelperProcess")
+		// Simulate sox writing the recording
+		cmd.Env = append(os.Environ(), "GO_WANT_HELPER_PROCESS=1", "GO_HELPER_OUTPUT="+args[6])
+		return cmd
+	}
+
+	var out bytes.Buffer
+	if err := runRecord([]string{"permission_prompt", "--seconds", "2"}, configFile, homeDir, &out); err != nil {
+		t.Fatalf("runRecord() error = %v", err)
+	}
+
+	if len(got) < 11 || got[0] != "/usr/bin/sox" || got[10] != "2" {
+		t.Errorf("sox args = %v", got)
+	}
+	dest := filepath.Join(homeDir, ".claude", "ccbell", "sounds", "permission_prompt.wav")
+	if _, err := os.Stat(dest); err != nil {
+		t.Errorf("recording not saved: %v", err)
+	}
+	cfg, _, err := config.LoadFile(configFile)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if got := cfg.Events["permission_prompt"].Sound; got != "custom:"+dest {
+		t.Errorf("sound = %q, want custom:%s", got, dest)
+	}
+}
+
+func TestRunRecordNoRecorder(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "ccbell-record-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(homeDir)
+
+	orig := lookPath
+	lookPath = func(string) (string, error) { return "", errors.New("not found") }
+	defer func() { lookPath = orig }()
+
+	if err := runRecord([]string{"stop"}, filepath.Join(homeDir, "c.json"), homeDir, &bytes.Buffer{}); err == nil {
+		t.Error("expected error without sox or ffmpeg")
+	}
+}
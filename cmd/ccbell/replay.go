@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/journal"
+)
+
+// runReplay handles "ccbell replay [--last|--id N]": running a journaled
+// event through the pipeline again with its original payload.
+func runReplay(opts *cliOptions) error {
+	entry, err := loadReplayEntry(opts.args, os.Getenv("HOME"))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Replaying event #%d (%s) from %s\n", entry.ID, entry.Event, entry.Time.Local().Format(time.DateTime))
+	replay := *opts
+	replay.command, replay.args = entry.Event, nil
+	return runNotify(&replay, entry)
+}
+
+// loadReplayEntry selects the journaled event for "ccbell replay
+// [--last|--id N]". With no arguments the most recent event is used.
+func loadReplayEntry(args []string, homeDir string) (*journal.Entry, error) {
+	j := journal.New(homeDir, 0)
+	if j.Path() == "" {
+		return nil, cliLocale.Errorf("replay: HOME is not set")
+	}
+
+	switch {
+	case len(args) == 0 || (len(args) == 1 && args[0] == "--last"):
+		return j.Last()
+	case len(args) == 2 && args[0] == "--id":
+		return findReplayID(j, args[1])
+	case len(args) == 1 && strings.HasPrefix(args[0], "--id="):
+		return findReplayID(j, strings.TrimPrefix(args[0], "--id="))
+	default:
+		return nil, cliLocale.Errorf("usage: ccbell replay [--last|--id N]")
+	}
+}
+
+// findReplayID looks up an entry by its ID argument.
+func findReplayID(j *journal.Journal, arg string) (*journal.Entry, error) {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || id <= 0 {
+		return nil, cliLocale.Errorf("replay: invalid id: %s", arg)
+	}
+	return j.Find(id)
+}
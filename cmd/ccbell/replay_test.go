@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/mpolatcan/ccbell/internal/journal"
+)
+
+func TestLoadReplayEntry(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-replay-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	j := journal.New(tmpDir, 0)
+	if _, err := loadReplayEntry(nil, tmpDir); !errors.Is(err, journal.ErrNotFound) {
+		t.Errorf("empty journal error = %v, want ErrNotFound", err)
+	}
+
+	for _, event := range []string{"stop", "permission_prompt", "idle_prompt"} {
+		if _, err := j.Append(event, []byte(`{"session_id":"s1"}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantEvent string
+		wantErr   bool
+	}{
+		{name: "default is last", args: nil, wantEvent: "idle_prompt"},
+		{name: "last", args: []string{"--last"}, wantEvent: "idle_prompt"},
+		{name: "id", args: []string{"--id", "2"}, wantEvent: "permission_prompt"},
+		{name: "id equals", args: []string{"--id=1"}, wantEvent: "stop"},
+		{name: "unknown id", args: []string{"--id", "9"}, wantErr: true},
+		{name: "invalid id", args: []string{"--id", "x"}, wantErr: true},
+		{name: "missing id", args: []string{"--id"}, wantErr: true},
+		{name: "unknown flag", args: []string{"--first"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := loadReplayEntry(tt.args, tmpDir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadReplayEntry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && entry.Event != tt.wantEvent {
+				t.Errorf("event = %q, want %q", entry.Event, tt.wantEvent)
+			}
+		})
+	}
+
+	if _, err := loadReplayEntry(nil, ""); err == nil {
+		t.Error("expected error without a home dir")
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mpolatcan/ccbell/internal/secret"
+)
+
+// runSecret handles the "ccbell secret <set|get|delete> <name>" subcommand.
+func runSecret(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) != 2 {
+		return cliLocale.Errorf("usage: ccbell secret <set|get|delete> <name>")
+	}
+	action, name := args[0], args[1]
+
+	switch action {
+	case "set":
+		if f, ok := stdin.(*os.File); ok && isTerminal(f) {
+			fmt.Fprintf(stdout, "Enter value for %s: ", name)
+		}
+		value, err := bufio.NewReader(stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return cliLocale.Errorf("failed to read secret value: %w", err)
+		}
+		value = strings.TrimRight(value, "\r\n")
+		if value == "" {
+			return cliLocale.Errorf("secret value cannot be empty")
+		}
+		if err := secret.Set(name, value); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Stored secret %q; reference it as %q\n", name, secret.RefPrefix+name)
+		return nil
+
+	case "get":
+		value, err := secret.Get(name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, value)
+		return nil
+
+	case "delete":
+		if err := secret.Delete(name); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Deleted secret %q\n", name)
+		return nil
+
+	default:
+		return cliLocale.Errorf("unknown secret action: %s (valid: set, get, delete)", action)
+	}
+}
+
+// isTerminal reports whether f is a character device (interactive terminal).
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunSecretUsage(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"no args", nil, "usage"},
+		{"missing name", []string{"set"}, "usage"},
+		{"unknown action", []string{"rotate", "token"}, "unknown secret action"},
+		{"invalid name", []string{"get", "bad name"}, "invalid secret name"},
+		{"empty value", []string{"set", "token"}, "cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := runSecret(tt.args, strings.NewReader(""), &out)
+			if err == nil {
+				t.Fatalf("runSecret(%v) expected error", tt.args)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("runSecret(%v) error = %q, want containing %q", tt.args, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mpolatcan/ccbell/internal/audio"
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/freesound"
+	"github.com/mpolatcan/ccbell/internal/secret"
+)
+
+// soundsSearchLimit is the number of search results shown.
+const soundsSearchLimit = 15
+
+// newFreesoundClient is replaceable in tests.
+var newFreesoundClient = freesound.NewClient
+
+// soundsUsage describes the sounds subcommand.
+const soundsUsage = "usage: ccbell sounds <search <query>|preview <id>|use <id> --event <event>>"
+
+// runSounds handles "ccbell sounds": searching Freesound, previewing results
+// and wiring one into the config as an event sound.
+func runSounds(ctx context.Context, args []string, configFile, homeDir, pluginRoot string, stdout io.Writer) error {
+	if len(args) < 2 {
+		return cliLocale.Errorf(soundsUsage)
+	}
+
+	cfg, _, err := config.LoadFile(configFile)
+	if err != nil {
+		return err
+	}
+	token := ""
+	if cfg.Freesound != nil {
+		if token, err = secret.Resolve(cfg.Freesound.Token); err != nil {
+			return cliLocale.Errorf("freesound token: %w", err)
+		}
+	}
+	client := newFreesoundClient(token)
+
+	switch args[0] {
+	case "search":
+		sounds, err := client.Search(ctx, strings.Join(args[1:], " "), soundsSearchLimit)
+		if err != nil {
+			return err
+		}
+		if len(sounds) == 0 {
+			fmt.Fprintln(stdout, "No sounds found")
+			return nil
+		}
+		for _, s := range sounds {
+			fmt.Fprintf(stdout, "%8d  %-40s %5.1fs  by %s\n", s.ID, s.Name, s.Duration, s.Username)
+		}
+		fmt.Fprintln(stdout, "\nPreview with: ccbell sounds preview <id>")
+		return nil
+
+	case "preview":
+		sound, err := fetchSound(ctx, client, args[1:])
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(homeDir, ".claude", "ccbell", "cache", "freesound", strconv.Itoa(sound.ID)+".mp3")
+		if err := client.DownloadPreview(ctx, sound, dest); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Playing %q by %s\n", sound.Name, sound.Username)
+		player := audio.NewPlayer(pluginRoot)
+		if cfg.MaxVolume != nil {
+			player.SetMaxVolume(*cfg.MaxVolume)
+		}
+		return player.Play(ctx, dest, 0.5)
+
+	case "use":
+		id, event, err := parseSoundsUseArgs(args[1:])
+		if err != nil {
+			return err
+		}
+		sound, err := client.Sound(ctx, id)
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(homeDir, ".claude", "ccbell", "sounds", fmt.Sprintf("freesound-%d.mp3", id))
+		if err := client.DownloadPreview(ctx, sound, dest); err != nil {
+			return err
+		}
+		if err := config.SetEventSound(configFile, event, "custom:"+dest); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Set %q by %s as the %s sound\nLicense: %s\n", sound.Name, sound.Username, event, sound.License)
+		return nil
+
+	default:
+		return cliLocale.Errorf("unknown sounds action: %s (valid: search, preview, use)", args[0])
+	}
+}
+
+// fetchSound looks up the sound whose ID is the only argument.
+func fetchSound(ctx context.Context, client *freesound.Client, args []string) (*freesound.Sound, error) {
+	if len(args) != 1 {
+		return nil, cliLocale.Errorf(soundsUsage)
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil || id <= 0 {
+		return nil, cliLocale.Errorf("invalid sound id: %s", args[0])
+	}
+	return client.Sound(ctx, id)
+}
+
+// parseSoundsUseArgs parses "<id> --event <event>".
+func parseSoundsUseArgs(args []string) (int, string, error) {
+	var idArg, event string
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--event" && i+1 < len(args):
+			i++
+			event = args[i]
+		case strings.HasPrefix(args[i], "--event="):
+			event = strings.TrimPrefix(args[i], "--event=")
+		case idArg == "":
+			idArg = args[i]
+		default:
+			return 0, "", cliLocale.Errorf(soundsUsage)
+		}
+	}
+	if idArg == "" || event == "" {
+		return 0, "", cliLocale.Errorf(soundsUsage)
+	}
+	id, err := strconv.Atoi(idArg)
+	if err != nil || id <= 0 {
+		return 0, "", cliLocale.Errorf("invalid sound id: %s", idArg)
+	}
+	if err := config.ValidateEventType(event); err != nil {
+		return 0, "", err
+	}
+	return id, event, nil
+}
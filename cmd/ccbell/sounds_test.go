@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/freesound"
+)
+
+func TestParseSoundsUseArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantID    int
+		wantEvent string
+		wantErr   bool
+	}{
+		{name: "valid", args: []string{"42", "--event", "stop"}, wantID: 42, wantEvent: "stop"},
+		{name: "equals", args: []string{"--event=subagent", "7"}, wantID: 7, wantEvent: "subagent"},
+		{name: "missing event", args: []string{"42"}, wantErr: true},
+		{name: "bad id", args: []string{"abc", "--event", "stop"}, wantErr: true},
+		{name: "unknown event", args: []string{"42", "--event", "done"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, event, err := parseSoundsUseArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSoundsUseArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (id != tt.wantID || event != tt.wantEvent) {
+				t.Errorf("parseSoundsUseArgs() = %d, %q", id, event)
+			}
+		})
+	}
+}
+
+func TestRunSounds(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "ccbell-sounds-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(homeDir)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sound := fmt.Sprintf(`{"id": 42, "name": "ding", "username": "bob", "license": "CC0", "duration": 1.5,
+			"previews": {"preview-hq-mp3": "%s/preview.mp3"}}`, server.URL)
+		switch r.URL.Path {
+		case "/search/text/":
+			fmt.Fprintf(w, `{"results": [%s]}`, sound)
+		case "/sounds/42/":
+			fmt.Fprint(w, sound)
+		case "/preview.mp3":
+			fmt.Fprint(w, "ID3 preview")
+		}
+	}))
+	defer server.Close()
+
+	orig := newFreesoundClient
+	newFreesoundClient = func(token string) *freesound.Client {
+		if token != "abc" {
+			t.Errorf("token = %q, want abc", token)
+		}
+		client := freesound.NewClient(token)
+		client.SetBaseURL(server.URL)
+		return client
+	}
+	defer func() { newFreesoundClient = orig }()
+
+	configFile := filepath.Join(homeDir, "ccbell.config.json")
+	if err := os.WriteFile(configFile, []byte(`{"enabled": true, "freesound": {"token": "abc"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runSounds(context.Background(), []string{"search", "bell"}, configFile, homeDir, "", &out); err != nil {
+		t.Fatalf("search error = %v", err)
+	}
+	if !strings.Contains(out.String(), "42") || !strings.Contains(out.String(), "ding") {
+		t.Errorf("search output = %q", out.String())
+	}
+
+	if err := runSounds(context.Background(), []string{"use", "42", "--event", "stop"}, configFile, homeDir, "", &out); err != nil {
+		t.Fatalf("use error = %v", err)
+	}
+	dest := filepath.Join(homeDir, ".claude", "ccbell", "sounds", "freesound-42.mp3")
+	if _, err := os.Stat(dest); err != nil {
+		t.Errorf("sound not downloaded: %v", err)
+	}
+	cfg, _, err := config.LoadFile(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Events["stop"].Sound; got != "custom:"+dest {
+		t.Errorf("stop sound = %q", got)
+	}
+
+	if err := runSounds(context.Background(), []string{"remix", "42"}, configFile, homeDir, "", &out); err == nil {
+		t.Error("expected error for unknown action")
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/state"
+)
+
+// stateUsage describes the state subcommand.
+const stateUsage = "usage: ccbell state gc | show [event] | clear [event]"
+
+// stateTimeFormat is how trigger times are shown.
+const stateTimeFormat = "Mon 15:04:05"
+
+// runState handles "ccbell state": inspecting and maintaining the cooldown
+// state file.
+func runState(args []string, configFile, homeDir string, now time.Time, stdout io.Writer) error {
+	if len(args) == 0 || len(args) > 2 {
+		return cliLocale.Errorf(stateUsage)
+	}
+	event := ""
+	if len(args) == 2 {
+		event = args[1]
+		if err := config.ValidateEventType(event); err != nil {
+			return err
+		}
+	}
+	m := state.NewManager(homeDir)
+
+	switch {
+	case args[0] == "gc" && len(args) == 1:
+		removed, err := m.GC()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Removed %d stale state entries\n", removed)
+		return nil
+	case args[0] == "show":
+		cfg, _, err := config.LoadFile(configFile)
+		if err != nil {
+			return err
+		}
+		st, err := m.Read()
+		if err != nil {
+			return err
+		}
+		showState(st, cfg, event, now, stdout)
+		return nil
+	case args[0] == "clear" && event == "":
+		if err := m.Clear(); err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, "Cleared all state")
+		return nil
+	case args[0] == "clear":
+		found, err := m.ClearEvent(event)
+		if err != nil {
+			return err
+		}
+		if found {
+			fmt.Fprintf(stdout, "Cleared %s state\n", event)
+		} else {
+			fmt.Fprintf(stdout, "No state for %s\n", event)
+		}
+		return nil
+	default:
+		return cliLocale.Errorf(stateUsage)
+	}
+}
+
+// showState prints trigger times and remaining cooldowns, and unless event
+// is set, the pause, held subagents, queued deliveries, open circuits, the
+// player ranking and the unacknowledged notification.
+func showState(st *state.State, cfg *config.Config, event string, now time.Time, stdout io.Writer) {
+	events := []string{event}
+	if event == "" {
+		events = slices.Sorted(maps.Keys(st.LastTrigger))
+	}
+	if len(events) == 0 {
+		fmt.Fprintln(stdout, "No events triggered")
+	}
+	for _, name := range events {
+		since, ok := st.SinceTrigger(name, now)
+		if !ok {
+			fmt.Fprintf(stdout, "%-18s never triggered\n", name)
+			continue
+		}
+		last := time.Unix(st.LastTrigger[name], 0)
+		var cooldown time.Duration
+		if e := cfg.GetEventConfig(name); e.Cooldown != nil {
+			cooldown = time.Duration(*e.Cooldown) * time.Second
+		}
+		var left string
+		switch {
+		case cooldown == 0:
+			left = "no cooldown"
+		case since < cooldown:
+			left = fmt.Sprintf("cooldown %s left", cooldown-since)
+		default:
+			left = "cooldown over"
+		}
+		fmt.Fprintf(stdout, "%-18s last %s, %s\n", name, last.Format(stateTimeFormat), left)
+	}
+
+	if u := st.Unacked; u != nil && (event == "" || u.Event == event) {
+		line := fmt.Sprintf("%s from %s ago", u.Event, now.Sub(time.Unix(u.Sent, 0)).Round(time.Second))
+		if u.EscalateAt != 0 {
+			line += ", escalates " + time.Unix(u.EscalateAt, 0).Format(stateTimeFormat)
+		}
+		fmt.Fprintf(stdout, "Unacknowledged:    %s\n", line)
+	}
+	if event != "" {
+		return
+	}
+
+	if st.PausedUntil != 0 && now.Unix() < st.PausedUntil {
+		fmt.Fprintf(stdout, "Paused:            until %s\n", time.Unix(st.PausedUntil, 0).Format(pauseTimeFormat))
+	}
+	held := 0
+	for _, session := range st.Sessions {
+		held += session.Subagents
+	}
+	if held > 0 {
+		fmt.Fprintf(stdout, "Held subagents:    %d in %d sessions\n", held, len(st.Sessions))
+	}
+	if len(st.Queue) > 0 {
+		fmt.Fprintf(stdout, "Queued deliveries: %d\n", len(st.Queue))
+	}
+	for _, endpoint := range slices.Sorted(maps.Keys(st.Circuits)) {
+		if until := st.Circuits[endpoint].OpenUntil; now.Unix() < until {
+			fmt.Fprintf(stdout, "Circuit open:      %s until %s\n", endpoint, time.Unix(until, 0).Format(stateTimeFormat))
+		}
+	}
+	if p := st.Players; p != nil && len(p.Order) > 0 {
+		fmt.Fprintf(stdout, "Player ranking:    %s (measured %s)\n", strings.Join(p.Order, ", "), time.Unix(p.Checked, 0).Format(stateTimeFormat))
+	}
+}
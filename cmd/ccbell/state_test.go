@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunState(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "ccbell-state-cmd-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(homeDir)
+
+	statePath := filepath.Join(homeDir, ".claude", "ccbell.state")
+	if err := os.MkdirAll(filepath.Dir(statePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().Unix()
+	content := fmt.Sprintf(`{"lastTrigger": {"stop": %d, "old_custom": 1000}, "fingerprints": {"abc": 1000}}`, now)
+	if err := os.WriteFile(statePath, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runState([]string{"gc"}, "", homeDir, time.Now(), &out); err != nil {
+		t.Fatalf("runState() error = %v", err)
+	}
+	if want := "Removed 2 stale state entries\n"; out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+
+	if err := runState(nil, "", homeDir, time.Now(), &out); err == nil {
+		t.Error("runState() without args should fail")
+	}
+}
+
+func TestRunStateShowClear(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "ccbell-state-cmd-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(homeDir)
+
+	configFile := filepath.Join(homeDir, "ccbell.config.json")
+	if err := os.WriteFile(configFile, []byte(`{"events": {"stop": {"cooldown": 300}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	statePath := filepath.Join(homeDir, ".claude", "ccbell.state")
+	if err := os.MkdirAll(filepath.Dir(statePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(time.Now().Unix(), 0)
+	content := fmt.Sprintf(`{"lastTrigger": {"stop": %d, "subagent": %d}, "pausedUntil": %d, "queue": [{"channel": "webhook"}],
+		"circuits": {"https://example.com": {"failures": 5, "openUntil": %d}}, "unacked": {"event": "stop", "sent": %d},
+		"players": {"order": ["paplay", "mpv"], "checked": %d}}`,
+		now.Unix()-60, now.Unix()-600, now.Unix()+3600, now.Unix()+120, now.Unix()-60, now.Unix()-60)
+	if err := os.WriteFile(statePath, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runState([]string{"show"}, configFile, homeDir, now, &out); err != nil {
+		t.Fatalf("runState(show) error = %v", err)
+	}
+	for _, want := range []string{
+		"stop               last " + now.Add(-time.Minute).Format(stateTimeFormat) + ", cooldown 4m0s left\n",
+		"subagent           last " + now.Add(-10*time.Minute).Format(stateTimeFormat) + ", no cooldown\n",
+		"Unacknowledged:    stop from 1m0s ago\n",
+		"Paused:            until ",
+		"Queued deliveries: 1\n",
+		"Circuit open:      https://example.com until ",
+		"Player ranking:    paplay, mpv (measured " + now.Add(-time.Minute).Format(stateTimeFormat) + ")\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("show output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := runState([]string{"show", "permission_prompt"}, configFile, homeDir, now, &out); err != nil {
+		t.Fatalf("runState(show permission_prompt) error = %v", err)
+	}
+	if want := "permission_prompt  never triggered\n"; out.String() != want {
+		t.Errorf("show permission_prompt = %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	if err := runState([]string{"clear", "stop"}, configFile, homeDir, now, &out); err != nil {
+		t.Fatalf("runState(clear stop) error = %v", err)
+	}
+	if want := "Cleared stop state\n"; out.String() != want {
+		t.Errorf("clear stop = %q, want %q", out.String(), want)
+	}
+	out.Reset()
+	if err := runState([]string{"show"}, configFile, homeDir, now, &out); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "stop ") || !strings.Contains(out.String(), "subagent ") {
+		t.Errorf("after clear stop, show = %q", out.String())
+	}
+
+	out.Reset()
+	if err := runState([]string{"clear", "stop"}, configFile, homeDir, now, &out); err != nil {
+		t.Fatal(err)
+	}
+	if want := "No state for stop\n"; out.String() != want {
+		t.Errorf("second clear stop = %q, want %q", out.String(), want)
+	}
+
+	for _, args := range [][]string{{"show", "Bad Event!"}, {"gc", "stop"}, {"reset"}} {
+		if err := runState(args, configFile, homeDir, now, &out); err == nil {
+			t.Errorf("runState(%v) should fail", args)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/history"
+)
+
+// statsUsage describes the stats subcommand.
+const statsUsage = "usage: ccbell stats [--since <duration>] [--event <type>]"
+
+// defaultStatsSince is the period "ccbell stats" covers by default.
+const defaultStatsSince = 7 * 24 * time.Hour
+
+// runStats handles "ccbell stats": per-day notification counts from the
+// history database.
+func runStats(args []string, configFile, homeDir string, now time.Time, stdout io.Writer) error {
+	since, event := now.Add(-defaultStatsSince), ""
+	for i := 0; i < len(args); i++ {
+		if i+1 >= len(args) {
+			return cliLocale.Errorf(statsUsage)
+		}
+		switch args[i] {
+		case "--since":
+			d, err := parseSince(args[i+1])
+			if err != nil {
+				return err
+			}
+			since = now.Add(-d)
+		case "--event":
+			if err := config.ValidateEventType(args[i+1]); err != nil {
+				return err
+			}
+			event = args[i+1]
+		default:
+			return cliLocale.Errorf(statsUsage)
+		}
+		i++
+	}
+
+	path := history.Path(homeDir)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		cfg, _, loadErr := config.LoadFile(configFile)
+		if _, enabled := cfg.HistoryRetentionDays(); loadErr == nil && !enabled {
+			return cliLocale.Errorf(`no history recorded (set "history": {"enabled": true} in the config)`)
+		}
+		fmt.Fprintln(stdout, "No notifications recorded yet")
+		return nil
+	}
+
+	store, err := history.Open(path)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	counts, err := store.Counts(since, event)
+	if err != nil {
+		return err
+	}
+	if len(counts) == 0 {
+		fmt.Fprintf(stdout, "No notifications since %s\n", since.Format("2006-01-02"))
+		return nil
+	}
+
+	// Rows are per day and event, with one column per outcome
+	type row struct {
+		day, event        string
+		delivered, failed uint64
+	}
+	var rows []*row
+	index := map[[2]string]*row{}
+	var total row
+	for _, c := range counts {
+		r, ok := index[[2]string{c.Day, c.Event}]
+		if !ok {
+			r = &row{day: c.Day, event: c.Event}
+			index[[2]string{c.Day, c.Event}] = r
+			rows = append(rows, r)
+		}
+		switch c.Outcome {
+		case history.OutcomeDelivered:
+			r.delivered += c.Count
+			total.delivered += c.Count
+		case history.OutcomeFailed:
+			r.failed += c.Count
+			total.failed += c.Count
+		}
+	}
+
+	fmt.Fprintf(stdout, "%-10s  %-18s %9s %7s\n", "DAY", "EVENT", "DELIVERED", "FAILED")
+	for _, r := range rows {
+		fmt.Fprintf(stdout, "%-10s  %-18s %9d %7d\n", r.day, r.event, r.delivered, r.failed)
+	}
+	fmt.Fprintf(stdout, "%-10s  %-18s %9d %7d\n", "TOTAL", "", total.delivered, total.failed)
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/history"
+)
+
+func TestRunStats(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "ccbell-stats-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(homeDir)
+
+	configFile := filepath.Join(homeDir, "ccbell.config.json")
+	if err := os.WriteFile(configFile, []byte(`{"enabled": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.Local)
+
+	var out bytes.Buffer
+	if err := runStats(nil, configFile, homeDir, now, &out); err == nil || !strings.Contains(err.Error(), "history") {
+		t.Errorf("runStats() with history disabled error = %v", err)
+	}
+
+	store, err := history.Open(history.Path(homeDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range []history.Record{
+		{Time: now.AddDate(0, 0, -30), Event: "stop", Outcome: history.OutcomeDelivered},
+		{Time: now.AddDate(0, 0, -1), Event: "stop", Outcome: history.OutcomeDelivered},
+		{Time: now, Event: "stop", Outcome: history.OutcomeFailed},
+		{Time: now, Event: "subagent", Outcome: history.OutcomeDelivered},
+	} {
+		if err := store.Add(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store.Close()
+
+	if err := runStats(nil, configFile, homeDir, now, &out); err != nil {
+		t.Fatalf("runStats() error = %v", err)
+	}
+	want := `DAY         EVENT              DELIVERED  FAILED
+2025-03-09  stop                       1       0
+2025-03-10  stop                       0       1
+2025-03-10  subagent                   1       0
+TOTAL                                  2       1
+`
+	if out.String() != want {
+		t.Errorf("stats output =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	if err := runStats([]string{"--since", "60d", "--event", "stop"}, configFile, homeDir, now, &out); err != nil {
+		t.Fatalf("runStats() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "TOTAL                                  2       1") {
+		t.Errorf("filtered stats output = %q", out.String())
+	}
+
+	if err := runStats([]string{"--since"}, configFile, homeDir, now, &out); err == nil {
+		t.Error("runStats() with a missing value should fail")
+	}
+}
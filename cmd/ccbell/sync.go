@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+)
+
+// syncUsage describes the config push and pull subcommands.
+const syncUsage = "usage: ccbell config push|pull [--sounds] [--repo <url> | --gist <id>]"
+
+// syncConfigName is the config file's name in the sync repository.
+const syncConfigName = "ccbell.config.json"
+
+// syncSoundExtensions are the custom sound files synced with --sounds. They
+// sit next to the config, since gists can't hold directories.
+var syncSoundExtensions = map[string]bool{
+	".aiff": true, ".aif": true, ".wav": true, ".mp3": true,
+	".ogg": true, ".oga": true, ".flac": true, ".m4a": true,
+}
+
+// syncOptions holds parsed config push/pull arguments.
+type syncOptions struct {
+	sounds bool
+	remote string // Overrides the config's "sync" setting
+}
+
+// runConfigSync handles "ccbell config push" and "ccbell config pull":
+// copying the config, and with --sounds the custom sounds in
+// ~/.claude/ccbell/sounds, to or from a git repository or gist.
+func runConfigSync(command string, args []string, configFile, homeDir string, stdout io.Writer) error {
+	opts, err := parseSyncArgs(args)
+	if err != nil {
+		return err
+	}
+	if opts.remote == "" {
+		cfg, _, err := config.LoadFile(configFile)
+		if err != nil {
+			return err
+		}
+		if cfg.Sync != nil {
+			opts.remote = cfg.Sync.Remote()
+		}
+	}
+	if opts.remote == "" {
+		return cliLocale.Errorf(`no sync target: set "sync": {"repo": "<git url>"} or {"gist": "<id>"}, or pass --repo or --gist`)
+	}
+
+	dir, err := os.MkdirTemp("", "ccbell-sync")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	if _, err := runGit("", "clone", "--quiet", opts.remote, dir); err != nil {
+		return err
+	}
+
+	soundsDir := filepath.Join(homeDir, ".claude", "ccbell", "sounds")
+	if command == "push" {
+		return pushConfig(dir, configFile, homeDir, soundsDir, opts, stdout)
+	}
+	return pullConfig(dir, configFile, soundsDir, opts, stdout)
+}
+
+// parseSyncArgs parses "[--sounds] [--repo <url> | --gist <id>]".
+func parseSyncArgs(args []string) (*syncOptions, error) {
+	opts := &syncOptions{}
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--sounds":
+			opts.sounds = true
+		case (args[i] == "--repo" || args[i] == "--gist") && i+1 < len(args) && opts.remote == "":
+			sync := &config.Sync{Repo: args[i+1]}
+			if args[i] == "--gist" {
+				sync = &config.Sync{Gist: args[i+1]}
+			}
+			if err := (&config.Config{Sync: sync}).Validate(); err != nil {
+				return nil, err
+			}
+			opts.remote = sync.Remote()
+			i++
+		default:
+			return nil, cliLocale.Errorf(syncUsage)
+		}
+	}
+	return opts, nil
+}
+
+// pushConfig commits the config and sounds to the clone in dir and pushes.
+func pushConfig(dir, configFile, homeDir, soundsDir string, opts *syncOptions, stdout io.Writer) error {
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return cliLocale.Errorf("failed to read config: %w", err)
+	}
+	data = portableConfig(data, homeDir)
+	if err := os.WriteFile(filepath.Join(dir, syncConfigName), data, 0644); err != nil {
+		return err
+	}
+	sounds := 0
+	if opts.sounds {
+		if sounds, err = mirrorSounds(soundsDir, dir); err != nil {
+			return err
+		}
+	}
+
+	if _, err := runGit(dir, "add", "-A"); err != nil {
+		return err
+	}
+	status, err := runGit(dir, "status", "--porcelain")
+	if err != nil {
+		return err
+	}
+	if status == "" {
+		fmt.Fprintf(stdout, "%s is already up to date\n", opts.remote)
+		return nil
+	}
+	host, _ := os.Hostname()
+	if _, err := runGit(dir, "commit", "--quiet", "-m", "Update ccbell config from "+host); err != nil {
+		return err
+	}
+	if _, err := runGit(dir, "push", "--quiet", "origin", "HEAD"); err != nil {
+		return err
+	}
+
+	if opts.sounds {
+		fmt.Fprintf(stdout, "Pushed config and %d sounds to %s\n", sounds, opts.remote)
+	} else {
+		fmt.Fprintf(stdout, "Pushed config to %s\n", opts.remote)
+	}
+	if clean, err := redactConfig(data); err == nil {
+		if n := bytes.Count(clean, []byte(redacted)); n > 0 {
+			fmt.Fprintf(stdout, "Warning: %d secret values were pushed in plain text; consider \"secret:<name>\" references\n", n)
+		}
+	}
+	return nil
+}
+
+// pullConfig replaces the config, and with --sounds the custom sounds, with
+// the copies in the clone in dir. The previous config is kept as a .bak file.
+func pullConfig(dir, configFile, soundsDir string, opts *syncOptions, stdout io.Writer) error {
+	pulled := filepath.Join(dir, syncConfigName)
+	data, err := os.ReadFile(pulled)
+	if os.IsNotExist(err) {
+		return cliLocale.Errorf("%s has no %s; run ccbell config push first", opts.remote, syncConfigName)
+	}
+	if err != nil {
+		return err
+	}
+	if _, _, err := config.LoadFile(pulled); err != nil {
+		return cliLocale.Errorf("pulled config is invalid, keeping the current one: %w", err)
+	}
+
+	sounds := 0
+	if opts.sounds {
+		if err := os.MkdirAll(soundsDir, 0750); err != nil {
+			return cliLocale.Errorf("failed to create sounds directory: %w", err)
+		}
+		if sounds, err = copySounds(dir, soundsDir); err != nil {
+			return err
+		}
+	}
+
+	current, err := os.ReadFile(configFile)
+	switch {
+	case err == nil && bytes.Equal(current, data):
+		fmt.Fprintf(stdout, "Config is already up to date with %s\n", opts.remote)
+		return nil
+	case err == nil:
+		if err := os.WriteFile(configFile+".bak", current, 0644); err != nil {
+			return cliLocale.Errorf("failed to back up config: %w", err)
+		}
+	case !os.IsNotExist(err):
+		return cliLocale.Errorf("failed to read config: %w", err)
+	}
+	if err := config.ReplaceFile(configFile, data); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "Pulled config from %s", opts.remote)
+	if opts.sounds {
+		fmt.Fprintf(stdout, " with %d sounds", sounds)
+	}
+	if current != nil {
+		fmt.Fprintf(stdout, " (previous config saved to %s.bak)", configFile)
+	}
+	fmt.Fprintln(stdout)
+	return nil
+}
+
+// portableConfig rewrites custom sound paths under the home directory to
+// ${HOME}, so they resolve on machines with a different home.
+func portableConfig(data []byte, homeDir string) []byte {
+	if homeDir == "" {
+		return data
+	}
+	return bytes.ReplaceAll(data, []byte(`"custom:`+homeDir+`/`), []byte(`"custom:${HOME}/`))
+}
+
+// mirrorSounds makes the sound files in dest match those in src, returning
+// how many there are.
+func mirrorSounds(src, dest string) (int, error) {
+	existing, err := soundFiles(dest)
+	if err != nil {
+		return 0, err
+	}
+	for _, name := range existing {
+		if _, err := os.Stat(filepath.Join(src, name)); os.IsNotExist(err) {
+			if err := os.Remove(filepath.Join(dest, name)); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return copySounds(src, dest)
+}
+
+// copySounds copies the sound files in src to dest, returning how many
+// were copied. A missing src has none.
+func copySounds(src, dest string) (int, error) {
+	names, err := soundFiles(src)
+	if err != nil {
+		return 0, err
+	}
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(src, name))
+		if err != nil {
+			return 0, err
+		}
+		if err := os.WriteFile(filepath.Join(dest, name), data, 0644); err != nil {
+			return 0, cliLocale.Errorf("failed to copy sound %s: %w", name, err)
+		}
+	}
+	return len(names), nil
+}
+
+// soundFiles lists the regular sound files in dir, skipping hidden files
+// such as in-progress recordings.
+func soundFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.Type().IsRegular() && !strings.HasPrefix(name, ".") && syncSoundExtensions[strings.ToLower(filepath.Ext(name))] {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// runGit runs git in dir (the current directory if empty), returning its
+// trimmed output.
+func runGit(dir string, args ...string) (string, error) {
+	gitArgs := args
+	if dir != "" {
+		gitArgs = append([]string{"-C", dir}, args...)
+	}
+	out, err := execCommand("git", gitArgs...).CombinedOutput()
+	if err != nil {
+		return "", cliLocale.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setGitIdentity sets a commit identity for git in tests, restoring the
+// environment afterwards.
+func setGitIdentity(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{"GIT_AUTHOR_NAME", "GIT_AUTHOR_EMAIL", "GIT_COMMITTER_NAME", "GIT_COMMITTER_EMAIL"} {
+		old, had := os.LookupEnv(name)
+		value := "ccbell test"
+		if strings.HasSuffix(name, "EMAIL") {
+			value = "test@example.com"
+		}
+		os.Setenv(name, value)
+		t.Cleanup(func() {
+			if had {
+				os.Setenv(name, old)
+			} else {
+				os.Unsetenv(name)
+			}
+		})
+	}
+}
+
+func TestConfigPushPull(t *testing.T) {
+	if _, err := lookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	setGitIdentity(t)
+
+	tmpDir, err := os.MkdirTemp("", "ccbell-sync-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	remote := filepath.Join(tmpDir, "remote.git")
+	if _, err := runGit("", "init", "--quiet", "--bare", remote); err != nil {
+		t.Fatal(err)
+	}
+
+	// Machine A pushes its config and a recorded sound
+	homeA := filepath.Join(tmpDir, "a")
+	soundsA := filepath.Join(homeA, ".claude", "ccbell", "sounds")
+	if err := os.MkdirAll(soundsA, 0750); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string]string{"stop.wav": "RIFF", ".stop.recording.wav": "partial", "notes.txt": "x"} {
+		if err := os.WriteFile(filepath.Join(soundsA, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	configA := filepath.Join(homeA, "ccbell.config.json")
+	configData := `{"enabled": true, "sync": {"repo": "` + remote + `"}, "events": {"stop": {"sound": "custom:` + soundsA + `/stop.wav"}}}`
+	if err := os.WriteFile(configA, []byte(configData), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runConfig([]string{"push", "--sounds"}, configA, homeA, false, &out); err != nil {
+		t.Fatalf("push error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Pushed config and 1 sounds") {
+		t.Errorf("push output = %q", out.String())
+	}
+	out.Reset()
+	if err := runConfig([]string{"push"}, configA, homeA, false, &out); err != nil {
+		t.Fatalf("second push error = %v", err)
+	}
+	if !strings.Contains(out.String(), "already up to date") {
+		t.Errorf("unchanged push output = %q", out.String())
+	}
+
+	// Machine B pulls it, keeping a backup of its own config
+	homeB := filepath.Join(tmpDir, "b")
+	if err := os.MkdirAll(homeB, 0750); err != nil {
+		t.Fatal(err)
+	}
+	configB := filepath.Join(homeB, "ccbell.config.json")
+	if err := os.WriteFile(configB, []byte(`{"enabled": false}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := runConfig([]string{"pull", "--sounds", "--repo", remote}, configB, homeB, false, &out); err != nil {
+		t.Fatalf("pull error = %v", err)
+	}
+	if !strings.Contains(out.String(), "with 1 sounds") {
+		t.Errorf("pull output = %q", out.String())
+	}
+
+	pulled, err := os.ReadFile(configB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(pulled), `"custom:${HOME}/.claude/ccbell/sounds/stop.wav"`) {
+		t.Errorf("pulled config should use ${HOME} for custom sounds:\n%s", pulled)
+	}
+	if backup, err := os.ReadFile(configB + ".bak"); err != nil || string(backup) != `{"enabled": false}` {
+		t.Errorf("backup = %q, %v", backup, err)
+	}
+	if _, err := os.Stat(filepath.Join(homeB, ".claude", "ccbell", "sounds", "stop.wav")); err != nil {
+		t.Errorf("sound not pulled: %v", err)
+	}
+	for _, name := range []string{".stop.recording.wav", "notes.txt"} {
+		if _, err := os.Stat(filepath.Join(homeB, ".claude", "ccbell", "sounds", name)); err == nil {
+			t.Errorf("%s should not be synced", name)
+		}
+	}
+}
+
+func TestConfigSyncErrors(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-sync-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	configFile := filepath.Join(tmpDir, "ccbell.config.json")
+	if err := os.WriteFile(configFile, []byte(`{"enabled": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"no target", []string{"push"}, "no sync target"},
+		{"bad flag", []string{"pull", "--force"}, "usage"},
+		{"both targets", []string{"push", "--repo", "/tmp/x", "--gist", "abc"}, "usage"},
+		{"bad gist", []string{"pull", "--gist", "../x"}, "invalid gist ID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runConfig(tt.args, configFile, tmpDir, false, &bytes.Buffer{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("runConfig(%v) error = %v, want %q", tt.args, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPortableConfig(t *testing.T) {
+	data := []byte(`{"sound": "custom:/home/me/.claude/ccbell/sounds/a.wav", "other": "/home/me/x"}`)
+	got := string(portableConfig(data, "/home/me"))
+	want := `{"sound": "custom:${HOME}/.claude/ccbell/sounds/a.wav", "other": "/home/me/x"}`
+	if got != want {
+		t.Errorf("portableConfig() = %s, want %s", got, want)
+	}
+	if got := portableConfig(data, ""); !bytes.Equal(got, data) {
+		t.Error("portableConfig() without a home should not change the config")
+	}
+}
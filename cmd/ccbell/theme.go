@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+)
+
+// themeUsage describes the theme subcommand.
+const themeUsage = "usage: ccbell theme <list|use <name>>"
+
+// runTheme handles "ccbell theme": listing themes and switching the active one.
+func runTheme(args []string, configFile string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return cliLocale.Errorf(themeUsage)
+	}
+
+	cfg, _, err := config.LoadFile(configFile)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "list":
+		for _, name := range cfg.ThemeNames() {
+			marker := " "
+			if name == cfg.Theme {
+				marker = "*"
+			}
+			fmt.Fprintf(stdout, "%s %s\n", marker, name)
+		}
+		return nil
+	case "use":
+		if len(args) != 2 {
+			return cliLocale.Errorf(themeUsage)
+		}
+		name := args[1]
+		if _, ok := cfg.GetTheme(name); !ok {
+			return cliLocale.Errorf("unknown theme: %s", name)
+		}
+		if err := config.SetKey(configFile, "theme", name); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Using theme %s\n", name)
+		return nil
+	default:
+		return cliLocale.Errorf(themeUsage)
+	}
+}
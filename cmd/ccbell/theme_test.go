@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+)
+
+func TestRunTheme(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-theme-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	configFile := filepath.Join(tempDir, "ccbell.config.json")
+	if err := os.WriteFile(configFile, []byte(`{"enabled": true, "themes": {"retro": {"stop": "bundled:retro"}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runTheme([]string{"use", "retro"}, configFile, &out); err != nil {
+		t.Fatalf("runTheme(use) error = %v", err)
+	}
+	cfg, _, err := config.LoadFile(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Theme != "retro" {
+		t.Errorf("theme = %q, want retro", cfg.Theme)
+	}
+
+	out.Reset()
+	if err := runTheme([]string{"list"}, configFile, &out); err != nil {
+		t.Fatalf("runTheme(list) error = %v", err)
+	}
+	if want := "  bundled\n* retro\n  system\n"; out.String() != want {
+		t.Errorf("list output = %q, want %q", out.String(), want)
+	}
+
+	if err := runTheme([]string{"use", "nope"}, configFile, &out); err == nil || !strings.Contains(err.Error(), "unknown theme") {
+		t.Errorf("runTheme(use nope) error = %v", err)
+	}
+	if err := runTheme(nil, configFile, &out); err == nil {
+		t.Error("runTheme() without args should fail")
+	}
+}
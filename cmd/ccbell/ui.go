@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/history"
+)
+
+// uiUsage describes the ui subcommand.
+const uiUsage = "usage: ccbell ui"
+
+// uiEvents are the events listed in the dashboard, in display order.
+var uiEvents = []string{"stop", "permission_prompt", "idle_prompt", "subagent"}
+
+const (
+	// uiRefreshInterval is how often the dashboard redraws without input.
+	uiRefreshInterval = 2 * time.Second
+	// uiRecentEvents is how many history records the dashboard shows.
+	uiRecentEvents = 8
+	// uiVolumeStep is the volume change per arrow key press.
+	uiVolumeStep = 0.1
+	// uiSliderWidth is the number of cells in a volume slider.
+	uiSliderWidth = 10
+)
+
+// Keys decoded from terminal input.
+const (
+	keyUp    = "up"
+	keyDown  = "down"
+	keyLeft  = "left"
+	keyRight = "right"
+	keySpace = "space"
+	keyQuit  = "quit"
+)
+
+// dashboard is the state of "ccbell ui".
+type dashboard struct {
+	configFile string
+	homeDir    string
+	selected   int
+	message    string // Result of the last action
+}
+
+// runUI handles "ccbell ui": a terminal dashboard with the current status,
+// per-event toggles and volume sliders, and recent notifications. Changes
+// are written to the config file immediately.
+func runUI(ctx context.Context, args []string, configFile, homeDir string, stdin *os.File, stdout io.Writer) error {
+	if len(args) != 0 {
+		return cliLocale.Errorf(uiUsage)
+	}
+	if !isTerminal(stdin) {
+		return cliLocale.Errorf("ccbell ui needs an interactive terminal")
+	}
+
+	restore, err := rawMode(stdin)
+	if err != nil {
+		return cliLocale.Errorf("failed to set up terminal: %w", err)
+	}
+	defer restore()
+	fmt.Fprint(stdout, "\x1b[?1049h\x1b[?25l") // Alternate screen, hide cursor
+	defer fmt.Fprint(stdout, "\x1b[?25h\x1b[?1049l")
+
+	keys := make(chan string)
+	go func() {
+		r := bufio.NewReader(stdin)
+		for {
+			key, err := readKey(r)
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- key
+		}
+	}()
+
+	d := &dashboard{configFile: configFile, homeDir: homeDir}
+	ticker := time.NewTicker(uiRefreshInterval)
+	defer ticker.Stop()
+	for {
+		var screen bytes.Buffer
+		d.render(&screen, time.Now())
+		fmt.Fprint(stdout, "\x1b[H\x1b[2J"+strings.ReplaceAll(screen.String(), "\n", "\r\n"))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case key, ok := <-keys:
+			if !ok || key == keyQuit {
+				return nil
+			}
+			d.handleKey(key)
+		}
+	}
+}
+
+// rawMode switches the terminal to raw input without echo using stty, and
+// returns a function restoring the previous settings.
+func rawMode(tty *os.File) (func(), error) {
+	stty := func(args ...string) ([]byte, error) {
+		cmd := exec.Command("stty", args...)
+		cmd.Stdin = tty
+		return cmd.Output()
+	}
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+	return func() { _, _ = stty(strings.TrimSpace(string(saved))) }, nil
+}
+
+// readKey reads one key press: arrows from their escape sequences, vi-style
+// hjkl as arrows, and other keys as their character.
+func readKey(r *bufio.Reader) (string, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	switch b {
+	case 0x1b:
+		// Escape sequences arrive in one read; a lone Esc quits
+		if next, err := r.Peek(min(2, r.Buffered())); err == nil && len(next) == 2 && (next[0] == '[' || next[0] == 'O') {
+			_, _ = r.Discard(2)
+			switch next[1] {
+			case 'A':
+				return keyUp, nil
+			case 'B':
+				return keyDown, nil
+			case 'C':
+				return keyRight, nil
+			case 'D':
+				return keyLeft, nil
+			}
+			return "", nil
+		}
+		return keyQuit, nil
+	case 'k':
+		return keyUp, nil
+	case 'j':
+		return keyDown, nil
+	case 'l', '+', '=':
+		return keyRight, nil
+	case 'h', '-':
+		return keyLeft, nil
+	case ' ', '\r', '\n':
+		return keySpace, nil
+	case 'q', 0x03: // Ctrl-C arrives as a byte in raw mode
+		return keyQuit, nil
+	}
+	return string(b), nil
+}
+
+// handleKey applies a key press to the selection or the config file.
+func (d *dashboard) handleKey(key string) {
+	cfg, _, err := config.LoadFile(d.configFile)
+	if err != nil {
+		d.message = err.Error()
+		return
+	}
+	event := uiEvents[d.selected]
+	eventCfg := cfg.GetEventConfig(event)
+
+	switch key {
+	case keyUp:
+		d.selected = (d.selected + len(uiEvents) - 1) % len(uiEvents)
+	case keyDown:
+		d.selected = (d.selected + 1) % len(uiEvents)
+	case keySpace:
+		enabled := !*eventCfg.Enabled
+		d.apply(config.SetEventKey(d.configFile, event, "enabled", enabled),
+			fmt.Sprintf("%s %s", event, onOff(enabled)))
+	case keyLeft, keyRight:
+		step := uiVolumeStep
+		if key == keyLeft {
+			step = -step
+		}
+		volume := math.Round((*eventCfg.Volume+step)*10) / 10
+		volume = math.Max(0, math.Min(1, volume))
+		d.apply(config.SetEventKey(d.configFile, event, "volume", volume),
+			fmt.Sprintf("%s volume %.1f", event, volume))
+	case "m":
+		d.apply(config.SetKey(d.configFile, "enabled", !cfg.Enabled),
+			fmt.Sprintf("notifications %s", onOff(!cfg.Enabled)))
+	}
+}
+
+// apply records the outcome of a config change.
+func (d *dashboard) apply(err error, done string) {
+	if err != nil {
+		d.message = err.Error()
+		return
+	}
+	d.message = "Saved: " + done
+}
+
+// render draws the dashboard.
+func (d *dashboard) render(w io.Writer, now time.Time) {
+	fmt.Fprintln(w, "ccbell  up/down select  space toggle  left/right volume  m mute  q quit")
+	fmt.Fprintln(w)
+
+	if err := runStatus(d.configFile, d.homeDir, now, w); err != nil {
+		fmt.Fprintf(w, "Config error: %v\n", err)
+		return
+	}
+	cfg, _, err := config.LoadFile(d.configFile)
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintln(w, "\nEVENTS")
+	for i, event := range uiEvents {
+		eventCfg := cfg.GetEventConfig(event)
+		cursor, check := " ", " "
+		if i == d.selected {
+			cursor = ">"
+		}
+		if *eventCfg.Enabled {
+			check = "x"
+		}
+		volume := *eventCfg.Volume
+		fmt.Fprintf(w, "%s [%s] %-18s %s %.1f\n", cursor, check, event, volumeSlider(volume), volume)
+	}
+	if cfg.ActiveProfile != "" && cfg.ActiveProfile != "default" {
+		fmt.Fprintf(w, "  (profile %q applied on top of these settings)\n", cfg.ActiveProfile)
+	}
+
+	fmt.Fprintln(w, "\nRECENT")
+	d.renderRecent(w, now)
+
+	if d.message != "" {
+		fmt.Fprintf(w, "\n%s\n", d.message)
+	}
+}
+
+// renderRecent lists the latest notifications from the history database.
+func (d *dashboard) renderRecent(w io.Writer, now time.Time) {
+	path := history.Path(d.homeDir)
+	if _, err := os.Stat(path); err != nil {
+		fmt.Fprintln(w, `  No history yet (set "history": {"enabled": true} to record events)`)
+		return
+	}
+	store, err := history.Open(path)
+	if err != nil {
+		fmt.Fprintf(w, "  %v\n", err)
+		return
+	}
+	defer store.Close()
+
+	records, err := store.Records(now.AddDate(0, 0, -7), "")
+	if err != nil {
+		fmt.Fprintf(w, "  %v\n", err)
+		return
+	}
+	if len(records) == 0 {
+		fmt.Fprintln(w, "  No notifications in the last 7 days")
+		return
+	}
+	for _, r := range records[max(0, len(records)-uiRecentEvents):] {
+		fmt.Fprintf(w, "  %s  %-18s %s\n", r.Time.Local().Format("Mon 15:04:05"), r.Event, r.Outcome)
+	}
+}
+
+// volumeSlider draws a volume between 0 and 1 as a bar.
+func volumeSlider(volume float64) string {
+	filled := int(math.Round(volume * uiSliderWidth))
+	filled = max(0, min(uiSliderWidth, filled))
+	return strings.Repeat("#", filled) + strings.Repeat("-", uiSliderWidth-filled)
+}
+
+// onOff describes a toggle state.
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
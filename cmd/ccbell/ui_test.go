@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/history"
+)
+
+func TestReadKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("\x1b[A\x1b[Bjk \rlhmq\x03"))
+	want := []string{keyUp, keyDown, keyDown, keyUp, keySpace, keySpace, keyRight, keyLeft, "m", keyQuit, keyQuit}
+	for i, w := range want {
+		got, err := readKey(r)
+		if err != nil {
+			t.Fatalf("key %d: readKey() error = %v", i, err)
+		}
+		if got != w {
+			t.Errorf("key %d = %q, want %q", i, got, w)
+		}
+	}
+	if _, err := readKey(r); err == nil {
+		t.Error("readKey() at EOF should fail")
+	}
+}
+
+func TestDashboard(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "ccbell-ui-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(homeDir)
+
+	configFile := filepath.Join(homeDir, "ccbell.config.json")
+	if err := os.WriteFile(configFile, []byte(`{"enabled": true, "events": {"permission_prompt": {"volume": 0.7}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	d := &dashboard{configFile: configFile, homeDir: homeDir}
+
+	// Select permission_prompt, disable it and raise its volume
+	for _, key := range []string{keyDown, keySpace, keyRight, keyRight} {
+		d.handleKey(key)
+	}
+	d.handleKey("m")
+
+	cfg, _, err := config.LoadFile(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prompt := cfg.GetEventConfig("permission_prompt")
+	if *prompt.Enabled || *prompt.Volume != 0.9 {
+		t.Errorf("permission_prompt = enabled %v, volume %v", *prompt.Enabled, *prompt.Volume)
+	}
+	if cfg.Enabled {
+		t.Error("m should mute notifications")
+	}
+
+	// Volume stays within 0-1
+	for i := 0; i < 5; i++ {
+		d.handleKey(keyRight)
+	}
+	cfg, _, _ = config.LoadFile(configFile)
+	if v := *cfg.GetEventConfig("permission_prompt").Volume; v != 1 {
+		t.Errorf("volume = %v, want 1", v)
+	}
+
+	store, err := history.Open(history.Path(homeDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if err := store.Add(history.Record{Time: now.Add(-time.Minute), Event: "stop", Outcome: history.OutcomeDelivered}); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	var out bytes.Buffer
+	d.render(&out, now)
+	screen := out.String()
+	for _, want := range []string{
+		"Enabled:     no",
+		"  [x] stop               #####----- 0.5",
+		"> [ ] permission_prompt  ########## 1.0",
+		"stop               delivered",
+		"Saved: permission_prompt volume 1.0",
+	} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("screen missing %q:\n%s", want, screen)
+		}
+	}
+}
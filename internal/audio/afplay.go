@@ -0,0 +1,28 @@
+package audio
+
+import "fmt"
+
+// SetAFPlayOptions scales afplay's playback rate by rate, keeping pitch,
+// and sets its rate-scaled playback quality (-q); a negative quality keeps
+// afplay's default. Other players ignore them.
+func (p *Player) SetAFPlayOptions(rate float64, quality int) {
+	p.afplayRate = rate
+	p.afplayQuality = max(quality, -1) + 1
+}
+
+// afplayArgs returns the afplay arguments for gain, the configured rate and
+// tempo variation. afplay time-stretches, so pitch variation is skipped.
+func (p *Player) afplayArgs() []string {
+	rate := 1.0
+	if p.variationMode != VariationPitch {
+		rate = p.playbackRate()
+	}
+	if p.afplayRate > 0 {
+		rate *= p.afplayRate
+	}
+	args := effectArgs("afplay", p.gain, rate, false)
+	if rate != 1 && p.afplayQuality > 0 {
+		args = append(args, "-q", fmt.Sprint(p.afplayQuality-1))
+	}
+	return args
+}
@@ -0,0 +1,42 @@
+package audio
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAFPlayArgs(t *testing.T) {
+	orig := randFloat
+	randFloat = func() float64 { return 1 }
+	defer func() { randFloat = orig }()
+
+	tests := []struct {
+		name    string
+		mode    string
+		rate    float64
+		quality int
+		want    []string
+	}{
+		{"defaults", "", 0, -1, nil},
+		{"rate", "", 1.5, -1, []string{"-r", "1.500"}},
+		{"rate and quality", "", 1.5, 1, []string{"-r", "1.500", "-q", "1"}},
+		{"quality alone", "", 1, 1, nil},
+		{"with tempo variation", VariationTempo, 2, 0, []string{"-r", "2.200", "-q", "0"}},
+		{"pitch variation skipped", VariationPitch, 2, -1, []string{"-r", "2.000"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Player{}
+			if tt.mode != "" {
+				p.SetVariation(tt.mode, 0.1)
+			}
+			if tt.rate != 0 {
+				p.SetAFPlayOptions(tt.rate, tt.quality)
+			}
+			if got := p.afplayArgs(); !slices.Equal(got, tt.want) {
+				t.Errorf("afplayArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
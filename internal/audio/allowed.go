@@ -0,0 +1,54 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ErrOutsideAllowedDirs is returned when a custom sound isn't inside any of
+// the allowed directories.
+var ErrOutsideAllowedDirs = errors.New("sound outside allowed directories")
+
+// SetAllowedDirs restricts custom sounds, including pack sounds and direct
+// paths, to files inside dirs. No dirs allows any path.
+func (p *Player) SetAllowedDirs(dirs []string) {
+	p.allowedDirs = dirs
+}
+
+// customSymlinkRoot returns the allowed directory containing path, which
+// its symlinks must stay within under SymlinkResolveWithinRoot, or "" if
+// there is none.
+func (p *Player) customSymlinkRoot(path string) string {
+	for _, dir := range p.allowedDirs {
+		rel, err := filepath.Rel(filepath.Clean(dir), path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return dir
+		}
+	}
+	return ""
+}
+
+// checkAllowedDir checks that path, with symlinks resolved, is inside one of
+// the allowed directories.
+func (p *Player) checkAllowedDir(path string) error {
+	if len(p.allowedDirs) == 0 {
+		return nil
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return err
+	}
+	for _, dir := range p.allowedDirs {
+		realDir, err := filepath.EvalSymlinks(dir)
+		if err != nil {
+			continue // A missing directory allows nothing
+		}
+		rel, err := filepath.Rel(realDir, resolved)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrOutsideAllowedDirs, path)
+}
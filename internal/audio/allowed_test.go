@@ -0,0 +1,69 @@
+package audio
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckAllowedDir(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-allowed-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	allowed := filepath.Join(tempDir, "allowed")
+	other := filepath.Join(tempDir, "allowed-other")
+	for _, dir := range []string{filepath.Join(allowed, "pack"), other} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	inside := filepath.Join(allowed, "pack", "stop.wav")
+	outside := filepath.Join(other, "stop.wav")
+	for _, f := range []string{inside, outside} {
+		if err := os.WriteFile(f, []byte("RIFF"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	escape := filepath.Join(allowed, "escape.wav")
+	if err := os.Symlink(outside, escape); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		dirs    []string
+		path    string
+		wantErr bool
+	}{
+		{"no restriction", nil, outside, false},
+		{"inside", []string{allowed}, inside, false},
+		{"sibling with shared prefix", []string{allowed}, outside, true},
+		{"symlink out of allowed dir", []string{allowed}, escape, true},
+		{"second dir", []string{filepath.Join(tempDir, "missing"), other}, outside, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPlayer(tempDir)
+			p.SetAllowedDirs(tt.dirs)
+			err := p.checkAllowedDir(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkAllowedDir() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrOutsideAllowedDirs) {
+				t.Errorf("error = %v, want ErrOutsideAllowedDirs", err)
+			}
+		})
+	}
+
+	p := NewPlayer(tempDir)
+	p.SetAllowedDirs([]string{allowed})
+	p.SetSymlinkPolicy(SymlinkAllow)
+	if _, err := p.ResolveSoundPath(escape, "stop"); !errors.Is(err, ErrOutsideAllowedDirs) {
+		t.Errorf("ResolveSoundPath(escaping symlink) error = %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package audio
+
+import (
+	"cmp"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Test tone played by CheckPlayers.
+const (
+	checkToneDuration = 300 * time.Millisecond
+	checkToneHz       = 880
+	checkVolume       = 0.5
+)
+
+// silenceDuration is the length of the silent sound RankPlayers plays.
+const silenceDuration = 100 * time.Millisecond
+
+// toneSampleRate is the sample rate of generated tones.
+const toneSampleRate = 44100
+
+// PlayerCheck is the result of playing the test tone with one player.
+type PlayerCheck struct {
+	Name      string
+	Installed bool
+	Latency   time.Duration // Time to finish beyond the tone's length
+	Err       error         // Set if the player failed
+}
+
+// CheckPlayers plays a short test tone with each of the platform's players,
+// in preference order, waiting for each to finish. A player's latency is
+// how much longer than the tone it took, which is mostly its startup time.
+func (p *Player) CheckPlayers(ctx context.Context) ([]PlayerCheck, error) {
+	return p.checkPlayers(ctx, checkToneDuration, 0.3)
+}
+
+// RankPlayers times each installed player playing a short silent sound and
+// returns the ones that played, fastest first. Mock playback isn't timed,
+// so the mock backend ranks none.
+func (p *Player) RankPlayers(ctx context.Context) ([]string, error) {
+	if _, ok := p.cmdRunner().(*MockRunner); ok {
+		return nil, nil
+	}
+	checks, err := p.checkPlayers(ctx, silenceDuration, 0)
+	if err != nil {
+		return nil, err
+	}
+	return RankChecks(checks), nil
+}
+
+// RankChecks returns the players that played in checks, fastest first.
+// Latencies are compared to the millisecond, so players that are about as
+// fast keep their order.
+func RankChecks(checks []PlayerCheck) []string {
+	var working []PlayerCheck
+	for _, c := range checks {
+		if c.Installed && c.Err == nil {
+			working = append(working, c)
+		}
+	}
+	slices.SortStableFunc(working, func(a, b PlayerCheck) int {
+		return cmp.Compare(a.Latency.Round(time.Millisecond), b.Latency.Round(time.Millisecond))
+	})
+	names := make([]string, len(working))
+	for i, c := range working {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// checkPlayers plays a tone of the given length and amplitude with each of
+// the platform's players.
+func (p *Player) checkPlayers(ctx context.Context, length time.Duration, amplitude float64) ([]PlayerCheck, error) {
+	names, err := p.platformPlayers()
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "ccbell-check")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	tone := filepath.Join(dir, "tone.wav")
+	if err := writeTone(tone, checkToneHz, length, amplitude); err != nil {
+		return nil, err
+	}
+
+	checks := make([]PlayerCheck, 0, len(names))
+	for _, name := range names {
+		check := PlayerCheck{Name: name}
+		if _, err := p.cmdRunner().LookPath(name); err == nil {
+			check.Installed = true
+			check.Latency, check.Err = p.timePlayer(ctx, name, tone, length)
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// platformPlayers returns the players tried on the platform, in preference
+// order.
+func (p *Player) platformPlayers() ([]string, error) {
+	switch p.platform {
+	case PlatformMacOS:
+		return []string{"afplay"}, nil
+	case PlatformLinux:
+		return p.orderPlayers(linuxAudioPlayerNames), nil
+	case PlatformBSD:
+		return p.orderPlayers(bsdAudioPlayerNames), nil
+	default:
+		return nil, fmt.Errorf("unsupported platform: %s", p.platform)
+	}
+}
+
+// timePlayer plays path, which lasts length, with the named player and
+// returns how much longer than length it took.
+func (p *Player) timePlayer(ctx context.Context, name, path string, length time.Duration) (time.Duration, error) {
+	volume := p.capLoudness(checkVolume)
+	args := getLinuxPlayerArgs(name, path, volume, 0, 1, false)
+	if name == "afplay" {
+		args = []string{"-v", fmt.Sprintf("%.2f", volume), path}
+	}
+	start := time.Now()
+	if err := p.cmdRunner().Run(p.command(ctx, name, args...)); err != nil {
+		return 0, err
+	}
+	return max(time.Since(start)-length, 0), nil
+}
+
+// writeTone writes a mono 16-bit WAV file of a sine wave at hz, with the
+// given length and amplitude (0-1; 0 for silence). The ends fade over 10ms
+// so the tone doesn't click.
+func writeTone(path string, hz float64, length time.Duration, amplitude float64) error {
+	samples := int(length.Seconds() * toneSampleRate)
+	fade := toneSampleRate / 100
+	data := make([]byte, 44+2*samples)
+	copy(data[0:], "RIFF")
+	binary.LittleEndian.PutUint32(data[4:], uint32(len(data)-8))
+	copy(data[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(data[16:], 16)               // fmt chunk size
+	binary.LittleEndian.PutUint16(data[20:], 1)                // PCM
+	binary.LittleEndian.PutUint16(data[22:], 1)                // Channels
+	binary.LittleEndian.PutUint32(data[24:], toneSampleRate)   // Sample rate
+	binary.LittleEndian.PutUint32(data[28:], toneSampleRate*2) // Byte rate
+	binary.LittleEndian.PutUint16(data[32:], 2)                // Block align
+	binary.LittleEndian.PutUint16(data[34:], 16)               // Bits per sample
+	copy(data[36:], "data")
+	binary.LittleEndian.PutUint32(data[40:], uint32(2*samples))
+	for i := range samples {
+		envelope := min(1, float64(i)/float64(fade), float64(samples-1-i)/float64(fade))
+		v := amplitude * envelope * math.Sin(2*math.Pi*hz*float64(i)/toneSampleRate)
+		binary.LittleEndian.PutUint16(data[44+2*i:], uint16(int16(v*math.MaxInt16)))
+	}
+	return os.WriteFile(path, data, 0644)
+}
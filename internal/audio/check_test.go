@@ -0,0 +1,123 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCheckPlayers(t *testing.T) {
+	runner := &brokenRunner{MockRunner: NewMockRunner(nil), missing: map[string]bool{"aplay": true, "setpriv": true}}
+	player := &Player{platform: PlatformLinux}
+	player.SetRunner(runner)
+	player.SetPlayerOrder([]string{"ffplay", "paplay"})
+
+	checks, err := player.CheckPlayers(context.Background())
+	if err != nil {
+		t.Fatalf("CheckPlayers() error = %v", err)
+	}
+	var names []string
+	for _, c := range checks {
+		names = append(names, c.Name)
+		if c.Installed == (c.Name == "aplay") {
+			t.Errorf("%s installed = %v", c.Name, c.Installed)
+		}
+		if c.Err != nil || c.Latency < 0 {
+			t.Errorf("%s: latency %v, error %v", c.Name, c.Latency, c.Err)
+		}
+	}
+	if want := []string{"ffplay", "paplay", "mpv", "aplay"}; !slices.Equal(names, want) {
+		t.Errorf("checked %v, want %v", names, want)
+	}
+
+	commands := runner.Commands()
+	if len(commands) != 3 {
+		t.Fatalf("recorded %d commands, want 3: %v", len(commands), commands)
+	}
+	if commands[0][0] != "ffplay" || filepath.Base(commands[0][len(commands[0])-1]) != "tone.wav" {
+		t.Errorf("first command = %v", commands[0])
+	}
+
+	if _, err := (&Player{platform: PlatformUnknown}).CheckPlayers(context.Background()); err == nil {
+		t.Error("CheckPlayers() on an unknown platform should fail")
+	}
+}
+
+func TestRankPlayers(t *testing.T) {
+	runner := &brokenRunner{MockRunner: NewMockRunner(nil), missing: map[string]bool{"mpv": true, "setpriv": true}}
+	player := &Player{platform: PlatformLinux}
+	player.SetRunner(runner)
+
+	order, err := player.RankPlayers(context.Background())
+	if err != nil {
+		t.Fatalf("RankPlayers() error = %v", err)
+	}
+	// Mock playback takes no time, so the default order is kept
+	if want := []string{"paplay", "aplay", "ffplay"}; !slices.Equal(order, want) {
+		t.Errorf("RankPlayers() = %v, want %v", order, want)
+	}
+
+	player.SetRunner(NewMockRunner(nil))
+	if order, err := player.RankPlayers(context.Background()); err != nil || order != nil {
+		t.Errorf("RankPlayers() with the mock backend = %v, %v; want none", order, err)
+	}
+}
+
+func TestRankChecks(t *testing.T) {
+	checks := []PlayerCheck{
+		{Name: "mpv", Installed: true, Latency: 180 * time.Millisecond},
+		{Name: "paplay", Installed: true, Latency: 40 * time.Millisecond},
+		{Name: "aplay"},
+		{Name: "ffplay", Installed: true, Err: errors.New("exit status 1")},
+		{Name: "aucat", Installed: true, Latency: 40*time.Millisecond + 200*time.Microsecond},
+	}
+	if got, want := RankChecks(checks), []string{"paplay", "aucat", "mpv"}; !slices.Equal(got, want) {
+		t.Errorf("RankChecks() = %v, want %v", got, want)
+	}
+}
+
+func TestOrderPlayers(t *testing.T) {
+	tests := []struct {
+		name      string
+		preferred []string
+		want      []string
+	}{
+		{"default", nil, linuxAudioPlayerNames},
+		{"one preferred", []string{"aplay"}, []string{"aplay", "mpv", "paplay", "ffplay"}},
+		{"several", []string{"ffplay", "paplay"}, []string{"ffplay", "paplay", "mpv", "aplay"}},
+		{"other platform's player skipped", []string{"aucat", "aplay", "aplay"}, []string{"aplay", "mpv", "paplay", "ffplay"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Player{}
+			p.SetPlayerOrder(tt.preferred)
+			if got := p.orderPlayers(linuxAudioPlayerNames); !slices.Equal(got, tt.want) {
+				t.Errorf("orderPlayers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteTone(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-tone-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	path := filepath.Join(tmpDir, "tone.wav")
+	if err := writeTone(path, 440, 250*time.Millisecond, 0.3); err != nil {
+		t.Fatalf("writeTone() error = %v", err)
+	}
+	got, err := wavDuration(path)
+	if err != nil {
+		t.Fatalf("wavDuration() error = %v", err)
+	}
+	if got != 250*time.Millisecond {
+		t.Errorf("duration = %v, want 250ms", got)
+	}
+}
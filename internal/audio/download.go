@@ -0,0 +1,140 @@
+package audio
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultMaxDownloadSize caps url: sound downloads when no size limit is set.
+const DefaultMaxDownloadSize = 10 * 1024 * 1024
+
+// downloadClient fetches url: sounds. Replaceable in tests.
+var downloadClient = &http.Client{Timeout: 10 * time.Second}
+
+// SetDownloadDir sets the directory caching url: sounds. Downloads are
+// disabled when dir is empty.
+func (p *Player) SetDownloadDir(dir string) {
+	p.downloadDir = dir
+}
+
+// resolveURLSound returns the cached copy of an https sound, downloading it
+// on first use. A "#sha256=<hex>" fragment pins the expected content.
+func (p *Player) resolveURLSound(ctx context.Context, rawURL string) (string, error) {
+	if p.downloadDir == "" {
+		return "", errors.New("url sounds need a download cache directory")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid sound url: %s", rawURL)
+	}
+	if u.Scheme != "https" {
+		return "", fmt.Errorf("sound url must use https: %s", rawURL)
+	}
+	ext := strings.ToLower(path.Ext(u.Path))
+	if !slices.Contains(searchPathExtensions, ext) {
+		return "", fmt.Errorf("unsupported sound format in url: %s", rawURL)
+	}
+	var wantHash string
+	if u.Fragment != "" {
+		hash, ok := strings.CutPrefix(u.Fragment, "sha256=")
+		if !ok || len(hash) != sha256.Size*2 {
+			return "", fmt.Errorf("url fragment must be sha256=<hex>: %s", rawURL)
+		}
+		wantHash = strings.ToLower(hash)
+		u.Fragment = ""
+	}
+
+	// Cache by URL (and pinned hash) so each sound downloads once
+	key := sha256.Sum256([]byte(u.String() + "#" + wantHash))
+	cached := filepath.Join(p.downloadDir, hex.EncodeToString(key[:])+ext)
+	if info, err := os.Stat(cached); err != nil || info.Size() == 0 {
+		if err := p.download(ctx, u.String(), cached, wantHash); err != nil {
+			return "", err
+		}
+	}
+
+	if err := p.checkLimits(cached); err != nil {
+		return "", err
+	}
+	return cached, nil
+}
+
+// httpsOnly is the download redirect policy: http's default, except that
+// every hop must stay on https.
+func httpsOnly(req *http.Request, via []*http.Request) error {
+	if req.URL.Scheme != "https" {
+		return fmt.Errorf("sound url redirected to non-https %s", req.URL.Redacted())
+	}
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	return nil
+}
+
+// download fetches rawURL into dest, enforcing the size limit and verifying
+// the content hash when wantHash is set.
+func (p *Player) download(ctx context.Context, rawURL, dest, wantHash string) error {
+	maxSize := p.limits.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxDownloadSize
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid sound url: %w", err)
+	}
+	client := *downloadClient
+	client.CheckRedirect = httpsOnly
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download sound: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download sound: status %d", resp.StatusCode)
+	}
+	if resp.ContentLength > maxSize {
+		return fmt.Errorf("%w: %s is %d KB (max %d KB)", ErrLimitExceeded, rawURL, resp.ContentLength/1024, maxSize/1024)
+	}
+
+	if err := os.MkdirAll(p.downloadDir, 0750); err != nil {
+		return fmt.Errorf("failed to create download cache: %w", err)
+	}
+	tmp, err := os.CreateTemp(p.downloadDir, "download-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(resp.Body, maxSize+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to download sound: %w", err)
+	}
+	if n > maxSize {
+		return fmt.Errorf("%w: %s is larger than %d KB", ErrLimitExceeded, rawURL, maxSize/1024)
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); wantHash != "" && got != wantHash {
+		return fmt.Errorf("sound checksum mismatch for %s: got %s", rawURL, got)
+	}
+
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return fmt.Errorf("failed to cache sound: %w", err)
+	}
+	return nil
+}
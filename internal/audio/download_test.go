@@ -0,0 +1,151 @@
+package audio
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// newDownloadServer serves body over TLS and counts requests.
+func newDownloadServer(t *testing.T, body []byte, requests *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		*requests++
+		w.Write(body)
+	}))
+	orig := downloadClient
+	downloadClient = server.Client()
+	t.Cleanup(func() {
+		downloadClient = orig
+		server.Close()
+	})
+	return server
+}
+
+func TestResolveURLSound(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-download-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	body := []byte("ID3 fake mp3 data")
+	var requests int
+	server := newDownloadServer(t, body, &requests)
+
+	player := &Player{platform: PlatformLinux}
+	player.SetDownloadDir(tmpDir)
+
+	spec := "url:" + server.URL + "/ding.mp3"
+	first, err := player.ResolveSoundPath(spec, "stop")
+	if err != nil {
+		t.Fatalf("ResolveSoundPath() error = %v", err)
+	}
+	data, err := os.ReadFile(first)
+	if err != nil || string(data) != string(body) {
+		t.Fatalf("cached file = %q, %v", data, err)
+	}
+
+	second, err := player.ResolveSoundPath(spec, "stop")
+	if err != nil || second != first {
+		t.Errorf("second resolve = %q, %v, want cached %q", second, err, first)
+	}
+	if requests != 1 {
+		t.Errorf("downloaded %d times, want 1", requests)
+	}
+
+	sum := sha256.Sum256(body)
+	if _, err := player.ResolveSoundPath(spec+"#sha256="+hex.EncodeToString(sum[:]), "stop"); err != nil {
+		t.Errorf("pinned checksum error = %v", err)
+	}
+	bad := sha256.Sum256([]byte("other"))
+	if _, err := player.ResolveSoundPath(spec+"#sha256="+hex.EncodeToString(bad[:]), "stop"); err == nil {
+		t.Error("expected checksum mismatch error")
+	}
+}
+
+func TestResolveURLSoundErrors(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-download-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var requests int
+	server := newDownloadServer(t, make([]byte, 4096), &requests)
+
+	player := &Player{platform: PlatformLinux}
+	player.SetDownloadDir(tmpDir)
+	player.SetLimits(Limits{MaxSize: 1024})
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"plain http", "http://example.com/ding.mp3"},
+		{"unsupported format", server.URL + "/ding.exe"},
+		{"bad fragment", server.URL + "/ding.mp3#md5=abc"},
+		{"too large", server.URL + "/big.wav"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := player.ResolveSoundPath("url:"+tt.url, "stop"); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	_, err = player.ResolveSoundPath("url:"+server.URL+"/big.wav", "stop")
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("oversized download error = %v, want ErrLimitExceeded", err)
+	}
+	if entries, _ := os.ReadDir(tmpDir); len(entries) != 0 {
+		t.Errorf("failed downloads left files behind: %v", entries)
+	}
+
+	if _, err := (&Player{}).ResolveSoundPath("url:"+server.URL+"/ding.mp3", "stop"); err == nil {
+		t.Error("expected error without a download directory")
+	}
+}
+
+func TestResolveURLSoundRedirectAndCancel(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-download-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ID3 fake mp3 data"))
+	}))
+	defer plain.Close()
+	var requests int
+	server := newDownloadServer(t, []byte("ID3 fake mp3 data"), &requests)
+	redirect := httptest.NewTLSServer(http.RedirectHandler(plain.URL+"/ding.mp3", http.StatusFound))
+	defer redirect.Close()
+	downloadClient = redirect.Client()
+
+	player := &Player{platform: PlatformLinux}
+	player.SetDownloadDir(tmpDir)
+
+	if _, err := player.ResolveSoundPath("url:"+redirect.URL+"/ding.mp3", "stop"); err == nil || !strings.Contains(err.Error(), "non-https") {
+		t.Errorf("https to http redirect error = %v, want refused", err)
+	}
+
+	downloadClient = server.Client()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := player.ResolveSoundPathContext(ctx, "url:"+server.URL+"/ding.mp3", "stop"); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled download error = %v, want context.Canceled", err)
+	}
+	if requests != 0 {
+		t.Errorf("canceled download made %d requests", requests)
+	}
+}
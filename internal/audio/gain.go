@@ -0,0 +1,42 @@
+package audio
+
+import "math"
+
+// Gain limits in dB, so a misconfigured value can't blast or mute a sound.
+const (
+	MinGain = -40.0
+	MaxGain = 20.0
+)
+
+// SetGain sets a loudness adjustment in dB applied on top of the volume,
+// e.g. to even out sounds mastered at different levels.
+func (p *Player) SetGain(db float64) {
+	p.gain = math.Max(MinGain, math.Min(MaxGain, db))
+}
+
+// gainFactor converts a dB adjustment to a linear amplitude factor.
+func gainFactor(db float64) float64 {
+	return math.Pow(10, db/20)
+}
+
+// SetMaxVolume caps the loudness of every sound played: the volume and any
+// positive gain are reduced so the volume with gain applied stays within
+// max (0.0-1.0).
+func (p *Player) SetMaxVolume(max float64) {
+	p.maxVolume = math.Max(0, math.Min(1, max))
+	p.capVolume = true
+}
+
+// capLoudness applies the maximum volume to a playback volume and the
+// player's gain.
+func (p *Player) capLoudness(volume float64) float64 {
+	if !p.capVolume {
+		return volume
+	}
+	volume = math.Min(volume, p.maxVolume)
+	if p.gain > 0 && volume*gainFactor(p.gain) > p.maxVolume {
+		// volume is now at most maxVolume, so the gain stays non-negative
+		p.gain = 20 * math.Log10(p.maxVolume/volume)
+	}
+	return volume
+}
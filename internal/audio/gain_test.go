@@ -0,0 +1,97 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSetGain(t *testing.T) {
+	tests := []struct {
+		db   float64
+		want float64
+	}{
+		{-6, -6},
+		{-100, MinGain},
+		{50, MaxGain},
+	}
+
+	for _, tt := range tests {
+		p := &Player{}
+		p.SetGain(tt.db)
+		if p.gain != tt.want {
+			t.Errorf("SetGain(%v) = %v, want %v", tt.db, p.gain, tt.want)
+		}
+	}
+}
+
+func TestPlayMacOSWithGain(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-gain-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	sound := filepath.Join(tmpDir, "stop.aiff")
+	if err := os.WriteFile(sound, []byte("FORM"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewMockRunner(&bytes.Buffer{})
+	player := &Player{platform: PlatformMacOS}
+	player.SetRunner(runner)
+	player.SetGain(6)
+
+	if err := player.Play(context.Background(), sound, 0.5); err != nil {
+		t.Fatalf("Play() error = %v", err)
+	}
+	want := []string{"afplay", "-v", "1.00", sound}
+	if commands := runner.Commands(); len(commands) != 1 || !slices.Equal(commands[0], want) {
+		t.Errorf("commands = %v, want %v", commands, want)
+	}
+}
+
+func TestPlayWithMaxVolume(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-gain-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	sound := filepath.Join(tmpDir, "stop.aiff")
+	if err := os.WriteFile(sound, []byte("FORM"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		volume float64
+		gain   float64
+		want   string
+	}{
+		{"volume capped", 1.0, 0, "0.40"},
+		{"gain reduced", 0.3, 12, "0.40"},
+		{"quiet sound unchanged", 0.2, 0, "0.20"},
+		{"negative gain kept", 0.8, -6, "0.20"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := NewMockRunner(&bytes.Buffer{})
+			player := &Player{platform: PlatformMacOS}
+			player.SetRunner(runner)
+			player.SetGain(tt.gain)
+			player.SetMaxVolume(0.4)
+
+			if err := player.Play(context.Background(), sound, tt.volume); err != nil {
+				t.Fatalf("Play() error = %v", err)
+			}
+			commands := runner.Commands()
+			if len(commands) != 1 || commands[0][2] != tt.want {
+				t.Errorf("commands = %v, want volume %s", commands, tt.want)
+			}
+		})
+	}
+}
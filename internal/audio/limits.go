@@ -0,0 +1,197 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrLimitExceeded is returned when a sound file exceeds the configured limits.
+var ErrLimitExceeded = errors.New("sound limit exceeded")
+
+// Limits constrains user-supplied sound files. Zero values disable a check.
+type Limits struct {
+	MaxSize     int64         // Maximum file size in bytes
+	MaxDuration time.Duration // Maximum playback duration
+}
+
+// SetLimits sets the size/duration limits applied to user-supplied sounds.
+func (p *Player) SetLimits(limits Limits) {
+	p.limits = limits
+}
+
+// checkLimits returns an error if the file exceeds the configured limits.
+// Duration is checked only when it can be determined (WAV/AIFF headers, or
+// ffprobe for other formats).
+func (p *Player) checkLimits(path string) error {
+	if p.limits.MaxSize > 0 {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("sound not accessible: %s", path)
+		}
+		if info.Size() > p.limits.MaxSize {
+			return fmt.Errorf("%w: %s is %d KB (max %d KB)",
+				ErrLimitExceeded, path, info.Size()/1024, p.limits.MaxSize/1024)
+		}
+	}
+
+	if p.limits.MaxDuration > 0 {
+		duration, err := soundDuration(p.cmdRunner(), path)
+		if err != nil {
+			return nil // Unknown duration, don't block
+		}
+		if duration > p.limits.MaxDuration {
+			return fmt.Errorf("%w: %s is %.1fs long (max %.0fs)",
+				ErrLimitExceeded, path, duration.Seconds(), p.limits.MaxDuration.Seconds())
+		}
+	}
+
+	return nil
+}
+
+// soundDuration determines the playback duration of a sound file.
+func soundDuration(r Runner, path string) (time.Duration, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".wav":
+		return wavDuration(path)
+	case ".aiff", ".aif":
+		return aiffDuration(path)
+	default:
+		return ffprobeDuration(r, path)
+	}
+}
+
+// wavDuration reads the duration from a RIFF/WAVE header.
+func wavDuration(path string) (time.Duration, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var header [12]byte
+	if _, err := io.ReadFull(f, header[:]); err != nil {
+		return 0, err
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return 0, errors.New("not a WAV file")
+	}
+
+	var byteRate uint32
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(f, chunk[:]); err != nil {
+			return 0, err
+		}
+		id := string(chunk[0:4])
+		size := binary.LittleEndian.Uint32(chunk[4:8])
+
+		switch id {
+		case "fmt ":
+			var fmtData [12]byte
+			if size < 12 {
+				return 0, errors.New("invalid WAV fmt chunk")
+			}
+			if _, err := io.ReadFull(f, fmtData[:]); err != nil {
+				return 0, err
+			}
+			byteRate = binary.LittleEndian.Uint32(fmtData[8:12])
+			if _, err := f.Seek(int64(size-12+size%2), io.SeekCurrent); err != nil {
+				return 0, err
+			}
+		case "data":
+			if byteRate == 0 {
+				return 0, errors.New("WAV data before fmt chunk")
+			}
+			return time.Duration(float64(size) / float64(byteRate) * float64(time.Second)), nil
+		default:
+			if _, err := f.Seek(int64(size+size%2), io.SeekCurrent); err != nil {
+				return 0, err
+			}
+		}
+	}
+}
+
+// aiffDuration reads the duration from an AIFF COMM chunk.
+func aiffDuration(path string) (time.Duration, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var header [12]byte
+	if _, err := io.ReadFull(f, header[:]); err != nil {
+		return 0, err
+	}
+	if string(header[0:4]) != "FORM" || (string(header[8:12]) != "AIFF" && string(header[8:12]) != "AIFC") {
+		return 0, errors.New("not an AIFF file")
+	}
+
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(f, chunk[:]); err != nil {
+			return 0, err
+		}
+		id := string(chunk[0:4])
+		size := binary.BigEndian.Uint32(chunk[4:8])
+
+		if id != "COMM" {
+			if _, err := f.Seek(int64(size+size%2), io.SeekCurrent); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		var comm [18]byte
+		if _, err := io.ReadFull(f, comm[:]); err != nil {
+			return 0, err
+		}
+		frames := binary.BigEndian.Uint32(comm[2:6])
+		rate := extendedToFloat(comm[8:18])
+		if rate <= 0 {
+			return 0, errors.New("invalid AIFF sample rate")
+		}
+		return time.Duration(float64(frames) / rate * float64(time.Second)), nil
+	}
+}
+
+// extendedToFloat converts an 80-bit IEEE 754 extended float (as used by AIFF
+// sample rates) to float64.
+func extendedToFloat(b []byte) float64 {
+	exponent := int(binary.BigEndian.Uint16(b[0:2]) & 0x7FFF)
+	mantissa := binary.BigEndian.Uint64(b[2:10])
+	if exponent == 0 && mantissa == 0 {
+		return 0
+	}
+	value := float64(mantissa) * math.Pow(2, float64(exponent-16383-63))
+	if b[0]&0x80 != 0 {
+		value = -value
+	}
+	return value
+}
+
+// ffprobeDuration asks ffprobe (if installed) for the duration.
+func ffprobeDuration(r Runner, path string) (time.Duration, error) {
+	if _, err := r.LookPath("ffprobe"); err != nil {
+		return 0, errors.New("ffprobe not available")
+	}
+	out, err := r.Output(exec.Command("ffprobe", "-v", "error", "-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1", path))
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe failed: %w", err)
+	}
+	secs, err := strconv.ParseFloat(strings.TrimSpace(string(out)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected ffprobe output: %w", err)
+	}
+	return time.Duration(secs * float64(time.Second)), nil
+}
@@ -0,0 +1,144 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestWAV writes a mono 8-bit WAV of the given duration at 8 kHz.
+func writeTestWAV(t *testing.T, path string, seconds int) {
+	t.Helper()
+	const rate = 8000
+	dataSize := uint32(rate * seconds)
+
+	buf := make([]byte, 0, 44+dataSize)
+	buf = append(buf, "RIFF"...)
+	buf = binary.LittleEndian.AppendUint32(buf, 36+dataSize)
+	buf = append(buf, "WAVE"...)
+	buf = append(buf, "fmt "...)
+	buf = binary.LittleEndian.AppendUint32(buf, 16)
+	buf = binary.LittleEndian.AppendUint16(buf, 1)    // PCM
+	buf = binary.LittleEndian.AppendUint16(buf, 1)    // channels
+	buf = binary.LittleEndian.AppendUint32(buf, rate) // sample rate
+	buf = binary.LittleEndian.AppendUint32(buf, rate) // byte rate
+	buf = binary.LittleEndian.AppendUint16(buf, 1)    // block align
+	buf = binary.LittleEndian.AppendUint16(buf, 8)    // bits per sample
+	buf = append(buf, "data"...)
+	buf = binary.LittleEndian.AppendUint32(buf, dataSize)
+	buf = append(buf, make([]byte, dataSize)...)
+
+	if err := os.WriteFile(path, buf, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeTestAIFF writes an AIFF header describing the given number of frames
+// at 44.1 kHz (no sample data is needed to read the duration).
+func writeTestAIFF(t *testing.T, path string, frames uint32) {
+	t.Helper()
+	buf := make([]byte, 0, 38)
+	buf = append(buf, "FORM"...)
+	buf = binary.BigEndian.AppendUint32(buf, 30)
+	buf = append(buf, "AIFF"...)
+	buf = append(buf, "COMM"...)
+	buf = binary.BigEndian.AppendUint32(buf, 18)
+	buf = binary.BigEndian.AppendUint16(buf, 1) // channels
+	buf = binary.BigEndian.AppendUint32(buf, frames)
+	buf = binary.BigEndian.AppendUint16(buf, 16) // sample size
+	// 44100 as 80-bit extended: exponent 16383+15, mantissa 44100<<48
+	buf = binary.BigEndian.AppendUint16(buf, 16383+15)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(44100)<<48)
+
+	if err := os.WriteFile(path, buf, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSoundDuration(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-duration-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	wav := filepath.Join(tempDir, "two.wav")
+	writeTestWAV(t, wav, 2)
+	got, err := soundDuration(execRunner{}, wav)
+	if err != nil {
+		t.Fatalf("wav duration error: %v", err)
+	}
+	if got != 2*time.Second {
+		t.Errorf("wav duration = %v, want 2s", got)
+	}
+
+	aiff := filepath.Join(tempDir, "three.aiff")
+	writeTestAIFF(t, aiff, 3*44100)
+	got, err = soundDuration(execRunner{}, aiff)
+	if err != nil {
+		t.Fatalf("aiff duration error: %v", err)
+	}
+	if math.Abs(got.Seconds()-3) > 0.001 {
+		t.Errorf("aiff duration = %v, want 3s", got)
+	}
+
+	bogus := filepath.Join(tempDir, "bogus.wav")
+	if err := os.WriteFile(bogus, []byte("not audio"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := soundDuration(execRunner{}, bogus); err == nil {
+		t.Error("expected error for invalid WAV")
+	}
+}
+
+func TestCheckLimits(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-limits-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	short := filepath.Join(tempDir, "short.wav")
+	long := filepath.Join(tempDir, "long.wav")
+	writeTestWAV(t, short, 1)
+	writeTestWAV(t, long, 5)
+
+	player := NewPlayer("")
+
+	t.Run("no limits", func(t *testing.T) {
+		if err := player.checkLimits(long); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("duration limit", func(t *testing.T) {
+		player.SetLimits(Limits{MaxDuration: 3 * time.Second})
+		if err := player.checkLimits(short); err != nil {
+			t.Errorf("short sound should pass: %v", err)
+		}
+		if err := player.checkLimits(long); !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("long sound error = %v, want ErrLimitExceeded", err)
+		}
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		player.SetLimits(Limits{MaxSize: 16 * 1024})
+		if err := player.checkLimits(short); err != nil {
+			t.Errorf("small sound should pass: %v", err)
+		}
+		if err := player.checkLimits(long); !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("large sound error = %v, want ErrLimitExceeded", err)
+		}
+	})
+
+	t.Run("custom sound resolution enforces limits", func(t *testing.T) {
+		player.SetLimits(Limits{MaxDuration: 3 * time.Second})
+		if _, err := player.ResolveSoundPath("custom:"+long, "stop"); !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("ResolveSoundPath error = %v, want ErrLimitExceeded", err)
+		}
+	})
+}
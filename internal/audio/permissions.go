@@ -0,0 +1,59 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// PermissionPolicy controls which custom sound files may be played, based
+// on their owner and mode. Hooks run with the user's privileges, so a sound
+// file others can replace is a way to feed crafted input to the player.
+type PermissionPolicy string
+
+// Permission policies applied to custom and pack sounds.
+const (
+	PermissionsOff              PermissionPolicy = "off"                // No checks (default)
+	PermissionsNotWorldWritable PermissionPolicy = "not-world-writable" // Reject files anyone can write
+	PermissionsOwner            PermissionPolicy = "owner"              // Also require the current user or root to own the file
+)
+
+// ErrUnsafePermissions is returned when a sound file violates the
+// permission policy.
+var ErrUnsafePermissions = errors.New("unsafe sound file permissions")
+
+// currentUID is the user sound files must belong to; replaceable in tests.
+var currentUID = os.Getuid
+
+// SetPermissionPolicy sets the permission policy. An empty policy means
+// PermissionsOff.
+func (p *Player) SetPermissionPolicy(policy PermissionPolicy) {
+	p.permissions = policy
+}
+
+// checkPermissions checks the file at path, following symlinks, against the
+// permission policy.
+func (p *Player) checkPermissions(path string) error {
+	if p.permissions == "" || p.permissions == PermissionsOff {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Mode().Perm()&0002 != 0 {
+		return fmt.Errorf("%w: %s is world-writable", ErrUnsafePermissions, path)
+	}
+	if p.permissions != PermissionsOwner {
+		return nil
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fmt.Errorf("%w: can't read the owner of %s", ErrUnsafePermissions, path)
+	}
+	if uid := int(stat.Uid); uid != currentUID() && uid != 0 {
+		return fmt.Errorf("%w: %s is owned by uid %d", ErrUnsafePermissions, path, uid)
+	}
+	return nil
+}
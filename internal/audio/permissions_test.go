@@ -0,0 +1,68 @@
+package audio
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckPermissions(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-permissions-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	private := filepath.Join(tempDir, "private.wav")
+	shared := filepath.Join(tempDir, "shared.wav")
+	for _, f := range []string{private, shared} {
+		if err := os.WriteFile(f, []byte("RIFF"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Chmod, since the umask may clear the world-writable bit
+	if err := os.Chmod(shared, 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	origUID := currentUID
+	defer func() { currentUID = origUID }()
+
+	tests := []struct {
+		name    string
+		policy  PermissionPolicy
+		path    string
+		uid     int
+		wantErr bool
+	}{
+		{"default allows world-writable", "", shared, os.Getuid(), false},
+		{"off allows world-writable", PermissionsOff, shared, os.Getuid(), false},
+		{"world-writable rejected", PermissionsNotWorldWritable, shared, os.Getuid(), true},
+		{"private allowed", PermissionsNotWorldWritable, private, os.Getuid(), false},
+		{"owned by current user", PermissionsOwner, private, os.Getuid(), false},
+		{"owned by another user", PermissionsOwner, private, os.Getuid() + 1, os.Getuid() != 0},
+		{"owner still rejects world-writable", PermissionsOwner, shared, os.Getuid(), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			currentUID = func() int { return tt.uid }
+			p := NewPlayer(tempDir)
+			p.SetPermissionPolicy(tt.policy)
+			err := p.checkPermissions(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkPermissions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrUnsafePermissions) {
+				t.Errorf("error = %v, want ErrUnsafePermissions", err)
+			}
+		})
+	}
+
+	p := NewPlayer(tempDir)
+	p.SetPermissionPolicy(PermissionsNotWorldWritable)
+	if _, err := p.ResolveSoundPath("custom:"+shared, "stop"); !errors.Is(err, ErrUnsafePermissions) {
+		t.Errorf("ResolveSoundPath(world-writable) error = %v", err)
+	}
+}
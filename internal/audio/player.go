@@ -2,14 +2,21 @@
 package audio
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Package managers and their install commands.
@@ -25,10 +32,10 @@ var packageManagers = map[string]string{
 
 // Packages to install for each audio player.
 var playerPackages = map[string]string{
-	"mpv":     "mpv",
-	"ffplay":  "ffmpeg",
-	"paplay":  "pulseaudio-utils",
-	"aplay":   "alsa-utils",
+	"mpv":    "mpv",
+	"ffplay": "ffmpeg",
+	"paplay": "pulseaudio-utils",
+	"aplay":  "alsa-utils",
 }
 
 // Platform represents the detected operating system.
@@ -38,36 +45,95 @@ type Platform string
 const (
 	PlatformMacOS   Platform = "macos" // Apple macOS
 	PlatformLinux   Platform = "linux" // Linux
+	PlatformBSD     Platform = "bsd"   // FreeBSD, OpenBSD, NetBSD, DragonFly BSD
 	PlatformUnknown Platform = "unknown"
 )
 
 // linuxAudioPlayerNames is the list of audio players checked on Linux (priority order).
 var linuxAudioPlayerNames = []string{"mpv", "paplay", "aplay", "ffplay"}
 
-// getLinuxPlayerArgs returns arguments for a Linux audio player.
-func getLinuxPlayerArgs(playerName, soundPath string, volume float64) []string {
+// bsdAudioPlayerNames is the list of audio players checked on the BSDs
+// (priority order): mpv and ffplay output through OSS (/dev/dsp) or sndio,
+// and aucat is sndio's own player, in the OpenBSD base system.
+var bsdAudioPlayerNames = []string{"mpv", "ffplay", "aucat"}
+
+// getLinuxPlayerArgs returns arguments for a Linux or BSD audio player, applying
+// effects (see effectArgs) where the player supports them.
+func getLinuxPlayerArgs(playerName, soundPath string, volume, gain, rate float64, pitch bool) []string {
 	volPercent := int(volume * 100)
+	var args []string
 	switch playerName {
 	case "paplay":
-		return []string{soundPath}
 	case "aplay":
-		return []string{"-q", soundPath}
+		args = []string{"-q"}
 	case "mpv":
-		return []string{"--really-quiet", fmt.Sprintf("--volume=%d", volPercent), soundPath}
+		args = []string{"--really-quiet", fmt.Sprintf("--volume=%d", volPercent)}
 	case "ffplay":
-		return []string{"-nodisp", "-autoexit", "-volume", fmt.Sprintf("%d", volPercent), soundPath}
+		args = []string{"-nodisp", "-autoexit", "-volume", fmt.Sprintf("%d", volPercent)}
+	case "aucat":
+		args = []string{"-v", strconv.Itoa(aucatVolume(volume)), "-i"}
 	default:
 		return nil
 	}
+	args = append(args, effectArgs(playerName, gain, rate, pitch)...)
+	return append(args, soundPath)
+}
+
+// aucatVolume converts a volume (0.0-1.0) to aucat's -v attenuation: 1-127
+// in 1/3 dB steps, 127 being full volume.
+func aucatVolume(volume float64) int {
+	if volume <= 0 {
+		return 1
+	}
+	steps := 127 + int(math.Round(60*math.Log10(volume)))
+	return max(1, min(127, steps))
 }
 
 // bundledSoundNameRegex validates bundled sound names.
 var bundledSoundNameRegex = regexp.MustCompile(`^[a-z_]+$`)
 
+// systemSoundNameRegex validates system sound names (e.g. "Glass", "complete").
+var systemSoundNameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// systemSoundDirs lists the OS sound directories for each platform.
+var systemSoundDirs = map[Platform][]string{
+	PlatformMacOS: {"/System/Library/Sounds"},
+	PlatformLinux: {"/usr/share/sounds/freedesktop/stereo"},
+	PlatformBSD:   {"/usr/local/share/sounds/freedesktop/stereo", "/usr/pkg/share/sounds/freedesktop/stereo"},
+}
+
+// systemSoundExtensions are the file extensions tried for system sounds.
+var systemSoundExtensions = []string{".aiff", ".oga", ".ogg", ".wav"}
+
+// searchPathExtensions are the file extensions tried (in order) when looking up
+// a bundled-style sound name in the user-configured search paths.
+var searchPathExtensions = []string{".aiff", ".wav", ".mp3", ".ogg", ".flac", ".m4a"}
+
 // Player handles audio playback.
 type Player struct {
-	platform   Platform
-	pluginRoot string
+	platform      Platform
+	pluginRoot    string
+	searchPaths   []string
+	cacheDir      string
+	downloadDir   string
+	limits        Limits
+	symlinkPolicy SymlinkPolicy
+	permissions   PermissionPolicy
+	allowedDirs   []string
+	players       []string // Preferred Linux and BSD players, tried first
+	timeout       time.Duration
+	gain          float64 // dB
+	maxVolume     float64
+	capVolume     bool // Whether maxVolume applies
+	variation     float64
+	variationMode string
+	variationStep int // Fixed step plus one; 0 for random variation
+	afplayRate    float64
+	afplayQuality int // -q value plus one; 0 for afplay's default
+	autoInstall   bool
+	confirm       func(question string) bool
+	dryRun        io.Writer // Where install commands are described instead of run
+	runner        Runner
 }
 
 // NewPlayer creates a new audio player.
@@ -75,23 +141,44 @@ func NewPlayer(pluginRoot string) *Player {
 	return &Player{
 		platform:   detectPlatform(),
 		pluginRoot: pluginRoot,
+		timeout:    DefaultPlayerTimeout,
+		runner:     defaultRunner(),
 	}
 }
 
+// SetSearchPaths sets additional directories consulted for bundled-style
+// sound names after the plugin's own sounds directory.
+func (p *Player) SetSearchPaths(paths []string) {
+	p.searchPaths = paths
+}
+
 // detectPlatform determines the current platform.
 func detectPlatform() Platform {
-	switch runtime.GOOS {
+	return platformFor(runtime.GOOS)
+}
+
+// platformFor returns the platform for a GOOS value.
+func platformFor(goos string) Platform {
+	switch goos {
 	case "darwin":
 		return PlatformMacOS
 	case "linux":
 		return PlatformLinux
+	case "freebsd", "openbsd", "netbsd", "dragonfly":
+		return PlatformBSD
 	default:
 		return PlatformUnknown
 	}
 }
 
-// Play plays a sound file at the specified volume (0.0-1.0).
-func (p *Player) Play(soundPath string, volume float64) error {
+// Play starts playing a sound file at the specified volume (0.0-1.0) and
+// returns without waiting for playback to finish. The player is stopped if
+// ctx is done first; pass a context that outlives the call for
+// fire-and-forget playback.
+func (p *Player) Play(ctx context.Context, soundPath string, volume float64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	if soundPath == "" {
 		return errors.New("no sound path specified")
 	}
@@ -99,12 +186,15 @@ func (p *Player) Play(soundPath string, volume float64) error {
 	if _, err := os.Stat(soundPath); os.IsNotExist(err) {
 		return fmt.Errorf("sound file not found: %s", soundPath)
 	}
+	volume = p.capLoudness(volume)
 
 	switch p.platform {
 	case PlatformMacOS:
-		return p.playMacOS(soundPath, volume)
+		return p.playMacOS(ctx, soundPath, volume)
 	case PlatformLinux:
-		return p.playLinux(soundPath, volume)
+		return p.playLinux(ctx, soundPath, volume)
+	case PlatformBSD:
+		return p.playBSD(ctx, soundPath, volume)
 	case PlatformUnknown:
 		return fmt.Errorf("unsupported platform: %s", p.platform)
 	default:
@@ -112,31 +202,97 @@ func (p *Player) Play(soundPath string, volume float64) error {
 	}
 }
 
-// playMacOS uses afplay on macOS.
-func (p *Player) playMacOS(soundPath string, volume float64) error {
-	cmd := exec.Command("afplay", "-v", fmt.Sprintf("%.2f", volume), soundPath)
-	return cmd.Start() // Non-blocking
+// playMacOS uses afplay on macOS. When afplay is missing or can't be
+// started, as in some hardened setups, it plays the system alert sound
+// through osascript instead, so there is always something audible.
+func (p *Player) playMacOS(ctx context.Context, soundPath string, volume float64) error {
+	if _, err := p.cmdRunner().LookPath("afplay"); err != nil {
+		return p.beepMacOS(ctx, fmt.Errorf("afplay not found: %w", err))
+	}
+	soundPath = p.transcodeIfNeeded(ctx, "afplay", soundPath)
+	args := []string{"-v", fmt.Sprintf("%.2f", volume*gainFactor(p.gain))}
+	args = append(args, p.afplayArgs()...)
+	cmd := p.command(ctx, "afplay", append(args, soundPath)...)
+	if err := p.start(cmd); err != nil {
+		return p.beepMacOS(ctx, fmt.Errorf("afplay failed: %w", err))
+	}
+	return nil
+}
+
+// beepMacOS plays the system alert sound with osascript after afplay
+// failed with afplayErr.
+func (p *Player) beepMacOS(ctx context.Context, afplayErr error) error {
+	if _, err := p.cmdRunner().LookPath("osascript"); err != nil {
+		return afplayErr
+	}
+	if err := p.start(p.command(ctx, "osascript", "-e", "beep")); err != nil {
+		return fmt.Errorf("%w; osascript beep failed: %v", afplayErr, err)
+	}
+	return nil
 }
 
 // playLinux tries available audio players on Linux.
-func (p *Player) playLinux(soundPath string, volume float64) error {
-	for _, playerName := range linuxAudioPlayerNames {
-		if _, err := exec.LookPath(playerName); err == nil {
-			args := getLinuxPlayerArgs(playerName, soundPath, volume)
-			cmd := exec.Command(playerName, args...)
-			return cmd.Start() // Non-blocking
+func (p *Player) playLinux(ctx context.Context, soundPath string, volume float64) error {
+	return p.playFirst(ctx, linuxAudioPlayerNames, soundPath, volume,
+		"no audio player found; install pulseaudio, alsa-utils, mpv, or ffmpeg")
+}
+
+// playBSD tries available audio players on the BSDs.
+func (p *Player) playBSD(ctx context.Context, soundPath string, volume float64) error {
+	return p.playFirst(ctx, bsdAudioPlayerNames, soundPath, volume,
+		"no audio player found; install mpv or ffmpeg, or use aucat (sndio)")
+}
+
+// playFirst plays the sound with the first installed player in names,
+// failing with notFound if none is.
+func (p *Player) playFirst(ctx context.Context, names []string, soundPath string, volume float64, notFound string) error {
+	for _, playerName := range p.orderPlayers(names) {
+		if _, err := p.cmdRunner().LookPath(playerName); err == nil {
+			soundPath = p.transcodeIfNeeded(ctx, playerName, soundPath)
+			args := getLinuxPlayerArgs(playerName, soundPath, volume, p.gain, p.playbackRate(), p.variationMode == VariationPitch)
+			cmd := p.command(ctx, playerName, args...)
+			return p.start(cmd)
 		}
 	}
+	return errors.New(notFound)
+}
 
-	return errors.New("no audio player found; install pulseaudio, alsa-utils, mpv, or ffmpeg")
+// SetPlayerOrder makes the named Linux and BSD players be tried first, in
+// the given order, before the rest in their default order.
+func (p *Player) SetPlayerOrder(names []string) {
+	p.players = names
+}
+
+// orderPlayers returns names with the preferred players among them first.
+func (p *Player) orderPlayers(names []string) []string {
+	ordered := make([]string, 0, len(names))
+	for _, name := range p.players {
+		if slices.Contains(names, name) && !slices.Contains(ordered, name) {
+			ordered = append(ordered, name)
+		}
+	}
+	for _, name := range names {
+		if !slices.Contains(ordered, name) {
+			ordered = append(ordered, name)
+		}
+	}
+	return ordered
 }
 
 // ResolveSoundPath resolves a sound specification to an absolute file path.
 // Supported formats:
 //   - bundled:stop (bundled with plugin)
+//   - system:Glass (OS sound, e.g. /System/Library/Sounds on macOS)
 //   - custom:/path/to/file.mp3
+//   - url:https://example.com/ding.mp3 (downloaded once and cached)
 //   - /absolute/path/to/file.mp3
 func (p *Player) ResolveSoundPath(soundSpec, eventType string) (string, error) {
+	return p.ResolveSoundPathContext(context.Background(), soundSpec, eventType)
+}
+
+// ResolveSoundPathContext is like ResolveSoundPath, with ctx canceling a
+// url: sound's download.
+func (p *Player) ResolveSoundPathContext(ctx context.Context, soundSpec, eventType string) (string, error) {
 	if soundSpec == "" {
 		soundSpec = fmt.Sprintf("bundled:%s", eventType)
 	}
@@ -145,9 +301,15 @@ func (p *Player) ResolveSoundPath(soundSpec, eventType string) (string, error) {
 	case strings.HasPrefix(soundSpec, "bundled:"):
 		return p.resolveBundledSound(strings.TrimPrefix(soundSpec, "bundled:"))
 
+	case strings.HasPrefix(soundSpec, "system:"):
+		return p.resolveSystemSound(strings.TrimPrefix(soundSpec, "system:"))
+
 	case strings.HasPrefix(soundSpec, "custom:"):
 		return p.resolveCustomSound(strings.TrimPrefix(soundSpec, "custom:"))
 
+	case strings.HasPrefix(soundSpec, "url:"):
+		return p.resolveURLSound(ctx, strings.TrimPrefix(soundSpec, "url:"))
+
 	default:
 		// Direct path - apply same security checks as custom
 		return p.resolveCustomSound(soundSpec)
@@ -171,39 +333,116 @@ func (p *Player) resolveCustomSound(path string) (string, error) {
 		return "", fmt.Errorf("custom sound not accessible: %s", path)
 	}
 
+	// Security: symlink policy. A custom sound's own directory is no trust
+	// root, so resolve-within-root keeps symlinks inside the allowed
+	// directory holding the sound, and without one follows them.
+	if root := p.customSymlinkRoot(path); root != "" || p.symlinkPolicy == SymlinkDeny {
+		resolved, err := p.applySymlinkPolicy(path, root)
+		if err != nil {
+			return "", err
+		}
+		path = resolved
+	}
+
+	// Security: allowed directories and owner and mode policy
+	if err := p.checkAllowedDir(path); err != nil {
+		return "", err
+	}
+	if err := p.checkPermissions(path); err != nil {
+		return "", err
+	}
+
+	if err := p.checkLimits(path); err != nil {
+		return "", err
+	}
+
 	return path, nil
 }
 
+// resolveSystemSound resolves an OS-provided sound by name.
+func (p *Player) resolveSystemSound(name string) (string, error) {
+	if !systemSoundNameRegex.MatchString(name) {
+		return "", fmt.Errorf("invalid system sound name: %s", name)
+	}
+
+	for _, dir := range systemSoundDirs[p.platform] {
+		for _, ext := range systemSoundExtensions {
+			path := filepath.Join(dir, name+ext)
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("system sound not found: %s", name)
+}
+
 // resolveBundledSound resolves a bundled sound name.
-// Uses os.Lstat to prevent symlink attacks.
+// The plugin's sounds directory is checked first, then each configured
+// search path in order. Uses os.Lstat and the symlink policy to prevent
+// symlink attacks.
 func (p *Player) resolveBundledSound(name string) (string, error) {
 	// Validate name (lowercase letters and underscores only)
 	if !bundledSoundNameRegex.MatchString(name) {
 		return "", fmt.Errorf("invalid bundled sound name: %s", name)
 	}
 
-	path := filepath.Join(p.pluginRoot, "sounds", name+".aiff")
+	soundsDir := filepath.Join(p.pluginRoot, "sounds")
+	path := filepath.Join(soundsDir, name+".aiff")
 	// Use Lstat to detect symlinks and prevent path traversal via symlinks
-	if _, err := os.Lstat(path); os.IsNotExist(err) {
-		return "", fmt.Errorf("bundled sound not found: %s", name)
+	if _, err := os.Lstat(path); err == nil {
+		return p.applySymlinkPolicy(path, soundsDir)
 	}
 
-	return path, nil
+	path, err := p.findInSearchPaths(name)
+	if err != nil {
+		return "", err
+	}
+	if path != "" {
+		if err := p.checkLimits(path); err != nil {
+			return "", err
+		}
+		return path, nil
+	}
+
+	return "", fmt.Errorf("bundled sound not found: %s", name)
+}
+
+// findInSearchPaths looks up a sound name in the configured search paths,
+// trying each supported extension. Returns an empty path if not found.
+func (p *Player) findInSearchPaths(name string) (string, error) {
+	for _, dir := range p.searchPaths {
+		for _, ext := range searchPathExtensions {
+			path := filepath.Join(dir, name+ext)
+			if _, err := os.Lstat(path); err != nil {
+				continue
+			}
+			resolved, err := p.applySymlinkPolicy(path, dir)
+			if err != nil {
+				return "", err
+			}
+			if info, err := os.Stat(resolved); err == nil && info.Mode().IsRegular() {
+				return resolved, nil
+			}
+		}
+	}
+	return "", nil
 }
 
 // GetFallbackPath returns a fallback sound path for the event type.
-// Uses Lstat to prevent symlink attacks.
+// Uses Lstat and the symlink policy to prevent symlink attacks.
 func (p *Player) GetFallbackPath(eventType string) string {
-	// Try bundled sound for this event
-	path := filepath.Join(p.pluginRoot, "sounds", eventType+".aiff")
-	if _, err := os.Lstat(path); err == nil {
-		return path
-	}
+	soundsDir := filepath.Join(p.pluginRoot, "sounds")
 
-	// Try bundled stop sound (always present)
-	path = filepath.Join(p.pluginRoot, "sounds", "stop.aiff")
-	if _, err := os.Lstat(path); err == nil {
-		return path
+	// Try bundled sound for this event, then bundled stop sound (always present)
+	for _, name := range []string{eventType, "stop"} {
+		path := filepath.Join(soundsDir, name+".aiff")
+		if _, err := os.Lstat(path); err != nil {
+			continue
+		}
+		if resolved, err := p.applySymlinkPolicy(path, soundsDir); err == nil {
+			return resolved
+		}
 	}
 
 	return ""
@@ -218,11 +457,19 @@ func (p *Player) Platform() Platform {
 func (p *Player) HasAudioPlayer() bool {
 	switch p.platform {
 	case PlatformMacOS:
-		_, err := exec.LookPath("afplay")
-		return err == nil
-	case PlatformLinux:
-		for _, player := range linuxAudioPlayerNames {
-			if _, err := exec.LookPath(player); err == nil {
+		for _, player := range []string{"afplay", "osascript"} {
+			if _, err := p.cmdRunner().LookPath(player); err == nil {
+				return true
+			}
+		}
+		return false
+	case PlatformLinux, PlatformBSD:
+		names := linuxAudioPlayerNames
+		if p.platform == PlatformBSD {
+			names = bsdAudioPlayerNames
+		}
+		for _, player := range names {
+			if _, err := p.cmdRunner().LookPath(player); err == nil {
 				return true
 			}
 		}
@@ -234,53 +481,117 @@ func (p *Player) HasAudioPlayer() bool {
 	}
 }
 
-// findPackageManager detects available package manager.
-func findPackageManager() string {
-	for pm := range packageManagers {
-		if _, err := exec.LookPath(pm); err == nil {
+// findPackageManager detects available package manager, checking them in
+// name order so the suggested install command is stable.
+func findPackageManager(r Runner) string {
+	for _, pm := range slices.Sorted(maps.Keys(packageManagers)) {
+		if _, err := r.LookPath(pm); err == nil {
 			return pm
 		}
 	}
 	return ""
 }
 
+// installCommand returns the shell command that installs player with pm.
+func installCommand(pm, player string) string {
+	return packageManagers[pm] + " " + playerPackages[player]
+}
+
 // installAudioPlayer attempts to install the specified audio player.
-func installAudioPlayer(player string) error {
-	pm := findPackageManager()
+func installAudioPlayer(r Runner, player string) error {
+	pm := findPackageManager(r)
 	if pm == "" {
 		return errors.New("no package manager found")
 	}
 
-	cmdStr := packageManagers[pm]
-	pkg := playerPackages[player]
-	if pkg == "" {
+	if playerPackages[player] == "" {
 		return fmt.Errorf("unknown player: %s", player)
 	}
 
-	cmd := exec.Command("sh", "-c", fmt.Sprintf("%s %s", cmdStr, pkg))
+	cmd := exec.Command("sh", "-c", installCommand(pm, player))
 	cmd.Stdout = nil
 	cmd.Stderr = nil
 
-	return cmd.Run()
+	return r.Run(cmd)
+}
+
+// MissingPlayerError reports that no Linux audio player is installed, with
+// the command that would install one.
+type MissingPlayerError struct {
+	PackageManager string // Detected package manager; "" if none was found
+	Player         string // Player the command installs
+	Command        string // Install command; "" if no package manager was found
+}
+
+func (e *MissingPlayerError) Error() string {
+	if e.Command == "" {
+		return "no audio player found; install mpv, ffmpeg, pulseaudio-utils, or alsa-utils"
+	}
+	return "no audio player found; install one with: " + e.Command
+}
+
+// missingPlayer describes how to install the preferred audio player.
+func missingPlayer(r Runner) *MissingPlayerError {
+	player := linuxAudioPlayerNames[0]
+	e := &MissingPlayerError{PackageManager: findPackageManager(r), Player: player}
+	if e.PackageManager != "" {
+		e.Command = installCommand(e.PackageManager, player)
+	}
+	return e
+}
+
+// SetAutoInstall sets whether EnsureAudioPlayer may offer to install a
+// missing audio player with the system package manager, which runs sudo.
+// Off by default.
+func (p *Player) SetAutoInstall(enabled bool) {
+	p.autoInstall = enabled
+}
+
+// SetInstallPrompt sets how EnsureAudioPlayer asks the user before running
+// an install command. Without one, as in hooks, nothing is installed.
+func (p *Player) SetInstallPrompt(confirm func(question string) bool) {
+	p.confirm = confirm
+}
+
+// SetInstallDryRun makes EnsureAudioPlayer write the detected package
+// manager and the install command to out instead of running anything,
+// whatever the auto-install setting. Nil turns dry-run mode off.
+func (p *Player) SetInstallDryRun(out io.Writer) {
+	p.dryRun = out
 }
 
-// EnsureAudioPlayer finds or installs an audio player. Returns the player name and error.
+// EnsureAudioPlayer finds an audio player. If auto-install is enabled and
+// the user agrees to the prompt, the preferred one is installed. Returns
+// the player name, or a *MissingPlayerError.
 func (p *Player) EnsureAudioPlayer() (string, error) {
 	// Already have a player?
-	for _, player := range linuxAudioPlayerNames {
-		if _, err := exec.LookPath(player); err == nil {
+	runner := p.cmdRunner()
+	for _, player := range p.orderPlayers(linuxAudioPlayerNames) {
+		if _, err := runner.LookPath(player); err == nil {
 			return player, nil
 		}
 	}
-
-	// Try to install
-	for _, player := range linuxAudioPlayerNames {
-		if err := installAudioPlayer(player); err == nil {
-			if _, err := exec.LookPath(player); err == nil {
-				return player, nil
-			}
+	missing := missingPlayer(runner)
+	if p.dryRun != nil {
+		if missing.Command == "" {
+			fmt.Fprintln(p.dryRun, "Package manager: none found")
+		} else {
+			fmt.Fprintf(p.dryRun, "Package manager: %s\nWould run:       %s\n", missing.PackageManager, missing.Command)
 		}
+		return "", missing
+	}
+	if !p.autoInstall || p.confirm == nil || missing.Command == "" {
+		return "", missing
+	}
+	if !p.confirm(fmt.Sprintf("Install %s via %s?", missing.Player, missing.PackageManager)) {
+		return "", missing
 	}
 
-	return "", errors.New("no audio player found; install mpv, ffmpeg, pulseaudio-utils, or alsa-utils")
+	if err := installAudioPlayer(runner, missing.Player); err != nil {
+		return "", fmt.Errorf("failed to install %s: %w", missing.Player, err)
+	}
+	if _, err := runner.LookPath(missing.Player); err != nil {
+		return "", missing
+	}
+	return missing.Player, nil
 }
@@ -1,6 +1,7 @@
 package audio
 
 import (
+	"context"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -107,20 +108,21 @@ func TestResolveSoundPath(t *testing.T) {
 }
 
 func TestDetectPlatform(t *testing.T) {
-	platform := detectPlatform()
+	if got, want := detectPlatform(), platformFor(runtime.GOOS); got != want {
+		t.Errorf("detectPlatform() = %s, want %s", got, want)
+	}
 
-	switch runtime.GOOS {
-	case darwinOS:
-		if platform != PlatformMacOS {
-			t.Errorf("expected PlatformMacOS on darwin, got %s", platform)
-		}
-	case linuxOS:
-		if platform != PlatformLinux {
-			t.Errorf("expected PlatformLinux on linux, got %s", platform)
-		}
-	default:
-		if platform != PlatformUnknown {
-			t.Errorf("expected PlatformUnknown on %s, got %s", runtime.GOOS, platform)
+	tests := map[string]Platform{
+		darwinOS:  PlatformMacOS,
+		linuxOS:   PlatformLinux,
+		"freebsd": PlatformBSD,
+		"openbsd": PlatformBSD,
+		"netbsd":  PlatformBSD,
+		"windows": PlatformUnknown,
+	}
+	for goos, want := range tests {
+		if got := platformFor(goos); got != want {
+			t.Errorf("platformFor(%s) = %s, want %s", goos, got, want)
 		}
 	}
 }
@@ -218,7 +220,7 @@ func TestPlayerPlatform(t *testing.T) {
 
 func TestPlayEmptyPath(t *testing.T) {
 	player := NewPlayer("")
-	err := player.Play("", 0.5)
+	err := player.Play(context.Background(), "", 0.5)
 	if err == nil {
 		t.Error("Play with empty path should return error")
 	}
@@ -226,7 +228,7 @@ func TestPlayEmptyPath(t *testing.T) {
 
 func TestPlayNonexistentFile(t *testing.T) {
 	player := NewPlayer("")
-	err := player.Play("/nonexistent/path/to/sound.aiff", 0.5)
+	err := player.Play(context.Background(), "/nonexistent/path/to/sound.aiff", 0.5)
 	if err == nil {
 		t.Error("Play with nonexistent file should return error")
 	}
@@ -352,6 +354,9 @@ func TestGetLinuxPlayerArgs(t *testing.T) {
 		player    string
 		soundPath string
 		volume    float64
+		gain      float64
+		rate      float64
+		pitch     bool
 		want      []string
 	}{
 		{
@@ -382,6 +387,87 @@ func TestGetLinuxPlayerArgs(t *testing.T) {
 			volume:    0.25,
 			want:      []string{"-nodisp", "-autoexit", "-volume", "25", "/path/to/sound.aiff"},
 		},
+		{
+			name:      "aucat attenuation",
+			player:    "aucat",
+			soundPath: "/path/to/sound.wav",
+			volume:    0.5,
+			want:      []string{"-v", "109", "-i", "/path/to/sound.wav"},
+		},
+		{
+			name:      "aucat full volume",
+			player:    "aucat",
+			soundPath: "/path/to/sound.wav",
+			volume:    1,
+			want:      []string{"-v", "127", "-i", "/path/to/sound.wav"},
+		},
+		{
+			name:      "mpv with gain",
+			player:    "mpv",
+			soundPath: "/path/to/sound.aiff",
+			volume:    0.5,
+			gain:      -6,
+			want:      []string{"--really-quiet", "--volume=50", "--af=lavfi=[volume=-6.0dB]", "/path/to/sound.aiff"},
+		},
+		{
+			name:      "ffplay with gain",
+			player:    "ffplay",
+			soundPath: "/path/to/sound.aiff",
+			volume:    0.5,
+			gain:      3,
+			want:      []string{"-nodisp", "-autoexit", "-volume", "50", "-af", "volume=3.0dB", "/path/to/sound.aiff"},
+		},
+		{
+			name:      "paplay with gain",
+			player:    "paplay",
+			soundPath: "/path/to/sound.aiff",
+			volume:    0.5,
+			gain:      -20,
+			want:      []string{"--volume=6553", "/path/to/sound.aiff"},
+		},
+		{
+			name:      "aplay ignores gain",
+			player:    "aplay",
+			soundPath: "/path/to/sound.aiff",
+			volume:    0.5,
+			gain:      6,
+			want:      []string{"-q", "/path/to/sound.aiff"},
+		},
+		{
+			name:      "mpv with tempo",
+			player:    "mpv",
+			soundPath: "/path/to/sound.aiff",
+			volume:    0.5,
+			rate:      1.05,
+			want:      []string{"--really-quiet", "--volume=50", "--speed=1.050", "/path/to/sound.aiff"},
+		},
+		{
+			name:      "mpv with pitch",
+			player:    "mpv",
+			soundPath: "/path/to/sound.aiff",
+			volume:    0.5,
+			rate:      0.95,
+			pitch:     true,
+			want:      []string{"--really-quiet", "--volume=50", "--speed=0.950", "--audio-pitch-correction=no", "/path/to/sound.aiff"},
+		},
+		{
+			name:      "ffplay with gain and tempo",
+			player:    "ffplay",
+			soundPath: "/path/to/sound.aiff",
+			volume:    0.5,
+			gain:      -3,
+			rate:      1.1,
+			want:      []string{"-nodisp", "-autoexit", "-volume", "50", "-af", "volume=-3.0dB,atempo=1.100", "/path/to/sound.aiff"},
+		},
+		{
+			name:      "ffplay skips pitch",
+			player:    "ffplay",
+			soundPath: "/path/to/sound.aiff",
+			volume:    0.5,
+			rate:      1.1,
+			pitch:     true,
+			want:      []string{"-nodisp", "-autoexit", "-volume", "50", "/path/to/sound.aiff"},
+		},
 		{
 			name:      "unknown player",
 			player:    "unknown_player",
@@ -393,7 +479,11 @@ func TestGetLinuxPlayerArgs(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := getLinuxPlayerArgs(tt.player, tt.soundPath, tt.volume)
+			rate := tt.rate
+			if rate == 0 {
+				rate = 1
+			}
+			got := getLinuxPlayerArgs(tt.player, tt.soundPath, tt.volume, tt.gain, rate, tt.pitch)
 			switch {
 			case tt.want == nil:
 				if got != nil {
@@ -415,13 +505,13 @@ func TestGetLinuxPlayerArgs(t *testing.T) {
 func TestFindPackageManager(t *testing.T) {
 	// This test verifies the function doesn't panic
 	// The actual result depends on the environment
-	result := findPackageManager()
+	result := findPackageManager(execRunner{})
 	t.Logf("Found package manager: %q", result)
 
 	// Verify it returns empty string or a valid key from packageManagers
 	if result != "" {
 		if _, ok := packageManagers[result]; !ok {
-			t.Errorf("findPackageManager() returned unknown package manager: %q", result)
+			t.Errorf("findPackageManager(execRunner{}) returned unknown package manager: %q", result)
 		}
 	}
 }
@@ -430,9 +520,9 @@ func TestInstallAudioPlayer(t *testing.T) {
 	// Test with unknown player - this checks the pkg mapping first
 	// Note: if no package manager is found, the error will be "no package manager"
 	// rather than "unknown player" because findPackageManager is called first
-	err := installAudioPlayer("unknown_player")
+	err := installAudioPlayer(execRunner{}, "unknown_player")
 	if err == nil {
-		t.Error("installAudioPlayer(unknown) should return error")
+		t.Error("installAudioPlayer(execRunner{}, unknown) should return error")
 	}
 	// The error message depends on whether a package manager is found
 	errMsg := err.Error()
@@ -520,7 +610,7 @@ func TestPlayMacOSNonBlocking(t *testing.T) {
 	player := NewPlayer("")
 
 	// Should not block - returns immediately after starting process
-	err = player.playMacOS(soundFile, 0.5)
+	err = player.playMacOS(context.Background(), soundFile, 0.5)
 	if err != nil {
 		t.Errorf("playMacOS should not return error: %v", err)
 	}
@@ -544,7 +634,7 @@ func TestPlayLinuxNoPlayer(t *testing.T) {
 
 	// Mock: if no player is available, should return error
 	// This test verifies the error message
-	err := player.playLinux("/nonexistent.aiff", 0.5)
+	err := player.playLinux(context.Background(), "/nonexistent.aiff", 0.5)
 	if hasPlayer {
 		// Player available - playLinux may succeed or fail depending on player
 		t.Logf("Audio player available, playLinux result: %v", err)
@@ -579,7 +669,7 @@ func TestPlayUnsupportedPlatform(t *testing.T) {
 	}
 
 	player := &Player{platform: PlatformUnknown, pluginRoot: ""}
-	err = player.Play(soundFile, 0.5)
+	err = player.Play(context.Background(), soundFile, 0.5)
 	if err == nil {
 		t.Error("Play with unknown platform should return error")
 	}
@@ -611,6 +701,79 @@ func TestResolveBundledSoundNotFound(t *testing.T) {
 	}
 }
 
+func TestResolveBundledSoundSearchPaths(t *testing.T) {
+	pluginRoot, err := os.MkdirTemp("", "ccbell-searchpath-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(pluginRoot)
+
+	if err := os.MkdirAll(filepath.Join(pluginRoot, "sounds"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	bundledStop := filepath.Join(pluginRoot, "sounds", "stop.aiff")
+	if err := os.WriteFile(bundledStop, []byte("dummy"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	personalDir := filepath.Join(pluginRoot, "personal")
+	if err := os.MkdirAll(personalDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	personalStop := filepath.Join(personalDir, "stop.wav")
+	personalDing := filepath.Join(personalDir, "ding.mp3")
+	for _, f := range []string{personalStop, personalDing} {
+		if err := os.WriteFile(f, []byte("dummy"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	player := NewPlayer(pluginRoot)
+	player.SetSearchPaths([]string{filepath.Join(pluginRoot, "missing"), personalDir})
+
+	tests := []struct {
+		name     string
+		spec     string
+		wantPath string
+		wantErr  bool
+	}{
+		{"plugin sound takes precedence", "bundled:stop", bundledStop, false},
+		{"found in search path", "bundled:ding", personalDing, false},
+		{"not found anywhere", "bundled:nonexistent", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := player.ResolveSoundPath(tt.spec, "stop")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveSoundPath(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if got != tt.wantPath {
+				t.Errorf("ResolveSoundPath(%q) = %q, want %q", tt.spec, got, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestResolveSystemSound(t *testing.T) {
+	player := NewPlayer("")
+
+	if _, err := player.ResolveSoundPath("system:../../etc/passwd", "stop"); err == nil {
+		t.Error("system sound with path traversal should be rejected")
+	}
+	if _, err := player.ResolveSoundPath("system:DefinitelyMissingSound", "stop"); err == nil {
+		t.Error("missing system sound should return error")
+	}
+
+	if runtime.GOOS == darwinOS {
+		path, err := player.ResolveSoundPath("system:Glass", "stop")
+		if err != nil {
+			t.Errorf("system:Glass should resolve on macOS: %v", err)
+		}
+		t.Logf("system:Glass resolved to %s", path)
+	}
+}
+
 func TestLinuxAudioPlayerNamesOrder(t *testing.T) {
 	// Verify the priority order is correct
 	expectedOrder := []string{"mpv", "paplay", "aplay", "ffplay"}
@@ -681,7 +844,7 @@ func TestPlayLinuxWithPlayer(t *testing.T) {
 	player := NewPlayer("")
 
 	// Try to play - will succeed if any audio player is installed
-	err = player.playLinux(soundFile, 0.5)
+	err = player.playLinux(context.Background(), soundFile, 0.5)
 	// Either succeeds (player found) or fails (no player) - both are valid
 	t.Logf("playLinux result: err=%v", err)
 }
@@ -723,7 +886,7 @@ func TestHasAudioPlayerUnknown(t *testing.T) {
 
 func TestInstallAudioPlayerWithMock(t *testing.T) {
 	// This tests the error path when player is unknown
-	err := installAudioPlayer("totally_fake_player_xyz")
+	err := installAudioPlayer(execRunner{}, "totally_fake_player_xyz")
 	if err == nil {
 		t.Error("installAudioPlayer with unknown player should return error")
 	}
@@ -770,7 +933,7 @@ func TestPlayWithValidLinuxPlayer(t *testing.T) {
 	player := NewPlayer("")
 
 	// Try to play - may succeed if a player like aplay is available
-	err = player.Play(soundFile, 0.5)
+	err = player.Play(context.Background(), soundFile, 0.5)
 	t.Logf("Play with valid file: err=%v", err)
 }
 
@@ -778,14 +941,14 @@ func TestInstallAudioPlayerNoManager(t *testing.T) {
 	// Test with a mock that has no package manager
 	// by calling installAudioPlayer when findPackageManager returns empty
 	// This tests the "no package manager found" error path
-	pm := findPackageManager()
+	pm := findPackageManager(execRunner{})
 	if pm == "" {
 		// Skip if no package manager on this system
 		t.Skip("no package manager available")
 	}
 
 	// Test with unknown player
-	err := installAudioPlayer("totally_fake_player_xyz_123")
+	err := installAudioPlayer(execRunner{}, "totally_fake_player_xyz_123")
 	if err == nil {
 		t.Error("installAudioPlayer with unknown player should return error")
 	}
@@ -799,20 +962,20 @@ func TestInstallAudioPlayerSuccess(t *testing.T) {
 		t.Skip("this test is only for Linux")
 	}
 
-	pm := findPackageManager()
+	pm := findPackageManager(execRunner{})
 	if pm == "" {
 		t.Skip("no package manager available")
 	}
 
 	// Test with a valid player (mpv) - this will fail because we can't actually install
 	// but it tests the code path
-	err := installAudioPlayer("mpv")
+	err := installAudioPlayer(execRunner{}, "mpv")
 	// Either succeeds or fails, but shouldn't panic
-	t.Logf("installAudioPlayer(mpv): err=%v", err)
+	t.Logf("installAudioPlayer(execRunner{}, mpv): err=%v", err)
 }
 
 func TestFindPackageManagerKnown(t *testing.T) {
-	result := findPackageManager()
+	result := findPackageManager(execRunner{})
 	if result != "" {
 		// Package manager found, verify it's a valid one
 		if _, ok := packageManagers[result]; !ok {
@@ -830,7 +993,7 @@ func TestPlayLinuxErrorPath(t *testing.T) {
 	}
 
 	player := NewPlayer("")
-	err := player.playLinux("/nonexistent/path/to/sound.aiff", 0.5)
+	err := player.playLinux(context.Background(), "/nonexistent/path/to/sound.aiff", 0.5)
 
 	// Should return error because no player is available
 	if err == nil {
@@ -0,0 +1,160 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// BackendEnvVar selects the audio backend. "mock" records player commands
+// (and prints them to stderr) instead of running them, for CI.
+const BackendEnvVar = "CCBELL_AUDIO_BACKEND"
+
+// Runner executes the external programs the player depends on (players,
+// ffmpeg, ffprobe, package managers). Tests substitute it with SetRunner.
+type Runner interface {
+	// LookPath reports where an executable is, like exec.LookPath.
+	LookPath(name string) (string, error)
+	// Start starts cmd without waiting for it to finish.
+	Start(cmd *exec.Cmd) error
+	// Run runs cmd to completion.
+	Run(cmd *exec.Cmd) error
+	// Output runs cmd and returns its standard output.
+	Output(cmd *exec.Cmd) ([]byte, error)
+}
+
+// execRunner runs commands for real.
+type execRunner struct{}
+
+func (execRunner) LookPath(name string) (string, error) { return exec.LookPath(name) }
+func (execRunner) Start(cmd *exec.Cmd) error            { return cmd.Start() }
+func (execRunner) Run(cmd *exec.Cmd) error              { return cmd.Run() }
+func (execRunner) Output(cmd *exec.Cmd) ([]byte, error) { return cmd.Output() }
+
+// MockRunner pretends every program is installed and records commands
+// instead of running them.
+type MockRunner struct {
+	out      io.Writer
+	mu       sync.Mutex
+	commands [][]string
+}
+
+// NewMockRunner creates a MockRunner. If out is non-nil, each recorded
+// command line is also written to it.
+func NewMockRunner(out io.Writer) *MockRunner {
+	return &MockRunner{out: out}
+}
+
+// LookPath reports every program as installed.
+func (m *MockRunner) LookPath(name string) (string, error) {
+	return "/mock/bin/" + name, nil
+}
+
+// Start records cmd.
+func (m *MockRunner) Start(cmd *exec.Cmd) error {
+	m.record(cmd)
+	return nil
+}
+
+// Run records cmd.
+func (m *MockRunner) Run(cmd *exec.Cmd) error {
+	m.record(cmd)
+	return nil
+}
+
+// Output records cmd. The mock produces no output.
+func (m *MockRunner) Output(cmd *exec.Cmd) ([]byte, error) {
+	m.record(cmd)
+	return nil, errors.New("mock runner produces no output")
+}
+
+// Commands returns the argv of each recorded command, in order.
+func (m *MockRunner) Commands() [][]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([][]string(nil), m.commands...)
+}
+
+func (m *MockRunner) record(cmd *exec.Cmd) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.commands = append(m.commands, cmd.Args)
+	if m.out != nil {
+		fmt.Fprintf(m.out, "ccbell: mock audio: %s\n", strings.Join(cmd.Args, " "))
+	}
+}
+
+// lookupCache is a Runner that answers LookPath from earlier results, such
+// as ones persisted by a previous run, and records the lookups it had to do.
+type lookupCache struct {
+	Runner
+	mu    sync.Mutex
+	known map[string]string // Executable -> path; "" if not found
+	found map[string]string // Lookups done through the wrapped runner
+}
+
+// LookPath returns a known result, or looks name up and records it.
+func (c *lookupCache) LookPath(name string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	path, ok := c.known[name]
+	if !ok {
+		path, _ = c.Runner.LookPath(name)
+		c.known[name] = path
+		c.found[name] = path
+	}
+	if path == "" {
+		return "", &exec.Error{Name: name, Err: exec.ErrNotFound}
+	}
+	return path, nil
+}
+
+// SetLookupCache makes the player trust known executable lookups (name to
+// path, "" if not installed) instead of searching PATH again. Lookups it
+// still has to do are returned by NewLookups.
+func (p *Player) SetLookupCache(known map[string]string) {
+	if _, ok := p.cmdRunner().(*MockRunner); ok {
+		return // Mock lookups aren't real, so they mustn't be persisted
+	}
+	c := &lookupCache{Runner: p.cmdRunner(), known: make(map[string]string), found: make(map[string]string)}
+	maps.Copy(c.known, known)
+	p.runner = c
+}
+
+// NewLookups returns the executable lookups done since SetLookupCache, for
+// persisting. It is nil without a lookup cache.
+func (p *Player) NewLookups() map[string]string {
+	c, ok := p.runner.(*lookupCache)
+	if !ok {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return maps.Clone(c.found)
+}
+
+// defaultRunner returns the runner selected by the environment.
+func defaultRunner() Runner {
+	if os.Getenv(BackendEnvVar) == "mock" {
+		return NewMockRunner(os.Stderr)
+	}
+	return execRunner{}
+}
+
+// SetRunner replaces how the player runs external programs.
+func (p *Player) SetRunner(r Runner) {
+	p.runner = r
+}
+
+// cmdRunner returns the player's runner, defaulting to real execution.
+func (p *Player) cmdRunner() Runner {
+	if p.runner == nil {
+		return execRunner{}
+	}
+	return p.runner
+}
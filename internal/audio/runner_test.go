@@ -0,0 +1,294 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPlayWithMockRunner(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-runner-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	sound := filepath.Join(tmpDir, "stop.aiff")
+	if err := os.WriteFile(sound, []byte("FORM"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		platform Platform
+		want     []string
+	}{
+		{
+			name:     "macos",
+			platform: PlatformMacOS,
+			want:     []string{"afplay", "-v", "0.50", sound},
+		},
+		{
+			name:     "linux",
+			platform: PlatformLinux,
+			want:     []string{"setpriv", "--no-new-privs", "mpv", "--really-quiet", "--volume=50", sound},
+		},
+		{
+			name:     "bsd",
+			platform: PlatformBSD,
+			want:     []string{"mpv", "--really-quiet", "--volume=50", sound},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			runner := NewMockRunner(&out)
+			player := &Player{platform: tt.platform}
+			player.SetRunner(runner)
+
+			if err := player.Play(context.Background(), sound, 0.5); err != nil {
+				t.Fatalf("Play() error = %v", err)
+			}
+
+			commands := runner.Commands()
+			if len(commands) != 1 {
+				t.Fatalf("recorded %d commands, want 1: %v", len(commands), commands)
+			}
+			if !slices.Equal(commands[0], tt.want) {
+				t.Errorf("command = %v, want %v", commands[0], tt.want)
+			}
+			if !strings.Contains(out.String(), "mock audio: "+strings.Join(tt.want, " ")) {
+				t.Errorf("mock output = %q", out.String())
+			}
+		})
+	}
+}
+
+func TestDefaultRunner(t *testing.T) {
+	old := os.Getenv(BackendEnvVar)
+	defer os.Setenv(BackendEnvVar, old)
+
+	os.Setenv(BackendEnvVar, "mock")
+	if _, ok := defaultRunner().(*MockRunner); !ok {
+		t.Errorf("defaultRunner() with %s=mock should be a MockRunner", BackendEnvVar)
+	}
+
+	os.Setenv(BackendEnvVar, "")
+	if _, ok := defaultRunner().(execRunner); !ok {
+		t.Error("defaultRunner() should execute commands by default")
+	}
+}
+
+func TestMockRunnerEnsureAudioPlayer(t *testing.T) {
+	player := &Player{platform: PlatformLinux}
+	player.SetRunner(NewMockRunner(nil))
+
+	name, err := player.EnsureAudioPlayer()
+	if err != nil {
+		t.Fatalf("EnsureAudioPlayer() error = %v", err)
+	}
+	if name != linuxAudioPlayerNames[0] {
+		t.Errorf("EnsureAudioPlayer() = %q, want %q", name, linuxAudioPlayerNames[0])
+	}
+}
+
+func TestEnsureAudioPlayerMissing(t *testing.T) {
+	missing := map[string]bool{}
+	for _, name := range linuxAudioPlayerNames {
+		missing[name] = true
+	}
+	for pm := range packageManagers {
+		missing[pm] = pm != "apt-get"
+	}
+
+	yes, no := true, false
+	tests := []struct {
+		name        string
+		autoInstall bool
+		answer      *bool // nil: not interactive
+		want        [][]string
+	}{
+		{"auto-install off", false, &yes, nil},
+		{"not interactive", true, nil, nil},
+		{"declined", true, &no, nil},
+		{"accepted", true, &yes, [][]string{
+			{"sh", "-c", "sudo apt-get update && sudo apt-get install -y mpv"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &brokenRunner{MockRunner: NewMockRunner(nil), missing: missing}
+			player := &Player{platform: PlatformLinux}
+			player.SetRunner(runner)
+			player.SetAutoInstall(tt.autoInstall)
+			var asked []string
+			if tt.answer != nil {
+				player.SetInstallPrompt(func(question string) bool {
+					asked = append(asked, question)
+					return *tt.answer
+				})
+			}
+
+			// The mock never installs anything, so mpv is still missing
+			_, err := player.EnsureAudioPlayer()
+			var mpe *MissingPlayerError
+			if !errors.As(err, &mpe) {
+				t.Fatalf("EnsureAudioPlayer() error = %v, want *MissingPlayerError", err)
+			}
+			if mpe.PackageManager != "apt-get" || mpe.Command != "sudo apt-get update && sudo apt-get install -y mpv" {
+				t.Errorf("MissingPlayerError = %+v", mpe)
+			}
+			if commands := runner.Commands(); !slices.EqualFunc(commands, tt.want, slices.Equal) {
+				t.Errorf("commands = %v, want %v", commands, tt.want)
+			}
+			if tt.autoInstall && tt.answer != nil && !slices.Equal(asked, []string{"Install mpv via apt-get?"}) {
+				t.Errorf("asked %q", asked)
+			}
+		})
+	}
+
+	// A dry run describes the command without running or asking
+	var out bytes.Buffer
+	runner := &brokenRunner{MockRunner: NewMockRunner(nil), missing: missing}
+	player := &Player{platform: PlatformLinux}
+	player.SetRunner(runner)
+	player.SetAutoInstall(true)
+	player.SetInstallPrompt(func(string) bool { t.Error("dry run should not prompt"); return true })
+	player.SetInstallDryRun(&out)
+	if _, err := player.EnsureAudioPlayer(); err == nil {
+		t.Error("EnsureAudioPlayer() dry run should report the missing player")
+	}
+	if want := "Package manager: apt-get\nWould run:       sudo apt-get update && sudo apt-get install -y mpv\n"; out.String() != want {
+		t.Errorf("dry run output = %q, want %q", out.String(), want)
+	}
+	if commands := runner.Commands(); len(commands) != 0 {
+		t.Errorf("dry run ran %v", commands)
+	}
+
+	// Without a package manager there is no command to suggest
+	for pm := range packageManagers {
+		missing[pm] = true
+	}
+	player = &Player{platform: PlatformLinux}
+	player.SetRunner(&brokenRunner{MockRunner: NewMockRunner(nil), missing: missing})
+	_, err := player.EnsureAudioPlayer()
+	var mpe *MissingPlayerError
+	if !errors.As(err, &mpe) || mpe.Command != "" || !strings.Contains(err.Error(), "install mpv") {
+		t.Errorf("EnsureAudioPlayer() error = %v", err)
+	}
+}
+
+func TestLookupCache(t *testing.T) {
+	runner := &brokenRunner{MockRunner: NewMockRunner(nil), missing: map[string]bool{"mpv": true, "paplay": true}}
+	player := &Player{platform: PlatformLinux}
+	player.SetRunner(runner)
+	// Cached results win over searching again
+	player.SetLookupCache(map[string]string{"mpv": "/usr/bin/mpv", "paplay": ""})
+
+	if path, err := player.cmdRunner().LookPath("mpv"); err != nil || path != "/usr/bin/mpv" {
+		t.Errorf("LookPath(mpv) = %q, %v; want the cached path", path, err)
+	}
+	if _, err := player.cmdRunner().LookPath("paplay"); !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("LookPath(paplay) error = %v, want exec.ErrNotFound", err)
+	}
+	if path, err := player.cmdRunner().LookPath("aplay"); err != nil || path != "/mock/bin/aplay" {
+		t.Errorf("LookPath(aplay) = %q, %v", path, err)
+	}
+	if got := player.NewLookups(); !maps.Equal(got, map[string]string{"aplay": "/mock/bin/aplay"}) {
+		t.Errorf("NewLookups() = %v, want only aplay", got)
+	}
+
+	// The mock backend's lookups are never cached
+	player = &Player{platform: PlatformLinux}
+	player.SetRunner(NewMockRunner(nil))
+	player.SetLookupCache(map[string]string{"mpv": ""})
+	if _, err := player.cmdRunner().LookPath("mpv"); err != nil || player.NewLookups() != nil {
+		t.Errorf("mock runner should bypass the lookup cache: %v", err)
+	}
+}
+
+func TestPlayCanceledContext(t *testing.T) {
+	runner := NewMockRunner(nil)
+	player := &Player{platform: PlatformMacOS}
+	player.SetRunner(runner)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := player.Play(ctx, "/tmp/stop.aiff", 0.5); !errors.Is(err, context.Canceled) {
+		t.Errorf("Play() error = %v, want context.Canceled", err)
+	}
+	if len(runner.Commands()) != 0 {
+		t.Error("no player should be started after cancellation")
+	}
+}
+
+// brokenRunner is a MockRunner where some programs are missing or fail to
+// start.
+type brokenRunner struct {
+	*MockRunner
+	missing   map[string]bool
+	failStart map[string]bool
+}
+
+func (r *brokenRunner) LookPath(name string) (string, error) {
+	if r.missing[name] {
+		return "", errors.New("executable file not found")
+	}
+	return r.MockRunner.LookPath(name)
+}
+
+func (r *brokenRunner) Start(cmd *exec.Cmd) error {
+	if r.failStart[filepath.Base(cmd.Path)] {
+		return errors.New("operation not permitted")
+	}
+	return r.MockRunner.Start(cmd)
+}
+
+func TestPlayMacOSBeepFallback(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-runner-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	sound := filepath.Join(tmpDir, "stop.aiff")
+	if err := os.WriteFile(sound, []byte("FORM"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	beep := []string{"osascript", "-e", "beep"}
+	tests := []struct {
+		name      string
+		missing   map[string]bool
+		failStart map[string]bool
+		want      [][]string
+		wantErr   bool
+	}{
+		{"afplay missing", map[string]bool{"afplay": true}, nil, [][]string{beep}, false},
+		{"afplay blocked", nil, map[string]bool{"afplay": true}, [][]string{beep}, false},
+		{"nothing available", map[string]bool{"afplay": true, "osascript": true}, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &brokenRunner{MockRunner: NewMockRunner(nil), missing: tt.missing, failStart: tt.failStart}
+			player := &Player{platform: PlatformMacOS}
+			player.SetRunner(runner)
+
+			err := player.Play(context.Background(), sound, 0.5)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Play() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if commands := runner.Commands(); !slices.EqualFunc(commands, tt.want, slices.Equal) {
+				t.Errorf("commands = %v, want %v", commands, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package audio
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// DefaultPlayerTimeout is the hard limit on how long a player process may run.
+const DefaultPlayerTimeout = 10 * time.Second
+
+// playerEnvAllowlist lists the environment variables passed to player
+// processes. Everything else (tokens, proxies, LD_PRELOAD, ...) is dropped.
+var playerEnvAllowlist = []string{
+	"PATH", "HOME", "USER", "LANG", "LC_ALL", "TMPDIR",
+	"XDG_RUNTIME_DIR", "DBUS_SESSION_BUS_ADDRESS",
+	"PULSE_SERVER", "PULSE_RUNTIME_PATH", "PIPEWIRE_RUNTIME_DIR",
+	"DISPLAY", "WAYLAND_DISPLAY",
+}
+
+// watchdogScript runs "$@" and kills it after $CCBELL_TIMEOUT seconds. It is
+// used when the coreutils timeout command is unavailable (e.g. macOS).
+const watchdogScript = `"$@" & p=$!; (sleep "$CCBELL_TIMEOUT"; kill -9 $p) 2>/dev/null & w=$!; wait $p; s=$?; kill $w 2>/dev/null; exit $s`
+
+// SetTimeout sets the hard timeout for player processes. Zero disables it.
+func (p *Player) SetTimeout(timeout time.Duration) {
+	p.timeout = timeout
+}
+
+// command builds a sandboxed player command: a restricted environment, a hard
+// timeout, and no-new-privileges on Linux when setpriv is available.
+func (p *Player) command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	argv := append([]string{name}, args...)
+	env := playerEnv()
+
+	if secs := int(p.timeout.Seconds()); secs > 0 {
+		if _, err := p.cmdRunner().LookPath("timeout"); err == nil {
+			argv = append([]string{"timeout", "--kill-after=1", strconv.Itoa(secs)}, argv...)
+		} else {
+			argv = append([]string{"sh", "-c", watchdogScript, "ccbell-player"}, argv...)
+			env = append(env, "CCBELL_TIMEOUT="+strconv.Itoa(secs))
+		}
+	}
+
+	if p.platform == PlatformLinux {
+		if _, err := p.cmdRunner().LookPath("setpriv"); err == nil {
+			argv = append([]string{"setpriv", "--no-new-privs"}, argv...)
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Env = env
+	// The player runs under the timeout and setpriv wrappers, so canceling
+	// kills its whole process group rather than argv[0] alone.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	return cmd
+}
+
+// start launches cmd without waiting for it (non-blocking). The process is
+// reaped in the background so long-running callers don't collect zombies.
+func (p *Player) start(cmd *exec.Cmd) error {
+	if err := p.cmdRunner().Start(cmd); err != nil {
+		return err
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
+// playerEnv returns the allowlisted subset of the current environment.
+func playerEnv() []string {
+	env := make([]string, 0, len(playerEnvAllowlist))
+	for _, kv := range os.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		for _, allowed := range playerEnvAllowlist {
+			if key == allowed {
+				env = append(env, kv)
+				break
+			}
+		}
+	}
+	return env
+}
@@ -0,0 +1,140 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCommandSandboxing(t *testing.T) {
+	os.Setenv("CCBELL_TEST_TOKEN", "leak")
+	defer os.Unsetenv("CCBELL_TEST_TOKEN")
+
+	player := &Player{platform: PlatformLinux, timeout: 5 * time.Second}
+	cmd := player.command(context.Background(), "mpv", "--really-quiet", "/tmp/stop.aiff")
+
+	if !slices.Contains(cmd.Args, "mpv") || cmd.Args[len(cmd.Args)-1] != "/tmp/stop.aiff" {
+		t.Errorf("command args missing player invocation: %v", cmd.Args)
+	}
+	if _, err := exec.LookPath("timeout"); err == nil {
+		if !slices.Contains(cmd.Args, "timeout") || !slices.Contains(cmd.Args, "5") {
+			t.Errorf("expected timeout wrapper, got %v", cmd.Args)
+		}
+	} else if !slices.Contains(cmd.Args, watchdogScript) {
+		t.Errorf("expected watchdog wrapper, got %v", cmd.Args)
+	}
+
+	for _, kv := range cmd.Env {
+		if strings.HasPrefix(kv, "CCBELL_TEST_TOKEN=") {
+			t.Error("player environment should not include non-allowlisted variables")
+		}
+	}
+}
+
+func TestCommandNoTimeout(t *testing.T) {
+	player := &Player{platform: PlatformMacOS}
+	cmd := player.command(context.Background(), "afplay", "/tmp/stop.aiff")
+
+	want := []string{"afplay", "/tmp/stop.aiff"}
+	if !slices.Equal(cmd.Args, want) {
+		t.Errorf("command args = %v, want %v", cmd.Args, want)
+	}
+}
+
+func TestWatchdogScriptKillsHungProcess(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not available")
+	}
+
+	cmd := exec.Command("sh", "-c", watchdogScript, "ccbell-player", "sleep", "30")
+	cmd.Env = append(os.Environ(), "CCBELL_TIMEOUT=1")
+
+	start := time.Now()
+	_ = cmd.Run()
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("watchdog did not kill hung process in time (took %v)", elapsed)
+	}
+}
+
+func TestCommandCanceledByContext(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not available")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	player := &Player{platform: PlatformMacOS}
+	cmd := player.command(ctx, "sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	cancel()
+	_ = cmd.Wait()
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("canceling the context did not stop the player (took %v)", elapsed)
+	}
+}
+
+func TestCommandCancelKillsWrappedPlayer(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not available")
+	}
+	tmpDir, err := os.MkdirTemp("", "ccbell-sandbox-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	pidFile := filepath.Join(tmpDir, "pid")
+
+	// The timeout wrapper (or watchdog shell) is argv[0], not the player
+	ctx, cancel := context.WithCancel(context.Background())
+	player := &Player{platform: PlatformLinux, timeout: 30 * time.Second}
+	cmd := player.command(ctx, "sh", "-c", `echo $$ > "$0"; exec sleep 37`, pidFile)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	var pid int
+	for deadline := time.Now().Add(5 * time.Second); pid == 0; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			cancel()
+			t.Fatal("player didn't start")
+		}
+		data, _ := os.ReadFile(pidFile)
+		pid, _ = strconv.Atoi(strings.TrimSpace(string(data)))
+	}
+
+	cancel()
+	_ = cmd.Wait()
+	for deadline := time.Now().Add(5 * time.Second); processRunning(pid); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			syscall.Kill(pid, syscall.SIGKILL)
+			t.Fatal("canceling the context left the wrapped player running")
+		}
+	}
+}
+
+// processRunning reports whether pid is alive and not a zombie awaiting
+// its parent.
+func processRunning(pid int) bool {
+	if syscall.Kill(pid, 0) != nil {
+		return false
+	}
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return true // No procfs; trust kill
+	}
+	_, rest, _ := strings.Cut(string(stat), ") ")
+	return !strings.HasPrefix(rest, "Z")
+}
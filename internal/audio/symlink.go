@@ -0,0 +1,69 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SymlinkPolicy controls how symlinked sound files are treated.
+type SymlinkPolicy string
+
+// Symlink policies applied to bundled, search-path and custom sounds. The
+// root of a custom sound is the allowedSoundDirs entry holding it; custom
+// sounds outside any are only checked by SymlinkDeny.
+const (
+	SymlinkDeny              SymlinkPolicy = "deny"                // Reject any symlinked sound file
+	SymlinkResolveWithinRoot SymlinkPolicy = "resolve-within-root" // Follow only if the target stays within the sound's root (default)
+	SymlinkAllow             SymlinkPolicy = "allow"               // Follow symlinks anywhere
+)
+
+// ErrSymlinkNotAllowed is returned when a sound file violates the symlink policy.
+var ErrSymlinkNotAllowed = errors.New("symlink not allowed")
+
+// SetSymlinkPolicy sets the symlink policy. An empty policy means
+// SymlinkResolveWithinRoot.
+func (p *Player) SetSymlinkPolicy(policy SymlinkPolicy) {
+	p.symlinkPolicy = policy
+}
+
+// applySymlinkPolicy checks path against the symlink policy, where root is
+// the directory the sound is expected to live in. It returns the path to play,
+// which is the resolved target under SymlinkResolveWithinRoot.
+func (p *Player) applySymlinkPolicy(path, root string) (string, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		return path, nil
+	}
+
+	switch p.symlinkPolicy {
+	case SymlinkAllow:
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("broken symlink: %s", path)
+		}
+		return path, nil
+
+	case SymlinkDeny:
+		return "", fmt.Errorf("%w: %s", ErrSymlinkNotAllowed, path)
+
+	default:
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return "", fmt.Errorf("broken symlink: %s", path)
+		}
+		realRoot, err := filepath.EvalSymlinks(root)
+		if err != nil {
+			return "", fmt.Errorf("sound root not accessible: %s", root)
+		}
+		rel, err := filepath.Rel(realRoot, resolved)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("%w: %s points outside %s", ErrSymlinkNotAllowed, path, root)
+		}
+		return resolved, nil
+	}
+}
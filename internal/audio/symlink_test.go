@@ -0,0 +1,165 @@
+package audio
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplySymlinkPolicy(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-symlink-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	root := filepath.Join(tempDir, "sounds")
+	outside := filepath.Join(tempDir, "outside")
+	for _, dir := range []string{root, outside} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	regular := filepath.Join(root, "stop.aiff")
+	target := filepath.Join(root, "real.aiff")
+	secret := filepath.Join(outside, "secret.aiff")
+	for _, f := range []string{regular, target, secret} {
+		if err := os.WriteFile(f, []byte("dummy"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	inside := filepath.Join(root, "inside.aiff")
+	escape := filepath.Join(root, "escape.aiff")
+	if err := os.Symlink(target, inside); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(secret, escape); err != nil {
+		t.Fatal(err)
+	}
+
+	realTarget, _ := filepath.EvalSymlinks(target)
+
+	tests := []struct {
+		name     string
+		policy   SymlinkPolicy
+		path     string
+		wantPath string
+		wantErr  bool
+	}{
+		{"regular file with deny", SymlinkDeny, regular, regular, false},
+		{"symlink with deny", SymlinkDeny, inside, "", true},
+		{"symlink within root", SymlinkResolveWithinRoot, inside, realTarget, false},
+		{"symlink escaping root", SymlinkResolveWithinRoot, escape, "", true},
+		{"default policy escaping root", "", escape, "", true},
+		{"symlink escaping root with allow", SymlinkAllow, escape, escape, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player := NewPlayer(tempDir)
+			player.SetSymlinkPolicy(tt.policy)
+
+			got, err := player.applySymlinkPolicy(tt.path, root)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applySymlinkPolicy(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrSymlinkNotAllowed) {
+					t.Errorf("error = %v, want ErrSymlinkNotAllowed", err)
+				}
+				return
+			}
+			if got != tt.wantPath {
+				t.Errorf("applySymlinkPolicy(%q) = %q, want %q", tt.path, got, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestResolveSoundPathSymlinkPolicy(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-symlink-resolve-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	soundsDir := filepath.Join(tempDir, "sounds")
+	if err := os.MkdirAll(soundsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	secret := filepath.Join(tempDir, "secret.aiff")
+	if err := os.WriteFile(secret, []byte("dummy"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(secret, filepath.Join(soundsDir, "stop.aiff")); err != nil {
+		t.Fatal(err)
+	}
+
+	player := NewPlayer(tempDir)
+	if _, err := player.ResolveSoundPath("bundled:stop", "stop"); !errors.Is(err, ErrSymlinkNotAllowed) {
+		t.Errorf("bundled symlink escaping sounds dir: error = %v, want ErrSymlinkNotAllowed", err)
+	}
+	if path := player.GetFallbackPath("stop"); path != "" {
+		t.Errorf("GetFallbackPath should skip disallowed symlink, got %q", path)
+	}
+
+	player.SetSymlinkPolicy(SymlinkAllow)
+	if _, err := player.ResolveSoundPath("bundled:stop", "stop"); err != nil {
+		t.Errorf("bundled symlink with allow policy: unexpected error %v", err)
+	}
+}
+
+func TestResolveCustomSoundSymlinks(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-symlink-custom-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	allowed := filepath.Join(tempDir, "allowed")
+	if err := os.MkdirAll(allowed, 0755); err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join(tempDir, "shared", "ding.aiff")
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("dummy"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(allowed, "ding.aiff")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		policy  SymlinkPolicy
+		allowed []string
+		wantErr error
+	}{
+		{"default follows", "", nil, nil},
+		{"resolve-within-root without a root follows", SymlinkResolveWithinRoot, nil, nil},
+		{"allow", SymlinkAllow, nil, nil},
+		{"deny", SymlinkDeny, nil, ErrSymlinkNotAllowed},
+		{"escapes allowed dir", "", []string{allowed}, ErrSymlinkNotAllowed},
+		{"within allowed dir", "", []string{tempDir}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player := NewPlayer(tempDir)
+			player.SetSymlinkPolicy(tt.policy)
+			player.SetAllowedDirs(tt.allowed)
+			_, err := player.ResolveSoundPath("custom:"+link, "stop")
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("ResolveSoundPath() error = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("ResolveSoundPath() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
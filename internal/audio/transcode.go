@@ -0,0 +1,107 @@
+package audio
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// playerFormats lists the file extensions each limited player can decode.
+// Players not listed (mpv, ffplay) handle any format ffmpeg supports.
+var playerFormats = map[string][]string{
+	"aplay":  {".wav"},
+	"paplay": {".wav", ".aiff", ".aif", ".flac", ".ogg", ".oga", ".au"},
+	"afplay": {".aiff", ".aif", ".wav", ".mp3", ".m4a", ".aac", ".caf", ".flac"},
+	"aucat":  {".wav", ".aiff", ".aif", ".au"},
+}
+
+// playerSupportsFormat reports whether playerName can play soundPath directly.
+func playerSupportsFormat(playerName, soundPath string) bool {
+	formats, limited := playerFormats[playerName]
+	if !limited {
+		return true
+	}
+	ext := strings.ToLower(filepath.Ext(soundPath))
+	for _, f := range formats {
+		if f == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// SetCacheDir sets the directory used for transcoded sound files.
+// Transcoding is disabled when dir is empty.
+func (p *Player) SetCacheDir(dir string) {
+	p.cacheDir = dir
+}
+
+// transcodeIfNeeded returns a path playable by playerName. If the player
+// cannot decode soundPath, the file is converted once to WAV via ffmpeg and
+// cached by content hash. On any failure the original path is returned.
+func (p *Player) transcodeIfNeeded(ctx context.Context, playerName, soundPath string) string {
+	if playerSupportsFormat(playerName, soundPath) || p.cacheDir == "" {
+		return soundPath
+	}
+	cached, err := p.transcodeToWAV(ctx, soundPath)
+	if err != nil {
+		return soundPath
+	}
+	return cached
+}
+
+// transcodeToWAV converts soundPath to WAV in the cache directory, keyed by
+// the SHA-256 of its content. An existing cache entry is reused.
+func (p *Player) transcodeToWAV(ctx context.Context, soundPath string) (string, error) {
+	hash, err := fileHash(soundPath)
+	if err != nil {
+		return "", err
+	}
+
+	cached := filepath.Join(p.cacheDir, hash+".wav")
+	if info, err := os.Stat(cached); err == nil && info.Size() > 0 {
+		return cached, nil
+	}
+
+	if _, err := p.cmdRunner().LookPath("ffmpeg"); err != nil {
+		return "", errors.New("ffmpeg not found for transcoding")
+	}
+	if err := os.MkdirAll(p.cacheDir, 0750); err != nil {
+		return "", fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Write to a temp name first so a failed conversion never leaves a
+	// truncated cache entry behind.
+	tmp := cached + ".tmp.wav"
+	defer os.Remove(tmp)
+	cmd := exec.CommandContext(ctx, "ffmpeg", "-y", "-loglevel", "error", "-i", soundPath, tmp)
+	if err := p.cmdRunner().Run(cmd); err != nil {
+		return "", fmt.Errorf("ffmpeg transcoding failed: %w", err)
+	}
+	if err := os.Rename(tmp, cached); err != nil {
+		return "", fmt.Errorf("failed to store transcoded file: %w", err)
+	}
+	return cached, nil
+}
+
+// fileHash returns a short hex SHA-256 digest of the file content.
+func fileHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32], nil
+}
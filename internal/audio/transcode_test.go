@@ -0,0 +1,113 @@
+package audio
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPlayerSupportsFormat(t *testing.T) {
+	tests := []struct {
+		player string
+		path   string
+		want   bool
+	}{
+		{"aplay", "/s/stop.wav", true},
+		{"aplay", "/s/stop.aiff", false},
+		{"aplay", "/s/stop.WAV", true},
+		{"paplay", "/s/stop.aiff", true},
+		{"paplay", "/s/stop.mp3", false},
+		{"afplay", "/s/stop.mp3", true},
+		{"afplay", "/s/stop.ogg", false},
+		{"mpv", "/s/stop.ogg", true},
+		{"ffplay", "/s/stop.opus", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.player+tt.path, func(t *testing.T) {
+			if got := playerSupportsFormat(tt.player, tt.path); got != tt.want {
+				t.Errorf("playerSupportsFormat(%q, %q) = %v, want %v", tt.player, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranscodeIfNeeded(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-transcode-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	soundFile := filepath.Join(tempDir, "stop.aiff")
+	if err := os.WriteFile(soundFile, []byte("dummy aiff"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	player := NewPlayer("")
+
+	t.Run("no cache dir returns original", func(t *testing.T) {
+		if got := player.transcodeIfNeeded(context.Background(), "aplay", soundFile); got != soundFile {
+			t.Errorf("transcodeIfNeeded() = %q, want original", got)
+		}
+	})
+
+	cacheDir := filepath.Join(tempDir, "cache")
+	player.SetCacheDir(cacheDir)
+
+	t.Run("supported format returns original", func(t *testing.T) {
+		if got := player.transcodeIfNeeded(context.Background(), "paplay", soundFile); got != soundFile {
+			t.Errorf("transcodeIfNeeded() = %q, want original", got)
+		}
+	})
+
+	t.Run("cached transcode is reused", func(t *testing.T) {
+		hash, err := fileHash(soundFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(cacheDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		cached := filepath.Join(cacheDir, hash+".wav")
+		if err := os.WriteFile(cached, []byte("dummy wav"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := player.transcodeIfNeeded(context.Background(), "aplay", soundFile); got != cached {
+			t.Errorf("transcodeIfNeeded() = %q, want cached %q", got, cached)
+		}
+	})
+}
+
+func TestFileHashStable(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-hash-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	a := filepath.Join(tempDir, "a.wav")
+	b := filepath.Join(tempDir, "b.wav")
+	for _, f := range []string{a, b} {
+		if err := os.WriteFile(f, []byte("same content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hashA, err := fileHash(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashB, err := fileHash(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hashA != hashB {
+		t.Errorf("identical content should hash equally: %s != %s", hashA, hashB)
+	}
+	if _, err := fileHash(filepath.Join(tempDir, "missing")); err == nil {
+		t.Error("fileHash of missing file should error")
+	}
+}
@@ -0,0 +1,93 @@
+package audio
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// Variation modes.
+const (
+	VariationPitch = "pitch" // Vary playback rate, shifting pitch and tempo together
+	VariationTempo = "tempo" // Vary tempo, keeping pitch
+)
+
+// MaxVariation caps the random variation amount.
+const MaxVariation = 0.25
+
+// VariationSteps is how many fixed rates SetVariationStep picks from,
+// evenly spaced across ±amount so neighbors are told apart by ear.
+const VariationSteps = 5
+
+// randFloat returns a value in [0, 1). Replaceable in tests.
+var randFloat = rand.Float64
+
+// SetVariation randomizes each playback by up to ±amount (a fraction, e.g.
+// 0.05 for 5%) of pitch or tempo, where the player supports it.
+func (p *Player) SetVariation(mode string, amount float64) {
+	p.variationMode = mode
+	p.variation = min(max(amount, 0), MaxVariation)
+}
+
+// SetVariationStep fixes the variation to one of VariationSteps rates (step
+// 0 being the lowest) instead of a random one, e.g. to give each session its
+// own recognizable sound.
+func (p *Player) SetVariationStep(step int) {
+	p.variationStep = min(max(step, 0), VariationSteps-1) + 1
+}
+
+// playbackRate returns the rate factor for the configured variation: the
+// fixed step's, else a random one, or 1 if none.
+func (p *Player) playbackRate() float64 {
+	if p.variation == 0 || (p.variationMode != VariationPitch && p.variationMode != VariationTempo) {
+		return 1
+	}
+	position := randFloat()
+	if p.variationStep > 0 {
+		position = float64(p.variationStep-1) / (VariationSteps - 1)
+	}
+	return 1 + (position*2-1)*p.variation
+}
+
+// effectArgs returns player arguments applying a dB gain and a playback rate
+// (shifting pitch if pitch is set). Unsupported effects are skipped.
+func effectArgs(playerName string, gain, rate float64, pitch bool) []string {
+	var args []string
+	switch playerName {
+	case "afplay":
+		// afplay time-stretches, so only tempo variation applies
+		if rate != 1 && !pitch {
+			args = append(args, "-r", fmt.Sprintf("%.3f", rate))
+		}
+	case "mpv":
+		if gain != 0 {
+			args = append(args, fmt.Sprintf("--af=lavfi=[volume=%.1fdB]", gain))
+		}
+		if rate != 1 {
+			args = append(args, fmt.Sprintf("--speed=%.3f", rate))
+			if pitch {
+				args = append(args, "--audio-pitch-correction=no")
+			}
+		}
+	case "ffplay":
+		var filters []string
+		if gain != 0 {
+			filters = append(filters, fmt.Sprintf("volume=%.1fdB", gain))
+		}
+		if rate != 1 && !pitch {
+			filters = append(filters, fmt.Sprintf("atempo=%.3f", rate))
+		}
+		for i, filter := range filters {
+			if i == 0 {
+				args = append(args, "-af", filter)
+			} else {
+				args[len(args)-1] += "," + filter
+			}
+		}
+	case "paplay":
+		// paplay volume is linear, 65536 being 100%
+		if gain != 0 {
+			args = append(args, fmt.Sprintf("--volume=%d", int(65536*gainFactor(gain))))
+		}
+	}
+	return args
+}
@@ -0,0 +1,92 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestPlaybackRate(t *testing.T) {
+	orig := randFloat
+	defer func() { randFloat = orig }()
+
+	tests := []struct {
+		name   string
+		mode   string
+		amount float64
+		rand   float64
+		want   float64
+	}{
+		{"disabled", "", 0, 0.9, 1},
+		{"unknown mode", "speed", 0.1, 0.9, 1},
+		{"low end", VariationTempo, 0.1, 0, 0.9},
+		{"high end", VariationPitch, 0.1, 1, 1.1},
+		{"clamped", VariationTempo, 0.9, 1, 1 + MaxVariation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			randFloat = func() float64 { return tt.rand }
+			p := &Player{}
+			p.SetVariation(tt.mode, tt.amount)
+			if got := p.playbackRate(); got < tt.want-1e-9 || got > tt.want+1e-9 {
+				t.Errorf("playbackRate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Fixed steps ignore the random source
+	randFloat = func() float64 { return 0.9 }
+	for step, want := range map[int]float64{0: 0.9, 1: 0.95, 2: 1, 4: 1.1, 9: 1.1} {
+		p := &Player{}
+		p.SetVariation(VariationPitch, 0.1)
+		p.SetVariationStep(step)
+		if got := p.playbackRate(); got < want-1e-9 || got > want+1e-9 {
+			t.Errorf("playbackRate() at step %d = %v, want %v", step, got, want)
+		}
+	}
+}
+
+func TestPlayMacOSWithVariation(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-variation-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	sound := filepath.Join(tmpDir, "stop.aiff")
+	if err := os.WriteFile(sound, []byte("FORM"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	orig := randFloat
+	randFloat = func() float64 { return 0.75 }
+	defer func() { randFloat = orig }()
+
+	tests := []struct {
+		mode string
+		want []string
+	}{
+		{VariationTempo, []string{"afplay", "-v", "0.50", "-r", "1.050", sound}},
+		{VariationPitch, []string{"afplay", "-v", "0.50", sound}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			runner := NewMockRunner(&bytes.Buffer{})
+			player := &Player{platform: PlatformMacOS}
+			player.SetRunner(runner)
+			player.SetVariation(tt.mode, 0.1)
+
+			if err := player.Play(context.Background(), sound, 0.5); err != nil {
+				t.Fatalf("Play() error = %v", err)
+			}
+			if commands := runner.Commands(); len(commands) != 1 || !slices.Equal(commands[0], tt.want) {
+				t.Errorf("commands = %v, want %v", commands, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Accessibility modes used when the OS asks for fewer sounds.
+const (
+	ReducedSoundVisual = "visual" // Show a desktop notification instead of playing
+	ReducedSoundQuiet  = "quiet"  // Play at a capped volume
+)
+
+// DefaultReducedSoundVolume is the volume cap in "quiet" mode.
+const DefaultReducedSoundVolume = 0.2
+
+// Accessibility configures how notifications adapt to OS preferences for
+// fewer sounds, such as macOS "Play user interface sound effects" or GNOME
+// event sounds being turned off.
+type Accessibility struct {
+	FollowSystem *bool    `json:"followSystem,omitempty"` // Default true
+	Mode         string   `json:"mode,omitempty"`         // "visual" (default) or "quiet"
+	Volume       *float64 `json:"volume,omitempty"`       // Cap in "quiet" mode
+}
+
+// validate checks the accessibility configuration.
+func (a *Accessibility) validate() error {
+	switch a.Mode {
+	case "", ReducedSoundVisual, ReducedSoundQuiet:
+	default:
+		return fmt.Errorf("accessibility.mode: unknown mode %q (use %q or %q)", a.Mode, ReducedSoundVisual, ReducedSoundQuiet)
+	}
+	if a.Volume != nil && (*a.Volume < 0 || *a.Volume > 1) {
+		return fmt.Errorf("accessibility.volume must be between 0 and 1, got %v", *a.Volume)
+	}
+	return nil
+}
+
+// FollowsSystemSound reports whether notifications adapt to the OS sound
+// accessibility preferences.
+func (c *Config) FollowsSystemSound() bool {
+	return c.Accessibility == nil || c.Accessibility.FollowSystem == nil || *c.Accessibility.FollowSystem
+}
+
+// ForReducedSound returns a copy of an event adapted for an OS asking for
+// fewer sounds: its sound channel replaced by a desktop notification, or in
+// "quiet" mode its volume capped.
+func (c *Config) ForReducedSound(e *Event) *Event {
+	result := *e
+	mode, volume := ReducedSoundVisual, DefaultReducedSoundVolume
+	if a := c.Accessibility; a != nil {
+		if a.Mode != "" {
+			mode = a.Mode
+		}
+		if a.Volume != nil {
+			volume = *a.Volume
+		}
+	}
+
+	if mode == ReducedSoundQuiet {
+		if result.Volume == nil || *result.Volume > volume {
+			result.Volume = &volume
+		}
+		return &result
+	}
+	result.Channels = replaceSound(EventChannels(e), ChannelDesktop)
+	return &result
+}
+
+// replaceSound returns channels with the sound channel replaced, without
+// duplicates, or dropped when replacement is empty.
+func replaceSound(channels []string, replacement string) []string {
+	result := make([]string, 0, len(channels)+1)
+	for _, name := range channels {
+		if name == ChannelSound {
+			name = replacement
+		}
+		if name != "" && !slices.Contains(result, name) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestForReducedSound(t *testing.T) {
+	volume := 0.8
+	low := 0.1
+	tests := []struct {
+		name         string
+		access       *Accessibility
+		event        *Event
+		wantChannels []string
+		wantVolume   float64
+	}{
+		{
+			name:         "visual by default",
+			event:        &Event{Volume: &volume},
+			wantChannels: []string{ChannelDesktop},
+			wantVolume:   0.8,
+		},
+		{
+			name:         "visual keeps other channels without duplicates",
+			event:        &Event{Volume: &volume, Channels: []string{ChannelSound, ChannelDesktop, ChannelBark}},
+			wantChannels: []string{ChannelDesktop, ChannelBark},
+			wantVolume:   0.8,
+		},
+		{
+			name:       "quiet caps the volume",
+			access:     &Accessibility{Mode: ReducedSoundQuiet},
+			event:      &Event{Volume: &volume},
+			wantVolume: DefaultReducedSoundVolume,
+		},
+		{
+			name:       "quiet keeps a lower volume",
+			access:     &Accessibility{Mode: ReducedSoundQuiet, Volume: &volume},
+			event:      &Event{Volume: &low},
+			wantVolume: 0.1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Accessibility: tt.access}
+			got := cfg.ForReducedSound(tt.event)
+			if !reflect.DeepEqual(got.Channels, tt.wantChannels) {
+				t.Errorf("Channels = %v, want %v", got.Channels, tt.wantChannels)
+			}
+			if *got.Volume != tt.wantVolume {
+				t.Errorf("Volume = %v, want %v", *got.Volume, tt.wantVolume)
+			}
+			if got == tt.event {
+				t.Error("ForReducedSound() should return a copy")
+			}
+		})
+	}
+}
+
+func TestAccessibilityValidate(t *testing.T) {
+	disabled := false
+	if !(&Config{}).FollowsSystemSound() {
+		t.Error("system sound settings should be followed by default")
+	}
+	if (&Config{Accessibility: &Accessibility{FollowSystem: &disabled}}).FollowsSystemSound() {
+		t.Error("followSystem false should ignore system settings")
+	}
+
+	loud := 1.5
+	for _, a := range []*Accessibility{{Mode: "mute"}, {Volume: &loud}} {
+		if err := (&Config{Accessibility: a}).Validate(); err == nil {
+			t.Errorf("Validate(%+v) should fail", a)
+		}
+	}
+	if err := (&Config{Accessibility: &Accessibility{Mode: ReducedSoundQuiet}}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package config
+
+import "fmt"
+
+// Active call modes.
+const (
+	ActiveCallDesktop = "desktop" // Show a desktop notification instead of playing
+	ActiveCallMute    = "mute"    // Drop the sound, keeping other channels
+)
+
+// ActiveCall keeps notifications from sounding while the microphone or
+// camera is in use, as during a video call. It is off unless "activeCall"
+// is set.
+type ActiveCall struct {
+	Mode string `json:"mode,omitempty"` // "desktop" (default) or "mute"
+}
+
+// validate checks the active call mode.
+func (a *ActiveCall) validate() error {
+	switch a.Mode {
+	case "", ActiveCallDesktop, ActiveCallMute:
+		return nil
+	}
+	return fmt.Errorf("activeCall.mode: unknown mode %q (use %q or %q)", a.Mode, ActiveCallDesktop, ActiveCallMute)
+}
+
+// ForActiveCall returns a copy of an event adapted for a call in progress:
+// its sound channel replaced by a desktop notification, or in "mute" mode
+// dropped. The copy has no channels when only the sound was left.
+func (c *Config) ForActiveCall(e *Event) *Event {
+	replacement := ChannelDesktop
+	if c.ActiveCall != nil && c.ActiveCall.Mode == ActiveCallMute {
+		replacement = ""
+	}
+	result := *e
+	result.Channels = replaceSound(EventChannels(e), replacement)
+	return &result
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestForActiveCall(t *testing.T) {
+	tests := []struct {
+		name       string
+		activeCall *ActiveCall
+		channels   []string
+		want       []string
+	}{
+		{"desktop by default", &ActiveCall{}, nil, []string{ChannelDesktop}},
+		{"desktop without duplicates", &ActiveCall{}, []string{ChannelDesktop, ChannelSound, ChannelBark}, []string{ChannelDesktop, ChannelBark}},
+		{"mute keeps other channels", &ActiveCall{Mode: ActiveCallMute}, []string{ChannelSound, ChannelBark}, []string{ChannelBark}},
+		{"mute only sound", &ActiveCall{Mode: ActiveCallMute}, []string{ChannelSound}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{ActiveCall: tt.activeCall}
+			if got := cfg.ForActiveCall(&Event{Channels: tt.channels}); !reflect.DeepEqual(got.Channels, tt.want) {
+				t.Errorf("Channels = %v, want %v", got.Channels, tt.want)
+			}
+		})
+	}
+}
+
+func TestActiveCallValidate(t *testing.T) {
+	if err := (&Config{ActiveCall: &ActiveCall{Mode: "silent"}}).Validate(); err == nil {
+		t.Error("Validate() should reject unknown modes")
+	}
+	if err := (&Config{ActiveCall: &ActiveCall{Mode: ActiveCallMute}}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// AFPlay rate limits.
+const (
+	MinAFPlayRate = 0.25
+	MaxAFPlayRate = 4.0
+)
+
+// AFPlay passes playback options to afplay, the macOS player, e.g. to speed
+// up a long custom sound without editing the file. Other players ignore it.
+type AFPlay struct {
+	Rate    *float64 `json:"rate,omitempty"`    // Playback speed (-r), e.g. 1.5; pitch is kept
+	Quality *int     `json:"quality,omitempty"` // Rate-scaled playback quality (-q): 0 (afplay's default) or 1 (high)
+}
+
+// validate checks the rate and quality.
+func (a *AFPlay) validate() error {
+	if a.Rate != nil && (*a.Rate < MinAFPlayRate || *a.Rate > MaxAFPlayRate) {
+		return fmt.Errorf("afplay.rate must be %g to %g, got %g", MinAFPlayRate, MaxAFPlayRate, *a.Rate)
+	}
+	if a.Quality != nil && *a.Quality != 0 && *a.Quality != 1 {
+		return errors.New("afplay.quality must be 0 or 1")
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package config
+
+import "testing"
+
+func TestAFPlayValidate(t *testing.T) {
+	rate := func(v float64) *float64 { return &v }
+	quality := func(v int) *int { return &v }
+	tests := []struct {
+		name    string
+		afplay  AFPlay
+		wantErr bool
+	}{
+		{"empty", AFPlay{}, false},
+		{"rate and quality", AFPlay{Rate: rate(1.5), Quality: quality(1)}, false},
+		{"slow", AFPlay{Rate: rate(0.25)}, false},
+		{"rate too low", AFPlay{Rate: rate(0.1)}, true},
+		{"rate too high", AFPlay{Rate: rate(5)}, true},
+		{"bad quality", AFPlay{Quality: quality(2)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Events["stop"].AFPlay = &tt.afplay
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
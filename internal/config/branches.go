@@ -0,0 +1,137 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+)
+
+// BranchRule overrides events when the hook runs in a matching git checkout.
+// Branch and Worktree are glob patterns; a rule matches when every pattern
+// it sets matches.
+type BranchRule struct {
+	Branch   string            `json:"branch,omitempty"`   // e.g. "release/*"
+	Worktree string            `json:"worktree,omitempty"` // e.g. "/home/me/src/app-*"
+	Events   map[string]*Event `json:"events,omitempty"`
+}
+
+// matches reports whether the rule applies to a checkout.
+func (r *BranchRule) matches(branch, worktree string) bool {
+	if r.Branch != "" {
+		if ok, _ := path.Match(r.Branch, branch); !ok || branch == "" {
+			return false
+		}
+	}
+	if r.Worktree != "" {
+		if ok, _ := filepath.Match(r.Worktree, worktree); !ok || worktree == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// ForBranch returns the event with the overrides of every branch rule
+// matching the checkout applied in order. The event itself is not modified.
+func (c *Config) ForBranch(eventType string, e *Event, branch, worktree string) *Event {
+	result := e
+	for _, rule := range c.Branches {
+		override, ok := rule.Events[eventType]
+		if !ok || override == nil || !rule.matches(branch, worktree) {
+			continue
+		}
+		if result == e {
+			copied := *e
+			result = &copied
+		}
+		if override.Sound != "" || override.PlatformSounds != nil {
+			// A sound override replaces the whole spec, platform entries included
+			result.Sound, result.PlatformSounds = "", nil
+		}
+		mergeEvent(result, override)
+	}
+	return result
+}
+
+// validateBranches checks branch rule patterns and event overrides.
+func (c *Config) validateBranches() error {
+	for i, rule := range c.Branches {
+		if rule == nil {
+			return fmt.Errorf("branches[%d]: empty rule", i)
+		}
+		if rule.Branch == "" && rule.Worktree == "" {
+			return fmt.Errorf("branches[%d]: branch or worktree is required", i)
+		}
+		if _, err := path.Match(rule.Branch, ""); err != nil {
+			return fmt.Errorf("branches[%d]: invalid branch pattern %q", i, rule.Branch)
+		}
+		if _, err := filepath.Match(rule.Worktree, ""); err != nil {
+			return fmt.Errorf("branches[%d]: invalid worktree pattern %q", i, rule.Worktree)
+		}
+		for name, event := range rule.Events {
+			if err := c.validateOverride(name, event); err != nil {
+				return fmt.Errorf("branches[%d], event %s: %w", i, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateOverride checks an event override.
+func (c *Config) validateOverride(name string, event *Event) error {
+	if !ValidEvents[name] {
+		return unknownEventType(name)
+	}
+	if event == nil {
+		return nil
+	}
+	if event.Volume != nil && (*event.Volume < 0 || *event.Volume > 1) {
+		return errors.New("volume must be 0.0-1.0")
+	}
+	if event.Gain != nil && (*event.Gain < MinGain || *event.Gain > MaxGain) {
+		return fmt.Errorf("gain must be %.0f to +%.0f dB", MinGain, MaxGain)
+	}
+	if event.Variation != nil {
+		if err := event.Variation.validate(); err != nil {
+			return err
+		}
+	}
+	if event.AFPlay != nil {
+		if err := event.AFPlay.validate(); err != nil {
+			return err
+		}
+	}
+	if event.Cooldown != nil && *event.Cooldown < 0 {
+		return errors.New("cooldown cannot be negative")
+	}
+	if event.DedupeWindow != nil && *event.DedupeWindow < 0 {
+		return errors.New("dedupeWindow cannot be negative")
+	}
+	if err := validatePlatformSounds(event.PlatformSounds); err != nil {
+		return err
+	}
+	if err := validateSessionSounds(event.SessionSounds); err != nil {
+		return err
+	}
+	if event.ResponseLength != nil {
+		if err := event.ResponseLength.validate(); err != nil {
+			return err
+		}
+	}
+	if event.Escalate != nil {
+		if err := event.Escalate.validate(c); err != nil {
+			return err
+		}
+	}
+	if event.Format != nil {
+		if err := event.Format.validate(); err != nil {
+			return err
+		}
+	}
+	if event.Desktop != nil {
+		if err := event.Desktop.validate(); err != nil {
+			return err
+		}
+	}
+	return c.validateChannels(event.Channels)
+}
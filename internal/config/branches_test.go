@@ -0,0 +1,83 @@
+package config
+
+import "testing"
+
+func TestForBranch(t *testing.T) {
+	cfg := Default()
+	cfg.Branches = []*BranchRule{
+		{Branch: "release/*", Events: map[string]*Event{
+			"permission_prompt": {Volume: ptrFloat(1.0), Sound: "bundled:stop"},
+		}},
+		{Worktree: "/src/app-*", Events: map[string]*Event{
+			"permission_prompt": {Volume: ptrFloat(0.8)},
+		}},
+		{Branch: "main", Worktree: "/src/main", Events: map[string]*Event{
+			"stop": {Enabled: ptrBool(false)},
+		}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		event      string
+		branch     string
+		worktree   string
+		wantVolume float64
+		wantSound  string
+	}{
+		{"no match", "permission_prompt", "feature/x", "/src/other", 0.7, "bundled:permission_prompt"},
+		{"branch match", "permission_prompt", "release/1.2", "/src/other", 1.0, "bundled:stop"},
+		{"nested branch does not match", "permission_prompt", "release/1.2/rc", "", 0.7, "bundled:permission_prompt"},
+		{"later rule wins", "permission_prompt", "release/1.2", "/src/app-2", 0.8, "bundled:stop"},
+		{"worktree match", "permission_prompt", "", "/src/app-2", 0.8, "bundled:permission_prompt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := cfg.GetEventConfig(tt.event)
+			got := cfg.ForBranch(tt.event, base, tt.branch, tt.worktree)
+			if *got.Volume != tt.wantVolume {
+				t.Errorf("Volume = %v, want %v", *got.Volume, tt.wantVolume)
+			}
+			if got.Sound != tt.wantSound {
+				t.Errorf("Sound = %q, want %q", got.Sound, tt.wantSound)
+			}
+			if *base.Volume != 0.7 {
+				t.Errorf("base event modified: Volume = %v", *base.Volume)
+			}
+		})
+	}
+
+	// Rules with both patterns need both to match
+	stop := cfg.GetEventConfig("stop")
+	if got := cfg.ForBranch("stop", stop, "main", "/src/other"); !*got.Enabled {
+		t.Error("rule should not match a different worktree")
+	}
+	if got := cfg.ForBranch("stop", stop, "main", "/src/main"); *got.Enabled {
+		t.Error("rule should disable stop on main in /src/main")
+	}
+}
+
+func TestValidateBranches(t *testing.T) {
+	tests := []struct {
+		name string
+		rule *BranchRule
+	}{
+		{"empty rule", nil},
+		{"no pattern", &BranchRule{Events: map[string]*Event{"stop": {}}}},
+		{"bad pattern", &BranchRule{Branch: "release/["}},
+		{"unknown event", &BranchRule{Branch: "main", Events: map[string]*Event{"nope": {}}}},
+		{"bad volume", &BranchRule{Branch: "main", Events: map[string]*Event{"stop": {Volume: ptrFloat(2)}}}},
+		{"unknown channel", &BranchRule{Branch: "main", Events: map[string]*Event{"stop": {Channels: []string{"fax"}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Branches = []*BranchRule{tt.rule}
+			if err := cfg.Validate(); err == nil {
+				t.Error("Validate() expected error")
+			}
+		})
+	}
+}
@@ -7,36 +7,413 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/pack"
 )
 
 // Config represents the full ccbell configuration.
 type Config struct {
-	Enabled       bool                `json:"enabled"`
-	Debug         bool                `json:"debug"`
-	ActiveProfile string              `json:"activeProfile"`
-	QuietHours    *QuietHours         `json:"quietHours,omitempty"`
-	Events        map[string]*Event   `json:"events,omitempty"`
-	Profiles      map[string]*Profile `json:"profiles,omitempty"`
+	Enabled         bool                `json:"enabled"`
+	Debug           bool                `json:"debug"`
+	Strict          bool                `json:"strict,omitempty"` // Fail loading on unknown keys, missing sounds, unreachable profiles
+	ActiveProfile   string              `json:"activeProfile"`
+	ProfileSchedule *ProfileSchedule    `json:"-"` // Set when activeProfile is a weekday/weekend object
+	Theme           string              `json:"theme,omitempty"`
+	Themes          map[string]Theme    `json:"themes,omitempty"`
+	ActivePack      string              `json:"activePack,omitempty"` // Pack whose sounds replace the default bundled sounds
+	QuietHours      *QuietHours         `json:"quietHours,omitempty"`
+	Holidays        *Holidays           `json:"holidays,omitempty"`
+	SoundPaths      []string            `json:"soundPaths,omitempty"`
+	AllowedDirs     []string            `json:"allowedSoundDirs,omitempty"` // When set, custom sounds must be inside one of these
+	Language        string              `json:"language,omitempty"`         // Voice pack language; default from locale
+	SoundLimits     *SoundLimits        `json:"soundLimits,omitempty"`
+	AllowSymlinks   string              `json:"allowSymlinks,omitempty"`
+	SoundPerms      string              `json:"soundPermissions,omitempty"`  // Custom sound owner/mode checks: "off" (default), "not-world-writable" or "owner"
+	PlayerTimeout   *int                `json:"playerTimeout,omitempty"`     // Seconds; 0 disables
+	FreshStart      *int                `json:"freshStartSeconds,omitempty"` // Idle seconds after which an event skips cooldown at full volume; 0 disables
+	MaxVolume       *float64            `json:"maxVolume,omitempty"`         // Caps every sound, after profiles, rules and gain
+	SoundFallback   string              `json:"soundFallback,omitempty"`     // When a sound fails: "desktop" (default), "bell" or "none"
+	AutoInstall     *bool               `json:"autoInstallPlayer,omitempty"` // Install a missing Linux audio player with sudo; default false
+	Players         []string            `json:"players,omitempty"`           // Linux and BSD players to try first, in order
+	Webhook         *Webhook            `json:"webhook,omitempty"`
+	Bark            *Bark               `json:"bark,omitempty"`
+	Retry           *Retry              `json:"retry,omitempty"`
+	CircuitBreaker  *CircuitBreaker     `json:"circuitBreaker,omitempty"`
+	LED             *LED                `json:"led,omitempty"`
+	Text            *Text               `json:"text,omitempty"`
+	Freesound       *Freesound          `json:"freesound,omitempty"`
+	Accessibility   *Accessibility      `json:"accessibility,omitempty"`
+	ScreenLock      *ScreenLock         `json:"screenLock,omitempty"`
+	ActiveCall      *ActiveCall         `json:"activeCall,omitempty"`
+	Sync            *Sync               `json:"sync,omitempty"`
+	Desktop         *Desktop            `json:"desktop,omitempty"`
+	Title           string              `json:"notificationTitle,omitempty"` // Template with {project}, {branch}, {hostname}, {event}
+	SendHostname    *bool               `json:"sendHostname,omitempty"`      // Hostname in webhook and push payloads; default true
+	Terminal        *Terminal           `json:"terminal,omitempty"`
+	SubagentBatch   *SubagentBatch      `json:"subagentBatch,omitempty"`
+	WaitSubagents   *WaitSubagents      `json:"waitForSubagents,omitempty"`
+	Journal         *Journal            `json:"journal,omitempty"`
+	History         *History            `json:"history,omitempty"`
+	Events          map[string]*Event   `json:"events,omitempty"`
+	Profiles        map[string]*Profile `json:"profiles,omitempty"`
+	Branches        []*BranchRule       `json:"branches,omitempty"`
+
+	// UnknownKeys lists keys in the loaded files that no setting reads,
+	// prefixed with the file name, e.g. misspellings like "quitHours".
+	UnknownKeys []string `json:"-"`
+
+	// unreachableProfiles describes profile files hidden by a profile of
+	// the same name in the main config.
+	unreachableProfiles []string
 }
 
 // defaultProfileName is the name of the default profile.
 const defaultProfileName = "default"
 
+// ConfigEnvVar is the environment variable that overrides the config file path.
+const ConfigEnvVar = "CCBELL_CONFIG"
+
+// SystemConfigEnvVar is the environment variable that overrides the system-wide
+// base config path.
+const SystemConfigEnvVar = "CCBELL_SYSTEM_CONFIG"
+
+// defaultSystemConfigPath is the system-wide base config shared by all users.
+const defaultSystemConfigPath = "/etc/ccbell/config.json"
+
 // QuietHours represents do-not-disturb time window.
 type QuietHours struct {
-	Start string `json:"start"` // HH:MM format
-	End   string `json:"end"`   // HH:MM format
+	Start     string      `json:"start"`               // HH:MM, "sunrise" or "sunset", e.g. "sunset+30m"
+	End       string      `json:"end"`                 // Same formats as Start
+	Latitude  *float64    `json:"latitude,omitempty"`  // For sunrise/sunset; default: the time zone's location
+	Longitude *float64    `json:"longitude,omitempty"` // Degrees east
+	Weekend   *QuietHours `json:"weekend,omitempty"`   // Window used on weekends and holidays instead
+}
+
+// SoundLimits caps the size and duration of user-supplied sound files.
+// Unset values use defaults; 0 disables the check.
+type SoundLimits struct {
+	MaxSizeKB          *int `json:"maxSizeKB,omitempty"`
+	MaxDurationSeconds *int `json:"maxDurationSeconds,omitempty"`
+}
+
+// Default sound limits applied when not configured.
+const (
+	DefaultMaxSoundSizeKB          = 10 * 1024
+	DefaultMaxSoundDurationSeconds = 30
+)
+
+// Webhook configures the webhook notification channel.
+type Webhook struct {
+	URL      string            `json:"url"`                // http(s) URL or "secret:<name>"
+	Headers  map[string]string `json:"headers,omitempty"`  // Values may be "secret:<name>"
+	Preset   string            `json:"preset,omitempty"`   // Payload shape: "ifttt" or "zapier"
+	Template string            `json:"template,omitempty"` // Go template for the JSON body; overrides preset
+	Timeout  *int              `json:"timeout,omitempty"`  // Seconds
+}
+
+// ValidWebhookPresets is the whitelist of webhook payload presets.
+var ValidWebhookPresets = map[string]bool{
+	"ifttt":  true,
+	"zapier": true,
+}
+
+// Bark configures the Bark (iOS push) notification channel.
+type Bark struct {
+	Server    string `json:"server,omitempty"`  // Defaults to https://api.day.app
+	DeviceKey string `json:"deviceKey"`         // Key or "secret:<name>"
+	Body      string `json:"body,omitempty"`    // Go template for the push text
+	Timeout   *int   `json:"timeout,omitempty"` // Seconds
+}
+
+// LED configures the blink(1) USB LED notification channel.
+type LED struct {
+	Colors map[string]string `json:"colors,omitempty"` // Event -> #rrggbb
+	Blinks *int              `json:"blinks,omitempty"`
+}
+
+// ledColorPattern matches #rrggbb colors.
+var ledColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// validate checks the LED configuration.
+func (l *LED) validate() error {
+	for event, color := range l.Colors {
+		if !ValidEvents[event] {
+			return fmt.Errorf("led.colors: %w", unknownEventType(event))
+		}
+		if !ledColorPattern.MatchString(color) {
+			return fmt.Errorf("led.colors: invalid color for %s: %s (use #rrggbb)", event, color)
+		}
+	}
+	if l.Blinks != nil && (*l.Blinks < 1 || *l.Blinks > 20) {
+		return errors.New("led.blinks must be 1-20")
+	}
+	return nil
+}
+
+// Text configures the screen-reader friendly text channel.
+type Text struct {
+	Speak *bool `json:"speak,omitempty"` // Also read the message aloud
+}
+
+// Freesound configures the "ccbell sounds" commands.
+type Freesound struct {
+	Token string `json:"token"` // API token or "secret:<name>"
+}
+
+// Sync configures "ccbell config push/pull": the git repository or gist
+// that keeps the config, and optionally custom sounds, in step across
+// machines.
+type Sync struct {
+	Repo string `json:"repo,omitempty"` // Git URL or local path
+	Gist string `json:"gist,omitempty"` // Gist ID
+}
+
+// gistIDPattern matches gist IDs.
+var gistIDPattern = regexp.MustCompile(`^[0-9A-Za-z]+$`)
+
+// validate checks the sync configuration.
+func (s *Sync) validate() error {
+	if s.Repo != "" && s.Gist != "" {
+		return errors.New("sync: set either repo or gist, not both")
+	}
+	if s.Gist != "" && !gistIDPattern.MatchString(s.Gist) {
+		return fmt.Errorf("sync.gist: invalid gist ID: %s", s.Gist)
+	}
+	return nil
+}
+
+// Remote returns the git URL to sync with, or "" if none is set.
+func (s *Sync) Remote() string {
+	if s.Gist != "" {
+		return "https://gist.github.com/" + s.Gist + ".git"
+	}
+	return s.Repo
+}
+
+// Desktop configures the desktop notification channel.
+type Desktop struct {
+	// FocusApp is activated when the notification is clicked: an application
+	// name, "auto" to detect the terminal, or empty to disable.
+	FocusApp string `json:"focusApp,omitempty"`
+	// Group is how many seconds an event's desktop notification absorbs
+	// repeats: they replace it, counting the events, instead of stacking
+	// another. 0 (default) disables.
+	Group *int `json:"group,omitempty"`
+	// BypassDND lets critical events break through do-not-disturb where the
+	// platform allows: urgency=critical on Linux, terminal-notifier's
+	// -ignoreDnD on macOS. Default true.
+	BypassDND *bool `json:"bypassDnd,omitempty"`
+}
+
+// Terminal configures the terminal escape-sequence notification channel.
+type Terminal struct {
+	Sequence string `json:"sequence,omitempty"` // "osc9" (default) or "osc777"
+	UserVar  string `json:"userVar,omitempty"`  // Variable set by the uservar channel
+}
+
+// userVarPattern matches valid terminal user variable names.
+var userVarPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// ValidTerminalSequences is the whitelist of terminal notification sequences.
+var ValidTerminalSequences = map[string]bool{
+	"osc9":   true,
+	"osc777": true,
+}
+
+// SubagentBatch configures summarizing subagent completions into a single
+// notification once no more have arrived for the quiet period.
+type SubagentBatch struct {
+	Enabled     *bool `json:"enabled,omitempty"`
+	QuietPeriod *int  `json:"quietPeriod,omitempty"` // Seconds
+}
+
+// DefaultSubagentQuietPeriod is the batching quiet period in seconds.
+const DefaultSubagentQuietPeriod = 5
+
+// WaitSubagents configures holding back subagent notifications until the
+// session's final stop event, which then plays a distinct sound.
+type WaitSubagents struct {
+	Enabled *bool  `json:"enabled,omitempty"`
+	Sound   string `json:"sound,omitempty"` // Defaults to an OS "complete" sound
+}
+
+// defaultAllDoneSounds are the per-platform sounds for the final stop event.
+var defaultAllDoneSounds = map[string]string{
+	"macos": "system:Glass",
+	"linux": "system:complete",
+}
+
+// Journal configures recording of received hook payloads.
+type Journal struct {
+	Enabled   *bool `json:"enabled,omitempty"`
+	MaxSizeKB *int  `json:"maxSizeKB,omitempty"` // Rotate after this size
+}
+
+// DefaultJournalMaxSizeKB is the journal size in KB before rotation.
+const DefaultJournalMaxSizeKB = 1024
+
+// History configures the notification history database used by
+// "ccbell stats".
+type History struct {
+	Enabled       *bool `json:"enabled,omitempty"`
+	RetentionDays *int  `json:"retentionDays,omitempty"` // Prune older records
+}
+
+// DefaultHistoryRetentionDays is how long history records are kept.
+const DefaultHistoryRetentionDays = 90
+
+// Variation randomizes each playback slightly so repeated sounds feel less
+// robotic.
+type Variation struct {
+	Mode   string  `json:"mode"`   // "pitch" or "tempo"
+	Amount float64 `json:"amount"` // Fraction, e.g. 0.05 for up to ±5%
+
+	// PerSession derives the variation from the hook's session ID instead
+	// of randomizing it, so each session has its own recognizable sound.
+	PerSession bool `json:"perSession,omitempty"`
+}
+
+// MaxVariation caps the variation amount.
+const MaxVariation = 0.25
+
+// validate checks the variation settings.
+func (v *Variation) validate() error {
+	if v.Mode != "pitch" && v.Mode != "tempo" {
+		return fmt.Errorf("variation.mode must be pitch or tempo, got %q", v.Mode)
+	}
+	if v.Amount <= 0 || v.Amount > MaxVariation {
+		return fmt.Errorf("variation.amount must be greater than 0 and at most %g", MaxVariation)
+	}
+	return nil
+}
+
+// languagePattern matches language tags such as "en", "pt-BR" or "pt_BR".
+var languagePattern = regexp.MustCompile(`^[A-Za-z]{2,3}([-_][A-Za-z0-9]+)*$`)
+
+// Gain limits in dB.
+const (
+	MinGain = -40.0
+	MaxGain = 20.0
+)
+
+// Channel names.
+const (
+	ChannelSound    = "sound"
+	ChannelDesktop  = "desktop"
+	ChannelWebhook  = "webhook"
+	ChannelTerminal = "terminal"
+	ChannelUserVar  = "uservar"
+	ChannelBark     = "bark"
+	ChannelLED      = "led"
+	ChannelText     = "text"
+)
+
+// ValidChannels is the whitelist of notification channel names.
+var ValidChannels = map[string]bool{
+	ChannelSound:    true,
+	ChannelDesktop:  true,
+	ChannelWebhook:  true,
+	ChannelTerminal: true,
+	ChannelUserVar:  true,
+	ChannelBark:     true,
+	ChannelLED:      true,
+	ChannelText:     true,
+}
+
+// DefaultChannels are used for events that don't configure channels.
+var DefaultChannels = []string{ChannelSound}
+
+// ValidSymlinkPolicies is the set of allowed "allowSymlinks" values.
+var ValidSymlinkPolicies = map[string]bool{
+	"deny":                true,
+	"resolve-within-root": true,
+	"allow":               true,
+}
+
+// ValidSoundPermissions is the set of allowed "soundPermissions" values.
+var ValidSoundPermissions = map[string]bool{
+	"off":                true,
+	"not-world-writable": true,
+	"owner":              true,
+}
+
+// Sound fallbacks used when a sound can't be played.
+const (
+	SoundFallbackDesktop = "desktop" // Show a desktop notification (default)
+	SoundFallbackBell    = "bell"    // Ring the terminal bell
+	SoundFallbackNone    = "none"
+)
+
+// ValidPlayers is the set of audio players "players" may name.
+var ValidPlayers = map[string]bool{
+	"mpv":    true,
+	"paplay": true,
+	"aplay":  true,
+	"ffplay": true,
+	"aucat":  true,
+}
+
+// ValidSoundFallbacks is the set of allowed "soundFallback" values.
+var ValidSoundFallbacks = map[string]bool{
+	SoundFallbackDesktop: true,
+	SoundFallbackBell:    true,
+	SoundFallbackNone:    true,
 }
 
 // Event represents configuration for a single event type.
 type Event struct {
-	Enabled  *bool    `json:"enabled,omitempty"`
-	Sound    string   `json:"sound,omitempty"`
-	Volume   *float64 `json:"volume,omitempty"`
-	Cooldown *int     `json:"cooldown,omitempty"`
+	Enabled   *bool      `json:"enabled,omitempty"`
+	Sound     string     `json:"sound,omitempty"`
+	Volume    *float64   `json:"volume,omitempty"`
+	Gain      *float64   `json:"gain,omitempty"` // Loudness adjustment in dB
+	Variation *Variation `json:"variation,omitempty"`
+	Cooldown  *int       `json:"cooldown,omitempty"`
+	Channels  []string   `json:"channels,omitempty"`
+
+	// DedupeWindow suppresses repeats of an identical hook payload within
+	// this many seconds. Unlike cooldown, different payloads still notify.
+	DedupeWindow *int `json:"dedupeWindow,omitempty"`
+
+	// ResponseLength overrides the sound or volume for short and long
+	// responses.
+	ResponseLength *ResponseLength `json:"responseLength,omitempty"`
+
+	// Escalate re-sends the notification if it isn't acknowledged in time.
+	Escalate *Escalation `json:"escalate,omitempty"`
+
+	// SessionSounds are sound variants; each session plays the one picked by
+	// its session ID instead of "sound".
+	SessionSounds []string `json:"sessionSounds,omitempty"`
+
+	// Format sets the icon, severity and text template for text channels.
+	Format *Format `json:"format,omitempty"`
+
+	// Desktop sets the desktop notification's timeout and urgency.
+	Desktop *EventDesktop `json:"desktop,omitempty"`
+
+	// AFPlay sets afplay's playback rate and quality on macOS.
+	AFPlay *AFPlay `json:"afplay,omitempty"`
+
+	// PlatformSounds holds per-platform sound specs, set when "sound" is an
+	// object such as {"macos": "system:Glass", "linux": "bundled:stop"}.
+	// The "default" key (if any) is stored in Sound.
+	PlatformSounds map[string]string `json:"-"`
+}
+
+// ValidPlatforms is the set of platform keys allowed in per-platform sounds.
+var ValidPlatforms = map[string]bool{
+	"macos": true,
+	"linux": true,
+	"bsd":   true,
 }
 
+// defaultPlatformKey is the fallback key in a per-platform sound object.
+const defaultPlatformKey = "default"
+
 // Profile represents a named configuration preset.
 type Profile struct {
 	Events map[string]*Event `json:"events,omitempty"`
@@ -76,40 +453,204 @@ func Default() *Config {
 	}
 }
 
+// Path returns the config file path. The CCBELL_CONFIG environment variable
+// takes precedence over the global config at ~/.claude/ccbell.config.json.
+// Returns an empty string if neither is available.
+func Path(homeDir string) string {
+	if override := os.Getenv(ConfigEnvVar); override != "" {
+		return override
+	}
+	if homeDir == "" {
+		return ""
+	}
+	return filepath.Join(homeDir, ".claude", "ccbell.config.json")
+}
+
 // Load reads configuration from file, falling back to defaults.
-// It only checks the global config at ~/.claude/ccbell.config.json.
+// The file location is determined by Path.
 func Load(homeDir string) (*Config, string, error) {
+	return LoadFile(Path(homeDir))
+}
+
+// SystemPath returns the system-wide base config path. The CCBELL_SYSTEM_CONFIG
+// environment variable takes precedence over /etc/ccbell/config.json.
+func SystemPath() string {
+	if override := os.Getenv(SystemConfigEnvVar); override != "" {
+		return override
+	}
+	return defaultSystemConfigPath
+}
+
+// LoadFile reads configuration from the given file, falling back to defaults
+// if the path is empty or the file does not exist. The system-wide base config
+// (see SystemPath) is applied first, so the user config only overrides the
+// values it sets.
+func LoadFile(path string) (*Config, string, error) {
+	return loadFile(path, false)
+}
+
+// Disabled reports whether notifications are off, reading only the
+// "enabled", "strict" and "journal" keys of the system and user configs, so
+// hooks can exit without a full load. It is false when anything else is
+// still due: journaling, strict checks, or files that can't be read.
+func Disabled(path string) bool {
+	quick := struct {
+		Enabled bool     `json:"enabled"`
+		Strict  bool     `json:"strict"`
+		Journal *Journal `json:"journal"`
+	}{Enabled: true}
+	for _, p := range []string{SystemPath(), path} {
+		if p == "" {
+			continue
+		}
+		data, err := os.ReadFile(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil || json.Unmarshal(data, &quick) != nil {
+			return false
+		}
+	}
+	_, journaling := (&Config{Journal: quick.Journal}).JournalMaxSizeKB()
+	return !quick.Enabled && !quick.Strict && !journaling
+}
+
+// Language returns the "language" of the system and user configs, reading
+// only that key, so the CLI can pick the language of its messages before
+// the config is loaded. It is empty when neither sets one.
+func Language(path string) string {
+	var quick struct {
+		Language string `json:"language"`
+	}
+	for _, p := range []string{SystemPath(), path} {
+		if p == "" {
+			continue
+		}
+		if data, err := os.ReadFile(p); err == nil {
+			_ = json.Unmarshal(data, &quick)
+		}
+	}
+	return quick.Language
+}
+
+// LoadFileStrict loads a config like LoadFile, applying the strict checks
+// even if the config doesn't set "strict".
+func LoadFileStrict(path string) (*Config, string, error) {
+	return loadFile(path, true)
+}
+
+// loadFile loads, expands and validates the config. Errors of a strict
+// load wrap ErrStrict, so callers can fail instead of using defaults.
+func loadFile(path string, strict bool) (_ *Config, configPath string, err error) {
 	cfg := Default()
-	configPath := ""
+	defer func() {
+		if err != nil && (strict || cfg.Strict) {
+			err = fmt.Errorf("%w: %w", ErrStrict, err)
+		}
+	}()
 
-	// Load global config
-	if homeDir != "" {
-		globalConfig := filepath.Join(homeDir, ".claude", "ccbell.config.json")
-		if data, err := os.ReadFile(globalConfig); err == nil {
+	// Apply system-wide base layer
+	systemLoaded := false
+	if systemPath := SystemPath(); systemPath != "" {
+		if data, err := os.ReadFile(systemPath); err == nil {
 			if err := json.Unmarshal(data, cfg); err != nil {
-				return nil, "", fmt.Errorf("invalid JSON in %s: %w", globalConfig, err)
+				return nil, "", fmt.Errorf("invalid JSON in %s: %w", systemPath, err)
 			}
-			configPath = globalConfig
+			cfg.addUnknownKeys(systemPath, data, reflect.TypeOf(Config{}))
+			configPath = systemPath
+			systemLoaded = true
 		}
 	}
 
+	if path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			if err := cfg.applyLayer(data, systemLoaded); err != nil {
+				return nil, "", fmt.Errorf("invalid JSON in %s: %w", path, err)
+			}
+			cfg.addUnknownKeys(path, data, reflect.TypeOf(Config{}))
+			configPath = path
+		}
+
+		// Load additional profiles from separate files
+		if err := cfg.loadProfileFiles(ProfilesDir(path)); err != nil {
+			return nil, configPath, err
+		}
+	}
+
+	// Expand ${VAR} references before validating paths
+	cfg.expandEnvRefs()
+
+	if cfg.Holidays != nil {
+		if err := cfg.Holidays.load(); err != nil {
+			return nil, configPath, err
+		}
+	}
+
+	// Pick today's profile when activeProfile is a schedule
+	cfg.resolveProfileSchedule(time.Now())
+
 	// Validate after loading
 	if err := cfg.Validate(); err != nil {
 		return nil, configPath, fmt.Errorf("config validation failed: %w", err)
 	}
+	if strict || cfg.Strict {
+		if err := cfg.checkStrict(); err != nil {
+			return nil, configPath, err
+		}
+	}
 
 	return cfg, configPath, nil
 }
 
+// applyLayer decodes data over the current configuration. When mergeEvents is
+// set, event entries are merged field by field with the existing ones instead
+// of replacing them, so a base layer's settings survive partial overrides.
+func (c *Config) applyLayer(data []byte, mergeEvents bool) error {
+	base := make(map[string]*Event, len(c.Events))
+	for name, event := range c.Events {
+		copied := *event
+		base[name] = &copied
+	}
+
+	if err := json.Unmarshal(data, c); err != nil {
+		return err
+	}
+	if !mergeEvents {
+		return nil
+	}
+
+	for name, event := range c.Events {
+		if baseEvent, ok := base[name]; ok && event != nil {
+			mergeEvent(baseEvent, event)
+			c.Events[name] = baseEvent
+		}
+	}
+	return nil
+}
+
 // EnsureConfig creates default config file if it doesn't exist.
 func EnsureConfig(homeDir string) error {
-	configPath := filepath.Join(homeDir, ".claude", "ccbell.config.json")
+	configPath := Path(homeDir)
+	if configPath == "" {
+		configPath = filepath.Join(homeDir, ".claude", "ccbell.config.json")
+	}
+	return EnsureConfigFile(configPath)
+}
+
+// EnsureConfigFile creates a default config file at path if it doesn't exist.
+func EnsureConfigFile(configPath string) error {
 	if _, err := os.Stat(configPath); err == nil {
 		return nil // Already exists
 	}
 
-	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
+	// Another hook may be creating it at the same time
+	unlock, err := lockFile(configPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if _, err := os.Stat(configPath); err == nil {
+		return nil
 	}
 
 	data, err := json.MarshalIndent(Default(), "", "  ")
@@ -117,7 +658,7 @@ func EnsureConfig(homeDir string) error {
 		return fmt.Errorf("failed to marshal default config: %w", err)
 	}
 
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
+	if err := writeFileAtomic(configPath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 
@@ -127,53 +668,520 @@ func EnsureConfig(homeDir string) error {
 // Validate checks the configuration for errors.
 func (c *Config) Validate() error {
 	// Validate quiet hours format
-	if c.QuietHours != nil {
-		if c.QuietHours.Start != "" && !timeFormatRegex.MatchString(c.QuietHours.Start) {
-			return fmt.Errorf("invalid quietHours.start format: %s (expected HH:MM)", c.QuietHours.Start)
+	if qh := c.QuietHours; qh != nil {
+		if err := validateQuietTime("quietHours.start", qh.Start); err != nil {
+			return err
 		}
-		if c.QuietHours.End != "" && !timeFormatRegex.MatchString(c.QuietHours.End) {
-			return fmt.Errorf("invalid quietHours.end format: %s (expected HH:MM)", c.QuietHours.End)
+		if err := validateQuietTime("quietHours.end", qh.End); err != nil {
+			return err
+		}
+		if (qh.Latitude == nil) != (qh.Longitude == nil) {
+			return fmt.Errorf("quietHours: latitude and longitude must be set together")
+		}
+		if qh.Latitude != nil && (*qh.Latitude < -90 || *qh.Latitude > 90) {
+			return fmt.Errorf("quietHours.latitude must be between -90 and 90, got %v", *qh.Latitude)
+		}
+		if qh.Longitude != nil && (*qh.Longitude < -180 || *qh.Longitude > 180) {
+			return fmt.Errorf("quietHours.longitude must be between -180 and 180, got %v", *qh.Longitude)
+		}
+		if w := qh.Weekend; w != nil {
+			if err := validateQuietTime("quietHours.weekend.start", w.Start); err != nil {
+				return err
+			}
+			if err := validateQuietTime("quietHours.weekend.end", w.End); err != nil {
+				return err
+			}
+			if w.Latitude != nil || w.Longitude != nil {
+				return fmt.Errorf("quietHours.weekend: set latitude and longitude on quietHours")
+			}
+			if w.Weekend != nil {
+				return fmt.Errorf("quietHours.weekend: nested weekend not allowed")
+			}
+		}
+	}
+
+	if err := validateTitle(c.Title); err != nil {
+		return err
+	}
+
+	// Validate sound search paths
+	for _, dir := range c.SoundPaths {
+		if !filepath.IsAbs(dir) {
+			return fmt.Errorf("soundPaths: must be absolute path: %s", dir)
+		}
+		if strings.Contains(dir, "..") {
+			return fmt.Errorf("soundPaths: path traversal not allowed: %s", dir)
+		}
+	}
+
+	// Validate allowed sound directories
+	for _, dir := range c.AllowedDirs {
+		if !filepath.IsAbs(dir) {
+			return fmt.Errorf("allowedSoundDirs: must be absolute path: %s", dir)
+		}
+		if strings.Contains(dir, "..") {
+			return fmt.Errorf("allowedSoundDirs: path traversal not allowed: %s", dir)
+		}
+	}
+
+	// Validate sound limits
+	if c.SoundLimits != nil {
+		if c.SoundLimits.MaxSizeKB != nil && *c.SoundLimits.MaxSizeKB < 0 {
+			return errors.New("soundLimits.maxSizeKB cannot be negative")
+		}
+		if c.SoundLimits.MaxDurationSeconds != nil && *c.SoundLimits.MaxDurationSeconds < 0 {
+			return errors.New("soundLimits.maxDurationSeconds cannot be negative")
+		}
+	}
+
+	// Validate player timeout
+	if c.PlayerTimeout != nil && *c.PlayerTimeout < 0 {
+		return errors.New("playerTimeout cannot be negative")
+	}
+	if c.FreshStart != nil && *c.FreshStart < 0 {
+		return errors.New("freshStartSeconds cannot be negative")
+	}
+
+	// Validate maximum volume
+	if c.MaxVolume != nil && (*c.MaxVolume < 0 || *c.MaxVolume > 1) {
+		return fmt.Errorf("maxVolume must be 0.0-1.0, got %f", *c.MaxVolume)
+	}
+
+	// Validate bark
+	if c.Bark != nil {
+		if err := c.Bark.validate(); err != nil {
+			return err
+		}
+	}
+
+	// Validate themes
+	if err := c.validateThemes(); err != nil {
+		return err
+	}
+
+	// Validate active pack
+	if c.ActivePack != "" && !pack.ValidID(c.ActivePack) {
+		return fmt.Errorf("invalid activePack: %s (use a pack directory name such as retro)", c.ActivePack)
+	}
+
+	// Validate language
+	if c.Language != "" && !languagePattern.MatchString(c.Language) {
+		return fmt.Errorf("invalid language: %s (use a tag such as en or pt-BR)", c.Language)
+	}
+
+	// Validate LED
+	if c.LED != nil {
+		if err := c.LED.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.Accessibility != nil {
+		if err := c.Accessibility.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.Retry != nil {
+		if err := c.Retry.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.CircuitBreaker != nil {
+		if err := c.CircuitBreaker.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.ScreenLock != nil {
+		if err := c.ScreenLock.validate(c); err != nil {
+			return err
+		}
+	}
+
+	if c.ActiveCall != nil {
+		if err := c.ActiveCall.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.Sync != nil {
+		if err := c.Sync.validate(); err != nil {
+			return err
+		}
+	}
+
+	// Validate terminal sequence
+	if c.Terminal != nil && c.Terminal.Sequence != "" && !ValidTerminalSequences[c.Terminal.Sequence] {
+		return fmt.Errorf("invalid terminal.sequence: %s (use osc9 or osc777)", c.Terminal.Sequence)
+	}
+	if c.Terminal != nil && c.Terminal.UserVar != "" && !userVarPattern.MatchString(c.Terminal.UserVar) {
+		return fmt.Errorf("invalid terminal.userVar: %s (use letters, digits and underscores)", c.Terminal.UserVar)
+	}
+
+	// Validate desktop grouping
+	if c.Desktop != nil && c.Desktop.Group != nil && *c.Desktop.Group < 0 {
+		return errors.New("desktop.group cannot be negative")
+	}
+
+	// Validate webhook
+	if c.Webhook != nil {
+		if err := c.Webhook.validate(); err != nil {
+			return err
+		}
+	}
+
+	// Validate subagent batching
+	if c.SubagentBatch != nil && c.SubagentBatch.QuietPeriod != nil && *c.SubagentBatch.QuietPeriod <= 0 {
+		return errors.New("subagentBatch.quietPeriod must be positive")
+	}
+
+	// Validate journal
+	if c.Journal != nil && c.Journal.MaxSizeKB != nil && *c.Journal.MaxSizeKB <= 0 {
+		return errors.New("journal.maxSizeKB must be positive")
+	}
+
+	// Validate history
+	if c.History != nil && c.History.RetentionDays != nil && *c.History.RetentionDays <= 0 {
+		return errors.New("history.retentionDays must be positive")
+	}
+
+	// Validate symlink policy
+	if c.AllowSymlinks != "" && !ValidSymlinkPolicies[c.AllowSymlinks] {
+		return fmt.Errorf("invalid allowSymlinks: %s (valid: deny, resolve-within-root, allow)", c.AllowSymlinks)
+	}
+
+	// Validate sound permission policy
+	if c.SoundPerms != "" && !ValidSoundPermissions[c.SoundPerms] {
+		return fmt.Errorf("invalid soundPermissions: %s (valid: off, not-world-writable, owner)", c.SoundPerms)
+	}
+
+	// Validate sound fallback
+	if c.SoundFallback != "" && !ValidSoundFallbacks[c.SoundFallback] {
+		return fmt.Errorf("invalid soundFallback: %s (valid: desktop, bell, none)", c.SoundFallback)
+	}
+
+	// Validate player preference
+	for _, name := range c.Players {
+		if !ValidPlayers[name] {
+			return fmt.Errorf("invalid players entry: %s (valid: mpv, paplay, aplay, ffplay, aucat)", name)
 		}
 	}
 
 	// Validate activeProfile exists in Profiles (if not default)
 	if c.ActiveProfile != "" && c.ActiveProfile != defaultProfileName {
-		if _, ok := c.Profiles[c.ActiveProfile]; !ok {
+		if _, ok := c.GetProfile(c.ActiveProfile); !ok {
 			return fmt.Errorf("activeProfile %q not found in profiles", c.ActiveProfile)
 		}
 	}
+	if err := c.validateProfileSchedule(); err != nil {
+		return err
+	}
 
 	// Validate event configs
 	for name, event := range c.Events {
 		if !ValidEvents[name] {
-			return fmt.Errorf("unknown event type: %s", name)
+			return unknownEventType(name)
 		}
 		if event.Volume != nil && (*event.Volume < 0 || *event.Volume > 1) {
 			return fmt.Errorf("event %s: volume must be 0.0-1.0, got %f", name, *event.Volume)
 		}
+		if event.Gain != nil && (*event.Gain < MinGain || *event.Gain > MaxGain) {
+			return fmt.Errorf("event %s: gain must be %.0f to +%.0f dB, got %g", name, MinGain, MaxGain, *event.Gain)
+		}
+		if event.Variation != nil {
+			if err := event.Variation.validate(); err != nil {
+				return fmt.Errorf("event %s: %w", name, err)
+			}
+		}
+		if event.AFPlay != nil {
+			if err := event.AFPlay.validate(); err != nil {
+				return fmt.Errorf("event %s: %w", name, err)
+			}
+		}
 		if event.Cooldown != nil && *event.Cooldown < 0 {
 			return fmt.Errorf("event %s: cooldown cannot be negative", name)
 		}
+		if event.DedupeWindow != nil && *event.DedupeWindow < 0 {
+			return fmt.Errorf("event %s: dedupeWindow cannot be negative", name)
+		}
+		if err := validatePlatformSounds(event.PlatformSounds); err != nil {
+			return fmt.Errorf("event %s: %w", name, err)
+		}
+		if err := validateSessionSounds(event.SessionSounds); err != nil {
+			return fmt.Errorf("event %s: %w", name, err)
+		}
+		if event.ResponseLength != nil {
+			if err := event.ResponseLength.validate(); err != nil {
+				return fmt.Errorf("event %s: %w", name, err)
+			}
+		}
+		if err := c.validateChannels(event.Channels); err != nil {
+			return fmt.Errorf("event %s: %w", name, err)
+		}
+		if event.Escalate != nil {
+			if err := event.Escalate.validate(c); err != nil {
+				return fmt.Errorf("event %s: %w", name, err)
+			}
+		}
+		if event.Format != nil {
+			if err := event.Format.validate(); err != nil {
+				return fmt.Errorf("event %s: %w", name, err)
+			}
+		}
+		if event.Desktop != nil {
+			if err := event.Desktop.validate(); err != nil {
+				return fmt.Errorf("event %s: %w", name, err)
+			}
+		}
 	}
 
 	// Validate profile event configs
 	for profileName, profile := range c.Profiles {
 		for eventName, event := range profile.Events {
 			if !ValidEvents[eventName] {
-				return fmt.Errorf("profile %s: unknown event type: %s", profileName, eventName)
+				return fmt.Errorf("profile %s: %w", profileName, unknownEventType(eventName))
 			}
 			if event.Volume != nil && (*event.Volume < 0 || *event.Volume > 1) {
 				return fmt.Errorf("profile %s, event %s: volume must be 0.0-1.0", profileName, eventName)
 			}
+			if event.Gain != nil && (*event.Gain < MinGain || *event.Gain > MaxGain) {
+				return fmt.Errorf("profile %s, event %s: gain must be %.0f to +%.0f dB", profileName, eventName, MinGain, MaxGain)
+			}
+			if event.Variation != nil {
+				if err := event.Variation.validate(); err != nil {
+					return fmt.Errorf("profile %s, event %s: %w", profileName, eventName, err)
+				}
+			}
+			if event.AFPlay != nil {
+				if err := event.AFPlay.validate(); err != nil {
+					return fmt.Errorf("profile %s, event %s: %w", profileName, eventName, err)
+				}
+			}
 			if event.Cooldown != nil && *event.Cooldown < 0 {
 				return fmt.Errorf("profile %s, event %s: cooldown cannot be negative", profileName, eventName)
 			}
+			if event.DedupeWindow != nil && *event.DedupeWindow < 0 {
+				return fmt.Errorf("profile %s, event %s: dedupeWindow cannot be negative", profileName, eventName)
+			}
+			if err := validatePlatformSounds(event.PlatformSounds); err != nil {
+				return fmt.Errorf("profile %s, event %s: %w", profileName, eventName, err)
+			}
+			if err := validateSessionSounds(event.SessionSounds); err != nil {
+				return fmt.Errorf("profile %s, event %s: %w", profileName, eventName, err)
+			}
+			if event.ResponseLength != nil {
+				if err := event.ResponseLength.validate(); err != nil {
+					return fmt.Errorf("profile %s, event %s: %w", profileName, eventName, err)
+				}
+			}
+			if err := c.validateChannels(event.Channels); err != nil {
+				return fmt.Errorf("profile %s, event %s: %w", profileName, eventName, err)
+			}
+			if event.Escalate != nil {
+				if err := event.Escalate.validate(c); err != nil {
+					return fmt.Errorf("profile %s, event %s: %w", profileName, eventName, err)
+				}
+			}
+			if event.Format != nil {
+				if err := event.Format.validate(); err != nil {
+					return fmt.Errorf("profile %s, event %s: %w", profileName, eventName, err)
+				}
+			}
+			if event.Desktop != nil {
+				if err := event.Desktop.validate(); err != nil {
+					return fmt.Errorf("profile %s, event %s: %w", profileName, eventName, err)
+				}
+			}
 		}
 	}
 
+	return c.validateBranches()
+}
+
+// titlePlaceholderPattern matches placeholders in a notification title.
+var titlePlaceholderPattern = regexp.MustCompile(`\{[a-z]+\}`)
+
+// validTitlePlaceholders lists the placeholders a notification title can use.
+var validTitlePlaceholders = map[string]bool{
+	"{project}":  true,
+	"{branch}":   true,
+	"{hostname}": true,
+	"{event}":    true,
+}
+
+// validateTitle checks a notification title template's placeholders.
+func validateTitle(title string) error {
+	for _, placeholder := range titlePlaceholderPattern.FindAllString(title, -1) {
+		if !validTitlePlaceholders[placeholder] {
+			return fmt.Errorf("notificationTitle: unknown placeholder %s (use {project}, {branch}, {hostname} or {event})", placeholder)
+		}
+	}
 	return nil
 }
 
+// validateChannels checks channel names and that used channels are configured.
+func (c *Config) validateChannels(channels []string) error {
+	for _, name := range channels {
+		if !ValidChannels[name] {
+			return fmt.Errorf("unknown channel: %s", name)
+		}
+		if name == ChannelWebhook && (c.Webhook == nil || c.Webhook.URL == "") {
+			return errors.New("webhook channel requires webhook.url")
+		}
+		if name == ChannelBark && (c.Bark == nil || c.Bark.DeviceKey == "") {
+			return errors.New("bark channel requires bark.deviceKey")
+		}
+	}
+	return nil
+}
+
+// validate checks the webhook configuration.
+func (w *Webhook) validate() error {
+	if w.URL != "" && !strings.HasPrefix(w.URL, "secret:") &&
+		!strings.HasPrefix(w.URL, "https://") && !strings.HasPrefix(w.URL, "http://") {
+		return fmt.Errorf("webhook.url must be an http(s) URL or secret reference: %s", w.URL)
+	}
+	if w.Preset != "" && !ValidWebhookPresets[w.Preset] {
+		return fmt.Errorf("invalid webhook.preset: %s (use ifttt or zapier)", w.Preset)
+	}
+	if _, err := ParseTemplate("webhook.template", w.Template); err != nil {
+		return err
+	}
+	if w.Timeout != nil && *w.Timeout <= 0 {
+		return errors.New("webhook.timeout must be positive")
+	}
+	return nil
+}
+
+// validate checks the bark configuration.
+func (b *Bark) validate() error {
+	if b.Server != "" && !strings.HasPrefix(b.Server, "https://") && !strings.HasPrefix(b.Server, "http://") {
+		return fmt.Errorf("bark.server must be an http(s) URL: %s", b.Server)
+	}
+	if _, err := ParseTemplate("bark.body", b.Body); err != nil {
+		return err
+	}
+	if b.Timeout != nil && *b.Timeout <= 0 {
+		return errors.New("bark.timeout must be positive")
+	}
+	return nil
+}
+
+// EventChannels returns the channels to notify for an event configuration.
+func EventChannels(event *Event) []string {
+	if len(event.Channels) == 0 {
+		return DefaultChannels
+	}
+	return event.Channels
+}
+
+// SoundLimitValues returns the effective size (KB) and duration (seconds)
+// limits for user-supplied sounds.
+func (c *Config) SoundLimitValues() (maxSizeKB, maxDurationSeconds int) {
+	maxSizeKB, maxDurationSeconds = DefaultMaxSoundSizeKB, DefaultMaxSoundDurationSeconds
+	if c.SoundLimits != nil {
+		if c.SoundLimits.MaxSizeKB != nil {
+			maxSizeKB = *c.SoundLimits.MaxSizeKB
+		}
+		if c.SoundLimits.MaxDurationSeconds != nil {
+			maxDurationSeconds = *c.SoundLimits.MaxDurationSeconds
+		}
+	}
+	return maxSizeKB, maxDurationSeconds
+}
+
+// SubagentQuietPeriod returns the batching quiet period in seconds and
+// whether subagent batching is enabled.
+func (c *Config) SubagentQuietPeriod() (int, bool) {
+	if c.SubagentBatch == nil || c.SubagentBatch.Enabled == nil || !*c.SubagentBatch.Enabled {
+		return 0, false
+	}
+	if c.SubagentBatch.QuietPeriod != nil {
+		return *c.SubagentBatch.QuietPeriod, true
+	}
+	return DefaultSubagentQuietPeriod, true
+}
+
+// DesktopGroupWindow returns how long an event's desktop notification is
+// replaced by repeats, and whether grouping is enabled.
+func (c *Config) DesktopGroupWindow() (time.Duration, bool) {
+	if c.Desktop == nil || c.Desktop.Group == nil || *c.Desktop.Group == 0 {
+		return 0, false
+	}
+	return time.Duration(*c.Desktop.Group) * time.Second, true
+}
+
+// JournalMaxSizeKB returns the journal rotation size in KB and whether
+// journaling is enabled.
+func (c *Config) JournalMaxSizeKB() (int, bool) {
+	if c.Journal == nil || c.Journal.Enabled == nil || !*c.Journal.Enabled {
+		return 0, false
+	}
+	if c.Journal.MaxSizeKB != nil {
+		return *c.Journal.MaxSizeKB, true
+	}
+	return DefaultJournalMaxSizeKB, true
+}
+
+// HistoryRetentionDays returns how many days of history are kept and
+// whether history is enabled.
+func (c *Config) HistoryRetentionDays() (int, bool) {
+	if c.History == nil || c.History.Enabled == nil || !*c.History.Enabled {
+		return 0, false
+	}
+	if c.History.RetentionDays != nil {
+		return *c.History.RetentionDays, true
+	}
+	return DefaultHistoryRetentionDays, true
+}
+
+// SendsHostname reports whether notifications carry the machine's hostname.
+func (c *Config) SendsHostname() bool {
+	return c.SendHostname == nil || *c.SendHostname
+}
+
+// BypassesDND reports whether critical desktop notifications break through
+// do-not-disturb.
+func (c *Config) BypassesDND() bool {
+	return c.Desktop == nil || c.Desktop.BypassDND == nil || *c.Desktop.BypassDND
+}
+
+// AutoInstallsPlayer reports whether a missing Linux audio player may be
+// installed with the system package manager.
+func (c *Config) AutoInstallsPlayer() bool {
+	return c.AutoInstall != nil && *c.AutoInstall
+}
+
+// FreshStartAfter returns how long without events makes the next one a
+// fresh start, and whether fresh starts are enabled.
+func (c *Config) FreshStartAfter() (time.Duration, bool) {
+	if c.FreshStart == nil || *c.FreshStart <= 0 {
+		return 0, false
+	}
+	return time.Duration(*c.FreshStart) * time.Second, true
+}
+
+// WaitForSubagents reports whether subagent notifications are held until the
+// final stop event.
+func (c *Config) WaitForSubagents() bool {
+	return c.WaitSubagents != nil && c.WaitSubagents.Enabled != nil && *c.WaitSubagents.Enabled
+}
+
+// AllDoneEvent returns a copy of the stop event config that plays the
+// "everything done" sound.
+func (c *Config) AllDoneEvent(stop *Event) *Event {
+	event := *stop
+	if c.WaitSubagents != nil && c.WaitSubagents.Sound != "" {
+		event.Sound = c.WaitSubagents.Sound
+		event.PlatformSounds = nil
+	} else {
+		event.PlatformSounds = defaultAllDoneSounds
+	}
+	return &event
+}
+
 // GetEventConfig returns the effective configuration for an event,
 // considering the active profile.
 func (c *Config) GetEventConfig(eventType string) *Event {
@@ -190,9 +1198,14 @@ func (c *Config) GetEventConfig(eventType string) *Event {
 		mergeEvent(result, baseEvent)
 	}
 
+	// Apply the active pack's or theme's sound in place of the default
+	// bundled sound
+	c.applyActivePack(eventType, result)
+	c.applyTheme(eventType, result)
+
 	// Apply profile overrides (if not default profile)
 	if c.ActiveProfile != "" && c.ActiveProfile != "default" {
-		if profile, ok := c.Profiles[c.ActiveProfile]; ok {
+		if profile, ok := c.GetProfile(c.ActiveProfile); ok {
 			if profileEvent, ok := profile.Events[eventType]; ok {
 				mergeEvent(result, profileEvent)
 			}
@@ -208,15 +1221,49 @@ func mergeEvent(dst, src *Event) {
 	if src.Enabled != nil {
 		dst.Enabled = src.Enabled
 	}
-	if src.Sound != "" {
+	if src.Sound != "" || src.PlatformSounds != nil {
 		dst.Sound = src.Sound
+		// A plain sound override also replaces any per-platform and
+		// per-session sounds
+		dst.PlatformSounds = src.PlatformSounds
+		dst.SessionSounds = src.SessionSounds
 	}
 	if src.Volume != nil {
 		dst.Volume = src.Volume
 	}
+	if src.Gain != nil {
+		dst.Gain = src.Gain
+	}
+	if src.Variation != nil {
+		dst.Variation = src.Variation
+	}
+	if src.AFPlay != nil {
+		dst.AFPlay = src.AFPlay
+	}
 	if src.Cooldown != nil {
 		dst.Cooldown = src.Cooldown
 	}
+	if src.Channels != nil {
+		dst.Channels = src.Channels
+	}
+	if src.DedupeWindow != nil {
+		dst.DedupeWindow = src.DedupeWindow
+	}
+	if src.ResponseLength != nil {
+		dst.ResponseLength = src.ResponseLength
+	}
+	if src.Escalate != nil {
+		dst.Escalate = src.Escalate
+	}
+	if src.Format != nil {
+		dst.Format = mergeFormat(dst.Format, src.Format)
+	}
+	if src.Desktop != nil {
+		dst.Desktop = mergeEventDesktop(dst.Desktop, src.Desktop)
+	}
+	if src.SessionSounds != nil {
+		dst.SessionSounds = src.SessionSounds
+	}
 }
 
 // ValidateEventType returns an error if the event type is invalid.
@@ -228,11 +1275,7 @@ func ValidateEventType(eventType string) error {
 
 	// Check whitelist
 	if !ValidEvents[eventType] {
-		valid := make([]string, 0, len(ValidEvents))
-		for k := range ValidEvents {
-			valid = append(valid, k)
-		}
-		return fmt.Errorf("unknown event type: %s (valid: %v)", eventType, valid)
+		return unknownEventType(eventType)
 	}
 
 	return nil
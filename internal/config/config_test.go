@@ -67,6 +67,56 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "sunset quiet hours",
+			config: &Config{
+				QuietHours: &QuietHours{Start: "sunset+30m", End: "sunrise", Latitude: ptrFloat(41), Longitude: ptrFloat(29)},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid sun offset",
+			config: &Config{
+				QuietHours: &QuietHours{Start: "sunset30m", End: "07:00"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "latitude without longitude",
+			config: &Config{
+				QuietHours: &QuietHours{Start: "sunset", End: "sunrise", Latitude: ptrFloat(41)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "latitude out of range",
+			config: &Config{
+				QuietHours: &QuietHours{Start: "sunset", End: "sunrise", Latitude: ptrFloat(91), Longitude: ptrFloat(0)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "escalation to bark",
+			config: &Config{
+				Bark:   &Bark{DeviceKey: "key"},
+				Events: map[string]*Event{"permission_prompt": {Escalate: &Escalation{After: 300}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "escalation without remote channels",
+			config: &Config{
+				Events: map[string]*Event{"permission_prompt": {Escalate: &Escalation{After: 300}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "escalation without delay",
+			config: &Config{
+				Events: map[string]*Event{"permission_prompt": {Escalate: &Escalation{Channels: []string{ChannelDesktop}}}},
+			},
+			wantErr: true,
+		},
 		{
 			name: "volume out of range",
 			config: &Config{
@@ -85,6 +135,70 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid gain",
+			config: &Config{
+				Events: map[string]*Event{
+					"stop": {Gain: ptrFloat(-6)},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "gain out of range",
+			config: &Config{
+				Events: map[string]*Event{
+					"stop": {Gain: ptrFloat(30)},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "profile gain out of range",
+			config: &Config{
+				Profiles: map[string]*Profile{
+					"work": {Events: map[string]*Event{"stop": {Gain: ptrFloat(-60)}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid variation",
+			config: &Config{
+				Events: map[string]*Event{
+					"stop": {Variation: &Variation{Mode: "tempo", Amount: 0.05}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "variation unknown mode",
+			config: &Config{
+				Events: map[string]*Event{
+					"stop": {Variation: &Variation{Mode: "speed", Amount: 0.05}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "variation amount too large",
+			config: &Config{
+				Events: map[string]*Event{
+					"stop": {Variation: &Variation{Mode: "pitch", Amount: 0.5}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "valid language",
+			config:  &Config{Language: "pt-BR"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid language",
+			config:  &Config{Language: "../en"},
+			wantErr: true,
+		},
 		{
 			name: "negative cooldown",
 			config: &Config{
@@ -94,6 +208,15 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "negative dedupe window",
+			config: &Config{
+				Events: map[string]*Event{
+					"stop": {DedupeWindow: ptrInt(-1)},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "unknown event type",
 			config: &Config{
@@ -103,6 +226,55 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid sound paths",
+			config: &Config{
+				SoundPaths: []string{"/home/user/sounds"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "relative sound path",
+			config: &Config{
+				SoundPaths: []string{"sounds"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "sound path traversal",
+			config: &Config{
+				SoundPaths: []string{"/home/user/../etc"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative player timeout",
+			config: &Config{
+				PlayerTimeout: ptrInt(-1),
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero retry attempts",
+			config: &Config{
+				Retry: &Retry{Attempts: ptrInt(0)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown sound fallback",
+			config: &Config{
+				SoundFallback: "siren",
+			},
+			wantErr: true,
+		},
+		{
+			name: "max volume above 1",
+			config: &Config{
+				MaxVolume: ptrFloat(1.5),
+			},
+			wantErr: true,
+		},
 		{
 			name: "activeProfile not found",
 			config: &Config{
@@ -310,3 +482,653 @@ func TestEnsureConfig(t *testing.T) {
 		t.Logf("EnsureConfig with empty homeDir completed without panic")
 	})
 }
+
+func TestPath(t *testing.T) {
+	oldOverride, hadOverride := os.LookupEnv(ConfigEnvVar)
+	defer func() {
+		if hadOverride {
+			os.Setenv(ConfigEnvVar, oldOverride)
+		} else {
+			os.Unsetenv(ConfigEnvVar)
+		}
+	}()
+
+	t.Run("default path", func(t *testing.T) {
+		os.Unsetenv(ConfigEnvVar)
+		want := filepath.Join("/home/user", ".claude", "ccbell.config.json")
+		if got := Path("/home/user"); got != want {
+			t.Errorf("Path() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("empty home dir", func(t *testing.T) {
+		os.Unsetenv(ConfigEnvVar)
+		if got := Path(""); got != "" {
+			t.Errorf("Path(\"\") = %q, want empty", got)
+		}
+	})
+
+	t.Run("env override", func(t *testing.T) {
+		os.Setenv(ConfigEnvVar, "/tmp/alt.json")
+		if got := Path("/home/user"); got != "/tmp/alt.json" {
+			t.Errorf("Path() = %q, want %q", got, "/tmp/alt.json")
+		}
+	})
+}
+
+func TestLoadFile(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-loadfile-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	altPath := filepath.Join(tempDir, "alt.json")
+	if err := os.WriteFile(altPath, []byte(`{"enabled": false, "debug": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, path, err := LoadFile(altPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != altPath {
+		t.Errorf("expected path %s, got %s", altPath, path)
+	}
+	if cfg.Enabled || !cfg.Debug {
+		t.Errorf("config not loaded from alternate file: enabled=%v debug=%v", cfg.Enabled, cfg.Debug)
+	}
+
+	cfg, path, err = LoadFile(filepath.Join(tempDir, "missing.json"))
+	if err != nil {
+		t.Fatalf("unexpected error for missing file: %v", err)
+	}
+	if path != "" || !cfg.Enabled {
+		t.Errorf("missing file should return defaults, got path=%q enabled=%v", path, cfg.Enabled)
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-disabled-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	systemPath := filepath.Join(tempDir, "system.json")
+	userPath := filepath.Join(tempDir, "user.json")
+	t.Setenv(SystemConfigEnvVar, systemPath)
+
+	tests := []struct {
+		name   string
+		system string // "" for no system config
+		user   string // "" for no user config
+		want   bool
+	}{
+		{"no config", "", "", false},
+		{"disabled", "", `{"enabled": false}`, true},
+		{"enabled", "", `{"enabled": true}`, false},
+		{"disabled by system config", `{"enabled": false}`, `{"debug": true}`, true},
+		{"user re-enables", `{"enabled": false}`, `{"enabled": true}`, false},
+		{"journaling", "", `{"enabled": false, "journal": {"enabled": true}}`, false},
+		{"journal off", "", `{"enabled": false, "journal": {"enabled": false}}`, true},
+		{"strict", "", `{"enabled": false, "strict": true}`, false},
+		{"invalid JSON", "", `{"enabled": false`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(systemPath)
+			os.Remove(userPath)
+			if tt.system != "" {
+				if err := os.WriteFile(systemPath, []byte(tt.system), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.user != "" {
+				if err := os.WriteFile(userPath, []byte(tt.user), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := Disabled(userPath); got != tt.want {
+				t.Errorf("Disabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-language-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	systemPath := filepath.Join(tempDir, "system.json")
+	userPath := filepath.Join(tempDir, "user.json")
+	t.Setenv(SystemConfigEnvVar, systemPath)
+
+	tests := []struct {
+		name   string
+		system string // "" for no system config
+		user   string // "" for no user config
+		want   string
+	}{
+		{"no config", "", "", ""},
+		{"user", "", `{"language": "tr"}`, "tr"},
+		{"system", `{"language": "de"}`, `{"debug": true}`, "de"},
+		{"user overrides system", `{"language": "de"}`, `{"language": "tr"}`, "tr"},
+		{"invalid JSON", "", `{"language": `, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(systemPath)
+			os.Remove(userPath)
+			if tt.system != "" {
+				if err := os.WriteFile(systemPath, []byte(tt.system), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.user != "" {
+				if err := os.WriteFile(userPath, []byte(tt.user), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := Language(userPath); got != tt.want {
+				t.Errorf("Language() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFileWithSystemConfig(t *testing.T) {
+	oldSystem, hadSystem := os.LookupEnv(SystemConfigEnvVar)
+	defer func() {
+		if hadSystem {
+			os.Setenv(SystemConfigEnvVar, oldSystem)
+		} else {
+			os.Unsetenv(SystemConfigEnvVar)
+		}
+	}()
+
+	tempDir, err := os.MkdirTemp("", "ccbell-system-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	systemPath := filepath.Join(tempDir, "system.json")
+	userPath := filepath.Join(tempDir, "user.json")
+	os.Setenv(SystemConfigEnvVar, systemPath)
+
+	systemContent := `{
+		"quietHours": {"start": "22:00", "end": "07:00"},
+		"events": {"stop": {"sound": "bundled:subagent", "volume": 0.2}}
+	}`
+	if err := os.WriteFile(systemPath, []byte(systemContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("system config applies without user config", func(t *testing.T) {
+		cfg, path, err := LoadFile(userPath)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if path != systemPath {
+			t.Errorf("expected path %s, got %s", systemPath, path)
+		}
+		if cfg.QuietHours == nil || cfg.QuietHours.Start != "22:00" {
+			t.Error("expected quiet hours from system config")
+		}
+	})
+
+	t.Run("user config overrides per field", func(t *testing.T) {
+		userContent := `{"events": {"stop": {"volume": 0.8}}}`
+		if err := os.WriteFile(userPath, []byte(userContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, path, err := LoadFile(userPath)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if path != userPath {
+			t.Errorf("expected path %s, got %s", userPath, path)
+		}
+		eventCfg := cfg.GetEventConfig("stop")
+		if *eventCfg.Volume != 0.8 {
+			t.Errorf("expected user volume 0.8, got %f", *eventCfg.Volume)
+		}
+		if eventCfg.Sound != "bundled:subagent" {
+			t.Errorf("expected system sound to survive, got %q", eventCfg.Sound)
+		}
+		if cfg.QuietHours == nil || cfg.QuietHours.End != "07:00" {
+			t.Error("expected system quiet hours to survive user layer")
+		}
+	})
+
+	t.Run("invalid system config", func(t *testing.T) {
+		if err := os.WriteFile(systemPath, []byte("{invalid"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := LoadFile(userPath); err == nil {
+			t.Error("expected error for invalid system config")
+		}
+	})
+}
+
+func TestSoundLimitValues(t *testing.T) {
+	cfg := Default()
+	size, duration := cfg.SoundLimitValues()
+	if size != DefaultMaxSoundSizeKB || duration != DefaultMaxSoundDurationSeconds {
+		t.Errorf("defaults = (%d, %d), want (%d, %d)", size, duration,
+			DefaultMaxSoundSizeKB, DefaultMaxSoundDurationSeconds)
+	}
+
+	cfg.SoundLimits = &SoundLimits{MaxDurationSeconds: ptrInt(0)}
+	size, duration = cfg.SoundLimitValues()
+	if size != DefaultMaxSoundSizeKB || duration != 0 {
+		t.Errorf("override = (%d, %d), want (%d, 0)", size, duration, DefaultMaxSoundSizeKB)
+	}
+
+	cfg.SoundLimits = &SoundLimits{MaxSizeKB: ptrInt(-1)}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for negative maxSizeKB")
+	}
+}
+
+func TestValidateAllowSymlinks(t *testing.T) {
+	for _, policy := range []string{"", "deny", "resolve-within-root", "allow"} {
+		cfg := &Config{AllowSymlinks: policy}
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("allowSymlinks %q should be valid: %v", policy, err)
+		}
+	}
+
+	cfg := &Config{AllowSymlinks: "sometimes"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for unknown allowSymlinks policy")
+	}
+}
+
+func TestValidateAllowedSoundDirs(t *testing.T) {
+	for _, dirs := range [][]string{{"sounds"}, {"/home/me/../etc"}} {
+		cfg := &Config{AllowedDirs: dirs}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("allowedSoundDirs %v should be invalid", dirs)
+		}
+	}
+	cfg := &Config{AllowedDirs: []string{"/home/me/.claude/ccbell", "/usr/share/sounds"}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("absolute allowedSoundDirs should be valid: %v", err)
+	}
+}
+
+func TestValidateSoundPermissions(t *testing.T) {
+	for _, policy := range []string{"", "off", "not-world-writable", "owner"} {
+		cfg := &Config{SoundPerms: policy}
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("soundPermissions %q should be valid: %v", policy, err)
+		}
+	}
+
+	cfg := &Config{SoundPerms: "strict"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for unknown soundPermissions policy")
+	}
+}
+
+func TestValidatePlayers(t *testing.T) {
+	cfg := &Config{Players: []string{"paplay", "mpv", "aucat"}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("known players should be valid: %v", err)
+	}
+
+	cfg = &Config{Players: []string{"paplay", "vlc"}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for unknown player")
+	}
+}
+
+func TestValidateTitle(t *testing.T) {
+	for _, title := range []string{"", "Claude", "{project} ({branch}): {event}"} {
+		cfg := &Config{Title: title}
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("notificationTitle %q should be valid: %v", title, err)
+		}
+	}
+
+	cfg := &Config{Title: "{host}: {event}"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for unknown placeholder")
+	}
+}
+
+func TestSync(t *testing.T) {
+	tests := []struct {
+		sync       Sync
+		wantRemote string
+		wantErr    bool
+	}{
+		{Sync{Repo: "git@github.com:me/ccbell-config.git"}, "git@github.com:me/ccbell-config.git", false},
+		{Sync{Gist: "aa5a315d61ae9438b18d"}, "https://gist.github.com/aa5a315d61ae9438b18d.git", false},
+		{Sync{}, "", false},
+		{Sync{Repo: "/tmp/repo", Gist: "abc"}, "", true},
+		{Sync{Gist: "../evil"}, "", true},
+	}
+	for _, tt := range tests {
+		cfg := &Config{Sync: &tt.sync}
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.sync, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && tt.sync.Remote() != tt.wantRemote {
+			t.Errorf("Remote() = %q, want %q", tt.sync.Remote(), tt.wantRemote)
+		}
+	}
+}
+
+func TestSendsHostname(t *testing.T) {
+	disabled := false
+	if !(&Config{}).SendsHostname() {
+		t.Error("hostname should be sent by default")
+	}
+	if (&Config{SendHostname: &disabled}).SendsHostname() {
+		t.Error("sendHostname false should disable the hostname")
+	}
+}
+
+func TestBypassesDND(t *testing.T) {
+	disabled := false
+	if !(&Config{}).BypassesDND() || !(&Config{Desktop: &Desktop{}}).BypassesDND() {
+		t.Error("critical notifications should bypass do-not-disturb by default")
+	}
+	if (&Config{Desktop: &Desktop{BypassDND: &disabled}}).BypassesDND() {
+		t.Error("bypassDnd false should disable the bypass")
+	}
+}
+
+func TestValidateChannels(t *testing.T) {
+	timeout := 0
+	tests := []struct {
+		name    string
+		config  *Config
+		wantErr bool
+	}{
+		{
+			name:   "default channels",
+			config: &Config{Events: map[string]*Event{"stop": {}}},
+		},
+		{
+			name:   "sound and desktop",
+			config: &Config{Events: map[string]*Event{"stop": {Channels: []string{"sound", "desktop"}}}},
+		},
+		{
+			name: "webhook with url",
+			config: &Config{
+				Webhook: &Webhook{URL: "https://example.com/hook"},
+				Events:  map[string]*Event{"stop": {Channels: []string{"webhook"}}},
+			},
+		},
+		{
+			name: "webhook with secret url",
+			config: &Config{
+				Webhook: &Webhook{URL: "secret:slack"},
+				Events:  map[string]*Event{"stop": {Channels: []string{"webhook"}}},
+			},
+		},
+		{
+			name:    "unknown channel",
+			config:  &Config{Events: map[string]*Event{"stop": {Channels: []string{"pager"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "webhook without url",
+			config:  &Config{Events: map[string]*Event{"stop": {Channels: []string{"webhook"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "webhook with invalid scheme",
+			config:  &Config{Webhook: &Webhook{URL: "ftp://example.com"}},
+			wantErr: true,
+		},
+		{
+			name:   "webhook with ifttt preset",
+			config: &Config{Webhook: &Webhook{URL: "https://maker.ifttt.com/trigger/ccbell/with/key/k", Preset: "ifttt"}},
+		},
+		{
+			name:    "webhook with unknown preset",
+			config:  &Config{Webhook: &Webhook{URL: "https://example.com", Preset: "n8n"}},
+			wantErr: true,
+		},
+		{
+			name:   "webhook with template",
+			config: &Config{Webhook: &Webhook{URL: "https://example.com", Template: `{"text": {{json .Message}}}`}},
+		},
+		{
+			name:    "webhook with broken template",
+			config:  &Config{Webhook: &Webhook{URL: "https://example.com", Template: `{"text": {{json .Message}`}},
+			wantErr: true,
+		},
+		{
+			name:    "webhook template with unknown function",
+			config:  &Config{Webhook: &Webhook{URL: "https://example.com", Template: `{{yaml .Message}}`}},
+			wantErr: true,
+		},
+		{
+			name:    "webhook with zero timeout",
+			config:  &Config{Webhook: &Webhook{URL: "https://example.com", Timeout: &timeout}},
+			wantErr: true,
+		},
+		{
+			name: "terminal osc777",
+			config: &Config{
+				Terminal: &Terminal{Sequence: "osc777"},
+				Events:   map[string]*Event{"stop": {Channels: []string{"terminal"}}},
+			},
+		},
+		{
+			name:    "terminal invalid sequence",
+			config:  &Config{Terminal: &Terminal{Sequence: "osc99"}},
+			wantErr: true,
+		},
+		{
+			name: "uservar with name",
+			config: &Config{
+				Terminal: &Terminal{UserVar: "claude_state"},
+				Events:   map[string]*Event{"stop": {Channels: []string{"uservar"}}},
+			},
+		},
+		{
+			name: "bark with device key",
+			config: &Config{
+				Bark:   &Bark{DeviceKey: "secret:bark"},
+				Events: map[string]*Event{"permission_prompt": {Channels: []string{"bark"}}},
+			},
+		},
+		{
+			name:    "bark without device key",
+			config:  &Config{Events: map[string]*Event{"stop": {Channels: []string{"bark"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "bark with broken body template",
+			config:  &Config{Bark: &Bark{DeviceKey: "k", Body: "{{.Message"}},
+			wantErr: true,
+		},
+		{
+			name:    "bark with invalid server",
+			config:  &Config{Bark: &Bark{Server: "api.day.app", DeviceKey: "k"}},
+			wantErr: true,
+		},
+		{
+			name: "led with colors",
+			config: &Config{
+				LED:    &LED{Colors: map[string]string{"stop": "#00FF00"}},
+				Events: map[string]*Event{"stop": {Channels: []string{"led"}}},
+			},
+		},
+		{
+			name:    "led invalid color",
+			config:  &Config{LED: &LED{Colors: map[string]string{"stop": "green"}}},
+			wantErr: true,
+		},
+		{
+			name:    "led unknown event",
+			config:  &Config{LED: &LED{Colors: map[string]string{"done": "#00ff00"}}},
+			wantErr: true,
+		},
+		{
+			name:    "led zero blinks",
+			config:  &Config{LED: &LED{Blinks: &timeout}},
+			wantErr: true,
+		},
+		{
+			name:    "uservar invalid name",
+			config:  &Config{Terminal: &Terminal{UserVar: "bad=name"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEventChannels(t *testing.T) {
+	if got := EventChannels(&Event{}); len(got) != 1 || got[0] != ChannelSound {
+		t.Errorf("EventChannels(empty) = %v, want [sound]", got)
+	}
+	want := []string{"desktop", "webhook"}
+	if got := EventChannels(&Event{Channels: want}); len(got) != 2 || got[0] != "desktop" || got[1] != "webhook" {
+		t.Errorf("EventChannels() = %v, want %v", got, want)
+	}
+}
+
+func TestSubagentQuietPeriod(t *testing.T) {
+	tests := []struct {
+		name        string
+		batch       *SubagentBatch
+		wantPeriod  int
+		wantEnabled bool
+	}{
+		{"not configured", nil, 0, false},
+		{"disabled", &SubagentBatch{Enabled: ptrBool(false), QuietPeriod: ptrInt(10)}, 0, false},
+		{"enabled with default period", &SubagentBatch{Enabled: ptrBool(true)}, DefaultSubagentQuietPeriod, true},
+		{"enabled with custom period", &SubagentBatch{Enabled: ptrBool(true), QuietPeriod: ptrInt(10)}, 10, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{SubagentBatch: tt.batch}
+			period, enabled := cfg.SubagentQuietPeriod()
+			if period != tt.wantPeriod || enabled != tt.wantEnabled {
+				t.Errorf("SubagentQuietPeriod() = (%d, %v), want (%d, %v)", period, enabled, tt.wantPeriod, tt.wantEnabled)
+			}
+		})
+	}
+
+	cfg := &Config{SubagentBatch: &SubagentBatch{QuietPeriod: ptrInt(0)}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for zero quietPeriod")
+	}
+}
+
+func TestAllDoneEvent(t *testing.T) {
+	stop := &Event{Sound: "bundled:stop", Volume: ptrFloat(0.3)}
+
+	t.Run("default sounds", func(t *testing.T) {
+		cfg := &Config{WaitSubagents: &WaitSubagents{Enabled: ptrBool(true)}}
+		if !cfg.WaitForSubagents() {
+			t.Fatal("WaitForSubagents() should be true")
+		}
+		event := cfg.AllDoneEvent(stop)
+		if got := event.SoundFor("macos"); got != "system:Glass" {
+			t.Errorf("macos sound = %q, want system:Glass", got)
+		}
+		if got := event.SoundFor("linux"); got != "system:complete" {
+			t.Errorf("linux sound = %q, want system:complete", got)
+		}
+		if *event.Volume != 0.3 {
+			t.Errorf("volume = %v, want stop volume", *event.Volume)
+		}
+		if stop.PlatformSounds != nil {
+			t.Error("stop event should not be modified")
+		}
+	})
+
+	t.Run("configured sound", func(t *testing.T) {
+		cfg := &Config{WaitSubagents: &WaitSubagents{Enabled: ptrBool(true), Sound: "custom:/tmp/done.wav"}}
+		if got := cfg.AllDoneEvent(stop).SoundFor("linux"); got != "custom:/tmp/done.wav" {
+			t.Errorf("sound = %q, want custom:/tmp/done.wav", got)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		cfg := &Config{WaitSubagents: &WaitSubagents{Enabled: ptrBool(false)}}
+		if cfg.WaitForSubagents() {
+			t.Error("WaitForSubagents() should be false")
+		}
+	})
+}
+
+func TestJournalMaxSizeKB(t *testing.T) {
+	tests := []struct {
+		name        string
+		journal     *Journal
+		wantSize    int
+		wantEnabled bool
+	}{
+		{"not configured", nil, 0, false},
+		{"disabled", &Journal{Enabled: ptrBool(false)}, 0, false},
+		{"enabled with default size", &Journal{Enabled: ptrBool(true)}, DefaultJournalMaxSizeKB, true},
+		{"enabled with custom size", &Journal{Enabled: ptrBool(true), MaxSizeKB: ptrInt(64)}, 64, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Journal: tt.journal}
+			size, enabled := cfg.JournalMaxSizeKB()
+			if size != tt.wantSize || enabled != tt.wantEnabled {
+				t.Errorf("JournalMaxSizeKB() = (%d, %v), want (%d, %v)", size, enabled, tt.wantSize, tt.wantEnabled)
+			}
+		})
+	}
+
+	cfg := &Config{Journal: &Journal{MaxSizeKB: ptrInt(-1)}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for negative journal.maxSizeKB")
+	}
+}
+
+func TestHistoryRetentionDays(t *testing.T) {
+	tests := []struct {
+		name        string
+		history     *History
+		wantDays    int
+		wantEnabled bool
+	}{
+		{"not configured", nil, 0, false},
+		{"disabled", &History{Enabled: ptrBool(false)}, 0, false},
+		{"enabled with default retention", &History{Enabled: ptrBool(true)}, DefaultHistoryRetentionDays, true},
+		{"enabled with custom retention", &History{Enabled: ptrBool(true), RetentionDays: ptrInt(7)}, 7, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{History: tt.history}
+			days, enabled := cfg.HistoryRetentionDays()
+			if days != tt.wantDays || enabled != tt.wantEnabled {
+				t.Errorf("HistoryRetentionDays() = (%d, %v), want (%d, %v)", days, enabled, tt.wantDays, tt.wantEnabled)
+			}
+		})
+	}
+
+	cfg := Default()
+	cfg.History = &History{RetentionDays: ptrInt(0)}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for zero history.retentionDays")
+	}
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// EventDesktop sets how an event's desktop notifications are shown on
+// Linux, e.g. keeping permission prompts up until dismissed while stop
+// notifications expire. Unset fields keep the notification server's
+// defaults.
+type EventDesktop struct {
+	// Timeout is how many seconds the notification stays up; 0 keeps it
+	// until dismissed.
+	Timeout *int `json:"timeout,omitempty"`
+	// Urgency is "low", "normal" or "critical"; default from the format
+	// severity.
+	Urgency string `json:"urgency,omitempty"`
+}
+
+// ValidUrgencies is the set of allowed desktop notification urgencies.
+var ValidUrgencies = map[string]bool{
+	"low":      true,
+	"normal":   true,
+	"critical": true,
+}
+
+// validate checks the timeout and urgency.
+func (d *EventDesktop) validate() error {
+	if d.Timeout != nil && *d.Timeout < 0 {
+		return errors.New("desktop.timeout cannot be negative")
+	}
+	if d.Urgency != "" && !ValidUrgencies[d.Urgency] {
+		return fmt.Errorf("invalid desktop.urgency: %s (valid: low, normal, critical)", d.Urgency)
+	}
+	return nil
+}
+
+// mergeEventDesktop applies the set fields of src over dst, returning the
+// result without modifying either.
+func mergeEventDesktop(dst, src *EventDesktop) *EventDesktop {
+	if dst == nil {
+		return src
+	}
+	result := *dst
+	if src.Timeout != nil {
+		result.Timeout = src.Timeout
+	}
+	if src.Urgency != "" {
+		result.Urgency = src.Urgency
+	}
+	return &result
+}
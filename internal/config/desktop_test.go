@@ -0,0 +1,33 @@
+package config
+
+import "testing"
+
+func TestEventDesktopMergeAndValidate(t *testing.T) {
+	cfg := Default()
+	sticky := 0
+	cfg.Events["permission_prompt"].Desktop = &EventDesktop{Timeout: &sticky}
+	cfg.Profiles = map[string]*Profile{"focus": {Events: map[string]*Event{"permission_prompt": {Desktop: &EventDesktop{Urgency: "critical"}}}}}
+	cfg.ActiveProfile = "focus"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	d := cfg.GetEventConfig("permission_prompt").Desktop
+	if d == nil || d.Timeout == nil || *d.Timeout != 0 || d.Urgency != "critical" {
+		t.Errorf("merged desktop = %+v, want the base timeout with the profile's urgency", d)
+	}
+	if cfg.Events["permission_prompt"].Desktop.Urgency != "" {
+		t.Errorf("base desktop modified: %+v", cfg.Events["permission_prompt"].Desktop)
+	}
+	if d := cfg.GetEventConfig("stop").Desktop; d != nil {
+		t.Errorf("stop desktop = %+v, want unset", d)
+	}
+
+	negative := -1
+	for _, d := range []*EventDesktop{{Timeout: &negative}, {Urgency: "urgent"}} {
+		cfg.Events["stop"].Desktop = d
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Validate() accepted desktop %+v", d)
+		}
+	}
+}
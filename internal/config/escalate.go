@@ -0,0 +1,47 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxEscalateAfter caps how long an escalation waits, in seconds.
+const MaxEscalateAfter = 12 * 60 * 60
+
+// Escalation re-sends a notification that isn't acknowledged in time, with
+// "ccbell ack" or by the next event, to remote channels. The daemon checks
+// for due escalations.
+type Escalation struct {
+	After    int      `json:"after"`              // Seconds to wait for an acknowledgement
+	Channels []string `json:"channels,omitempty"` // Default: the configured remote channels
+}
+
+// validate checks the escalation settings.
+func (e *Escalation) validate(c *Config) error {
+	if e.After <= 0 || e.After > MaxEscalateAfter {
+		return fmt.Errorf("escalate.after must be 1-%d seconds", MaxEscalateAfter)
+	}
+	if err := c.validateChannels(e.Channels); err != nil {
+		return fmt.Errorf("escalate.channels: %w", err)
+	}
+	if len(c.EscalationChannels(e)) == 0 {
+		return errors.New("escalate needs channels or a configured webhook or bark")
+	}
+	return nil
+}
+
+// EscalationChannels returns the channels an escalation is sent to: its
+// own, or else every configured remote channel.
+func (c *Config) EscalationChannels(e *Escalation) []string {
+	if len(e.Channels) > 0 {
+		return e.Channels
+	}
+	var channels []string
+	if c.Webhook != nil && c.Webhook.URL != "" {
+		channels = append(channels, ChannelWebhook)
+	}
+	if c.Bark != nil && c.Bark.DeviceKey != "" {
+		channels = append(channels, ChannelBark)
+	}
+	return channels
+}
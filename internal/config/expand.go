@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// envRefRegex matches ${VAR} references, including the escaped $${VAR} form.
+var envRefRegex = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv replaces ${VAR} references with environment variable values.
+// Undefined variables expand to an empty string. $${VAR} is an escape that
+// yields a literal ${VAR}.
+func expandEnv(s string) string {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+	return envRefRegex.ReplaceAllStringFunc(s, func(match string) string {
+		if strings.HasPrefix(match, "$$") {
+			return match[1:]
+		}
+		name := match[2 : len(match)-1]
+		return os.Getenv(name)
+	})
+}
+
+// expandEnvRefs expands environment references in config string values.
+func (c *Config) expandEnvRefs() {
+	for i, dir := range c.SoundPaths {
+		c.SoundPaths[i] = expandEnv(dir)
+	}
+	for i, dir := range c.AllowedDirs {
+		c.AllowedDirs[i] = expandEnv(dir)
+	}
+	if c.Webhook != nil {
+		c.Webhook.URL = expandEnv(c.Webhook.URL)
+		for key, value := range c.Webhook.Headers {
+			c.Webhook.Headers[key] = expandEnv(value)
+		}
+	}
+	if c.Bark != nil {
+		c.Bark.Server = expandEnv(c.Bark.Server)
+		c.Bark.DeviceKey = expandEnv(c.Bark.DeviceKey)
+	}
+	if c.Freesound != nil {
+		c.Freesound.Token = expandEnv(c.Freesound.Token)
+	}
+	if c.Holidays != nil {
+		c.Holidays.ICS = expandEnv(c.Holidays.ICS)
+	}
+	if c.WaitSubagents != nil {
+		c.WaitSubagents.Sound = expandEnv(c.WaitSubagents.Sound)
+	}
+	for _, event := range c.Events {
+		event.expandEnvRefs()
+	}
+	for _, profile := range c.Profiles {
+		if profile == nil {
+			continue
+		}
+		for _, event := range profile.Events {
+			event.expandEnvRefs()
+		}
+	}
+	for _, rule := range c.Branches {
+		if rule == nil {
+			continue
+		}
+		rule.Worktree = expandEnv(rule.Worktree)
+		for _, event := range rule.Events {
+			event.expandEnvRefs()
+		}
+	}
+}
+
+// expandEnvRefs expands environment references in an event's sound specs.
+func (e *Event) expandEnvRefs() {
+	if e == nil {
+		return
+	}
+	e.Sound = expandEnv(e.Sound)
+	for platform, spec := range e.PlatformSounds {
+		e.PlatformSounds[platform] = expandEnv(spec)
+	}
+	for i, spec := range e.SessionSounds {
+		e.SessionSounds[i] = expandEnv(spec)
+	}
+	if e.ResponseLength != nil {
+		e.ResponseLength.Short.expandEnvRefs()
+		e.ResponseLength.Long.expandEnvRefs()
+	}
+}
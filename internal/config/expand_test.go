@@ -0,0 +1,97 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpandEnv(t *testing.T) {
+	os.Setenv("CCBELL_TEST_SOUNDS", "/home/user/sounds")
+	defer os.Unsetenv("CCBELL_TEST_SOUNDS")
+	os.Unsetenv("CCBELL_TEST_UNSET")
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"no reference", "bundled:stop", "bundled:stop"},
+		{"single reference", "custom:${CCBELL_TEST_SOUNDS}/ding.wav", "custom:/home/user/sounds/ding.wav"},
+		{"undefined variable", "${CCBELL_TEST_UNSET}/ding.wav", "/ding.wav"},
+		{"escaped reference", "/tmp/$${CCBELL_TEST_SOUNDS}", "/tmp/${CCBELL_TEST_SOUNDS}"},
+		{"bare dollar untouched", "/tmp/$CCBELL_TEST_SOUNDS", "/tmp/$CCBELL_TEST_SOUNDS"},
+		{"multiple references", "${CCBELL_TEST_SOUNDS}:${CCBELL_TEST_SOUNDS}", "/home/user/sounds:/home/user/sounds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandEnv(tt.input); got != tt.want {
+				t.Errorf("expandEnv(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFileExpandsEnv(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-expand-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	os.Setenv("CCBELL_TEST_DIR", tempDir)
+	defer os.Unsetenv("CCBELL_TEST_DIR")
+
+	if err := os.WriteFile(filepath.Join(tempDir, "holidays.ics"), []byte("BEGIN:VCALENDAR\nEND:VCALENDAR\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(tempDir, "ccbell.config.json")
+	configContent := `{
+		"soundPaths": ["${CCBELL_TEST_DIR}/sounds"],
+		"allowedSoundDirs": ["${CCBELL_TEST_DIR}"],
+		"events": {"stop": {
+			"sound": {"linux": "custom:${CCBELL_TEST_DIR}/stop.wav", "default": "custom:${CCBELL_TEST_DIR}/stop.wav"},
+			"sessionSounds": ["custom:${CCBELL_TEST_DIR}/a.wav"],
+			"responseLength": {"long": {"sound": "custom:${CCBELL_TEST_DIR}/long.wav"}}
+		}},
+		"branches": [{"worktree": "${CCBELL_TEST_DIR}/app-*", "events": {}}],
+		"webhook": {"url": "https://example.com/${CCBELL_TEST_DIR}", "headers": {"X-Dir": "${CCBELL_TEST_DIR}"}},
+		"bark": {"server": "https://example.com/${CCBELL_TEST_DIR}", "deviceKey": "${CCBELL_TEST_DIR}"},
+		"freesound": {"token": "${CCBELL_TEST_DIR}"},
+		"holidays": {"ics": "${CCBELL_TEST_DIR}/holidays.ics"},
+		"waitForSubagents": {"enabled": true, "sound": "custom:${CCBELL_TEST_DIR}/done.wav"}
+	}`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, _, err := LoadFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := tempDir + "/sounds"; cfg.SoundPaths[0] != want {
+		t.Errorf("soundPaths[0] = %q, want %q", cfg.SoundPaths[0], want)
+	}
+	stop := cfg.Events["stop"]
+	// Every field "ccbell --help" lists under VARIABLES
+	for name, got := range map[string]string{
+		"allowedSoundDirs":       cfg.AllowedDirs[0],
+		"sound":                  stop.PlatformSounds["linux"],
+		"sessionSounds":          stop.SessionSounds[0],
+		"responseLength":         stop.ResponseLength.Long.Sound,
+		"branches[].worktree":    cfg.Branches[0].Worktree,
+		"webhook.url":            cfg.Webhook.URL,
+		"webhook.headers":        cfg.Webhook.Headers["X-Dir"],
+		"bark.server":            cfg.Bark.Server,
+		"bark.deviceKey":         cfg.Bark.DeviceKey,
+		"freesound.token":        cfg.Freesound.Token,
+		"holidays.ics":           cfg.Holidays.ICS,
+		"waitForSubagents.sound": cfg.WaitSubagents.Sound,
+	} {
+		if !strings.Contains(got, tempDir) || strings.Contains(got, "${") {
+			t.Errorf("%s = %q, want ${CCBELL_TEST_DIR} expanded", name, got)
+		}
+	}
+}
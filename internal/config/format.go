@@ -0,0 +1,51 @@
+package config
+
+import "fmt"
+
+// Format sets how text channels (desktop, terminal, text and push) present
+// an event. Unset fields keep the event's defaults.
+type Format struct {
+	// Icon is the emoji for {icon} in the title and body templates.
+	Icon *string `json:"icon,omitempty"`
+	// Severity is "info", "warning" or "critical". Warnings and critical
+	// events are colored in the text channel, and critical ones are urgent
+	// desktop notifications on Linux.
+	Severity string `json:"severity,omitempty"`
+	// Body is a template for the notification text with {message}, {icon},
+	// {severity}, {project}, {branch}, {hostname} and {event}.
+	Body string `json:"body,omitempty"`
+}
+
+// ValidSeverities is the set of allowed format severities.
+var ValidSeverities = map[string]bool{
+	"info":     true,
+	"warning":  true,
+	"critical": true,
+}
+
+// validate checks the severity.
+func (f *Format) validate() error {
+	if f.Severity != "" && !ValidSeverities[f.Severity] {
+		return fmt.Errorf("invalid format severity: %s (valid: info, warning, critical)", f.Severity)
+	}
+	return nil
+}
+
+// mergeFormat applies the set fields of src over dst, returning the result
+// without modifying either.
+func mergeFormat(dst, src *Format) *Format {
+	if dst == nil {
+		return src
+	}
+	result := *dst
+	if src.Icon != nil {
+		result.Icon = src.Icon
+	}
+	if src.Severity != "" {
+		result.Severity = src.Severity
+	}
+	if src.Body != "" {
+		result.Body = src.Body
+	}
+	return &result
+}
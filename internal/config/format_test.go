@@ -0,0 +1,27 @@
+package config
+
+import "testing"
+
+func TestFormatMergeAndValidate(t *testing.T) {
+	cfg := Default()
+	icon := "🚀"
+	cfg.Events["stop"].Format = &Format{Icon: &icon, Body: "{icon} {message}"}
+	cfg.Profiles = map[string]*Profile{"focus": {Events: map[string]*Event{"stop": {Format: &Format{Severity: "critical"}}}}}
+	cfg.ActiveProfile = "focus"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	f := cfg.GetEventConfig("stop").Format
+	if f == nil || f.Icon == nil || *f.Icon != "🚀" || f.Body != "{icon} {message}" || f.Severity != "critical" {
+		t.Errorf("merged format = %+v, want the base icon and body with the profile's severity", f)
+	}
+	if *cfg.Events["stop"].Format != (Format{Icon: &icon, Body: "{icon} {message}"}) {
+		t.Errorf("base format modified: %+v", cfg.Events["stop"].Format)
+	}
+
+	cfg.Profiles["focus"].Events["stop"].Format.Severity = "loud"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() should reject an unknown severity")
+	}
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// dateFormat is the layout of holiday dates.
+const dateFormat = "2006-01-02"
+
+// maxHolidaySpan caps how many days one calendar event can cover.
+const maxHolidaySpan = 366
+
+// Holidays lists days treated like weekends for profile schedules and
+// weekend quiet hours.
+type Holidays struct {
+	Dates []string `json:"dates,omitempty"` // YYYY-MM-DD
+	ICS   string   `json:"ics,omitempty"`   // Path to an iCalendar file, e.g. a country's public holiday feed
+
+	days map[string]bool // Dates and ICS days, filled by load
+}
+
+// load reads the holiday dates and calendar into a day set.
+func (h *Holidays) load() error {
+	h.days = make(map[string]bool)
+	for _, date := range h.Dates {
+		if _, err := time.Parse(dateFormat, date); err != nil {
+			return fmt.Errorf("invalid holiday date %q (expected YYYY-MM-DD)", date)
+		}
+		h.days[date] = true
+	}
+	if h.ICS == "" {
+		return nil
+	}
+
+	f, err := os.Open(h.ICS)
+	if err != nil {
+		return fmt.Errorf("failed to read holiday calendar: %w", err)
+	}
+	defer f.Close()
+	days, err := parseICSDays(f)
+	if err != nil {
+		return fmt.Errorf("invalid holiday calendar %s: %w", h.ICS, err)
+	}
+	for _, day := range days {
+		h.days[day] = true
+	}
+	return nil
+}
+
+// contains reports whether t falls on a holiday.
+func (h *Holidays) contains(t time.Time) bool {
+	return h != nil && h.days[t.Format(dateFormat)]
+}
+
+// parseICSDays returns the days covered by the events of an iCalendar file.
+// All-day events cover DTSTART up to, not including, DTEND; other events
+// cover the day they start.
+func parseICSDays(f *os.File) ([]string, error) {
+	var days []string
+	var start, end time.Time
+	inEvent := false
+
+	for _, line := range unfoldICS(f) {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, _, _ = strings.Cut(name, ";") // Drop parameters such as VALUE=DATE
+		switch strings.ToUpper(name) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				inEvent, start, end = true, time.Time{}, time.Time{}
+			}
+		case "DTSTART", "DTEND":
+			if !inEvent {
+				continue
+			}
+			t, err := parseICSDate(value)
+			if err != nil {
+				return nil, err
+			}
+			if strings.EqualFold(name, "DTSTART") {
+				start = t
+			} else {
+				end = t
+			}
+		case "END":
+			if !strings.EqualFold(value, "VEVENT") || !inEvent {
+				continue
+			}
+			inEvent = false
+			if start.IsZero() {
+				continue
+			}
+			if end.IsZero() || !end.After(start) {
+				end = start.AddDate(0, 0, 1)
+			}
+			for d, n := start, 0; d.Before(end) && n < maxHolidaySpan; d, n = d.AddDate(0, 0, 1), n+1 {
+				days = append(days, d.Format(dateFormat))
+			}
+		}
+	}
+	return days, nil
+}
+
+// unfoldICS returns the logical lines of an iCalendar file, joining folded
+// continuation lines.
+func unfoldICS(f *os.File) []string {
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// parseICSDate parses a DATE (20251225) or DATE-TIME (20251225T090000Z)
+// value into its day.
+func parseICSDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	t, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return t, nil
+}
+
+// isDayOff reports whether t is a weekend day or holiday.
+func (c *Config) isDayOff(t time.Time) bool {
+	return isWeekend(t) || c.Holidays.contains(t)
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testICS = "BEGIN:VCALENDAR\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Christmas\r\n" +
+	" Day\r\n" +
+	"DTSTART;VALUE=DATE:20251225\r\n" +
+	"DTEND;VALUE=DATE:20251227\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20260101T000000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestHolidaysLoad(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-holidays-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	icsPath := filepath.Join(tmpDir, "holidays.ics")
+	if err := os.WriteFile(icsPath, []byte(testICS), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	h := &Holidays{Dates: []string{"2025-05-19"}, ICS: icsPath}
+	if err := h.load(); err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	tests := []struct {
+		date string
+		want bool
+	}{
+		{"2025-05-19", true},
+		{"2025-12-24", false},
+		{"2025-12-25", true},
+		{"2025-12-26", true},
+		{"2025-12-27", false}, // DTEND is exclusive
+		{"2026-01-01", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			day, _ := time.ParseInLocation(dateFormat, tt.date, time.Local)
+			if got := h.contains(day.Add(12 * time.Hour)); got != tt.want {
+				t.Errorf("contains(%s) = %v, want %v", tt.date, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHolidaysLoadErrors(t *testing.T) {
+	if err := (&Holidays{Dates: []string{"25-12-2025"}}).load(); err == nil {
+		t.Error("expected error for invalid date")
+	}
+	if err := (&Holidays{ICS: "/nonexistent/holidays.ics"}).load(); err == nil {
+		t.Error("expected error for missing calendar")
+	}
+}
+
+func TestHolidaysProfileSchedule(t *testing.T) {
+	monday := time.Date(2025, 12, 29, 9, 0, 0, 0, time.Local)
+	cfg := Default()
+	cfg.ProfileSchedule = &ProfileSchedule{Weekday: "work", Weekend: "home"}
+	cfg.Holidays = &Holidays{Dates: []string{"2025-12-29"}}
+	if err := cfg.Holidays.load(); err != nil {
+		t.Fatal(err)
+	}
+	cfg.resolveProfileSchedule(monday)
+	if cfg.ActiveProfile != "home" {
+		t.Errorf("ActiveProfile = %q, want %q", cfg.ActiveProfile, "home")
+	}
+}
+
+func TestHolidaysQuietHours(t *testing.T) {
+	monday := time.Date(2025, 12, 29, 9, 30, 0, 0, time.Local)
+	tuesday := monday.AddDate(0, 0, 1)
+	cfg := &Config{
+		QuietHours: &QuietHours{
+			Start:   "22:00",
+			End:     "07:00",
+			Weekend: &QuietHours{Start: "00:00", End: "23:59"},
+		},
+		Holidays: &Holidays{Dates: []string{"2025-12-29"}},
+	}
+	if err := cfg.Holidays.load(); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.quietHoursAt(monday) {
+		t.Error("expected weekend quiet hours on a holiday")
+	}
+	if cfg.quietHoursAt(tuesday) {
+		t.Error("expected weekday quiet hours on a working day")
+	}
+}
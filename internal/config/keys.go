@@ -0,0 +1,87 @@
+package config
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Key describes a config key for generated reference docs.
+type Key struct {
+	Path string // Dotted path; <event> etc. stand for map keys, [] for list items
+	Type string // JSON type, e.g. "string", "number" or "list"
+}
+
+// customKeyTypes are keys whose custom decoding accepts more than their
+// Go type suggests, by struct type name and JSON name.
+var customKeyTypes = map[string]string{
+	"Config.activeProfile": "string or object",
+	"Event.sound":          "string or object",
+}
+
+// Keys lists every config key with its JSON type, read from the Config
+// struct's json tags and sorted within each object.
+func Keys() []Key {
+	var keys []Key
+	walkKeys(reflect.TypeOf(Config{}), "", make(map[reflect.Type]bool), &keys)
+	return keys
+}
+
+// walkKeys appends the keys under t, at path, to keys. Types already being
+// walked (e.g. the weekend quiet hours) aren't expanded again.
+func walkKeys(t reflect.Type, path string, walking map[reflect.Type]bool, keys *[]Key) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if walking[t] {
+			return
+		}
+		walking[t] = true
+		defer delete(walking, t)
+		fields := jsonFields(t)
+		for _, name := range slices.Sorted(maps.Keys(fields)) {
+			key := Key{Path: joinPath(path, name), Type: jsonType(fields[name])}
+			if custom, ok := customKeyTypes[t.Name()+"."+name]; ok {
+				key.Type = custom
+			}
+			*keys = append(*keys, key)
+			walkKeys(fields[name], key.Path, walking, keys)
+		}
+	case reflect.Map:
+		if reflect.PointerTo(t).Implements(unmarshalerType) {
+			return
+		}
+		walkKeys(t.Elem(), joinPath(path, mapKeyName(path)), walking, keys)
+	case reflect.Slice:
+		walkKeys(t.Elem(), path+"[]", walking, keys)
+	}
+}
+
+// mapKeyName is the placeholder for the keys of the map at path, e.g.
+// <event> for "events".
+func mapKeyName(path string) string {
+	name := strings.TrimSuffix(path[strings.LastIndex(path, ".")+1:], "[]")
+	return "<" + strings.TrimSuffix(name, "s") + ">"
+}
+
+// jsonType names the JSON type a Go type decodes from.
+func jsonType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int64, reflect.Float64:
+		return "number"
+	case reflect.Slice:
+		return "list of " + jsonType(t.Elem()) + "s"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,36 @@
+package config
+
+import "testing"
+
+func TestKeys(t *testing.T) {
+	types := make(map[string]string)
+	for _, key := range Keys() {
+		if _, ok := types[key.Path]; ok {
+			t.Errorf("Keys() lists %s twice", key.Path)
+		}
+		types[key.Path] = key.Type
+	}
+
+	want := map[string]string{
+		"enabled":               "boolean",
+		"activeProfile":         "string or object",
+		"soundPaths":            "list of strings",
+		"quietHours.weekend":    "object",
+		"events.<event>.sound":  "string or object",
+		"events.<event>.volume": "number",
+		"profiles.<profile>.events.<event>.cooldown": "number",
+		"branches[].events.<event>.format.severity":  "string",
+		"webhook.headers": "object",
+		"themes":          "object",
+	}
+	for path, typ := range want {
+		if got := types[path]; got != typ {
+			t.Errorf("type of %s = %q, want %q", path, got, typ)
+		}
+	}
+	for _, path := range []string{"quietHours.weekend.start", "themes.<theme>", "unknownKeys"} {
+		if _, ok := types[path]; ok {
+			t.Errorf("Keys() lists %s", path)
+		}
+	}
+}
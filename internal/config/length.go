@@ -0,0 +1,112 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Response length classes.
+const (
+	LengthShort = "short"
+	LengthLong  = "long"
+)
+
+// ResponseLength picks a different sound or volume for short and long
+// responses, judged by the duration or output tokens in the hook payload.
+type ResponseLength struct {
+	ShortSeconds *int   `json:"shortSeconds,omitempty"` // Responses under this are short
+	LongSeconds  *int   `json:"longSeconds,omitempty"`  // Responses at or over this are long
+	ShortTokens  *int   `json:"shortTokens,omitempty"`  // Used when the payload has no duration
+	LongTokens   *int   `json:"longTokens,omitempty"`
+	Short        *Event `json:"short,omitempty"` // Overrides for short responses
+	Long         *Event `json:"long,omitempty"`  // Overrides for long responses
+}
+
+// Classify returns LengthShort, LengthLong or "" for a response. Zero
+// duration or tokens mean the payload didn't include them; duration is
+// preferred when both are known.
+func (r *ResponseLength) Classify(duration time.Duration, tokens int) string {
+	switch {
+	case r == nil:
+		return ""
+	case duration > 0:
+		return classify(int(duration/time.Second), r.ShortSeconds, r.LongSeconds)
+	case tokens > 0:
+		return classify(tokens, r.ShortTokens, r.LongTokens)
+	default:
+		return ""
+	}
+}
+
+// classify compares a measurement against optional thresholds.
+func classify(value int, short, long *int) string {
+	if short != nil && value < *short {
+		return LengthShort
+	}
+	if long != nil && value >= *long {
+		return LengthLong
+	}
+	return ""
+}
+
+// ForResponseLength returns the event with the overrides for a response
+// length class applied. The event itself is not modified.
+func (e *Event) ForResponseLength(class string) *Event {
+	if e.ResponseLength == nil {
+		return e
+	}
+	var override *Event
+	switch class {
+	case LengthShort:
+		override = e.ResponseLength.Short
+	case LengthLong:
+		override = e.ResponseLength.Long
+	}
+	if override == nil {
+		return e
+	}
+	result := *e
+	if override.Sound != "" || override.PlatformSounds != nil {
+		// A sound override replaces the whole spec, platform entries included
+		result.Sound, result.PlatformSounds = "", nil
+	}
+	mergeEvent(&result, override)
+	return &result
+}
+
+// validate checks thresholds and overrides.
+func (r *ResponseLength) validate() error {
+	for name, v := range map[string]*int{
+		"shortSeconds": r.ShortSeconds, "longSeconds": r.LongSeconds,
+		"shortTokens": r.ShortTokens, "longTokens": r.LongTokens,
+	} {
+		if v != nil && *v <= 0 {
+			return fmt.Errorf("responseLength.%s must be positive", name)
+		}
+	}
+	if r.ShortSeconds != nil && r.LongSeconds != nil && *r.ShortSeconds > *r.LongSeconds {
+		return errors.New("responseLength.shortSeconds must not exceed longSeconds")
+	}
+	if r.ShortTokens != nil && r.LongTokens != nil && *r.ShortTokens > *r.LongTokens {
+		return errors.New("responseLength.shortTokens must not exceed longTokens")
+	}
+	for class, override := range map[string]*Event{LengthShort: r.Short, LengthLong: r.Long} {
+		if override == nil {
+			continue
+		}
+		if override.Volume != nil && (*override.Volume < 0 || *override.Volume > 1) {
+			return fmt.Errorf("responseLength.%s: volume must be 0.0-1.0", class)
+		}
+		if override.Gain != nil && (*override.Gain < MinGain || *override.Gain > MaxGain) {
+			return fmt.Errorf("responseLength.%s: gain must be %.0f to +%.0f dB", class, MinGain, MaxGain)
+		}
+		if err := validatePlatformSounds(override.PlatformSounds); err != nil {
+			return fmt.Errorf("responseLength.%s: %w", class, err)
+		}
+		if override.ResponseLength != nil {
+			return fmt.Errorf("responseLength.%s cannot nest responseLength", class)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestResponseLengthClassify(t *testing.T) {
+	r := &ResponseLength{
+		ShortSeconds: ptrInt(10),
+		LongSeconds:  ptrInt(120),
+		ShortTokens:  ptrInt(200),
+		LongTokens:   ptrInt(2000),
+	}
+	tests := []struct {
+		name     string
+		duration time.Duration
+		tokens   int
+		want     string
+	}{
+		{"short duration", 5 * time.Second, 0, LengthShort},
+		{"medium duration", time.Minute, 0, ""},
+		{"long duration", 2 * time.Minute, 0, LengthLong},
+		{"duration preferred over tokens", 5 * time.Second, 5000, LengthShort},
+		{"short tokens", 0, 50, LengthShort},
+		{"long tokens", 0, 3000, LengthLong},
+		{"unknown", 0, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Classify(tt.duration, tt.tokens); got != tt.want {
+				t.Errorf("Classify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	var unset *ResponseLength
+	if got := unset.Classify(time.Second, 0); got != "" {
+		t.Errorf("nil Classify() = %q", got)
+	}
+	if got := (&ResponseLength{LongSeconds: ptrInt(60)}).Classify(time.Second, 0); got != "" {
+		t.Errorf("Classify() without short threshold = %q", got)
+	}
+}
+
+func TestEventForResponseLength(t *testing.T) {
+	var event Event
+	data := `{"sound": {"macos": "system:Glass", "default": "bundled:stop"}, "volume": 0.5,
+		"responseLength": {"shortSeconds": 10, "short": {"sound": "system:Tink", "volume": 0.2}, "long": {"volume": 0.9}}}`
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	short := event.ForResponseLength(LengthShort)
+	if short.SoundFor("macos") != "system:Tink" || *short.Volume != 0.2 {
+		t.Errorf("short = sound %q, volume %v", short.SoundFor("macos"), *short.Volume)
+	}
+	long := event.ForResponseLength(LengthLong)
+	if long.SoundFor("macos") != "system:Glass" || *long.Volume != 0.9 {
+		t.Errorf("long = sound %q, volume %v", long.SoundFor("macos"), *long.Volume)
+	}
+	if got := event.ForResponseLength(""); got != &event {
+		t.Error("no class should return the event unchanged")
+	}
+	if *event.Volume != 0.5 || event.Sound != "bundled:stop" {
+		t.Error("ForResponseLength modified the event")
+	}
+}
+
+func TestValidateResponseLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		rl      *ResponseLength
+		wantErr bool
+	}{
+		{"valid", &ResponseLength{ShortSeconds: ptrInt(10), LongSeconds: ptrInt(60), Short: &Event{Volume: ptrFloat(0.2)}}, false},
+		{"zero threshold", &ResponseLength{ShortTokens: ptrInt(0)}, true},
+		{"short above long", &ResponseLength{ShortSeconds: ptrInt(60), LongSeconds: ptrInt(10)}, true},
+		{"bad volume", &ResponseLength{Long: &Event{Volume: ptrFloat(2)}}, true},
+		{"bad platform", &ResponseLength{Long: &Event{PlatformSounds: map[string]string{"windows": "x"}}}, true},
+		{"nested", &ResponseLength{Long: &Event{ResponseLength: &ResponseLength{}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Events["stop"].ResponseLength = tt.rl
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// keyOrder maps the path of each JSON object in a document to its keys in
+// the order they were written. Paths join keys and array indexes with NUL,
+// which can't collide with a key.
+type keyOrder map[string][]string
+
+// childPath returns the path of a key or index under path.
+func childPath(path, key string) string {
+	return path + "\x00" + key
+}
+
+// readKeyOrder records the key order of every object in data.
+func readKeyOrder(data []byte) (keyOrder, error) {
+	order := make(keyOrder)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if err := order.read(dec, ""); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// read consumes one value from dec, recording object keys under path.
+func (o keyOrder) read(dec *json.Decoder, path string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('{'):
+		var keys []string
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key, ok := tok.(string)
+			if !ok {
+				return fmt.Errorf("unexpected object key %v", tok)
+			}
+			keys = append(keys, key)
+			if err := o.read(dec, childPath(path, key)); err != nil {
+				return err
+			}
+		}
+		o[path] = keys
+		_, err = dec.Token()
+		return err
+	case json.Delim('['):
+		for i := 0; dec.More(); i++ {
+			if err := o.read(dec, childPath(path, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+		return err
+	}
+	return nil
+}
+
+// marshal writes v indented, with object keys in their recorded order.
+// Keys that weren't recorded follow, sorted.
+func (o keyOrder) marshal(v any) ([]byte, error) {
+	var compact bytes.Buffer
+	if err := o.encode(&compact, v, ""); err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, compact.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// encode writes v compactly to buf.
+func (o keyOrder) encode(buf *bytes.Buffer, v any, path string) error {
+	switch v := v.(type) {
+	case map[string]any:
+		buf.WriteByte('{')
+		for i, key := range o.keys(v, path) {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeValue(buf, key); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := o.encode(buf, v[key], childPath(path, key)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := o.encode(buf, item, childPath(path, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		return encodeValue(buf, v)
+	}
+	return nil
+}
+
+// encodeValue writes a JSON value to buf without escaping <, > and &, so
+// templates such as "<b>{project}</b>" read as the user wrote them.
+func encodeValue(buf *bytes.Buffer, v any) error {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	buf.Truncate(buf.Len() - 1) // Encode's newline
+	return nil
+}
+
+// keys returns m's keys: recorded ones first, in order, then new ones
+// sorted.
+func (o keyOrder) keys(m map[string]any, path string) []string {
+	keys := make([]string, 0, len(m))
+	for _, key := range o[path] {
+		if _, ok := m[key]; ok && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	var added []string
+	for key := range m {
+		if !slices.Contains(keys, key) {
+			added = append(added, key)
+		}
+	}
+	sort.Strings(added)
+	return append(keys, added...)
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// profileNameRegex validates profile names derived from file names.
+var profileNameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// builtinProfiles can be activated without being defined in config. A
+// profile of the same name in config takes precedence.
+var builtinProfiles = map[string]*Profile{
+	"silent": {Events: map[string]*Event{
+		"stop":              {Enabled: ptrBool(false)},
+		"permission_prompt": {Enabled: ptrBool(false)},
+		"idle_prompt":       {Enabled: ptrBool(false)},
+		"subagent":          {Enabled: ptrBool(false)},
+	}},
+	"minimal": {Events: map[string]*Event{
+		"stop":              {Enabled: ptrBool(false)},
+		"permission_prompt": {Enabled: ptrBool(true)},
+		"idle_prompt":       {Enabled: ptrBool(false)},
+		"subagent":          {Enabled: ptrBool(false)},
+	}},
+}
+
+// GetProfile returns a profile defined in config or built in.
+func (c *Config) GetProfile(name string) (*Profile, bool) {
+	if profile, ok := c.Profiles[name]; ok {
+		return profile, true
+	}
+	profile, ok := builtinProfiles[name]
+	return profile, ok
+}
+
+// ProfileNames returns the names of all available profiles, including the
+// default profile, sorted.
+func (c *Config) ProfileNames() []string {
+	names := []string{defaultProfileName}
+	for name := range builtinProfiles {
+		names = append(names, name)
+	}
+	for name := range c.Profiles {
+		if _, builtin := builtinProfiles[name]; !builtin && name != defaultProfileName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProfilesDir returns the directory holding per-file profiles for a config file.
+// For ~/.claude/ccbell.config.json this is ~/.claude/ccbell/profiles.
+func ProfilesDir(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "ccbell", "profiles")
+}
+
+// loadProfileFiles reads every *.json file in dir as a profile named after the
+// file. Profiles already defined in the main config are not overridden.
+// A missing directory is not an error.
+func (c *Config) loadProfileFiles(dir string) error {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("failed to list profiles: %w", err)
+	}
+	sort.Strings(matches)
+
+	for _, path := range matches {
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		if !profileNameRegex.MatchString(name) {
+			return fmt.Errorf("invalid profile file name: %s", path)
+		}
+		if _, exists := c.Profiles[name]; exists {
+			// Main config takes precedence
+			c.unreachableProfiles = append(c.unreachableProfiles,
+				fmt.Sprintf("profile file %s is hidden by profile %q in the config", path, name))
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read profile %s: %w", path, err)
+		}
+		var profile Profile
+		if err := json.Unmarshal(data, &profile); err != nil {
+			return fmt.Errorf("invalid JSON in %s: %w", path, err)
+		}
+		c.addUnknownKeys(path, data, reflect.TypeOf(Profile{}))
+
+		if c.Profiles == nil {
+			c.Profiles = make(map[string]*Profile)
+		}
+		c.Profiles[name] = &profile
+	}
+
+	return nil
+}
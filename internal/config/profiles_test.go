@@ -0,0 +1,123 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestProfilesDir(t *testing.T) {
+	got := ProfilesDir("/home/user/.claude/ccbell.config.json")
+	want := filepath.Join("/home/user/.claude", "ccbell", "profiles")
+	if got != want {
+		t.Errorf("ProfilesDir() = %q, want %q", got, want)
+	}
+}
+
+func TestLoadProfileFiles(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-profiles-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	configPath := filepath.Join(tempDir, "ccbell.config.json")
+	profilesDir := ProfilesDir(configPath)
+	if err := os.MkdirAll(profilesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile(configPath, `{
+		"activeProfile": "night",
+		"profiles": {
+			"work": {"events": {"stop": {"volume": 0.2}}}
+		}
+	}`)
+	writeFile(filepath.Join(profilesDir, "night.json"), `{"events": {"stop": {"volume": 0.1}}}`)
+	writeFile(filepath.Join(profilesDir, "work.json"), `{"events": {"stop": {"volume": 0.9}}}`)
+	writeFile(filepath.Join(profilesDir, "notes.txt"), `ignored`)
+
+	t.Run("loads profiles from files", func(t *testing.T) {
+		cfg, _, err := LoadFile(configPath)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := cfg.Profiles["night"]; !ok {
+			t.Fatal("expected profile 'night' to be loaded from file")
+		}
+		if got := *cfg.GetEventConfig("stop").Volume; got != 0.1 {
+			t.Errorf("expected volume 0.1 from night profile, got %f", got)
+		}
+	})
+
+	t.Run("main config takes precedence", func(t *testing.T) {
+		cfg, _, err := LoadFile(configPath)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := *cfg.Profiles["work"].Events["stop"].Volume; got != 0.2 {
+			t.Errorf("expected inline work profile volume 0.2, got %f", got)
+		}
+	})
+
+	t.Run("invalid profile JSON", func(t *testing.T) {
+		bad := filepath.Join(profilesDir, "broken.json")
+		writeFile(bad, `{invalid`)
+		defer os.Remove(bad)
+
+		if _, _, err := LoadFile(configPath); err == nil {
+			t.Error("expected error for invalid profile JSON")
+		}
+	})
+
+	t.Run("invalid profile event", func(t *testing.T) {
+		bad := filepath.Join(profilesDir, "loud.json")
+		writeFile(bad, `{"events": {"stop": {"volume": 3}}}`)
+		defer os.Remove(bad)
+
+		if _, _, err := LoadFile(configPath); err == nil {
+			t.Error("expected validation error for profile file")
+		}
+	})
+}
+
+func TestBuiltinProfiles(t *testing.T) {
+	cfg := Default()
+
+	cfg.ActiveProfile = "minimal"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	for event := range ValidEvents {
+		want := event == "permission_prompt"
+		if got := *cfg.GetEventConfig(event).Enabled; got != want {
+			t.Errorf("minimal: %s enabled = %v, want %v", event, got, want)
+		}
+	}
+
+	cfg.ActiveProfile = "silent"
+	for event := range ValidEvents {
+		if *cfg.GetEventConfig(event).Enabled {
+			t.Errorf("silent: %s should be disabled", event)
+		}
+	}
+
+	// A configured profile shadows the built-in one
+	cfg.Profiles = map[string]*Profile{"silent": {Events: map[string]*Event{}}}
+	if !*cfg.GetEventConfig("stop").Enabled {
+		t.Error("configured silent profile should override the built-in one")
+	}
+
+	want := []string{"default", "minimal", "silent"}
+	if got := cfg.ProfileNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ProfileNames() = %v, want %v", got, want)
+	}
+}
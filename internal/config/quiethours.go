@@ -9,17 +9,34 @@ import (
 
 // IsInQuietHours checks if the current time is within quiet hours.
 func (c *Config) IsInQuietHours() bool {
-	if c.QuietHours == nil || c.QuietHours.Start == "" || c.QuietHours.End == "" {
+	return c.quietHoursAt(time.Now())
+}
+
+// QuietHoursFor returns the quiet hours window that applies on the day of
+// now: the weekend window on weekends and holidays when one is set.
+func (c *Config) QuietHoursFor(now time.Time) *QuietHours {
+	if c.QuietHours == nil {
+		return nil
+	}
+	if c.QuietHours.Weekend != nil && c.isDayOff(now) {
+		return c.QuietHours.Weekend
+	}
+	return c.QuietHours
+}
+
+// quietHoursAt checks if now is within quiet hours.
+func (c *Config) quietHoursAt(now time.Time) bool {
+	qh := c.QuietHoursFor(now)
+	if qh == nil || qh.Start == "" || qh.End == "" {
 		return false
 	}
 
-	startMins, err1 := parseTimeToMinutes(c.QuietHours.Start)
-	endMins, err2 := parseTimeToMinutes(c.QuietHours.End)
+	startMins, err1 := c.quietTimeToMinutes(qh.Start, now)
+	endMins, err2 := c.quietTimeToMinutes(qh.End, now)
 	if err1 != nil || err2 != nil {
-		return false // Invalid format, don't block
+		return false // Invalid format or no sunrise/sunset, don't block
 	}
 
-	now := time.Now()
 	currentMins := now.Hour()*60 + now.Minute()
 
 	// Handle start == end (24-hour quiet period, meaning quiet hours disabled)
@@ -37,6 +54,77 @@ func (c *Config) IsInQuietHours() bool {
 	return currentMins >= startMins && currentMins < endMins
 }
 
+// quietTimeToMinutes converts a quiet hours start or end to minutes since
+// midnight on the day of now: "HH:MM", or "sunrise"/"sunset" with an
+// optional offset at the configured or time zone's coordinates.
+func (c *Config) quietTimeToMinutes(spec string, now time.Time) (int, error) {
+	if !strings.HasPrefix(spec, "sun") {
+		return parseTimeToMinutes(spec)
+	}
+	sunset, offset, err := parseSunTime(spec)
+	if err != nil {
+		return 0, err
+	}
+	lat, lon, ok := c.QuietHours.coordinates()
+	if !ok {
+		return 0, fmt.Errorf("no location for %q: set quietHours.latitude and longitude", spec)
+	}
+	sunrise, sunsetAt, ok := sunTimes(now, lat, lon)
+	if !ok {
+		return 0, fmt.Errorf("the sun doesn't rise or set on %s", now.Format(time.DateOnly))
+	}
+	t := sunrise
+	if sunset {
+		t = sunsetAt
+	}
+	t = t.Add(offset).In(now.Location())
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// coordinates returns the configured latitude and longitude, or else the
+// local time zone's.
+func (q *QuietHours) coordinates() (lat, lon float64, ok bool) {
+	if q.Latitude != nil && q.Longitude != nil {
+		return *q.Latitude, *q.Longitude, true
+	}
+	coords, ok := localCoordinates()
+	return coords[0], coords[1], ok
+}
+
+// parseSunTime parses "sunrise" or "sunset" with an optional offset such as
+// "sunset+30m" or "sunrise-1h".
+func parseSunTime(spec string) (sunset bool, offset time.Duration, err error) {
+	rest, sunset := strings.CutPrefix(spec, "sunset")
+	if !sunset {
+		var ok bool
+		if rest, ok = strings.CutPrefix(spec, "sunrise"); !ok {
+			return false, 0, fmt.Errorf("invalid time format: %q (expected HH:MM, sunrise or sunset)", spec)
+		}
+	}
+	if rest == "" {
+		return sunset, 0, nil
+	}
+	if rest[0] != '+' && rest[0] != '-' {
+		return false, 0, fmt.Errorf("invalid time format: %q (expected an offset like sunset+30m)", spec)
+	}
+	offset, err = time.ParseDuration(rest)
+	if err != nil || offset.Abs() > 12*time.Hour {
+		return false, 0, fmt.Errorf("invalid offset in %q (expected up to 12h, like sunset+30m)", spec)
+	}
+	return sunset, offset, nil
+}
+
+// validateQuietTime checks a quiet hours start or end.
+func validateQuietTime(field, spec string) error {
+	if spec == "" || timeFormatRegex.MatchString(spec) {
+		return nil
+	}
+	if _, _, err := parseSunTime(spec); err != nil {
+		return fmt.Errorf("invalid %s format: %s (expected HH:MM, sunrise or sunset, optionally with an offset like sunset+30m)", field, spec)
+	}
+	return nil
+}
+
 // parseTimeToMinutes converts "HH:MM" to minutes since midnight.
 func parseTimeToMinutes(timeStr string) (int, error) {
 	parts := strings.Split(timeStr, ":")
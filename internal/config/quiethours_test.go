@@ -1,6 +1,8 @@
 package config
 
 import (
+	"math"
+	"strings"
 	"testing"
 	"time"
 )
@@ -154,3 +156,152 @@ func padZero(n int) string {
 	}
 	return string(rune('0'+n/10)) + string(rune('0'+n%10))
 }
+
+func TestQuietHoursWeekend(t *testing.T) {
+	saturday := time.Date(2025, 3, 15, 9, 30, 0, 0, time.Local)
+	monday := time.Date(2025, 3, 17, 9, 30, 0, 0, time.Local)
+	cfg := &Config{QuietHours: &QuietHours{
+		Start:   "22:00",
+		End:     "07:00",
+		Weekend: &QuietHours{Start: "22:00", End: "10:00"},
+	}}
+
+	if got := cfg.QuietHoursFor(saturday); got != cfg.QuietHours.Weekend {
+		t.Errorf("QuietHoursFor(saturday) = %+v, want weekend window", got)
+	}
+	if !cfg.quietHoursAt(saturday) {
+		t.Error("expected quiet hours on saturday morning")
+	}
+	if cfg.quietHoursAt(monday) {
+		t.Error("expected no quiet hours on monday morning")
+	}
+
+	cfg.QuietHours.Weekend.Weekend = &QuietHours{}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for nested weekend quiet hours")
+	}
+}
+
+func TestSunTimes(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip("no time zone database")
+	}
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	if err != nil {
+		t.Skip("no time zone database")
+	}
+	tests := []struct {
+		name            string
+		date            time.Time
+		lat, lon        float64
+		sunrise, sunset string
+	}{
+		{"london midsummer", time.Date(2024, 6, 21, 12, 0, 0, 0, london), 51.5074, -0.1278, "04:43", "21:21"},
+		{"london midwinter", time.Date(2024, 12, 21, 12, 0, 0, 0, london), 51.5074, -0.1278, "08:04", "15:53"},
+		{"sydney midsummer", time.Date(2024, 12, 21, 12, 0, 0, 0, sydney), -33.8688, 151.2093, "05:41", "20:05"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sunrise, sunset, ok := sunTimes(tt.date, tt.lat, tt.lon)
+			if !ok {
+				t.Fatal("sunTimes() ok = false")
+			}
+			for _, c := range []struct {
+				got  time.Time
+				want string
+			}{{sunrise, tt.sunrise}, {sunset, tt.sunset}} {
+				want, _ := parseTimeToMinutes(c.want)
+				got := c.got.Hour()*60 + c.got.Minute()
+				if got < want-3 || got > want+3 {
+					t.Errorf("sunTimes() = %s, want %s", c.got.Format("15:04"), c.want)
+				}
+			}
+		})
+	}
+
+	// Tromsø has midnight sun in June
+	if _, _, ok := sunTimes(time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC), 69.65, 18.96); ok {
+		t.Error("sunTimes() ok = true during the midnight sun")
+	}
+}
+
+func TestQuietHoursSun(t *testing.T) {
+	lat, lon := 41.0082, 28.9784 // Istanbul, UTC+3
+	loc := time.FixedZone("TRT", 3*60*60)
+	cfg := &Config{QuietHours: &QuietHours{Start: "sunset+30m", End: "sunrise", Latitude: &lat, Longitude: &lon}}
+
+	// Sunset on 2024-06-21 is about 20:40, sunrise about 05:32
+	tests := []struct {
+		clock string
+		want  bool
+	}{
+		{"20:50", false},
+		{"21:20", true},
+		{"03:00", true},
+		{"06:00", false},
+		{"12:00", false},
+	}
+	for _, tt := range tests {
+		mins, _ := parseTimeToMinutes(tt.clock)
+		now := time.Date(2024, 6, 21, mins/60, mins%60, 0, 0, loc)
+		if got := cfg.quietHoursAt(now); got != tt.want {
+			t.Errorf("quietHoursAt(%s) = %v, want %v", tt.clock, got, tt.want)
+		}
+	}
+
+	// Without sunrise or sunset, quiet hours don't apply
+	north := 78.22 // Svalbard
+	cfg.QuietHours.Latitude = &north
+	if cfg.quietHoursAt(time.Date(2024, 6, 21, 0, 0, 0, 0, loc)) {
+		t.Error("expected no quiet hours during the midnight sun")
+	}
+}
+
+func TestParseSunTime(t *testing.T) {
+	tests := []struct {
+		spec       string
+		wantSunset bool
+		wantOffset time.Duration
+		wantErr    bool
+	}{
+		{"sunset", true, 0, false},
+		{"sunrise", false, 0, false},
+		{"sunset+30m", true, 30 * time.Minute, false},
+		{"sunrise-1h15m", false, -75 * time.Minute, false},
+		{"sunset30m", false, 0, true},
+		{"sunset+13h", false, 0, true},
+		{"sundown", false, 0, true},
+	}
+	for _, tt := range tests {
+		sunset, offset, err := parseSunTime(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSunTime(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (sunset != tt.wantSunset || offset != tt.wantOffset) {
+			t.Errorf("parseSunTime(%q) = %v, %v, want %v, %v", tt.spec, sunset, offset, tt.wantSunset, tt.wantOffset)
+		}
+	}
+}
+
+func TestZoneCoordinates(t *testing.T) {
+	tab := "# comment\n" +
+		"TR\t+4101+02858\tEurope/Istanbul\n" +
+		"US\t+404251-0740023\tAmerica/New_York\tEastern (most areas)\n"
+	tests := []struct {
+		zone     string
+		lat, lon float64
+		ok       bool
+	}{
+		{"Europe/Istanbul", 41 + 1.0/60, 28 + 58.0/60, true},
+		{"America/New_York", 40 + 42.0/60 + 51.0/3600, -(74 + 0.0/60 + 23.0/3600), true},
+		{"Mars/Olympus", 0, 0, false},
+	}
+	for _, tt := range tests {
+		lat, lon, ok := zoneCoordinates(strings.NewReader(tab), tt.zone)
+		if ok != tt.ok || math.Abs(lat-tt.lat) > 1e-9 || math.Abs(lon-tt.lon) > 1e-9 {
+			t.Errorf("zoneCoordinates(%q) = %v, %v, %v, want %v, %v, %v", tt.zone, lat, lon, ok, tt.lat, tt.lon, tt.ok)
+		}
+	}
+}
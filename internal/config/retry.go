@@ -0,0 +1,68 @@
+package config
+
+import "errors"
+
+// Retry configures redelivery of failed remote notifications (webhook,
+// bark). Unset fields keep the defaults: 3 attempts within 10 seconds, then
+// queueing in the state file until a later invocation delivers them.
+type Retry struct {
+	Attempts *int  `json:"attempts,omitempty"` // Total attempts; 1 disables retries
+	Budget   *int  `json:"budget,omitempty"`   // Seconds allowed for all attempts
+	Queue    *bool `json:"queue,omitempty"`    // Keep failures for a later invocation; default true
+}
+
+// validate checks the retry limits.
+func (r *Retry) validate() error {
+	if r.Attempts != nil && (*r.Attempts < 1 || *r.Attempts > 10) {
+		return errors.New("retry.attempts must be 1-10")
+	}
+	if r.Budget != nil && *r.Budget < 1 {
+		return errors.New("retry.budget must be at least 1 second")
+	}
+	return nil
+}
+
+// QueuesFailedDeliveries reports whether remote deliveries that fail after
+// retrying are queued for a later invocation. Default true.
+func (c *Config) QueuesFailedDeliveries() bool {
+	return c.Retry == nil || c.Retry.Queue == nil || *c.Retry.Queue
+}
+
+// Circuit breaker defaults.
+const (
+	DefaultBreakerFailures = 3
+	DefaultBreakerCooldown = 300 // Seconds
+)
+
+// CircuitBreaker stops deliveries to a remote channel for a while after
+// consecutive failures, so a dead endpoint doesn't slow down every hook.
+type CircuitBreaker struct {
+	Failures *int `json:"failures,omitempty"` // Consecutive failures that open the circuit; 0 disables
+	Cooldown *int `json:"cooldown,omitempty"` // Seconds before trying again
+}
+
+// validate checks the circuit breaker limits.
+func (b *CircuitBreaker) validate() error {
+	if b.Failures != nil && *b.Failures < 0 {
+		return errors.New("circuitBreaker.failures cannot be negative")
+	}
+	if b.Cooldown != nil && *b.Cooldown < 1 {
+		return errors.New("circuitBreaker.cooldown must be at least 1 second")
+	}
+	return nil
+}
+
+// BreakerValues returns the consecutive failures that open a remote
+// channel's circuit (0 when disabled) and the seconds it stays open.
+func (c *Config) BreakerValues() (failures, cooldownSeconds int) {
+	failures, cooldownSeconds = DefaultBreakerFailures, DefaultBreakerCooldown
+	if b := c.CircuitBreaker; b != nil {
+		if b.Failures != nil {
+			failures = *b.Failures
+		}
+		if b.Cooldown != nil {
+			cooldownSeconds = *b.Cooldown
+		}
+	}
+	return failures, cooldownSeconds
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ProfileSchedule picks the active profile by day type, set when
+// "activeProfile" is an object such as {"weekday": "work", "weekend": "home"}.
+type ProfileSchedule struct {
+	Weekday string `json:"weekday,omitempty"`
+	Weekend string `json:"weekend,omitempty"`
+}
+
+// UnmarshalJSON accepts "activeProfile" as a profile name or a schedule.
+func (c *Config) UnmarshalJSON(data []byte) error {
+	type plain Config
+	aux := struct {
+		*plain
+		ActiveProfile json.RawMessage `json:"activeProfile"`
+	}{plain: (*plain)(c)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if len(aux.ActiveProfile) == 0 {
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(aux.ActiveProfile, &name); err == nil {
+		c.ActiveProfile, c.ProfileSchedule = name, nil
+		return nil
+	}
+	var schedule ProfileSchedule
+	if err := json.Unmarshal(aux.ActiveProfile, &schedule); err != nil {
+		return fmt.Errorf("activeProfile must be a profile name or {\"weekday\": ..., \"weekend\": ...}: %w", err)
+	}
+	c.ActiveProfile, c.ProfileSchedule = "", &schedule
+	return nil
+}
+
+// isWeekend reports whether t falls on a Saturday or Sunday.
+func isWeekend(t time.Time) bool {
+	day := t.Weekday()
+	return day == time.Saturday || day == time.Sunday
+}
+
+// resolveProfileSchedule sets the active profile from the schedule for the
+// day of now. Holidays count as weekend days. Days without a scheduled
+// profile use the default profile.
+func (c *Config) resolveProfileSchedule(now time.Time) {
+	if c.ProfileSchedule == nil {
+		return
+	}
+	name := c.ProfileSchedule.Weekday
+	if c.isDayOff(now) {
+		name = c.ProfileSchedule.Weekend
+	}
+	if name == "" {
+		name = defaultProfileName
+	}
+	c.ActiveProfile = name
+}
+
+// validateProfileSchedule checks that scheduled profiles exist.
+func (c *Config) validateProfileSchedule() error {
+	if c.ProfileSchedule == nil {
+		return nil
+	}
+	for dayType, name := range map[string]string{"weekday": c.ProfileSchedule.Weekday, "weekend": c.ProfileSchedule.Weekend} {
+		if name == "" || name == defaultProfileName {
+			continue
+		}
+		if _, ok := c.GetProfile(name); !ok {
+			return fmt.Errorf("activeProfile.%s %q not found in profiles", dayType, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestActiveProfileJSON(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string
+		wantProfile  string
+		wantSchedule *ProfileSchedule
+		wantErr      bool
+	}{
+		{"name", `{"activeProfile": "work"}`, "work", nil, false},
+		{"schedule", `{"activeProfile": {"weekday": "work", "weekend": "home"}}`, "", &ProfileSchedule{Weekday: "work", Weekend: "home"}, false},
+		{"absent keeps existing", `{"debug": true}`, "default", nil, false},
+		{"invalid", `{"activeProfile": 5}`, "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			err := json.Unmarshal([]byte(tt.data), cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if cfg.ActiveProfile != tt.wantProfile {
+				t.Errorf("ActiveProfile = %q, want %q", cfg.ActiveProfile, tt.wantProfile)
+			}
+			if (cfg.ProfileSchedule == nil) != (tt.wantSchedule == nil) ||
+				(tt.wantSchedule != nil && *cfg.ProfileSchedule != *tt.wantSchedule) {
+				t.Errorf("ProfileSchedule = %+v, want %+v", cfg.ProfileSchedule, tt.wantSchedule)
+			}
+		})
+	}
+}
+
+func TestResolveProfileSchedule(t *testing.T) {
+	monday := time.Date(2025, 3, 10, 9, 0, 0, 0, time.Local)
+	saturday := time.Date(2025, 3, 15, 9, 0, 0, 0, time.Local)
+	tests := []struct {
+		name     string
+		schedule *ProfileSchedule
+		now      time.Time
+		want     string
+	}{
+		{"weekday", &ProfileSchedule{Weekday: "work", Weekend: "home"}, monday, "work"},
+		{"weekend", &ProfileSchedule{Weekday: "work", Weekend: "home"}, saturday, "home"},
+		{"unscheduled day", &ProfileSchedule{Weekday: "work"}, saturday, "default"},
+		{"no schedule", nil, saturday, "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.ProfileSchedule = tt.schedule
+			cfg.resolveProfileSchedule(tt.now)
+			if cfg.ActiveProfile != tt.want {
+				t.Errorf("ActiveProfile = %q, want %q", cfg.ActiveProfile, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFileProfileSchedule(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-schedule-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldSystem, hadSystem := os.LookupEnv(SystemConfigEnvVar)
+	defer func() {
+		if hadSystem {
+			os.Setenv(SystemConfigEnvVar, oldSystem)
+		} else {
+			os.Unsetenv(SystemConfigEnvVar)
+		}
+	}()
+	os.Setenv(SystemConfigEnvVar, filepath.Join(tmpDir, "missing.json"))
+
+	path := filepath.Join(tmpDir, "ccbell.config.json")
+	data := `{"enabled": true, "activeProfile": {"weekday": "work", "weekend": "silent"},
+		"profiles": {"work": {"events": {"stop": {"volume": 0.9}}}}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, _, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	want := "work"
+	if isWeekend(time.Now()) {
+		want = "silent"
+	}
+	if cfg.ActiveProfile != want {
+		t.Errorf("ActiveProfile = %q, want %q", cfg.ActiveProfile, want)
+	}
+
+	bad := `{"enabled": true, "activeProfile": {"weekday": "missing"}}`
+	if err := os.WriteFile(path, []byte(bad), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := LoadFile(path); err == nil {
+		t.Error("LoadFile() should reject a schedule naming an unknown profile")
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import "fmt"
+
+// RemoteChannels are the channels that deliver off this machine, so they
+// reach the user away from the screen.
+var RemoteChannels = map[string]bool{
+	ChannelWebhook: true,
+	ChannelBark:    true,
+}
+
+// ScreenLock routes notifications away from a locked screen, where sounds
+// and desktop notifications go unnoticed. Routing is off unless
+// "screenLock" is set.
+type ScreenLock struct {
+	Channels []string `json:"channels,omitempty"` // Default: the event's remote channels
+}
+
+// validate checks the screen lock channels.
+func (s *ScreenLock) validate(c *Config) error {
+	if err := c.validateChannels(s.Channels); err != nil {
+		return fmt.Errorf("screenLock.channels: %w", err)
+	}
+	return nil
+}
+
+// ForLockedScreen returns a copy of an event routed for a locked screen: to
+// screenLock.channels, or else to the event's remote channels. The copy has
+// no channels when there is nowhere to route it.
+func (c *Config) ForLockedScreen(e *Event) *Event {
+	result := *e
+	if c.ScreenLock != nil && len(c.ScreenLock.Channels) > 0 {
+		result.Channels = c.ScreenLock.Channels
+		return &result
+	}
+	result.Channels = []string{}
+	for _, name := range EventChannels(e) {
+		if RemoteChannels[name] {
+			result.Channels = append(result.Channels, name)
+		}
+	}
+	return &result
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestForLockedScreen(t *testing.T) {
+	tests := []struct {
+		name       string
+		screenLock *ScreenLock
+		channels   []string
+		want       []string
+	}{
+		{"remote channels kept", &ScreenLock{}, []string{ChannelSound, ChannelBark, ChannelDesktop, ChannelWebhook}, []string{ChannelBark, ChannelWebhook}},
+		{"no remote channels", &ScreenLock{}, nil, []string{}},
+		{"configured channels", &ScreenLock{Channels: []string{ChannelBark}}, []string{ChannelSound}, []string{ChannelBark}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{ScreenLock: tt.screenLock}
+			event := &Event{Channels: tt.channels}
+			got := cfg.ForLockedScreen(event)
+			if !reflect.DeepEqual(got.Channels, tt.want) {
+				t.Errorf("Channels = %v, want %v", got.Channels, tt.want)
+			}
+			if !reflect.DeepEqual(event.Channels, tt.channels) {
+				t.Error("ForLockedScreen() should not modify the event")
+			}
+		})
+	}
+}
+
+func TestScreenLockValidate(t *testing.T) {
+	for _, channels := range [][]string{{"pager"}, {ChannelBark}} {
+		cfg := &Config{ScreenLock: &ScreenLock{Channels: channels}}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Validate(%v) should fail", channels)
+		}
+	}
+	cfg := &Config{
+		Bark:       &Bark{DeviceKey: "key"},
+		ScreenLock: &ScreenLock{Channels: []string{ChannelBark}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
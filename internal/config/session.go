@@ -0,0 +1,40 @@
+package config
+
+import (
+	"errors"
+	"hash/fnv"
+)
+
+// SessionVariant returns a stable index in [0, n) for a session ID, so each
+// Claude session keeps the same sound variant across its events.
+func SessionVariant(sessionID string, n int) int {
+	if n <= 0 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write([]byte(sessionID))
+	return int(h.Sum32() % uint32(n))
+}
+
+// ForSession returns a copy of the event playing the session's sound from
+// "sessionSounds", so parallel sessions can be told apart by ear. The event
+// itself is not modified.
+func (e *Event) ForSession(sessionID string) *Event {
+	if len(e.SessionSounds) == 0 || sessionID == "" {
+		return e
+	}
+	result := *e
+	result.Sound = e.SessionSounds[SessionVariant(sessionID, len(e.SessionSounds))]
+	result.PlatformSounds = nil
+	return &result
+}
+
+// validateSessionSounds checks the per-session sound variants.
+func validateSessionSounds(sounds []string) error {
+	for _, spec := range sounds {
+		if spec == "" {
+			return errors.New("sessionSounds cannot contain empty sounds")
+		}
+	}
+	return nil
+}
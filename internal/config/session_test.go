@@ -0,0 +1,49 @@
+package config
+
+import "testing"
+
+func TestSessionVariant(t *testing.T) {
+	seen := make(map[int]bool)
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		v := SessionVariant(id, 3)
+		if v < 0 || v >= 3 {
+			t.Fatalf("SessionVariant(%q, 3) = %d, out of range", id, v)
+		}
+		if again := SessionVariant(id, 3); again != v {
+			t.Errorf("SessionVariant(%q, 3) = %d then %d, want stable", id, v, again)
+		}
+		seen[v] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("SessionVariant() picked only %v for 8 sessions", seen)
+	}
+	if got := SessionVariant("a", 0); got != 0 {
+		t.Errorf("SessionVariant(a, 0) = %d, want 0", got)
+	}
+}
+
+func TestForSession(t *testing.T) {
+	e := &Event{
+		Sound:          "bundled:stop",
+		PlatformSounds: map[string]string{"macos": "system:Glass"},
+		SessionSounds:  []string{"system:Hero", "system:Pop", "system:Tink"},
+	}
+	if got := e.ForSession(""); got != e {
+		t.Error("ForSession() without a session should return the event")
+	}
+
+	got := e.ForSession("session-1")
+	want := e.SessionSounds[SessionVariant("session-1", 3)]
+	if got.Sound != want || got.PlatformSounds != nil {
+		t.Errorf("ForSession() = %q, %v, want %q", got.Sound, got.PlatformSounds, want)
+	}
+	if e.Sound != "bundled:stop" {
+		t.Error("ForSession() modified the event")
+	}
+
+	// A profile's sound override replaces the variants
+	mergeEvent(e, &Event{Sound: "system:Ping"})
+	if e.SessionSounds != nil {
+		t.Errorf("SessionSounds = %v after a sound override, want none", e.SessionSounds)
+	}
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// eventJSON is Event without methods, used to avoid recursive (un)marshaling.
+type eventJSON Event
+
+// UnmarshalJSON accepts "sound" either as a string or as an object mapping
+// platform names (plus an optional "default") to sound specs.
+func (e *Event) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		*eventJSON
+		Sound json.RawMessage `json:"sound,omitempty"`
+	}{eventJSON: (*eventJSON)(e)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if len(aux.Sound) == 0 || string(aux.Sound) == "null" {
+		return nil
+	}
+
+	var spec string
+	if err := json.Unmarshal(aux.Sound, &spec); err == nil {
+		e.Sound = spec
+		e.PlatformSounds = nil
+		return nil
+	}
+
+	var byPlatform map[string]string
+	if err := json.Unmarshal(aux.Sound, &byPlatform); err != nil {
+		return fmt.Errorf("sound must be a string or an object of platform sounds: %w", err)
+	}
+	e.Sound = byPlatform[defaultPlatformKey]
+	delete(byPlatform, defaultPlatformKey)
+	e.PlatformSounds = byPlatform
+	return nil
+}
+
+// MarshalJSON writes "sound" as an object when per-platform sounds are set.
+func (e Event) MarshalJSON() ([]byte, error) {
+	if len(e.PlatformSounds) == 0 {
+		return json.Marshal(eventJSON(e))
+	}
+
+	byPlatform := make(map[string]string, len(e.PlatformSounds)+1)
+	for platform, spec := range e.PlatformSounds {
+		byPlatform[platform] = spec
+	}
+	if e.Sound != "" {
+		byPlatform[defaultPlatformKey] = e.Sound
+	}
+	return json.Marshal(struct {
+		eventJSON
+		Sound map[string]string `json:"sound"`
+	}{eventJSON: eventJSON(e), Sound: byPlatform})
+}
+
+// SoundFor returns the sound spec for the given platform, falling back to
+// the platform-independent sound.
+func (e *Event) SoundFor(platform string) string {
+	if spec, ok := e.PlatformSounds[platform]; ok && spec != "" {
+		return spec
+	}
+	return e.Sound
+}
+
+// validatePlatformSounds checks that per-platform sound keys are known.
+func validatePlatformSounds(sounds map[string]string) error {
+	for platform := range sounds {
+		if !ValidPlatforms[platform] {
+			return fmt.Errorf("unknown platform in sound: %s", platform)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEventUnmarshalSound(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		wantSound     string
+		wantPlatforms map[string]string
+		wantErr       bool
+	}{
+		{
+			name:      "string sound",
+			input:     `{"sound": "bundled:stop", "volume": 0.4}`,
+			wantSound: "bundled:stop",
+		},
+		{
+			name:          "platform sounds",
+			input:         `{"sound": {"macos": "system:Glass", "linux": "bundled:stop"}}`,
+			wantPlatforms: map[string]string{"macos": "system:Glass", "linux": "bundled:stop"},
+		},
+		{
+			name:          "platform sounds with default",
+			input:         `{"sound": {"macos": "system:Glass", "default": "bundled:subagent"}}`,
+			wantSound:     "bundled:subagent",
+			wantPlatforms: map[string]string{"macos": "system:Glass"},
+		},
+		{
+			name:    "invalid sound type",
+			input:   `{"sound": 42}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var event Event
+			err := json.Unmarshal([]byte(tt.input), &event)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if event.Sound != tt.wantSound {
+				t.Errorf("Sound = %q, want %q", event.Sound, tt.wantSound)
+			}
+			if len(event.PlatformSounds) != len(tt.wantPlatforms) {
+				t.Fatalf("PlatformSounds = %v, want %v", event.PlatformSounds, tt.wantPlatforms)
+			}
+			for k, v := range tt.wantPlatforms {
+				if event.PlatformSounds[k] != v {
+					t.Errorf("PlatformSounds[%q] = %q, want %q", k, event.PlatformSounds[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestEventMarshalRoundTrip(t *testing.T) {
+	original := Event{
+		Volume:         ptrFloat(0.3),
+		Sound:          "bundled:stop",
+		PlatformSounds: map[string]string{"macos": "system:Glass"},
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+
+	var decoded Event
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if decoded.Sound != "bundled:stop" || decoded.PlatformSounds["macos"] != "system:Glass" {
+		t.Errorf("round trip mismatch: %s", data)
+	}
+	if decoded.Volume == nil || *decoded.Volume != 0.3 {
+		t.Errorf("volume lost in round trip: %s", data)
+	}
+}
+
+func TestSoundFor(t *testing.T) {
+	cfg := &Config{
+		ActiveProfile: "plain",
+		Events: map[string]*Event{
+			"stop": {Sound: "bundled:stop", PlatformSounds: map[string]string{"macos": "system:Glass"}},
+		},
+		Profiles: map[string]*Profile{
+			"plain": {Events: map[string]*Event{"stop": {Sound: "bundled:subagent"}}},
+		},
+	}
+
+	t.Run("platform specific sound", func(t *testing.T) {
+		cfg.ActiveProfile = "default"
+		eventCfg := cfg.GetEventConfig("stop")
+		if got := eventCfg.SoundFor("macos"); got != "system:Glass" {
+			t.Errorf("SoundFor(macos) = %q, want %q", got, "system:Glass")
+		}
+		if got := eventCfg.SoundFor("linux"); got != "bundled:stop" {
+			t.Errorf("SoundFor(linux) = %q, want %q", got, "bundled:stop")
+		}
+	})
+
+	t.Run("plain profile sound overrides platform sounds", func(t *testing.T) {
+		cfg.ActiveProfile = "plain"
+		eventCfg := cfg.GetEventConfig("stop")
+		if got := eventCfg.SoundFor("macos"); got != "bundled:subagent" {
+			t.Errorf("SoundFor(macos) = %q, want %q", got, "bundled:subagent")
+		}
+	})
+
+	t.Run("unknown platform key rejected", func(t *testing.T) {
+		bad := &Config{Events: map[string]*Event{
+			"stop": {PlatformSounds: map[string]string{"windows": "bundled:stop"}},
+		}}
+		if err := bad.Validate(); err == nil {
+			t.Error("expected validation error for unknown platform")
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrStrict is wrapped by load errors when strict mode is on.
+var ErrStrict = errors.New("strict mode")
+
+// checkStrict turns the problems a normal load tolerates into an error:
+// unknown keys, missing custom sound files and profiles that can never
+// apply.
+func (c *Config) checkStrict() error {
+	var problems []string
+	for _, key := range c.UnknownKeys {
+		problems = append(problems, "unknown key "+key)
+	}
+	problems = append(problems, c.missingSoundFiles()...)
+	problems = append(problems, c.unreachableProfiles...)
+	if c.Profiles[defaultProfileName] != nil {
+		problems = append(problems, `profile "default" is never applied; move its events to "events"`)
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("config check failed:\n  %s", strings.Join(problems, "\n  "))
+}
+
+// missingSoundFiles lists the custom sound files referenced by the config
+// that don't exist.
+func (c *Config) missingSoundFiles() []string {
+	specs := make(map[string]string) // Spec -> first place it is used
+	addSpec := func(where, spec string) {
+		if cur, seen := specs[spec]; spec != "" && (!seen || where < cur) {
+			specs[spec] = where
+		}
+	}
+	addEvent := func(where string, e *Event) {
+		if e == nil {
+			return
+		}
+		events := []*Event{e}
+		if e.ResponseLength != nil {
+			events = append(events, e.ResponseLength.Short, e.ResponseLength.Long)
+		}
+		for _, ev := range events {
+			if ev == nil {
+				continue
+			}
+			addSpec(where, ev.Sound)
+			for _, spec := range ev.PlatformSounds {
+				addSpec(where, spec)
+			}
+			for _, spec := range ev.SessionSounds {
+				addSpec(where, spec)
+			}
+		}
+	}
+
+	for name, e := range c.Events {
+		addEvent("events."+name, e)
+	}
+	for profileName, profile := range c.Profiles {
+		if profile == nil {
+			continue
+		}
+		for name, e := range profile.Events {
+			addEvent("profiles."+profileName+".events."+name, e)
+		}
+	}
+	for i, rule := range c.Branches {
+		if rule == nil {
+			continue
+		}
+		for name, e := range rule.Events {
+			addEvent(fmt.Sprintf("branches[%d].events.%s", i, name), e)
+		}
+	}
+	for themeName, theme := range c.Themes {
+		for name, e := range theme {
+			addEvent("themes."+themeName+"."+name, e)
+		}
+	}
+	if c.WaitSubagents != nil {
+		addSpec("waitForSubagents", c.WaitSubagents.Sound)
+	}
+
+	var missing []string
+	for spec, where := range specs {
+		path, ok := strings.CutPrefix(spec, "custom:")
+		if !ok && !filepath.IsAbs(spec) {
+			continue // bundled:, system:, url: and pack: sounds are resolved at play time
+		}
+		if !ok {
+			path = spec
+		}
+		if _, err := os.Stat(path); err != nil {
+			missing = append(missing, fmt.Sprintf("%s: sound file not found: %s", where, path))
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadFileStrict(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-strict-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldSystem, hadSystem := os.LookupEnv(SystemConfigEnvVar)
+	defer func() {
+		if hadSystem {
+			os.Setenv(SystemConfigEnvVar, oldSystem)
+		} else {
+			os.Unsetenv(SystemConfigEnvVar)
+		}
+	}()
+	os.Setenv(SystemConfigEnvVar, filepath.Join(tmpDir, "missing.json"))
+
+	sound := filepath.Join(tmpDir, "ding.wav")
+	if err := os.WriteFile(sound, []byte("RIFF"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(tmpDir, "gone.wav")
+
+	tests := []struct {
+		name    string
+		config  string
+		profile string // Contents of profiles/work.json
+		wantErr string // Substring of the strict error; empty if valid
+	}{
+		{"clean", `{"enabled": true, "events": {"stop": {"sound": "custom:` + sound + `"}}}`, "", ""},
+		{"unknown key", `{"enabled": true, "quitHours": {}}`, "", "unknown key"},
+		{"missing sound", `{"events": {"stop": {"sound": "custom:` + missing + `"}}}`, "", "events.stop: sound file not found"},
+		{"missing platform sound", `{"events": {"stop": {"sound": {"linux": "` + missing + `"}}}}`, "", "sound file not found"},
+		{"missing response length sound", `{"events": {"stop": {"responseLength": {"longSeconds": 60, "long": {"sound": "custom:` + missing + `"}}}}}`, "", "sound file not found"},
+		{"missing theme sound", `{"themes": {"mine": {"stop": "custom:` + missing + `"}}}`, "", "themes.mine.stop"},
+		{"default profile", `{"profiles": {"default": {"events": {}}}}`, "", `profile "default" is never applied`},
+		{"hidden profile file", `{"profiles": {"work": {}}}`, `{"events": {}}`, "is hidden by profile"},
+		{"invalid config", `{"quietHours": {"start": "25:00"}}`, "", "config validation failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := os.MkdirTemp(tmpDir, "case")
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(dir, "ccbell.config.json")
+			if err := os.WriteFile(path, []byte(tt.config), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if tt.profile != "" {
+				if err := os.MkdirAll(ProfilesDir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(ProfilesDir(path), "work.json"), []byte(tt.profile), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, _, err = LoadFileStrict(path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("LoadFileStrict() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrStrict) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadFileStrict() error = %v, want ErrStrict containing %q", err, tt.wantErr)
+			}
+
+			// Without strict mode only invalid configs fail
+			_, _, err = LoadFile(path)
+			if wantFail := tt.name == "invalid config"; (err != nil) != wantFail || errors.Is(err, ErrStrict) {
+				t.Errorf("LoadFile() error = %v", err)
+			}
+		})
+	}
+
+	// "strict": true in the config enables the checks
+	path := filepath.Join(tmpDir, "strict.json")
+	if err := os.WriteFile(path, []byte(`{"strict": true, "quitHours": {}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := LoadFile(path); !errors.Is(err, ErrStrict) {
+		t.Errorf("LoadFile() with strict config error = %v, want ErrStrict", err)
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// j2000 is the Julian date of 2000-01-01 12:00 UTC, and j2000Unix its Unix
+// time.
+const (
+	j2000     = 2451545.0
+	j2000Unix = 946728000
+)
+
+// sunTimes returns sunrise and sunset on the calendar day of date (in its
+// location) at a latitude and longitude in degrees, east positive. ok is
+// false when the sun doesn't rise or set that day, as in polar summer and
+// winter. Accurate to a minute or two, using the sunrise equation.
+func sunTimes(date time.Time, lat, lon float64) (sunrise, sunset time.Time, ok bool) {
+	y, m, d := date.Date()
+	noon := time.Date(y, m, d, 12, 0, 0, 0, time.UTC)
+	n := math.Round(float64(noon.Unix()-j2000Unix) / 86400)
+
+	meanSolarTime := n - lon/360
+	anomaly := math.Mod(357.5291+0.98560028*meanSolarTime, 360)
+	m1 := radians(anomaly)
+	center := 1.9148*math.Sin(m1) + 0.0200*math.Sin(2*m1) + 0.0003*math.Sin(3*m1)
+	longitude := radians(math.Mod(anomaly+center+180+102.9372, 360))
+	transit := j2000 + meanSolarTime + 0.0053*math.Sin(m1) - 0.0069*math.Sin(2*longitude)
+
+	declination := math.Asin(math.Sin(longitude) * math.Sin(radians(23.4397)))
+	phi := radians(lat)
+	cosHourAngle := (math.Sin(radians(-0.833)) - math.Sin(phi)*math.Sin(declination)) /
+		(math.Cos(phi) * math.Cos(declination))
+	if cosHourAngle < -1 || cosHourAngle > 1 {
+		return time.Time{}, time.Time{}, false
+	}
+	hourAngle := math.Acos(cosHourAngle) * 180 / math.Pi
+
+	sunrise = julianToTime(transit - hourAngle/360).In(date.Location())
+	sunset = julianToTime(transit + hourAngle/360).In(date.Location())
+	return sunrise, sunset, true
+}
+
+// julianToTime converts a Julian date to a time.
+func julianToTime(j float64) time.Time {
+	secs := (j - j2000) * 86400
+	return time.Unix(j2000Unix+int64(math.Round(secs)), 0).UTC()
+}
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
+
+// zoneTabPaths are the time zone tables mapping zone names to coordinates.
+var zoneTabPaths = []string{"/usr/share/zoneinfo/zone1970.tab", "/usr/share/zoneinfo/zone.tab"}
+
+// localCoordinates returns the coordinates of the local time zone's
+// principal city, from $TZ or the /etc/localtime link and the system zone
+// table. Close enough for sunrise and sunset within a few minutes for most
+// of a zone.
+var localCoordinates = sync.OnceValues(func() ([2]float64, bool) {
+	zone := strings.TrimPrefix(os.Getenv("TZ"), ":")
+	if zone == "" {
+		target, err := os.Readlink("/etc/localtime")
+		if err != nil {
+			return [2]float64{}, false
+		}
+		_, zone, _ = strings.Cut(target, "zoneinfo/")
+	}
+	if zone == "" {
+		return [2]float64{}, false
+	}
+	for _, path := range zoneTabPaths {
+		f, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		lat, lon, ok := zoneCoordinates(f, zone)
+		f.Close()
+		if ok {
+			return [2]float64{lat, lon}, true
+		}
+	}
+	return [2]float64{}, false
+})
+
+// zoneCoordinates finds zone in a zone.tab style table.
+func zoneCoordinates(r io.Reader, zone string) (lat, lon float64, ok bool) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 3 || strings.HasPrefix(fields[0], "#") || fields[2] != zone {
+			continue
+		}
+		return parseISO6709(fields[1])
+	}
+	return 0, 0, false
+}
+
+// parseISO6709 parses zone table coordinates: ±DDMM±DDDMM or
+// ±DDMMSS±DDDMMSS.
+func parseISO6709(s string) (lat, lon float64, ok bool) {
+	split := strings.IndexAny(s[min(1, len(s)):], "+-") + 1
+	if split <= 0 {
+		return 0, 0, false
+	}
+	lat, ok1 := parseDegrees(s[:split], 2)
+	lon, ok2 := parseDegrees(s[split:], 3)
+	return lat, lon, ok1 && ok2
+}
+
+// parseDegrees parses a signed ISO 6709 angle with the given number of
+// degree digits.
+func parseDegrees(s string, digits int) (float64, bool) {
+	if len(s) != 1+digits+2 && len(s) != 1+digits+4 {
+		return 0, false
+	}
+	var parts []float64
+	for i := 1; i < len(s); {
+		n := 2
+		if i == 1 {
+			n = digits
+		}
+		v, err := strconv.Atoi(s[i : i+n])
+		if err != nil {
+			return 0, false
+		}
+		parts = append(parts, float64(v))
+		i += n
+	}
+	deg := parts[0] + parts[1]/60
+	if len(parts) == 3 {
+		deg += parts[2] / 3600
+	}
+	if s[0] == '-' {
+		deg = -deg
+	}
+	return deg, true
+}
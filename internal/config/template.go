@@ -0,0 +1,25 @@
+package config
+
+import (
+	"encoding/json"
+	"text/template"
+)
+
+// templateFuncs are the functions available to message templates besides
+// the text/template built-ins.
+var templateFuncs = template.FuncMap{
+	// json encodes a value as JSON, e.g. {"text": {{json .Message}}}
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// ParseTemplate parses a webhook or push message template. Templates use Go
+// text/template syntax with .Event, .Title, .Message, .Project, .Branch,
+// .Hostname, .Icon, .Severity, .Timestamp and the hook payload's fields
+// under .Payload;
+// {{json .Payload.x}} is null for a missing field.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs).Parse(text)
+}
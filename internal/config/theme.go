@@ -0,0 +1,138 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/mpolatcan/ccbell/internal/pack"
+)
+
+// Theme maps event types to sounds. Values are sound specs or per-platform
+// objects, as for an event's "sound".
+type Theme map[string]*Event
+
+// builtinThemes are available without being defined in config.
+var builtinThemes = map[string]Theme{
+	"bundled": {
+		"stop":              {Sound: "bundled:stop"},
+		"permission_prompt": {Sound: "bundled:permission_prompt"},
+		"idle_prompt":       {Sound: "bundled:idle_prompt"},
+		"subagent":          {Sound: "bundled:subagent"},
+	},
+	"system": {
+		"stop":              {PlatformSounds: map[string]string{"macos": "system:Glass", "linux": "system:complete"}},
+		"permission_prompt": {PlatformSounds: map[string]string{"macos": "system:Sosumi", "linux": "system:dialog-warning"}},
+		"idle_prompt":       {PlatformSounds: map[string]string{"macos": "system:Tink", "linux": "system:message"}},
+		"subagent":          {PlatformSounds: map[string]string{"macos": "system:Pop", "linux": "system:bell"}},
+	},
+}
+
+// UnmarshalJSON decodes each event's sound like an event's "sound" field.
+func (t *Theme) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*t = make(Theme, len(raw))
+	for eventType, sound := range raw {
+		event := &Event{}
+		if err := json.Unmarshal([]byte(`{"sound":`+string(sound)+`}`), event); err != nil {
+			return fmt.Errorf("theme sound for %s: %w", eventType, err)
+		}
+		(*t)[eventType] = event
+	}
+	return nil
+}
+
+// MarshalJSON writes each event's sound like an event's "sound" field.
+func (t Theme) MarshalJSON() ([]byte, error) {
+	raw := make(map[string]json.RawMessage, len(t))
+	for eventType, event := range t {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return nil, err
+		}
+		var fields struct {
+			Sound json.RawMessage `json:"sound"`
+		}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+		raw[eventType] = fields.Sound
+	}
+	return json.Marshal(raw)
+}
+
+// GetTheme returns a theme defined in config or built in.
+func (c *Config) GetTheme(name string) (Theme, bool) {
+	if theme, ok := c.Themes[name]; ok {
+		return theme, true
+	}
+	theme, ok := builtinThemes[name]
+	return theme, ok
+}
+
+// ThemeNames returns the names of all available themes, sorted.
+func (c *Config) ThemeNames() []string {
+	names := make([]string, 0, len(builtinThemes)+len(c.Themes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	for name := range c.Themes {
+		if _, builtin := builtinThemes[name]; !builtin {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyTheme replaces an event's default bundled sound with the active
+// theme's sound. Events configured with any other sound keep it.
+func (c *Config) applyTheme(eventType string, event *Event) {
+	if c.Theme == "" || event.Sound != "bundled:"+eventType || event.PlatformSounds != nil {
+		return
+	}
+	theme, ok := c.GetTheme(c.Theme)
+	if !ok {
+		return
+	}
+	if sound, ok := theme[eventType]; ok && sound != nil {
+		event.Sound = sound.Sound
+		event.PlatformSounds = sound.PlatformSounds
+	}
+}
+
+// applyActivePack points an event's default bundled sound at the active
+// pack. Events the pack has no sound for still play the bundled sound, as
+// the fallback when a pack sound can't be resolved.
+func (c *Config) applyActivePack(eventType string, event *Event) {
+	if c.ActivePack == "" || event.Sound != "bundled:"+eventType || event.PlatformSounds != nil {
+		return
+	}
+	event.Sound = pack.SpecPrefix + c.ActivePack
+}
+
+// validateThemes checks theme definitions and the active theme.
+func (c *Config) validateThemes() error {
+	for name, theme := range c.Themes {
+		if !profileNameRegex.MatchString(name) {
+			return fmt.Errorf("invalid theme name: %s", name)
+		}
+		for eventType, sound := range theme {
+			if !ValidEvents[eventType] {
+				return fmt.Errorf("theme %s: %w", name, unknownEventType(eventType))
+			}
+			if err := validatePlatformSounds(sound.PlatformSounds); err != nil {
+				return fmt.Errorf("theme %s, event %s: %w", name, eventType, err)
+			}
+		}
+	}
+	if c.Theme != "" {
+		if _, ok := c.GetTheme(c.Theme); !ok {
+			return fmt.Errorf("unknown theme: %s", c.Theme)
+		}
+	}
+	return nil
+}
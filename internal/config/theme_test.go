@@ -0,0 +1,120 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestThemeJSON(t *testing.T) {
+	var cfg Config
+	data := `{"themes": {"retro": {"stop": "custom:/tmp/stop.wav", "subagent": {"macos": "system:Pop", "default": "bundled:subagent"}}}}`
+	if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	retro := cfg.Themes["retro"]
+	if retro["stop"].Sound != "custom:/tmp/stop.wav" {
+		t.Errorf("stop sound = %q", retro["stop"].Sound)
+	}
+	if retro["subagent"].SoundFor("macos") != "system:Pop" || retro["subagent"].SoundFor("linux") != "bundled:subagent" {
+		t.Errorf("subagent sounds = %+v", retro["subagent"])
+	}
+
+	out, err := json.Marshal(retro)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var again Theme
+	if err := json.Unmarshal(out, &again); err != nil {
+		t.Fatalf("Unmarshal(%s) error = %v", out, err)
+	}
+	if !reflect.DeepEqual(again, retro) {
+		t.Errorf("round trip = %s", out)
+	}
+}
+
+func TestApplyTheme(t *testing.T) {
+	cfg := Default()
+	cfg.Theme = "system"
+	cfg.Events["permission_prompt"].Sound = "custom:/tmp/mine.wav"
+	cfg.Themes = map[string]Theme{"retro": {"stop": {Sound: "bundled:retro"}}}
+
+	if got := cfg.GetEventConfig("stop").SoundFor("macos"); got != "system:Glass" {
+		t.Errorf("stop sound = %q, want system:Glass", got)
+	}
+	if got := cfg.GetEventConfig("stop").SoundFor("linux"); got != "system:complete" {
+		t.Errorf("stop sound = %q, want system:complete", got)
+	}
+	if got := cfg.GetEventConfig("permission_prompt").Sound; got != "custom:/tmp/mine.wav" {
+		t.Errorf("explicit sound replaced by theme: %q", got)
+	}
+
+	cfg.Theme = "retro"
+	if got := cfg.GetEventConfig("stop").Sound; got != "bundled:retro" {
+		t.Errorf("stop sound = %q, want bundled:retro", got)
+	}
+	if got := cfg.GetEventConfig("subagent").Sound; got != "bundled:subagent" {
+		t.Errorf("event missing from theme = %q, want default", got)
+	}
+}
+
+func TestApplyActivePack(t *testing.T) {
+	cfg := Default()
+	cfg.ActivePack = "retro"
+	cfg.Theme = "system"
+	cfg.Events["permission_prompt"].Sound = "custom:/tmp/mine.wav"
+
+	if got := cfg.GetEventConfig("stop").SoundFor("macos"); got != "pack:retro" {
+		t.Errorf("stop sound = %q, want pack:retro ahead of the theme", got)
+	}
+	if got := cfg.GetEventConfig("permission_prompt").Sound; got != "custom:/tmp/mine.wav" {
+		t.Errorf("explicit sound replaced by active pack: %q", got)
+	}
+
+	cfg.Profiles = map[string]*Profile{"work": {Events: map[string]*Event{"stop": {Sound: "system:Ping"}}}}
+	cfg.ActiveProfile = "work"
+	if got := cfg.GetEventConfig("stop").Sound; got != "system:Ping" {
+		t.Errorf("profile sound = %q, want system:Ping", got)
+	}
+
+	cfg.ActivePack = "Bad Pack"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() should reject an invalid activePack")
+	}
+}
+
+func TestValidateThemes(t *testing.T) {
+	tests := []struct {
+		name    string
+		theme   string
+		themes  map[string]Theme
+		wantErr bool
+	}{
+		{"no theme", "", nil, false},
+		{"builtin", "system", nil, false},
+		{"user theme", "retro", map[string]Theme{"retro": {"stop": {Sound: "bundled:stop"}}}, false},
+		{"unknown theme", "nope", nil, true},
+		{"unknown event", "", map[string]Theme{"retro": {"bogus": {Sound: "bundled:stop"}}}, true},
+		{"bad name", "", map[string]Theme{"Bad Name": {}}, true},
+		{"bad platform", "", map[string]Theme{"retro": {"stop": {PlatformSounds: map[string]string{"windows": "x"}}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Theme = tt.theme
+			cfg.Themes = tt.themes
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestThemeNames(t *testing.T) {
+	cfg := Default()
+	cfg.Themes = map[string]Theme{"retro": {}, "system": {}}
+	want := []string{"bundled", "retro", "system"}
+	if got := cfg.ThemeNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ThemeNames() = %v, want %v", got, want)
+	}
+}
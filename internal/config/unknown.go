@@ -0,0 +1,172 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// unmarshalerType is the json.Unmarshaler interface type.
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// addUnknownKeys records the keys of a loaded file that no setting reads.
+func (c *Config) addUnknownKeys(path string, data []byte, t reflect.Type) {
+	keys, err := unknownKeys(data, t)
+	if err != nil {
+		return
+	}
+	for _, key := range keys {
+		c.UnknownKeys = append(c.UnknownKeys, path+": "+key)
+	}
+}
+
+// unknownKeys returns the keys in data that type t doesn't decode, as dotted
+// paths with a suggestion for close matches.
+func unknownKeys(data []byte, t reflect.Type) ([]string, error) {
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	var unknown []string
+	walkUnknown(raw, t, "", &unknown)
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+// walkUnknown compares a decoded JSON value with the Go type it decodes
+// into, collecting object keys that have no matching field. Values whose
+// shape differs from the type (e.g. a per-platform "sound" object) and
+// types with their own non-struct decoding are not inspected.
+func walkUnknown(raw any, t reflect.Type, path string, unknown *[]string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch v := raw.(type) {
+	case map[string]any:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := jsonFields(t)
+			for key, child := range v {
+				field, ok := lookupField(fields, key)
+				if !ok {
+					*unknown = append(*unknown, unknownKeyMessage(joinPath(path, key), key, fields))
+					continue
+				}
+				walkUnknown(child, field, joinPath(path, key), unknown)
+			}
+		case reflect.Map:
+			if reflect.PointerTo(t).Implements(unmarshalerType) {
+				return
+			}
+			for key, child := range v {
+				walkUnknown(child, t.Elem(), joinPath(path, key), unknown)
+			}
+		}
+	case []any:
+		if t.Kind() != reflect.Slice {
+			return
+		}
+		for i, child := range v {
+			walkUnknown(child, t.Elem(), fmt.Sprintf("%s[%d]", path, i), unknown)
+		}
+	}
+}
+
+// jsonFields maps a struct's JSON field names to their types.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// lookupField finds a field by JSON name, case-insensitively like
+// encoding/json.
+func lookupField(fields map[string]reflect.Type, key string) (reflect.Type, bool) {
+	if t, ok := fields[key]; ok {
+		return t, true
+	}
+	for name, t := range fields {
+		if strings.EqualFold(name, key) {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// unknownKeyMessage describes an unknown key, suggesting the closest field.
+func unknownKeyMessage(path, key string, fields map[string]reflect.Type) string {
+	best := closestMatch(key, maps.Keys(fields))
+	if best == "" {
+		return path
+	}
+	return fmt.Sprintf("%s (did you mean %q?)", path, best)
+}
+
+// unknownEventType is the error for an event type that isn't valid,
+// suggesting the closest valid one, or listing them if none is close.
+func unknownEventType(eventType string) error {
+	if best := closestMatch(eventType, maps.Keys(ValidEvents)); best != "" {
+		return fmt.Errorf("unknown event type: %s (did you mean %q?)", eventType, best)
+	}
+	valid := slices.Sorted(maps.Keys(ValidEvents))
+	return fmt.Errorf("unknown event type: %s (valid: %s)", eventType, strings.Join(valid, ", "))
+}
+
+// closestMatch returns the candidate nearest to name, ignoring case, or ""
+// if none is close enough to be a likely typo.
+func closestMatch(name string, candidates iter.Seq[string]) string {
+	best, bestDist := "", 3 // Suggest only close matches
+	for candidate := range candidates {
+		if d := editDistance(strings.ToLower(name), strings.ToLower(candidate)); d < bestDist || (d == bestDist && candidate < best) {
+			best, bestDist = candidate, d
+		}
+	}
+	return best
+}
+
+// joinPath appends a key to a dotted path.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{"known keys", `{"enabled": true, "quietHours": {"start": "22:00", "end": "07:00", "weekend": {"start": "23:00"}}}`, nil},
+		{"misspelled top-level key", `{"quitHours": {}}`, []string{`quitHours (did you mean "quietHours"?)`}},
+		{"unrelated key", `{"zzzzzzzz": 1}`, []string{"zzzzzzzz"}},
+		{"case-insensitive match", `{"QuietHours": {}}`, nil},
+		{"nested event key", `{"events": {"stop": {"volum": 0.5}}}`, []string{`events.stop.volum (did you mean "volume"?)`}},
+		{"profile event key", `{"profiles": {"work": {"events": {"stop": {"cooldwn": 1}}}}}`, []string{`profiles.work.events.stop.cooldwn (did you mean "cooldown"?)`}},
+		{"branch rule key", `{"branches": [{"brnch": "main"}]}`, []string{`branches[0].brnch (did you mean "branch"?)`}},
+		{"activeProfile schedule", `{"activeProfile": {"weekday": "work"}}`, nil},
+		{"per-platform sound", `{"events": {"stop": {"sound": {"macos": "system:Glass"}}}}`, nil},
+		{"theme sounds", `{"themes": {"retro": {"stop": {"linux": "bundled:stop"}}}}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := unknownKeys([]byte(tt.data), reflect.TypeOf(Config{}))
+			if err != nil {
+				t.Fatalf("unknownKeys() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unknownKeys() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnknownEventType(t *testing.T) {
+	tests := []struct {
+		eventType string
+		want      string
+	}{
+		{"stp", `unknown event type: stp (did you mean "stop"?)`},
+		{"subagents", `unknown event type: subagents (did you mean "subagent"?)`},
+		{"idle_promt", `unknown event type: idle_promt (did you mean "idle_prompt"?)`},
+		{"build_done", "unknown event type: build_done (valid: idle_prompt, permission_prompt, stop, subagent)"},
+	}
+	for _, tt := range tests {
+		if err := ValidateEventType(tt.eventType); err == nil || err.Error() != tt.want {
+			t.Errorf("ValidateEventType(%q) error = %v, want %q", tt.eventType, err, tt.want)
+		}
+	}
+
+	cfg := Default()
+	cfg.Events["stpo"] = &Event{}
+	if err := cfg.Validate(); err == nil || err.Error() != `unknown event type: stpo (did you mean "stop"?)` {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestLoadFileUnknownKeys(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-unknown-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldSystem, hadSystem := os.LookupEnv(SystemConfigEnvVar)
+	defer func() {
+		if hadSystem {
+			os.Setenv(SystemConfigEnvVar, oldSystem)
+		} else {
+			os.Unsetenv(SystemConfigEnvVar)
+		}
+	}()
+	os.Setenv(SystemConfigEnvVar, filepath.Join(tmpDir, "missing.json"))
+
+	path := filepath.Join(tmpDir, "ccbell.config.json")
+	if err := os.WriteFile(path, []byte(`{"enabled": true, "debgu": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	profileDir := ProfilesDir(path)
+	if err := os.MkdirAll(profileDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	profilePath := filepath.Join(profileDir, "work.json")
+	if err := os.WriteFile(profilePath, []byte(`{"event": {}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, _, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	want := []string{
+		path + `: debgu (did you mean "debug"?)`,
+		profilePath + `: event (did you mean "events"?)`,
+	}
+	if !reflect.DeepEqual(cfg.UnknownKeys, want) {
+		t.Errorf("UnknownKeys = %q, want %q", cfg.UnknownKeys, want)
+	}
+}
@@ -0,0 +1,239 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/mpolatcan/ccbell/internal/pack"
+)
+
+// updateFile applies update to the config file's raw JSON object and writes
+// it back, keeping fields ccbell doesn't know about and the order of keys.
+// A missing file starts from an empty object. The file is locked while it
+// is rewritten, so concurrent hooks and commands don't lose each other's
+// changes, and replaced atomically, so readers never see a partial file.
+func updateFile(configPath string, update func(raw map[string]any) error) error {
+	unlock, err := lockFile(configPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	raw := make(map[string]any)
+	order := make(keyOrder)
+	data, err := os.ReadFile(configPath)
+	switch {
+	case err == nil:
+		// Numbers keep their text, so large IDs don't lose precision and
+		// 0.50 stays 0.50
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&raw); err != nil {
+			return fmt.Errorf("invalid JSON in config file: %w", err)
+		}
+		if order, err = readKeyOrder(data); err != nil {
+			return fmt.Errorf("invalid JSON in config file: %w", err)
+		}
+	case !os.IsNotExist(err):
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := update(raw); err != nil {
+		return err
+	}
+
+	data, err = order.marshal(raw)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := writeFileAtomic(configPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
+
+// ReplaceFile replaces the config file's contents with data, under the
+// same lock and atomic rename as ccbell's own config changes.
+func ReplaceFile(configPath string, data []byte) error {
+	unlock, err := lockFile(configPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := writeFileAtomic(configPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
+
+// lockFile takes an exclusive lock on a ".lock" file next to path, waiting
+// for other processes holding it, and returns the function releasing it.
+func lockFile(path string) (unlock func(), err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config lock: %w", err)
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock config: %w", err)
+	}
+	return func() {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
+
+// writeFileAtomic writes data to a temporary file in path's directory and
+// renames it over path. An existing file keeps its permissions, and a
+// symlinked one, such as a config kept in a dotfiles repository, stays a
+// symlink.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+	if fi, err := os.Stat(path); err == nil {
+		perm = fi.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op after the rename
+
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// SetEventSound sets an event's sound spec in the config file.
+func SetEventSound(configPath, eventType, soundSpec string) error {
+	return SetEventKey(configPath, eventType, "sound", soundSpec)
+}
+
+// SetEventKey sets a key of an event's base settings in the config file.
+func SetEventKey(configPath, eventType, key string, value any) error {
+	if err := ValidateEventType(eventType); err != nil {
+		return err
+	}
+	return updateFile(configPath, func(raw map[string]any) error {
+		events, _ := raw["events"].(map[string]any)
+		if events == nil {
+			events = make(map[string]any)
+			raw["events"] = events
+		}
+		event, _ := events[eventType].(map[string]any)
+		if event == nil {
+			event = make(map[string]any)
+			events[eventType] = event
+		}
+		event[key] = value
+		return nil
+	})
+}
+
+// SetKey sets a top-level key in the config file.
+func SetKey(configPath, key string, value any) error {
+	return updateFile(configPath, func(raw map[string]any) error {
+		raw[key] = value
+		return nil
+	})
+}
+
+// PackReferences lists the config file's settings that use pack id, such as
+// "events.stop.sound" or "activePack", in order.
+func PackReferences(configPath, id string) ([]string, error) {
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON in config file: %w", err)
+	}
+	var refs []string
+	walkPackReferences(raw, "", id, func(path string) bool {
+		refs = append(refs, path)
+		return false
+	})
+	return refs, nil
+}
+
+// RemovePackReferences removes the config file's settings that use pack id,
+// so they fall back to the default sounds, and returns them.
+func RemovePackReferences(configPath, id string) ([]string, error) {
+	var refs []string
+	err := updateFile(configPath, func(raw map[string]any) error {
+		walkPackReferences(raw, "", id, func(path string) bool {
+			refs = append(refs, path)
+			return true
+		})
+		return nil
+	})
+	return refs, err
+}
+
+// walkPackReferences calls ref with the path of each string in v that is
+// pack id or one of its sounds, in key order, and returns v without the
+// values ref returned true for. removed reports that v itself, or all of a
+// list's items, were removed.
+func walkPackReferences(v any, path, id string, ref func(path string) bool) (result any, removed bool) {
+	switch v := v.(type) {
+	case string:
+		if v == pack.SpecPrefix+id || strings.HasPrefix(v, pack.SpecPrefix+id+":") || (path == "activePack" && v == id) {
+			return v, ref(path)
+		}
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			if item, removed := walkPackReferences(v[key], keyPath, id, ref); removed {
+				delete(v, key)
+			} else {
+				v[key] = item
+			}
+		}
+	case []any:
+		var kept []any
+		for i, item := range v {
+			if item, removed := walkPackReferences(item, fmt.Sprintf("%s[%d]", path, i), id, ref); !removed {
+				kept = append(kept, item)
+			}
+		}
+		if len(kept) == 0 && len(v) > 0 {
+			return nil, true
+		}
+		if len(kept) < len(v) {
+			return kept, false
+		}
+	}
+	return v, false
+}
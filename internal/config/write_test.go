@@ -0,0 +1,268 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSetEventSound(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-write-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	configPath := filepath.Join(tempDir, "ccbell.config.json")
+	initial := `{"enabled": true, "custom": "kept", "events": {"stop": {"sound": {"macos": "system:Glass"}, "volume": 0.3}}}`
+	if err := os.WriteFile(configPath, []byte(initial), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetEventSound(configPath, "stop", "custom:/tmp/stop.wav"); err != nil {
+		t.Fatalf("SetEventSound() error = %v", err)
+	}
+	if err := SetEventSound(configPath, "subagent", "custom:/tmp/sub.wav"); err != nil {
+		t.Fatalf("SetEventSound() error = %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if raw["custom"] != "kept" {
+		t.Error("unknown field was dropped")
+	}
+
+	cfg, _, err := LoadFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	stop := cfg.Events["stop"]
+	if stop.Sound != "custom:/tmp/stop.wav" || stop.PlatformSounds != nil || *stop.Volume != 0.3 {
+		t.Errorf("stop event = %+v", stop)
+	}
+	if cfg.Events["subagent"].Sound != "custom:/tmp/sub.wav" {
+		t.Errorf("subagent sound = %q", cfg.Events["subagent"].Sound)
+	}
+
+	if err := SetEventSound(configPath, "unknown", "bundled:stop"); err == nil {
+		t.Error("expected error for unknown event")
+	}
+}
+
+func TestSetEventKey(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-write-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	configPath := filepath.Join(tempDir, "ccbell.config.json")
+	if err := SetEventKey(configPath, "stop", "enabled", false); err != nil {
+		t.Fatalf("SetEventKey() error = %v", err)
+	}
+	if err := SetEventKey(configPath, "stop", "volume", 0.8); err != nil {
+		t.Fatalf("SetEventKey() error = %v", err)
+	}
+
+	cfg, _, err := LoadFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	stop := cfg.GetEventConfig("stop")
+	if *stop.Enabled || *stop.Volume != 0.8 {
+		t.Errorf("stop event = enabled %v, volume %v", *stop.Enabled, *stop.Volume)
+	}
+}
+
+func TestUpdateFileKeepsKeyOrder(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-write-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	configPath := filepath.Join(tempDir, "ccbell.config.json")
+	initial := `{"events": {"subagent": {"volume": 0.4, "enabled": true}, "stop": {"sound": "bundled:stop"}},
+		"enabled": true, "branches": [{"branch": "main", "events": {}}], "activeProfile": "default"}`
+	if err := os.WriteFile(configPath, []byte(initial), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetEventKey(configPath, "subagent", "cooldown", 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetKey(configPath, "debug", true); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "events": {
+    "subagent": {
+      "volume": 0.4,
+      "enabled": true,
+      "cooldown": 5
+    },
+    "stop": {
+      "sound": "bundled:stop"
+    }
+  },
+  "enabled": true,
+  "branches": [
+    {
+      "branch": "main",
+      "events": {}
+    }
+  ],
+  "activeProfile": "default",
+  "debug": true
+}
+`
+	if string(data) != want {
+		t.Errorf("config =\n%s\nwant\n%s", data, want)
+	}
+	if fi, err := os.Stat(configPath); err != nil || fi.Mode().Perm() != 0600 {
+		t.Errorf("config mode = %v, %v, want 0600 kept", fi.Mode(), err)
+	}
+}
+
+func TestUpdateFileKeepsValues(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-write-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	configPath := filepath.Join(tempDir, "ccbell.config.json")
+	initial := `{"myTool": {"chatId": 12345678901234567890, "ratio": 0.50, "big": 1e3},
+		"notificationTitle": "<b>{project}</b> & {branch}", "events": {"stop": {"volume": 0.70}}}`
+	if err := os.WriteFile(configPath, []byte(initial), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetEventKey(configPath, "stop", "enabled", false); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`"chatId": 12345678901234567890,`,
+		`"ratio": 0.50,`,
+		`"big": 1e3`,
+		`"notificationTitle": "<b>{project}</b> & {branch}",`,
+		`"volume": 0.70,`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("config lost %s:\n%s", want, data)
+		}
+	}
+}
+
+func TestUpdateFileConcurrent(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ccbell-write-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// A symlinked config stays a symlink
+	target := filepath.Join(tempDir, "dotfiles.json")
+	if err := os.WriteFile(target, []byte(`{"enabled": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(tempDir, "ccbell.config.json")
+	if err := os.Symlink(target, configPath); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := SetKey(configPath, fmt.Sprintf("key%d", i), i); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if len(raw) != 21 {
+		t.Errorf("config has %d keys, want 21 (every write kept)", len(raw))
+	}
+	if fi, err := os.Lstat(configPath); err != nil || fi.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("config symlink replaced: %v, %v", fi.Mode(), err)
+	}
+}
+
+func TestPackReferences(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-write-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	configPath := filepath.Join(tmpDir, "config.json")
+	content := `{"activePack": "retro", "custom": {"keep": "pack:retro-two:stop"}, "quietHours": null,
+		"events": {"stop": {"sound": "pack:retro:stop", "sessionSounds": ["pack:retro:a", "bundled:stop"]},
+			"subagent": {"sessionSounds": ["pack:retro:a", "pack:retro:b"]}},
+		"themes": {"mine": {"stop": "pack:retro"}}}`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"activePack", "events.stop.sessionSounds[0]", "events.stop.sound",
+		"events.subagent.sessionSounds[0]", "events.subagent.sessionSounds[1]", "themes.mine.stop"}
+	refs, err := PackReferences(configPath, "retro")
+	if err != nil || !reflect.DeepEqual(refs, want) {
+		t.Errorf("PackReferences() = %v, %v, want %v", refs, err, want)
+	}
+	if refs, err := PackReferences(configPath, "other"); err != nil || refs != nil {
+		t.Errorf("PackReferences(other) = %v, %v", refs, err)
+	}
+
+	refs, err = RemovePackReferences(configPath, "retro")
+	if err != nil || !reflect.DeepEqual(refs, want) {
+		t.Errorf("RemovePackReferences() = %v, %v, want %v", refs, err, want)
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	wantRaw := map[string]any{
+		"custom":     map[string]any{"keep": "pack:retro-two:stop"},
+		"quietHours": nil,
+		"events":     map[string]any{"stop": map[string]any{"sessionSounds": []any{"bundled:stop"}}, "subagent": map[string]any{}},
+		"themes":     map[string]any{"mine": map[string]any{}},
+	}
+	if !reflect.DeepEqual(raw, wantRaw) {
+		t.Errorf("config after removal = %v, want %v", raw, wantRaw)
+	}
+}
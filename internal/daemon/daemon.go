@@ -0,0 +1,346 @@
+// Package daemon implements "ccbell daemon": a long-running process serving
+// a local HTTP API with ccbell's status and controls, for menu-bar and tray
+// companion apps.
+package daemon
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/history"
+	"github.com/mpolatcan/ccbell/internal/state"
+)
+
+const (
+	// DefaultAddr is where the daemon listens unless told otherwise.
+	DefaultAddr = "127.0.0.1:7337"
+	// InfoFileMode is the permission mode for the info file, which holds
+	// the access token.
+	InfoFileMode = 0600
+	// defaultEventLimit is how many events GET /v1/events returns.
+	defaultEventLimit = 20
+	// maxEventLimit caps the limit parameter of GET /v1/events.
+	maxEventLimit = 500
+	// eventWindow is how far back GET /v1/events looks.
+	eventWindow = 7 * 24 * time.Hour
+	// maxBodySize bounds request bodies.
+	maxBodySize = 64 << 10
+)
+
+// Info tells companion apps how to reach a running daemon. It is written to
+// InfoPath while the daemon runs.
+type Info struct {
+	Addr  string `json:"addr"`
+	Token string `json:"token"` // Bearer token for requests that change settings
+	PID   int    `json:"pid"`
+}
+
+// InfoPath returns the daemon info file path for a home directory, or "" if
+// homeDir is empty.
+func InfoPath(homeDir string) string {
+	if homeDir == "" {
+		return ""
+	}
+	return filepath.Join(homeDir, ".claude", "ccbell-daemon.json")
+}
+
+// WriteInfo writes the info file, readable only by the user.
+func WriteInfo(path string, info Info) error {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create info directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), InfoFileMode); err != nil {
+		return fmt.Errorf("failed to write daemon info: %w", err)
+	}
+	return nil
+}
+
+// ReadInfo reads the info file of a running daemon.
+func ReadInfo(path string) (*Info, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var info Info
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("invalid daemon info: %w", err)
+	}
+	return &info, nil
+}
+
+// NewToken returns a random access token.
+func NewToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// CheckLoopback returns an error unless addr is a host:port on the loopback
+// interface, so the API is never reachable from the network.
+func CheckLoopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid listen address %q: %w", addr, err)
+	}
+	if !isLoopbackHost(host) {
+		return fmt.Errorf("listen address %q is not a loopback address", addr)
+	}
+	return nil
+}
+
+// isLoopbackHost reports whether host names the loopback interface.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Options configures a Server.
+type Options struct {
+	ConfigFile string
+	HomeDir    string
+	Token      string // Required as a bearer token on POST requests
+	Version    string
+	Now        func() time.Time // Defaults to time.Now
+
+	// Notify runs an event through the notification pipeline. POST
+	// /v1/notify is unavailable when nil.
+	Notify NotifyFunc
+}
+
+// Server serves the daemon API:
+//
+//	GET  /v1/status  enabled, profile, theme, quiet hours and pause state
+//	POST /v1/mute    mute or unmute ({"muted": bool}); toggles without a body
+//	GET  /v1/events  recent notifications, newest first
+//	                 (?limit=N&event=<type>)
+//	POST /v1/notify  trigger a notification ({"event": "stop", "payload":
+//	                 {...}}), e.g. from test runners and CI watchers
+//
+// Settings are read from the config and state files on every request, so
+// changes made by hooks or other commands show up immediately.
+type Server struct {
+	opts Options
+	mux  *http.ServeMux
+}
+
+// New creates a Server.
+func New(opts Options) *Server {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	s := &Server{opts: opts, mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /v1/status", s.handleStatus)
+	s.mux.HandleFunc("POST /v1/mute", s.handleMute)
+	s.mux.HandleFunc("GET /v1/events", s.handleEvents)
+	if opts.Notify != nil {
+		s.mux.HandleFunc("POST /v1/notify", s.handleNotify)
+	}
+	return s
+}
+
+// ServeHTTP implements http.Handler. Requests must name a loopback host,
+// which stops web pages from reaching the API through DNS rebinding, and
+// POST requests must carry the token.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if !isLoopbackHost(strings.Trim(host, "[]")) {
+		writeError(w, http.StatusForbidden, errors.New("requests must be addressed to localhost"))
+		return
+	}
+	if r.Method == http.MethodPost && !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+	s.mux.ServeHTTP(w, r)
+}
+
+// authorized reports whether a request carries the server's token.
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && s.opts.Token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) == 1
+}
+
+// Status is the response of GET /v1/status.
+type Status struct {
+	Enabled     bool        `json:"enabled"`
+	Profile     string      `json:"profile"`
+	Theme       string      `json:"theme,omitempty"`
+	QuietHours  *QuietHours `json:"quietHours,omitempty"`
+	Paused      bool        `json:"paused"`
+	PausedUntil *time.Time  `json:"pausedUntil,omitempty"`
+	Version     string      `json:"version,omitempty"`
+}
+
+// QuietHours describes today's quiet hours window.
+type QuietHours struct {
+	Start  string `json:"start"`
+	End    string `json:"end"`
+	Active bool   `json:"active"`
+}
+
+// status reads the current status from the config and state files.
+func (s *Server) status() (*Status, error) {
+	cfg, _, err := config.LoadFile(s.opts.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	now := s.opts.Now()
+
+	st := &Status{
+		Enabled: cfg.Enabled,
+		Profile: cfg.ActiveProfile,
+		Theme:   cfg.Theme,
+		Version: s.opts.Version,
+	}
+	if st.Profile == "" {
+		st.Profile = "default"
+	}
+	if qh := cfg.QuietHoursFor(now); qh != nil && qh.Start != "" && qh.End != "" {
+		st.QuietHours = &QuietHours{Start: qh.Start, End: qh.End, Active: cfg.IsInQuietHours()}
+	}
+
+	until, err := state.NewManager(s.opts.HomeDir).PausedUntil()
+	if err != nil {
+		return nil, err
+	}
+	if !until.IsZero() && now.Before(until) {
+		st.Paused = true
+		st.PausedUntil = &until
+	}
+	return st, nil
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	st, err := s.status()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, st)
+}
+
+// muteRequest is the optional body of POST /v1/mute.
+type muteRequest struct {
+	Muted *bool `json:"muted"`
+}
+
+func (s *Server) handleMute(w http.ResponseWriter, r *http.Request) {
+	var req muteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	st, err := s.status()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	muted := st.Enabled // Toggle by default
+	if req.Muted != nil {
+		muted = *req.Muted
+	}
+	if err := config.SetKey(s.opts.ConfigFile, "enabled", !muted); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.handleStatus(w, r)
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	limit := defaultEventLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q", v))
+			return
+		}
+		limit = min(n, maxEventLimit)
+	}
+
+	records := []history.Record{}
+	path := history.Path(s.opts.HomeDir)
+	if _, err := os.Stat(path); err == nil {
+		// Opened per request: bbolt locks the file, and hooks record into it
+		store, err := history.Open(path)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		all, err := store.Records(s.opts.Now().Add(-eventWindow), r.URL.Query().Get("event"))
+		store.Close()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		// Newest first
+		for i := len(all) - 1; i >= 0 && len(records) < limit; i-- {
+			records = append(records, all[i])
+		}
+	}
+	writeJSON(w, http.StatusOK, records)
+}
+
+// notifyRequest is the body of POST /v1/notify. Payload is a hook payload,
+// e.g. {"message": "Tests passed", "cwd": "/path/to/project"}.
+type notifyRequest struct {
+	Event   string          `json:"event"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+func (s *Server) handleNotify(w http.ResponseWriter, r *http.Request) {
+	var req notifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	if err := config.ValidateEventType(req.Event); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	// The request's context ends with the response, which would kill the
+	// player mid-sound; playback is bounded by the player timeout instead.
+	if err := s.opts.Notify(context.WithoutCancel(r.Context()), req.Event, req.Payload); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeJSON writes v as the JSON response body.
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error response: {"error": "..."}.
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,329 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/history"
+	"github.com/mpolatcan/ccbell/internal/state"
+)
+
+// newTestServer returns a server over a temporary home directory with the
+// given config file contents.
+func newTestServer(t *testing.T, configJSON string) (*Server, string) {
+	t.Helper()
+	homeDir, err := os.MkdirTemp("", "ccbell-daemon-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(homeDir) })
+
+	configFile := filepath.Join(homeDir, "ccbell.config.json")
+	if err := os.WriteFile(configFile, []byte(configJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return New(Options{ConfigFile: configFile, HomeDir: homeDir, Token: "secret-token", Version: "1.2.3"}), homeDir
+}
+
+// serve sends a request to the server and returns the recorded response.
+func serve(s *Server, method, target, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "http://127.0.0.1:7337"+target, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestStatus(t *testing.T) {
+	s, homeDir := newTestServer(t, `{"enabled": true, "activeProfile": "work", "profiles": {"work": {}}}`)
+	until := time.Now().Add(time.Hour).Truncate(time.Second)
+	if err := state.NewManager(homeDir).Pause(until); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := serve(s, "GET", "/v1/status", "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /v1/status = %d: %s", rec.Code, rec.Body)
+	}
+	var st Status
+	if err := json.Unmarshal(rec.Body.Bytes(), &st); err != nil {
+		t.Fatal(err)
+	}
+	if !st.Enabled || st.Profile != "work" || st.Version != "1.2.3" {
+		t.Errorf("status = %+v", st)
+	}
+	if !st.Paused || st.PausedUntil == nil || !st.PausedUntil.Equal(until) {
+		t.Errorf("paused = %v until %v, want until %v", st.Paused, st.PausedUntil, until)
+	}
+}
+
+func TestStatusConfigError(t *testing.T) {
+	s, _ := newTestServer(t, `{not json`)
+	rec := serve(s, "GET", "/v1/status", "", "")
+	if rec.Code != http.StatusInternalServerError || !strings.Contains(rec.Body.String(), `"error"`) {
+		t.Errorf("GET /v1/status = %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestMute(t *testing.T) {
+	s, _ := newTestServer(t, `{"enabled": true}`)
+
+	tests := []struct {
+		name        string
+		body        string
+		wantEnabled bool
+	}{
+		{"toggle off", "", false},
+		{"toggle on", "", true},
+		{"explicit mute", `{"muted": true}`, false},
+		{"explicit mute again", `{"muted": true}`, false},
+		{"explicit unmute", `{"muted": false}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, "POST", "/v1/mute", "secret-token", tt.body)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("POST /v1/mute = %d: %s", rec.Code, rec.Body)
+			}
+			var st Status
+			if err := json.Unmarshal(rec.Body.Bytes(), &st); err != nil {
+				t.Fatal(err)
+			}
+			if st.Enabled != tt.wantEnabled {
+				t.Errorf("enabled = %v, want %v", st.Enabled, tt.wantEnabled)
+			}
+			cfg, _, err := config.LoadFile(s.opts.ConfigFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Enabled != tt.wantEnabled {
+				t.Errorf("config enabled = %v, want %v", cfg.Enabled, tt.wantEnabled)
+			}
+		})
+	}
+
+	if rec := serve(s, "POST", "/v1/mute", "secret-token", `{"muted": "yes"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid body = %d, want 400", rec.Code)
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	s, _ := newTestServer(t, `{"enabled": true}`)
+
+	for _, token := range []string{"", "wrong"} {
+		if rec := serve(s, "POST", "/v1/mute", token, ""); rec.Code != http.StatusUnauthorized {
+			t.Errorf("POST with token %q = %d, want 401", token, rec.Code)
+		}
+	}
+	if rec := serve(New(Options{ConfigFile: s.opts.ConfigFile}), "POST", "/v1/mute", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("POST to server without token = %d, want 401", rec.Code)
+	}
+
+	// DNS rebinding: a page on evil.example resolving to 127.0.0.1
+	req := httptest.NewRequest("GET", "http://evil.example:7337/v1/status", nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("non-loopback Host = %d, want 403", rec.Code)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	s, homeDir := newTestServer(t, `{"enabled": true}`)
+
+	// No history database yet
+	rec := serve(s, "GET", "/v1/events", "", "")
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != "[]" {
+		t.Fatalf("GET /v1/events without history = %d: %s", rec.Code, rec.Body)
+	}
+
+	store, err := history.Open(history.Path(homeDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for i, event := range []string{"stop", "subagent", "stop", "permission_prompt"} {
+		r := history.Record{Time: now.Add(time.Duration(i-4) * time.Minute), Event: event, Outcome: history.OutcomeDelivered}
+		if err := store.Add(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store.Close()
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"permission_prompt", "stop", "subagent", "stop"}},
+		{"?limit=2", []string{"permission_prompt", "stop"}},
+		{"?event=stop", []string{"stop", "stop"}},
+	}
+	for _, tt := range tests {
+		rec := serve(s, "GET", "/v1/events"+tt.query, "", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET /v1/events%s = %d: %s", tt.query, rec.Code, rec.Body)
+		}
+		var records []history.Record
+		if err := json.Unmarshal(rec.Body.Bytes(), &records); err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, r := range records {
+			got = append(got, r.Event)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("GET /v1/events%s = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	if rec := serve(s, "GET", "/v1/events?limit=0", "", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("limit=0 = %d, want 400", rec.Code)
+	}
+}
+
+func TestCheckLoopback(t *testing.T) {
+	tests := []struct {
+		addr    string
+		wantErr bool
+	}{
+		{"127.0.0.1:7337", false},
+		{"localhost:0", false},
+		{"[::1]:7337", false},
+		{"0.0.0.0:7337", true},
+		{":7337", true},
+		{"192.168.1.5:7337", true},
+		{"127.0.0.1", true},
+	}
+	for _, tt := range tests {
+		if err := CheckLoopback(tt.addr); (err != nil) != tt.wantErr {
+			t.Errorf("CheckLoopback(%q) error = %v, wantErr %v", tt.addr, err, tt.wantErr)
+		}
+	}
+}
+
+func TestInfo(t *testing.T) {
+	if InfoPath("") != "" {
+		t.Error("InfoPath(\"\") should be empty")
+	}
+	dir, err := os.MkdirTemp("", "ccbell-daemon-info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := InfoPath(dir)
+	want := Info{Addr: "127.0.0.1:7337", Token: "abc", PID: 42}
+	if err := WriteInfo(path, want); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != InfoFileMode {
+		t.Errorf("info file mode = %v, want %v", fi.Mode().Perm(), os.FileMode(InfoFileMode))
+	}
+	got, err := ReadInfo(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != want {
+		t.Errorf("ReadInfo() = %+v, want %+v", *got, want)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	s, _ := newTestServer(t, `{"enabled": true}`)
+	if rec := serve(s, "POST", "/v1/notify", "secret-token", `{"event": "stop"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("POST /v1/notify without Notify = %d, want 404", rec.Code)
+	}
+
+	var gotEvent, gotPayload string
+	var notifyErr error
+	s = New(Options{
+		ConfigFile: s.opts.ConfigFile,
+		Token:      "secret-token",
+		Notify: func(ctx context.Context, event string, payload []byte) error {
+			gotEvent, gotPayload = event, string(payload)
+			return notifyErr
+		},
+	})
+
+	rec := serve(s, "POST", "/v1/notify", "secret-token", `{"event": "stop", "payload": {"message": "Tests passed"}}`)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("POST /v1/notify = %d: %s", rec.Code, rec.Body)
+	}
+	if gotEvent != "stop" || gotPayload != `{"message": "Tests passed"}` {
+		t.Errorf("Notify(%q, %s)", gotEvent, gotPayload)
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		body     string
+		err      error
+		wantCode int
+	}{
+		{"no token", "", `{"event": "stop"}`, nil, http.StatusUnauthorized},
+		{"unknown event", "secret-token", `{"event": "build_done"}`, nil, http.StatusBadRequest},
+		{"invalid body", "secret-token", `{"event":`, nil, http.StatusBadRequest},
+		{"pipeline error", "secret-token", `{"event": "stop"}`, errors.New("boom"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifyErr = tt.err
+			if rec := serve(s, "POST", "/v1/notify", tt.token, tt.body); rec.Code != tt.wantCode {
+				t.Errorf("POST /v1/notify = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+		})
+	}
+}
+
+func TestNotifyOutlivesResponse(t *testing.T) {
+	played := make(chan error, 1)
+	s := New(Options{
+		Token: "secret-token",
+		Notify: func(ctx context.Context, event string, payload []byte) error {
+			// Like a player started with exec.CommandContext
+			go func() {
+				select {
+				case <-ctx.Done():
+					played <- ctx.Err()
+				case <-time.After(200 * time.Millisecond):
+					played <- nil
+				}
+			}()
+			return nil
+		},
+	})
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	req, err := http.NewRequest("POST", srv.URL+"/v1/notify", strings.NewReader(`{"event": "stop"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer secret-token")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("POST /v1/notify = %d", resp.StatusCode)
+	}
+	if err := <-played; err != nil {
+		t.Errorf("playback was stopped after the response: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package daemon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+)
+
+// FIFOMode is the permission mode for the named pipe: only the user can
+// trigger notifications through it.
+const FIFOMode = 0600
+
+// FIFOPath returns the named pipe path for a home directory, or "" if
+// homeDir is empty.
+func FIFOPath(homeDir string) string {
+	if homeDir == "" {
+		return ""
+	}
+	return filepath.Join(homeDir, ".claude", "ccbell.fifo")
+}
+
+// NotifyFunc runs an event through the notification pipeline.
+type NotifyFunc func(ctx context.Context, event string, payload []byte) error
+
+// ServeFIFO creates a named pipe at path and triggers a notification for
+// every line written to it until ctx is canceled, then removes the pipe.
+// A line is an event name ("echo stop > ~/.claude/ccbell.fifo") or a JSON
+// object like the POST /v1/notify body. Problems are reported to errlog.
+func ServeFIFO(ctx context.Context, path string, notify NotifyFunc, errlog io.Writer) error {
+	if err := makeFIFO(path); err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	// Opened for writing too, so the pipe never reports EOF between writers
+	// and the open doesn't block until the first one
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open FIFO: %w", err)
+	}
+	go func() {
+		<-ctx.Done()
+		f.Close() // Unblocks the read below
+	}()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 4096), maxBodySize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		req, err := parseFIFOLine(line)
+		if err == nil {
+			err = notify(ctx, req.Event, req.Payload)
+		}
+		if err != nil {
+			fmt.Fprintf(errlog, "ccbell: fifo: %v\n", err)
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return scanner.Err()
+}
+
+// makeFIFO creates the named pipe, reusing one left behind by an earlier
+// daemon.
+func makeFIFO(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create FIFO directory: %w", err)
+	}
+	fi, err := os.Lstat(path)
+	switch {
+	case err == nil && fi.Mode()&os.ModeNamedPipe != 0:
+		return os.Chmod(path, FIFOMode)
+	case err == nil:
+		return fmt.Errorf("%s exists and is not a FIFO", path)
+	case !os.IsNotExist(err):
+		return err
+	}
+	if err := syscall.Mkfifo(path, FIFOMode); err != nil {
+		return fmt.Errorf("failed to create FIFO: %w", err)
+	}
+	return nil
+}
+
+// parseFIFOLine decodes a line written to the pipe.
+func parseFIFOLine(line []byte) (notifyRequest, error) {
+	var req notifyRequest
+	if line[0] == '{' {
+		if err := json.Unmarshal(line, &req); err != nil {
+			return req, fmt.Errorf("invalid JSON: %w", err)
+		}
+	} else {
+		req.Event = string(line)
+	}
+	return req, config.ValidateEventType(req.Event)
+}
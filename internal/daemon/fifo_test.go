@@ -0,0 +1,118 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServeFIFO(t *testing.T) {
+	dir, err := os.MkdirTemp("", "ccbell-fifo-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := FIFOPath(dir)
+
+	type call struct{ event, payload string }
+	calls := make(chan call, 10)
+	notify := func(ctx context.Context, event string, payload []byte) error {
+		calls <- call{event, string(payload)}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	var errlog bytes.Buffer
+	go func() { done <- ServeFIFO(ctx, path, notify, &errlog) }()
+
+	// Wait for the pipe
+	for i := 0; i < 100; i++ {
+		if fi, err := os.Lstat(path); err == nil && fi.Mode()&os.ModeNamedPipe != 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Writers come and go, like separate echo commands
+	for _, line := range []string{"stop", "build_done", `{"event": "subagent", "payload": {"message": "hi"}}`} {
+		w, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.WriteString(line + "\n"); err != nil {
+			t.Fatal(err)
+		}
+		w.Close()
+	}
+
+	want := []call{{"stop", ""}, {"subagent", `{"message": "hi"}`}}
+	for _, w := range want {
+		select {
+		case got := <-calls:
+			if got != w {
+				t.Errorf("notify(%q, %q), want (%q, %q)", got.event, got.payload, w.event, w.payload)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s", w.event)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("ServeFIFO() error = %v", err)
+	}
+	if !strings.Contains(errlog.String(), "unknown event type: build_done") {
+		t.Errorf("errlog = %q, want the unknown event reported", errlog.String())
+	}
+	if _, err := os.Lstat(path); !os.IsNotExist(err) {
+		t.Error("FIFO should be removed on exit")
+	}
+}
+
+func TestServeFIFONotAPipe(t *testing.T) {
+	dir, err := os.MkdirTemp("", "ccbell-fifo-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "ccbell.fifo")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ServeFIFO(context.Background(), path, nil, &bytes.Buffer{}); err == nil {
+		t.Error("ServeFIFO() over a regular file should fail")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Error("a regular file in the way should be left alone")
+	}
+}
+
+func TestParseFIFOLine(t *testing.T) {
+	tests := []struct {
+		line      string
+		wantEvent string
+		wantErr   bool
+	}{
+		{"stop", "stop", false},
+		{`{"event": "idle_prompt"}`, "idle_prompt", false},
+		{"Stop", "", true},
+		{`{"event":`, "", true},
+		{`{"payload": {}}`, "", true},
+	}
+	for _, tt := range tests {
+		req, err := parseFIFOLine([]byte(tt.line))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseFIFOLine(%q) error = %v, wantErr %v", tt.line, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && req.Event != tt.wantEvent {
+			t.Errorf("parseFIFOLine(%q) event = %q, want %q", tt.line, req.Event, tt.wantEvent)
+		}
+	}
+}
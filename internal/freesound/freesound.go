@@ -0,0 +1,169 @@
+// Package freesound searches and fetches sounds from the Freesound API
+// (https://freesound.org/docs/api/).
+package freesound
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// DefaultBaseURL is the Freesound API root.
+const DefaultBaseURL = "https://freesound.org/apiv2"
+
+// MaxDuration is the longest sound returned by Search, in seconds;
+// notification sounds should be short.
+const MaxDuration = 10
+
+// maxPreviewSize caps preview downloads.
+const maxPreviewSize = 10 * 1024 * 1024
+
+// previewKey is the preview used for playback and downloads. Originals need
+// OAuth2, previews only the API token.
+const previewKey = "preview-hq-mp3"
+
+// fields are the sound fields requested from the API.
+const fields = "id,name,username,license,duration,previews"
+
+// Sound is a Freesound search result.
+type Sound struct {
+	ID       int               `json:"id"`
+	Name     string            `json:"name"`
+	Username string            `json:"username"`
+	License  string            `json:"license"`
+	Duration float64           `json:"duration"`
+	Previews map[string]string `json:"previews"`
+}
+
+// PreviewURL returns the high-quality MP3 preview URL.
+func (s *Sound) PreviewURL() string {
+	return s.Previews[previewKey]
+}
+
+// Client calls the Freesound API.
+type Client struct {
+	token   string
+	baseURL string
+	http    *http.Client
+}
+
+// NewClient creates a client authenticating with an API token.
+func NewClient(token string) *Client {
+	return &Client{
+		token:   token,
+		baseURL: DefaultBaseURL,
+		http:    &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// SetBaseURL overrides the API root (for tests or mirrors).
+func (c *Client) SetBaseURL(baseURL string) {
+	c.baseURL = baseURL
+}
+
+// Search returns up to limit short sounds matching query.
+func (c *Client) Search(ctx context.Context, query string, limit int) ([]Sound, error) {
+	params := url.Values{
+		"query":     {query},
+		"fields":    {fields},
+		"filter":    {fmt.Sprintf("duration:[0 TO %d]", MaxDuration)},
+		"page_size": {strconv.Itoa(limit)},
+	}
+	var result struct {
+		Results []Sound `json:"results"`
+	}
+	if err := c.get(ctx, "/search/text/", params, &result); err != nil {
+		return nil, err
+	}
+	return result.Results, nil
+}
+
+// Sound fetches a sound by ID.
+func (c *Client) Sound(ctx context.Context, id int) (*Sound, error) {
+	var sound Sound
+	if err := c.get(ctx, fmt.Sprintf("/sounds/%d/", id), url.Values{"fields": {fields}}, &sound); err != nil {
+		return nil, err
+	}
+	return &sound, nil
+}
+
+// DownloadPreview saves a sound's MP3 preview to dest.
+func (c *Client) DownloadPreview(ctx context.Context, sound *Sound, dest string) error {
+	previewURL := sound.PreviewURL()
+	if previewURL == "" {
+		return fmt.Errorf("sound %d has no preview", sound.ID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, previewURL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid preview url: %w", err)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download preview: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download preview: status %d", resp.StatusCode)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".freesound-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, io.LimitReader(resp.Body, maxPreviewSize+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to download preview: %w", err)
+	}
+	if n > maxPreviewSize {
+		return errors.New("preview is too large")
+	}
+	return os.Rename(tmp.Name(), dest)
+}
+
+// get calls an API endpoint and decodes the JSON response into v.
+func (c *Client) get(ctx context.Context, endpoint string, params url.Values, v any) error {
+	if c.token == "" {
+		return errors.New("freesound token not configured (set freesound.token)")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("invalid freesound request: %w", err)
+	}
+	req.Header.Set("Authorization", "Token "+c.token)
+	req.Header.Set("User-Agent", "ccbell")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("freesound request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body := io.LimitReader(resp.Body, 1024*1024)
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Detail string `json:"detail"`
+		}
+		_ = json.NewDecoder(body).Decode(&apiErr)
+		return fmt.Errorf("freesound returned status %d: %s", resp.StatusCode, apiErr.Detail)
+	}
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		return fmt.Errorf("invalid freesound response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package freesound
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTestServer fakes the Freesound API.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/preview.mp3" && r.Header.Get("Authorization") != "Token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"detail": "Invalid token"}`)
+			return
+		}
+		sound := fmt.Sprintf(`{"id": 42, "name": "ding", "username": "bob", "license": "CC0", "duration": 1.5,
+			"previews": {"preview-hq-mp3": "%s/preview.mp3"}}`, server.URL)
+		switch r.URL.Path {
+		case "/search/text/":
+			if r.URL.Query().Get("query") != "bell" || r.URL.Query().Get("page_size") != "5" {
+				t.Errorf("unexpected query: %s", r.URL.RawQuery)
+			}
+			fmt.Fprintf(w, `{"results": [%s]}`, sound)
+		case "/sounds/42/":
+			fmt.Fprint(w, sound)
+		case "/preview.mp3":
+			fmt.Fprint(w, "ID3 preview")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"detail": "Not found"}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSearch(t *testing.T) {
+	server := newTestServer(t)
+	client := NewClient("secret")
+	client.SetBaseURL(server.URL)
+
+	sounds, err := client.Search(context.Background(), "bell", 5)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(sounds) != 1 || sounds[0].ID != 42 || sounds[0].Name != "ding" {
+		t.Errorf("Search() = %+v", sounds)
+	}
+	if sounds[0].PreviewURL() != server.URL+"/preview.mp3" {
+		t.Errorf("PreviewURL() = %q", sounds[0].PreviewURL())
+	}
+}
+
+func TestSoundAndDownloadPreview(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-freesound-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	server := newTestServer(t)
+	client := NewClient("secret")
+	client.SetBaseURL(server.URL)
+
+	sound, err := client.Sound(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("Sound() error = %v", err)
+	}
+	dest := filepath.Join(tmpDir, "sounds", "freesound-42.mp3")
+	if err := client.DownloadPreview(context.Background(), sound, dest); err != nil {
+		t.Fatalf("DownloadPreview() error = %v", err)
+	}
+	if data, err := os.ReadFile(dest); err != nil || string(data) != "ID3 preview" {
+		t.Errorf("preview = %q, %v", data, err)
+	}
+
+	if _, err := client.Sound(context.Background(), 7); err == nil {
+		t.Error("expected error for a missing sound")
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	server := newTestServer(t)
+
+	noToken := NewClient("")
+	noToken.SetBaseURL(server.URL)
+	if _, err := noToken.Search(context.Background(), "bell", 5); err == nil {
+		t.Error("expected error without a token")
+	}
+
+	badToken := NewClient("wrong")
+	badToken.SetBaseURL(server.URL)
+	if _, err := badToken.Search(context.Background(), "bell", 5); err == nil {
+		t.Error("expected error for an invalid token")
+	}
+
+	if err := badToken.DownloadPreview(context.Background(), &Sound{ID: 1}, "/tmp/x.mp3"); err == nil {
+		t.Error("expected error for a sound without preview")
+	}
+}
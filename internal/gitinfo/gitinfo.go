@@ -0,0 +1,85 @@
+// Package gitinfo detects the git branch and worktree of a directory by
+// reading the repository metadata directly, without running git.
+package gitinfo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotRepo is returned when a directory is not inside a git worktree.
+var ErrNotRepo = errors.New("not a git repository")
+
+// Info describes the git checkout containing a directory.
+type Info struct {
+	Worktree string // Top-level directory of the worktree
+	Branch   string // Checked-out branch; empty for a detached HEAD
+}
+
+// Detect returns the git checkout containing dir.
+func Detect(dir string) (Info, error) {
+	if dir == "" {
+		return Info{}, ErrNotRepo
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return Info{}, err
+	}
+
+	for {
+		gitPath := filepath.Join(dir, ".git")
+		if fi, err := os.Stat(gitPath); err == nil {
+			gitDir := gitPath
+			if !fi.IsDir() {
+				// Linked worktrees and submodules use a "gitdir: <path>" file
+				if gitDir, err = readGitFile(gitPath); err != nil {
+					return Info{}, err
+				}
+			}
+			branch, err := readBranch(gitDir)
+			if err != nil {
+				return Info{}, err
+			}
+			return Info{Worktree: dir, Branch: branch}, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return Info{}, ErrNotRepo
+		}
+		dir = parent
+	}
+}
+
+// readGitFile resolves the git directory a .git file points to.
+func readGitFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", fmt.Errorf("invalid .git file: %s", path)
+	}
+	target = strings.TrimSpace(target)
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(path), target)
+	}
+	return target, nil
+}
+
+// readBranch returns the branch HEAD points to, or "" when detached.
+func readBranch(gitDir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read HEAD: %w", err)
+	}
+	ref, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "ref:")
+	if !ok {
+		return "", nil
+	}
+	return strings.TrimPrefix(strings.TrimSpace(ref), "refs/heads/"), nil
+}
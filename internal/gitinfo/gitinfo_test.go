@@ -0,0 +1,73 @@
+package gitinfo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-gitinfo-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Main checkout on release/1.2
+	repo := filepath.Join(tmpDir, "repo")
+	gitDir := filepath.Join(repo, ".git")
+	sub := filepath.Join(repo, "cmd", "app")
+	for _, dir := range []string{gitDir, sub} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, filepath.Join(gitDir, "HEAD"), "ref: refs/heads/release/1.2\n")
+
+	// Linked worktree on a detached HEAD
+	wtGitDir := filepath.Join(gitDir, "worktrees", "hotfix")
+	worktree := filepath.Join(tmpDir, "hotfix")
+	for _, dir := range []string{wtGitDir, worktree} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, filepath.Join(wtGitDir, "HEAD"), "0123456789abcdef0123456789abcdef01234567\n")
+	writeFile(t, filepath.Join(worktree, ".git"), "gitdir: "+wtGitDir+"\n")
+
+	tests := []struct {
+		name string
+		dir  string
+		want Info
+	}{
+		{"repo root", repo, Info{Worktree: repo, Branch: "release/1.2"}},
+		{"subdirectory", sub, Info{Worktree: repo, Branch: "release/1.2"}},
+		{"detached worktree", worktree, Info{Worktree: worktree}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Detect(tt.dir)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Detect() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Detect(tmpDir); !errors.Is(err, ErrNotRepo) {
+		t.Errorf("Detect(outside) error = %v, want ErrNotRepo", err)
+	}
+	if _, err := Detect(""); !errors.Is(err, ErrNotRepo) {
+		t.Errorf("Detect(\"\") error = %v, want ErrNotRepo", err)
+	}
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
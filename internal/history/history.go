@@ -0,0 +1,280 @@
+// Package history stores delivered notifications in a bbolt database,
+// indexed by day and event, for stats queries and pruning.
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// FileMode is the permission mode for the database file.
+	FileMode = 0600
+	// DefaultRetentionDays is how long records are kept.
+	DefaultRetentionDays = 90
+	// dayFormat keys records by local day.
+	dayFormat = "2006-01-02"
+	// openTimeout bounds waiting for another process holding the database.
+	openTimeout = time.Second
+)
+
+// Outcomes of a notification.
+const (
+	OutcomeDelivered = "delivered"
+	OutcomeFailed    = "failed"
+)
+
+// Bucket layout:
+//
+//	days/<day>/<seq>             -> Record JSON
+//	events/<event>/<day><seq>    -> (empty, index by event)
+//	counts/<day>\x00<event>\x00<outcome> -> uint64 count
+var (
+	daysBucket   = []byte("days")
+	eventsBucket = []byte("events")
+	countsBucket = []byte("counts")
+)
+
+// Record is one notification.
+type Record struct {
+	Time      time.Time `json:"time"`
+	Event     string    `json:"event"`
+	Outcome   string    `json:"outcome"`
+	SessionID string    `json:"sessionId,omitempty"`
+}
+
+// Count is the number of notifications for an event and outcome on a day.
+type Count struct {
+	Day     string
+	Event   string
+	Outcome string
+	Count   uint64
+}
+
+// Store is an open history database.
+type Store struct {
+	db *bolt.DB
+}
+
+// Path returns the history database path for a home directory.
+func Path(homeDir string) string {
+	return filepath.Join(homeDir, ".claude", "ccbell", "history.db")
+}
+
+// Open opens or creates the database at path.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+	db, err := bolt.Open(path, FileMode, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{daysBucket, eventsBucket, countsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize history: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Add records a notification.
+func (s *Store) Add(r Record) error {
+	if r.Event == "" || r.Outcome == "" {
+		return errors.New("history record needs an event and outcome")
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	day := []byte(r.Time.Local().Format(dayFormat))
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		dayBucket, err := tx.Bucket(daysBucket).CreateBucketIfNotExists(day)
+		if err != nil {
+			return err
+		}
+		seq, err := dayBucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		id := itob(seq)
+		if err := dayBucket.Put(id, data); err != nil {
+			return err
+		}
+
+		eventBucket, err := tx.Bucket(eventsBucket).CreateBucketIfNotExists([]byte(r.Event))
+		if err != nil {
+			return err
+		}
+		if err := eventBucket.Put(append(append([]byte{}, day...), id...), nil); err != nil {
+			return err
+		}
+
+		counts := tx.Bucket(countsBucket)
+		key := countKey(string(day), r.Event, r.Outcome)
+		var n uint64
+		if v := counts.Get(key); len(v) == 8 {
+			n = binary.BigEndian.Uint64(v)
+		}
+		return counts.Put(key, itob(n+1))
+	})
+}
+
+// Counts returns per-day counts since the given time, oldest first. An
+// empty event matches all events.
+func (s *Store) Counts(since time.Time, event string) ([]Count, error) {
+	var result []Count
+	from := []byte(since.Local().Format(dayFormat))
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(countsBucket).Cursor()
+		for k, v := c.Seek(from); k != nil; k, v = c.Next() {
+			day, ev, outcome, ok := splitCountKey(k)
+			if !ok || (event != "" && ev != event) {
+				continue
+			}
+			result = append(result, Count{Day: day, Event: ev, Outcome: outcome, Count: binary.BigEndian.Uint64(v)})
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Records returns notifications since the given time, oldest first. An
+// empty event matches all events; a specific event uses the event index.
+func (s *Store) Records(since time.Time, event string) ([]Record, error) {
+	var result []Record
+	from := []byte(since.Local().Format(dayFormat))
+	add := func(data []byte) error {
+		var r Record
+		if err := json.Unmarshal(data, &r); err != nil {
+			return err
+		}
+		if !r.Time.Before(since) {
+			result = append(result, r)
+		}
+		return nil
+	}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		days := tx.Bucket(daysBucket)
+		if event != "" {
+			index := tx.Bucket(eventsBucket).Bucket([]byte(event))
+			if index == nil {
+				return nil
+			}
+			c := index.Cursor()
+			for k, _ := c.Seek(from); k != nil; k, _ = c.Next() {
+				day, id := k[:len(dayFormat)], k[len(dayFormat):]
+				if dayBucket := days.Bucket(day); dayBucket != nil {
+					if data := dayBucket.Get(id); data != nil {
+						if err := add(data); err != nil {
+							return err
+						}
+					}
+				}
+			}
+			return nil
+		}
+
+		c := days.Cursor()
+		for day, _ := c.Seek(from); day != nil; day, _ = c.Next() {
+			if err := days.Bucket(day).ForEach(func(_, data []byte) error { return add(data) }); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Time.Before(result[j].Time) })
+	return result, err
+}
+
+// Prune removes records from days before the given time and returns how
+// many were removed.
+func (s *Store) Prune(before time.Time) (int, error) {
+	cutoff := before.Local().Format(dayFormat)
+	removed := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		days := tx.Bucket(daysBucket)
+		var stale [][]byte
+		c := days.Cursor()
+		for day, _ := c.First(); day != nil && string(day) < cutoff; day, _ = c.Next() {
+			stale = append(stale, append([]byte{}, day...))
+		}
+		for _, day := range stale {
+			removed += days.Bucket(day).Stats().KeyN
+			if err := days.DeleteBucket(day); err != nil {
+				return err
+			}
+		}
+
+		// Index entries and counters are keyed by day first
+		if err := deleteBefore(tx.Bucket(countsBucket), cutoff); err != nil {
+			return err
+		}
+		return tx.Bucket(eventsBucket).ForEachBucket(func(event []byte) error {
+			return deleteBefore(tx.Bucket(eventsBucket).Bucket(event), cutoff)
+		})
+	})
+	return removed, err
+}
+
+// deleteBefore removes the keys of b that sort before prefix.
+func deleteBefore(b *bolt.Bucket, prefix string) error {
+	c := b.Cursor()
+	for k, _ := c.First(); k != nil && string(k) < prefix; k, _ = c.First() {
+		if err := c.Delete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// countKey builds a counts bucket key.
+func countKey(day, event, outcome string) []byte {
+	return []byte(day + "\x00" + event + "\x00" + outcome)
+}
+
+// splitCountKey parses a counts bucket key.
+func splitCountKey(k []byte) (day, event, outcome string, ok bool) {
+	parts := make([]string, 0, 3)
+	start := 0
+	for i, b := range k {
+		if b == 0 {
+			parts = append(parts, string(k[start:i]))
+			start = i + 1
+		}
+	}
+	parts = append(parts, string(k[start:]))
+	if len(parts) != 3 {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], true
+}
+
+// itob encodes v as an 8-byte big-endian key, so keys sort numerically.
+func itob(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
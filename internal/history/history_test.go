@@ -0,0 +1,123 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// openTestStore opens a store in a temp directory.
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	tmpDir, err := os.MkdirTemp("", "ccbell-history-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+
+	s, err := Open(filepath.Join(tmpDir, "ccbell", "history.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestPath(t *testing.T) {
+	if got, want := Path("/home/u"), "/home/u/.claude/ccbell/history.db"; got != want {
+		t.Errorf("Path() = %q, want %q", got, want)
+	}
+}
+
+func TestStoreCountsAndRecords(t *testing.T) {
+	s := openTestStore(t)
+	day1 := time.Date(2025, 3, 9, 10, 0, 0, 0, time.Local)
+	day2 := time.Date(2025, 3, 10, 10, 0, 0, 0, time.Local)
+	records := []Record{
+		{Time: day1, Event: "stop", Outcome: OutcomeDelivered},
+		{Time: day2, Event: "stop", Outcome: OutcomeDelivered},
+		{Time: day2.Add(time.Minute), Event: "stop", Outcome: OutcomeFailed},
+		{Time: day2.Add(2 * time.Minute), Event: "stop", Outcome: OutcomeDelivered, SessionID: "s1"},
+		{Time: day2.Add(3 * time.Minute), Event: "subagent", Outcome: OutcomeDelivered},
+	}
+	for _, r := range records {
+		if err := s.Add(r); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	if err := s.Add(Record{Time: day2}); err == nil {
+		t.Error("Add() without event should fail")
+	}
+
+	counts, err := s.Counts(day2, "")
+	if err != nil {
+		t.Fatalf("Counts() error = %v", err)
+	}
+	want := []Count{
+		{Day: "2025-03-10", Event: "stop", Outcome: OutcomeDelivered, Count: 2},
+		{Day: "2025-03-10", Event: "stop", Outcome: OutcomeFailed, Count: 1},
+		{Day: "2025-03-10", Event: "subagent", Outcome: OutcomeDelivered, Count: 1},
+	}
+	if len(counts) != len(want) {
+		t.Fatalf("Counts() = %+v, want %+v", counts, want)
+	}
+	for i := range want {
+		if counts[i] != want[i] {
+			t.Errorf("Counts()[%d] = %+v, want %+v", i, counts[i], want[i])
+		}
+	}
+
+	stops, err := s.Records(day1, "stop")
+	if err != nil {
+		t.Fatalf("Records() error = %v", err)
+	}
+	if len(stops) != 4 || !stops[0].Time.Equal(day1) || stops[3].SessionID != "s1" {
+		t.Errorf("Records(stop) = %+v", stops)
+	}
+	all, err := s.Records(day2.Add(time.Minute), "")
+	if err != nil {
+		t.Fatalf("Records() error = %v", err)
+	}
+	if len(all) != 3 || all[2].Event != "subagent" {
+		t.Errorf("Records(all) = %+v", all)
+	}
+}
+
+func TestStorePrune(t *testing.T) {
+	s := openTestStore(t)
+	old := time.Date(2025, 1, 1, 10, 0, 0, 0, time.Local)
+	recent := time.Date(2025, 3, 10, 10, 0, 0, 0, time.Local)
+	for _, r := range []Record{
+		{Time: old, Event: "stop", Outcome: OutcomeDelivered},
+		{Time: old, Event: "subagent", Outcome: OutcomeFailed},
+		{Time: recent, Event: "stop", Outcome: OutcomeDelivered},
+	} {
+		if err := s.Add(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := s.Prune(recent.AddDate(0, 0, -30))
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Prune() removed %d, want 2", removed)
+	}
+
+	counts, err := s.Counts(old, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(counts) != 1 || counts[0].Day != "2025-03-10" {
+		t.Errorf("Counts() after prune = %+v", counts)
+	}
+	records, err := s.Records(old, "subagent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 0 {
+		t.Errorf("event index not pruned: %+v", records)
+	}
+}
@@ -0,0 +1,111 @@
+// Package hook reads and interprets the JSON payload Claude Code sends to
+// hook commands on stdin.
+package hook
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// MaxPayloadSize caps how much of stdin is read as the payload.
+const MaxPayloadSize = 1 << 20
+
+// DefaultReadTimeout bounds how long ccbell waits for the payload.
+const DefaultReadTimeout = 500 * time.Millisecond
+
+// ErrReadTimeout is returned when stdin doesn't close within the timeout.
+var ErrReadTimeout = errors.New("timed out reading hook payload")
+
+// Payload holds the hook payload fields ccbell uses.
+type Payload struct {
+	SessionID      string `json:"session_id,omitempty"`
+	TranscriptPath string `json:"transcript_path,omitempty"`
+	Cwd            string `json:"cwd,omitempty"`
+	HookEventName  string `json:"hook_event_name,omitempty"`
+	Message        string `json:"message,omitempty"`
+
+	// Response size, when the payload reports it.
+	DurationMS int64  `json:"duration_ms,omitempty"`
+	Usage      *Usage `json:"usage,omitempty"`
+
+	// Raw is the payload as received.
+	Raw json.RawMessage `json:"-"`
+}
+
+// Read reads the payload from r, giving up after timeout so a stdin that is
+// never closed can't hang the hook. Whatever isn't read in time (or beyond
+// MaxPayloadSize) is drained in the background.
+func Read(r io.Reader, timeout time.Duration) ([]byte, error) {
+	type result struct {
+		data []byte
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		data, err := io.ReadAll(io.LimitReader(r, MaxPayloadSize))
+		done <- result{data, err}
+		_, _ = io.Copy(io.Discard, r)
+	}()
+
+	select {
+	case res := <-done:
+		return res.data, res.err
+	case <-time.After(timeout):
+		return nil, ErrReadTimeout
+	}
+}
+
+// Usage holds token counts for a response.
+type Usage struct {
+	OutputTokens int `json:"output_tokens,omitempty"`
+}
+
+// Parse decodes a payload. Empty input yields an empty payload.
+func Parse(data []byte) (*Payload, error) {
+	p := &Payload{}
+	if len(data) == 0 {
+		return p, nil
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return p, fmt.Errorf("invalid hook payload: %w", err)
+	}
+	p.Raw = json.RawMessage(data)
+	return p, nil
+}
+
+// Duration returns the response duration, or 0 if not reported.
+func (p *Payload) Duration() time.Duration {
+	if p == nil {
+		return 0
+	}
+	return time.Duration(p.DurationMS) * time.Millisecond
+}
+
+// OutputTokens returns the response's output tokens, or 0 if not reported.
+func (p *Payload) OutputTokens() int {
+	if p == nil || p.Usage == nil {
+		return 0
+	}
+	return p.Usage.OutputTokens
+}
+
+// Empty reports whether no payload was received.
+func (p *Payload) Empty() bool {
+	return p == nil || len(p.Raw) == 0
+}
+
+// Fingerprint returns a stable hash of the fields that identify a
+// notification, so identical re-fired hooks can be detected.
+func (p *Payload) Fingerprint(eventType string) string {
+	h := sha256.New()
+	for _, field := range []string{eventType, p.SessionID, p.HookEventName, p.Message} {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,93 @@
+package hook
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRead(t *testing.T) {
+	data, err := Read(strings.NewReader(`{"session_id":"abc"}`), time.Second)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if string(data) != `{"session_id":"abc"}` {
+		t.Errorf("Read() = %q", data)
+	}
+}
+
+func TestReadTimeout(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	_, err := Read(pr, 20*time.Millisecond)
+	if !errors.Is(err, ErrReadTimeout) {
+		t.Errorf("Read() error = %v, want ErrReadTimeout", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		wantErr   bool
+		wantEmpty bool
+		wantID    string
+	}{
+		{name: "empty", data: "", wantEmpty: true},
+		{name: "notification", data: `{"session_id":"s1","hook_event_name":"Notification","message":"Claude needs your permission"}`, wantID: "s1"},
+		{name: "unknown fields ignored", data: `{"session_id":"s2","extra":1}`, wantID: "s2"},
+		{name: "invalid json", data: `{not json`, wantErr: true, wantEmpty: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Parse([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if p.Empty() != tt.wantEmpty {
+				t.Errorf("Empty() = %v, want %v", p.Empty(), tt.wantEmpty)
+			}
+			if p.SessionID != tt.wantID {
+				t.Errorf("SessionID = %q, want %q", p.SessionID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := &Payload{SessionID: "s1", HookEventName: "Notification", Message: "waiting"}
+	b := &Payload{SessionID: "s1", HookEventName: "Notification", Message: "waiting", TranscriptPath: "/tmp/t"}
+	c := &Payload{SessionID: "s1", HookEventName: "Notification", Message: "permission"}
+
+	if a.Fingerprint("idle_prompt") != b.Fingerprint("idle_prompt") {
+		t.Error("fingerprint should ignore unrelated fields")
+	}
+	if a.Fingerprint("idle_prompt") == c.Fingerprint("idle_prompt") {
+		t.Error("different messages should have different fingerprints")
+	}
+	if a.Fingerprint("idle_prompt") == a.Fingerprint("permission_prompt") {
+		t.Error("different events should have different fingerprints")
+	}
+}
+
+func TestResponseSize(t *testing.T) {
+	p, err := Parse([]byte(`{"session_id": "s", "duration_ms": 4500, "usage": {"output_tokens": 320}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Duration() != 4500*time.Millisecond {
+		t.Errorf("Duration() = %v", p.Duration())
+	}
+	if p.OutputTokens() != 320 {
+		t.Errorf("OutputTokens() = %d", p.OutputTokens())
+	}
+
+	empty, _ := Parse(nil)
+	if empty.Duration() != 0 || empty.OutputTokens() != 0 {
+		t.Error("empty payload should report no response size")
+	}
+}
@@ -0,0 +1,248 @@
+// Package journal records received hook payloads to a rotating JSONL file
+// for debugging and replay.
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxSize is the journal size before rotation (1MB).
+	DefaultMaxSize = 1024 * 1024
+	// RotateCount is the number of rotated journal files to keep.
+	RotateCount = 3
+	// FileMode is the permission mode for journal files.
+	FileMode = 0600
+	// maxStringLen truncates long payload strings (e.g. tool output).
+	maxStringLen = 2000
+)
+
+// ErrNotFound is returned when a journal entry doesn't exist.
+var ErrNotFound = errors.New("journal entry not found")
+
+// sensitiveKeyRegex matches payload keys whose values are redacted.
+var sensitiveKeyRegex = regexp.MustCompile(`(?i)(token|secret|password|passwd|api_?key|authorization|cookie|credential)`)
+
+// Entry is a single journaled hook invocation.
+type Entry struct {
+	ID      int64           `json:"id"`
+	Time    time.Time       `json:"time"`
+	Event   string          `json:"event"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Journal appends entries to ~/.claude/ccbell.events.jsonl.
+type Journal struct {
+	filePath string
+	maxSize  int64
+	mu       sync.Mutex
+}
+
+// New creates a journal in the user's .claude directory.
+func New(homeDir string, maxSize int64) *Journal {
+	journalPath := ""
+	if homeDir != "" {
+		journalPath = filepath.Join(homeDir, ".claude", "ccbell.events.jsonl")
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	return &Journal{filePath: journalPath, maxSize: maxSize}
+}
+
+// Path returns the journal file path.
+func (j *Journal) Path() string {
+	return j.filePath
+}
+
+// Append sanitizes and records a payload, returning the new entry's ID.
+func (j *Journal) Append(eventType string, payload []byte) (int64, error) {
+	if j.filePath == "" {
+		return 0, errors.New("no journal file available")
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entries, err := j.read()
+	if err != nil {
+		return 0, err
+	}
+	var id int64 = 1
+	if len(entries) > 0 {
+		id = entries[len(entries)-1].ID + 1
+	}
+
+	entry := Entry{
+		ID:      id,
+		Time:    time.Now().UTC(),
+		Event:   eventType,
+		Payload: Sanitize(payload),
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode journal entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	if err := os.MkdirAll(filepath.Dir(j.filePath), 0750); err != nil {
+		return 0, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	j.rotateIfNeeded(int64(len(line)))
+
+	f, err := os.OpenFile(j.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, FileMode)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(line); err != nil {
+		return 0, fmt.Errorf("failed to write journal: %w", err)
+	}
+	return id, nil
+}
+
+// Entries returns all journaled entries, oldest first.
+func (j *Journal) Entries() ([]Entry, error) {
+	if j.filePath == "" {
+		return nil, nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.read()
+}
+
+// Last returns the most recent entry.
+func (j *Journal) Last() (*Entry, error) {
+	entries, err := j.Entries()
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrNotFound
+	}
+	return &entries[len(entries)-1], nil
+}
+
+// Find returns the entry with the given ID.
+func (j *Journal) Find(id int64) (*Entry, error) {
+	entries, err := j.Entries()
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if entries[i].ID == id {
+			return &entries[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d", ErrNotFound, id)
+}
+
+// read parses rotated files (oldest first) then the current file.
+// Malformed lines are skipped.
+func (j *Journal) read() ([]Entry, error) {
+	paths := make([]string, 0, RotateCount+1)
+	for i := RotateCount - 1; i >= 0; i-- {
+		paths = append(paths, fmt.Sprintf("%s.%d", j.filePath, i))
+	}
+	paths = append(paths, j.filePath)
+
+	var entries []Entry
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read journal: %w", err)
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), int(j.maxSize)+1)
+		for scanner.Scan() {
+			var entry Entry
+			if err := json.Unmarshal(scanner.Bytes(), &entry); err == nil {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries, nil
+}
+
+// rotateIfNeeded rotates the journal if appending n bytes would exceed
+// the maximum size: .0 -> .1 -> .2, current -> .0.
+func (j *Journal) rotateIfNeeded(n int64) {
+	info, err := os.Stat(j.filePath)
+	if err != nil || info.Size()+n <= j.maxSize {
+		return
+	}
+
+	for i := RotateCount - 1; i >= 1; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", j.filePath, i-1), fmt.Sprintf("%s.%d", j.filePath, i))
+	}
+	_ = os.Rename(j.filePath, j.filePath+".0")
+}
+
+// Sanitize redacts sensitive values and truncates long strings in a JSON
+// payload. Non-JSON input is recorded as a truncated string.
+func Sanitize(payload []byte) json.RawMessage {
+	payload = bytes.TrimSpace(payload)
+	if len(payload) == 0 {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(payload, &value); err != nil {
+		value = truncate(string(payload))
+	} else {
+		value = sanitizeValue(value)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// sanitizeValue walks a decoded JSON value, redacting sensitive keys.
+func sanitizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, inner := range v {
+			if sensitiveKeyRegex.MatchString(key) {
+				v[key] = "[REDACTED]"
+			} else {
+				v[key] = sanitizeValue(inner)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, inner := range v {
+			v[i] = sanitizeValue(inner)
+		}
+		return v
+	case string:
+		return truncate(v)
+	default:
+		return v
+	}
+}
+
+// truncate shortens s to maxStringLen bytes.
+func truncate(s string) string {
+	if len(s) <= maxStringLen {
+		return s
+	}
+	return s[:maxStringLen] + "...[truncated]"
+}
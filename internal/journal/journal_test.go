@@ -0,0 +1,141 @@
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	j := New("/home/user", 0)
+	if j.Path() != "/home/user/.claude/ccbell.events.jsonl" {
+		t.Errorf("Path() = %q", j.Path())
+	}
+	if j.maxSize != DefaultMaxSize {
+		t.Errorf("maxSize = %d, want %d", j.maxSize, DefaultMaxSize)
+	}
+	if New("", 0).Path() != "" {
+		t.Error("Path() should be empty without a home dir")
+	}
+}
+
+func TestAppendAndFind(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-journal-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	j := New(tmpDir, 0)
+
+	if _, err := j.Last(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Last() on empty journal error = %v, want ErrNotFound", err)
+	}
+
+	id1, err := j.Append("stop", []byte(`{"session_id":"s1"}`))
+	if err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	id2, err := j.Append("idle_prompt", nil)
+	if err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if id1 != 1 || id2 != 2 {
+		t.Errorf("ids = %d, %d, want 1, 2", id1, id2)
+	}
+
+	last, err := j.Last()
+	if err != nil {
+		t.Fatalf("Last() error = %v", err)
+	}
+	if last.ID != 2 || last.Event != "idle_prompt" {
+		t.Errorf("Last() = %+v", last)
+	}
+
+	entry, err := j.Find(1)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if entry.Event != "stop" || string(entry.Payload) != `{"session_id":"s1"}` {
+		t.Errorf("Find(1) = %+v", entry)
+	}
+
+	if _, err := j.Find(99); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Find(99) error = %v, want ErrNotFound", err)
+	}
+
+	info, err := os.Stat(j.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != FileMode {
+		t.Errorf("file mode = %v, want %v", info.Mode().Perm(), os.FileMode(FileMode))
+	}
+}
+
+func TestRotation(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-journal-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	j := New(tmpDir, 200)
+	for i := 0; i < 10; i++ {
+		if _, err := j.Append("stop", []byte(`{"session_id":"abcdefghij"}`)); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, ".claude", "ccbell.events.jsonl.0")); err != nil {
+		t.Error("journal should have been rotated")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".claude", "ccbell.events.jsonl.3")); err == nil {
+		t.Errorf("at most %d rotated files should be kept", RotateCount)
+	}
+
+	// IDs continue across rotated files
+	last, err := j.Last()
+	if err != nil {
+		t.Fatalf("Last() error = %v", err)
+	}
+	if last.ID != 10 {
+		t.Errorf("last ID = %d, want 10", last.ID)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		want     string
+		contains string
+	}{
+		{name: "empty", payload: "", want: ""},
+		{name: "plain", payload: `{"session_id":"s1"}`, want: `{"session_id":"s1"}`},
+		{name: "redacts sensitive keys", payload: `{"api_key":"abc","nested":{"Authorization":"Bearer x"}}`, want: `{"api_key":"[REDACTED]","nested":{"Authorization":"[REDACTED]"}}`},
+		{name: "invalid json kept as string", payload: `not json`, want: `"not json"`},
+		{name: "truncates long strings", payload: `{"message":"` + strings.Repeat("a", maxStringLen+10) + `"}`, contains: "...[truncated]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Sanitize([]byte(tt.payload))
+			if tt.contains != "" {
+				if !strings.Contains(string(got), tt.contains) {
+					t.Errorf("Sanitize() = %s, want to contain %q", got, tt.contains)
+				}
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("Sanitize() = %s, want %s", got, tt.want)
+			}
+			if len(got) > 0 && !json.Valid(got) {
+				t.Errorf("Sanitize() produced invalid JSON: %s", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Package locale translates ccbell's user-facing text: notification text,
+// the CLI help and CLI error messages. Packages register catalogs of
+// translations keyed by the English text.
+package locale
+
+import (
+	"fmt"
+	"strings"
+)
+
+// catalogs hold the registered translations, keyed by base language and
+// then by the English text or format string.
+var catalogs = make(map[string]map[string]string)
+
+// Register adds translations into a base language such as "tr". It is
+// meant to be called from init functions.
+func Register(language string, messages map[string]string) {
+	catalog := catalogs[language]
+	if catalog == nil {
+		catalog = make(map[string]string, len(messages))
+		catalogs[language] = catalog
+	}
+	for english, translated := range messages {
+		catalog[english] = translated
+	}
+}
+
+// Locale translates text into a language. The zero Locale, and languages
+// without a catalog, use English.
+type Locale struct {
+	messages map[string]string
+}
+
+// New returns the locale for a language such as "tr" or "tr-TR".
+func New(language string) Locale {
+	base, _, _ := strings.Cut(strings.ReplaceAll(language, "_", "-"), "-")
+	return Locale{messages: catalogs[strings.ToLower(base)]}
+}
+
+// Translates reports whether the locale has a translation of text.
+func (l Locale) Translates(text string) bool {
+	_, ok := l.messages[text]
+	return ok
+}
+
+// Sprintf formats the translation of format, or format itself when it has
+// none.
+func (l Locale) Sprintf(format string, args ...any) string {
+	format = l.translate(format)
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Errorf is fmt.Errorf with the translation of format, so %w still wraps.
+func (l Locale) Errorf(format string, args ...any) error {
+	return fmt.Errorf(l.translate(format), args...)
+}
+
+// translate returns the translation of text, or text itself.
+func (l Locale) translate(text string) string {
+	if translated, ok := l.messages[text]; ok {
+		return translated
+	}
+	return text
+}
@@ -0,0 +1,47 @@
+package locale
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+)
+
+func TestLocale(t *testing.T) {
+	Register("xx", map[string]string{
+		"hello":           "hallo",
+		"%d files":        "%d Dateien",
+		"open failed: %w": "öffnen fehlgeschlagen: %w",
+	})
+
+	tests := []struct {
+		language string
+		want     string
+	}{
+		{"", "3 files"},
+		{"en-US", "3 files"},
+		{"xx", "3 Dateien"},
+		{"xx_YY", "3 Dateien"},
+		{"XX-yy", "3 Dateien"},
+	}
+	for _, tt := range tests {
+		if got := New(tt.language).Sprintf("%d files", 3); got != tt.want {
+			t.Errorf("New(%q).Sprintf() = %q, want %q", tt.language, got, tt.want)
+		}
+	}
+
+	l := New("xx")
+	if got := l.Sprintf("hello"); got != "hallo" {
+		t.Errorf("Sprintf() without args = %q", got)
+	}
+	if got := l.Sprintf("untranslated %d", 1); got != "untranslated 1" {
+		t.Errorf("Sprintf() without a translation = %q", got)
+	}
+	if !l.Translates("hello") || l.Translates("untranslated %d") || New("").Translates("hello") {
+		t.Error("Translates() is wrong")
+	}
+
+	err := l.Errorf("open failed: %w", fs.ErrNotExist)
+	if err.Error() != "öffnen fehlgeschlagen: file does not exist" || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Errorf() = %v", err)
+	}
+}
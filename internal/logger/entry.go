@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeFormat is the timestamp layout of log lines.
+const TimeFormat = "2006-01-02 15:04:05"
+
+// eventTagRegex matches event tags, as opposed to a message that happens to
+// start with a bracket.
+var eventTagRegex = regexp.MustCompile(`^[a-z_]+$`)
+
+// Entry is one parsed log line:
+//
+//	[2006-01-02 15:04:05] [1234] [DEBUG] [stop] message
+//
+// The event tag is omitted for lines written outside an event. Lines from
+// older versions have neither level nor event and parse as DEBUG.
+type Entry struct {
+	Time    time.Time
+	PID     int
+	Level   string
+	Event   string
+	Message string
+}
+
+// String formats the entry as a log line without a trailing newline.
+func (e Entry) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] [%d] [%s] ", e.Time.Format(TimeFormat), e.PID, e.Level)
+	if e.Event != "" {
+		fmt.Fprintf(&b, "[%s] ", e.Event)
+	}
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// ParseLine parses a log line. It reports false for lines that are not log
+// entries, such as continuation lines of a multi-line message.
+func ParseLine(line string) (Entry, bool) {
+	var e Entry
+	fields, rest := bracketFields(line, 4)
+	if len(fields) < 2 {
+		return e, false
+	}
+
+	t, err := time.ParseInLocation(TimeFormat, fields[0], time.Local)
+	if err != nil {
+		return e, false
+	}
+	pid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return e, false
+	}
+	e.Time, e.PID, e.Level = t, pid, LevelDebug
+
+	// Level and event tags are optional; anything else starts the message
+	consumed := 2
+	if len(fields) > consumed && isLevel(fields[consumed]) {
+		e.Level = fields[consumed]
+		consumed++
+		if len(fields) > consumed && eventTagRegex.MatchString(fields[consumed]) {
+			e.Event = fields[consumed]
+			consumed++
+		}
+	}
+	for _, f := range fields[consumed:] {
+		rest = "[" + f + "] " + rest
+	}
+	e.Message = rest
+	return e, true
+}
+
+// bracketFields splits up to max leading "[field] " groups off line.
+func bracketFields(line string, max int) ([]string, string) {
+	var fields []string
+	for len(fields) < max && strings.HasPrefix(line, "[") {
+		end := strings.Index(line, "] ")
+		if end < 0 {
+			break
+		}
+		fields = append(fields, line[1:end])
+		line = line[end+2:]
+	}
+	return fields, line
+}
+
+// isLevel reports whether s is a known log level.
+func isLevel(s string) bool {
+	return s == LevelDebug || s == LevelWarn || s == LevelError
+}
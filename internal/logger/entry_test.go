@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLine(t *testing.T) {
+	ts := time.Date(2025, 3, 10, 14, 5, 9, 0, time.Local)
+	tests := []struct {
+		name   string
+		line   string
+		want   Entry
+		wantOK bool
+	}{
+		{
+			name:   "full entry",
+			line:   "[2025-03-10 14:05:09] [42] [ERROR] [stop] Sound playback failed: boom",
+			want:   Entry{Time: ts, PID: 42, Level: LevelError, Event: "stop", Message: "Sound playback failed: boom"},
+			wantOK: true,
+		},
+		{
+			name:   "no event",
+			line:   "[2025-03-10 14:05:09] [42] [WARN] Journal write failed",
+			want:   Entry{Time: ts, PID: 42, Level: LevelWarn, Message: "Journal write failed"},
+			wantOK: true,
+		},
+		{
+			name:   "bracketed message is not an event",
+			line:   "[2025-03-10 14:05:09] [42] [DEBUG] [Mock] afplay x.aiff",
+			want:   Entry{Time: ts, PID: 42, Level: LevelDebug, Message: "[Mock] afplay x.aiff"},
+			wantOK: true,
+		},
+		{
+			name:   "legacy line",
+			line:   "[2025-03-10 14:05:09] [42] Event type: stop",
+			want:   Entry{Time: ts, PID: 42, Level: LevelDebug, Message: "Event type: stop"},
+			wantOK: true,
+		},
+		{name: "continuation line", line: "  more output", wantOK: false},
+		{name: "bad timestamp", line: "[yesterday] [42] hi", wantOK: false},
+		{name: "bad pid", line: "[2025-03-10 14:05:09] [abc] hi", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseLine(tt.line)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseLine() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && (!got.Time.Equal(tt.want.Time) || got.PID != tt.want.PID || got.Level != tt.want.Level ||
+				got.Event != tt.want.Event || got.Message != tt.want.Message) {
+				t.Errorf("ParseLine() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntryStringRoundTrip(t *testing.T) {
+	e := Entry{Time: time.Date(2025, 3, 10, 14, 5, 9, 0, time.Local), PID: 7, Level: LevelWarn, Event: "subagent", Message: "hello"}
+	want := "[2025-03-10 14:05:09] [7] [WARN] [subagent] hello"
+	if got := e.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	parsed, ok := ParseLine(e.String())
+	if !ok || parsed.Event != e.Event || parsed.Level != e.Level || parsed.Message != e.Message {
+		t.Errorf("ParseLine(String()) = %+v, %v", parsed, ok)
+	}
+}
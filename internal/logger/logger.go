@@ -18,30 +18,65 @@ const (
 	FileMode = 0600
 )
 
+// Log levels written in front of each message.
+const (
+	LevelDebug = "DEBUG"
+	LevelWarn  = "WARN"
+	LevelError = "ERROR"
+)
+
 // Logger handles debug logging with rotation.
 type Logger struct {
 	enabled  bool
 	filePath string
 	pid      int
+	event    string
 	mu       sync.Mutex
 }
 
 // New creates a new Logger instance.
 func New(enabled bool, homeDir string) *Logger {
-	logPath := ""
-	if homeDir != "" {
-		logPath = filepath.Join(homeDir, ".claude", "ccbell.log")
-	}
-
 	return &Logger{
 		enabled:  enabled,
-		filePath: logPath,
+		filePath: Path(homeDir),
 		pid:      os.Getpid(),
 	}
 }
 
+// Path returns the log file path for a home directory, or "" if homeDir is
+// empty.
+func Path(homeDir string) string {
+	if homeDir == "" {
+		return ""
+	}
+	return filepath.Join(homeDir, ".claude", "ccbell.log")
+}
+
+// SetEvent tags subsequent lines with the event being processed, so the log
+// can be filtered by event type.
+func (l *Logger) SetEvent(event string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.event = event
+}
+
 // Debug logs a message if debug mode is enabled.
 func (l *Logger) Debug(format string, args ...interface{}) {
+	l.write(LevelDebug, format, args...)
+}
+
+// Warn logs a recoverable problem if debug mode is enabled.
+func (l *Logger) Warn(format string, args ...interface{}) {
+	l.write(LevelWarn, format, args...)
+}
+
+// Error logs a failure if debug mode is enabled.
+func (l *Logger) Error(format string, args ...interface{}) {
+	l.write(LevelError, format, args...)
+}
+
+// write appends a formatted line at the given level.
+func (l *Logger) write(level, format string, args ...interface{}) {
 	if !l.enabled || l.filePath == "" {
 		return
 	}
@@ -60,9 +95,13 @@ func (l *Logger) Debug(format string, args ...interface{}) {
 	defer f.Close()
 
 	// Format and write
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	msg := fmt.Sprintf(format, args...)
-	fmt.Fprintf(f, "[%s] [%d] %s\n", timestamp, l.pid, msg)
+	fmt.Fprintln(f, Entry{
+		Time:    time.Now(),
+		PID:     l.pid,
+		Level:   level,
+		Event:   l.event,
+		Message: fmt.Sprintf(format, args...),
+	}.String())
 }
 
 // rotateIfNeeded checks log size and rotates if necessary.
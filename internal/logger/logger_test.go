@@ -98,6 +98,43 @@ func TestLogger_Debug(t *testing.T) {
 	})
 }
 
+func TestLogger_Levels(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-logger-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".claude"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	l := New(true, tmpDir)
+	l.Debug("starting")
+	l.SetEvent("stop")
+	l.Warn("retrying %d", 1)
+	l.Error("failed")
+
+	content, err := os.ReadFile(l.filePath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	want := []Entry{
+		{Level: LevelDebug, Message: "starting"},
+		{Level: LevelWarn, Event: "stop", Message: "retrying 1"},
+		{Level: LevelError, Event: "stop", Message: "failed"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d: %q", len(lines), len(want), content)
+	}
+	for i, line := range lines {
+		e, ok := ParseLine(line)
+		if !ok || e.Level != want[i].Level || e.Event != want[i].Event || e.Message != want[i].Message {
+			t.Errorf("line %d = %+v, want %+v", i, e, want[i])
+		}
+	}
+}
+
 func TestLogger_RotateIfNeeded(t *testing.T) {
 	// Create temp directory
 	tmpDir, err := os.MkdirTemp("", "ccbell-rotate-test")
@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/secret"
+)
+
+// DefaultBarkServer is the public Bark push server.
+const DefaultBarkServer = "https://api.day.app"
+
+// Bark pushes notifications to an iOS device through a Bark server.
+type Bark struct {
+	server    string
+	deviceKey string
+	body      *template.Template
+	timeout   time.Duration
+	client    *http.Client
+}
+
+// barkPush is the Bark /push request body.
+type barkPush struct {
+	DeviceKey string `json:"device_key"`
+	Title     string `json:"title"`
+	Subtitle  string `json:"subtitle,omitempty"`
+	Body      string `json:"body"`
+	Group     string `json:"group"`
+	Level     string `json:"level"`
+}
+
+// NewBark creates a Bark channel. server defaults to DefaultBarkServer; the
+// device key may be a "secret:<name>" reference resolved at send time.
+func NewBark(server, deviceKey string, timeout time.Duration) *Bark {
+	if server == "" {
+		server = DefaultBarkServer
+	}
+	return &Bark{
+		server:    strings.TrimSuffix(server, "/"),
+		deviceKey: deviceKey,
+		timeout:   timeout,
+		client:    &http.Client{},
+	}
+}
+
+// SetBodyTemplate sets a template rendering the push text instead of the
+// message body. Nil restores the message body.
+func (b *Bark) SetBodyTemplate(tmpl *template.Template) {
+	b.body = tmpl
+}
+
+// Name returns the channel name.
+func (b *Bark) Name() string { return "bark" }
+
+// Timeout returns the delivery timeout.
+func (b *Bark) Timeout() time.Duration { return b.timeout }
+
+// Send pushes the message with the hostname as subtitle. Permission prompts
+// are time-sensitive so they break through iOS Focus modes.
+func (b *Bark) Send(ctx context.Context, msg *Message) error {
+	deviceKey, err := secret.Resolve(b.deviceKey)
+	if err != nil {
+		return Permanent(fmt.Errorf("bark device key: %w", err))
+	}
+
+	body := msg.Body
+	if b.body != nil {
+		rendered, err := render(b.body, msg)
+		if err != nil {
+			return Permanent(fmt.Errorf("bark body template: %w", err))
+		}
+		body = string(rendered)
+	}
+	level := "active"
+	if msg.Event == "permission_prompt" {
+		level = "timeSensitive"
+	}
+	payload, err := json.Marshal(barkPush{
+		DeviceKey: deviceKey,
+		Title:     msg.Title,
+		Subtitle:  msg.Hostname,
+		Body:      body,
+		Group:     "ccbell",
+		Level:     level,
+	})
+	if err != nil {
+		return Permanent(fmt.Errorf("failed to encode payload: %w", err))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.server+"/push", bytes.NewReader(payload))
+	if err != nil {
+		return Permanent(fmt.Errorf("invalid bark request: %w", withoutURL(err)))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ccbell")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("bark request to %s failed: %w", redactURL(b.server), withoutURL(err))
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&result)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return statusError(fmt.Errorf("bark returned status %d: %s", resp.StatusCode, result.Message), resp.StatusCode)
+	}
+	if result.Code != 0 && result.Code != http.StatusOK {
+		return statusError(fmt.Errorf("bark returned code %d: %s", result.Code, result.Message), result.Code)
+	}
+	return nil
+}
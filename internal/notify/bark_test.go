@@ -0,0 +1,114 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+)
+
+func TestBarkSend(t *testing.T) {
+	tests := []struct {
+		event     string
+		wantLevel string
+	}{
+		{"stop", "active"},
+		{"permission_prompt", "timeSensitive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.event, func(t *testing.T) {
+			var got barkPush
+			var gotPath string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("invalid payload: %v", err)
+				}
+				w.Write([]byte(`{"code":200,"message":"success"}`))
+			}))
+			defer server.Close()
+
+			b := NewBark(server.URL+"/", "devkey", time.Second)
+			if b.Name() != "bark" {
+				t.Errorf("Name() = %q, want bark", b.Name())
+			}
+			msg := NewMessage(tt.event)
+			msg.Hostname = "devbox"
+			if err := b.Send(context.Background(), msg); err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+			if gotPath != "/push" {
+				t.Errorf("path = %q, want /push", gotPath)
+			}
+			if got.DeviceKey != "devkey" || got.Title != msg.Title || got.Subtitle != "devbox" || got.Body != msg.Body {
+				t.Errorf("payload = %+v", got)
+			}
+			if got.Level != tt.wantLevel || got.Group != "ccbell" {
+				t.Errorf("level/group = %q/%q, want %q/ccbell", got.Level, got.Group, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestBarkSendErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"http status", http.StatusInternalServerError, ""},
+		{"bark code", http.StatusOK, `{"code":400,"message":"failed to get device token"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			if err := NewBark(server.URL, "devkey", time.Second).Send(context.Background(), NewMessage("stop")); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestNewBarkDefaultServer(t *testing.T) {
+	if b := NewBark("", "key", time.Second); b.server != DefaultBarkServer {
+		t.Errorf("server = %q, want %q", b.server, DefaultBarkServer)
+	}
+}
+
+func TestBarkBodyTemplate(t *testing.T) {
+	var got barkPush
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		w.Write([]byte(`{"code":200,"message":"success"}`))
+	}))
+	defer server.Close()
+
+	tmpl, err := config.ParseTemplate("bark.body", `{{.Project}}: {{.Message}} ({{.Payload.session_id}})`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := NewBark(server.URL, "devkey", time.Second)
+	b.SetBodyTemplate(tmpl)
+	msg := NewMessage("stop")
+	msg.Project = "api"
+	msg.Payload = json.RawMessage(`{"session_id": "abc123"}`)
+	if err := b.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if want := "api: Claude finished responding (abc123)"; got.Body != want {
+		t.Errorf("body = %q, want %q", got.Body, want)
+	}
+}
@@ -0,0 +1,20 @@
+package notify
+
+// SetContext records the project and git branch a notification comes from,
+// so parallel sessions can be told apart. Set Hostname, Icon and Severity
+// first for templates that reference {hostname}, {icon} or {severity}. The
+// title is rendered from template, or from the default
+// "Claude Code · project (branch)" layout when template is empty.
+func (m *Message) SetContext(project, branch, template string) {
+	m.Project, m.Branch = project, branch
+	if template != "" {
+		m.Title = m.replacer().Replace(template)
+		return
+	}
+	switch {
+	case project != "" && branch != "":
+		m.Title = DefaultTitle + " · " + project + " (" + branch + ")"
+	case project != "":
+		m.Title = DefaultTitle + " · " + project
+	}
+}
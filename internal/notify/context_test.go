@@ -0,0 +1,33 @@
+package notify
+
+import "testing"
+
+func TestSetContext(t *testing.T) {
+	tests := []struct {
+		name      string
+		project   string
+		branch    string
+		template  string
+		wantTitle string
+	}{
+		{"no context", "", "", "", "Claude Code"},
+		{"project only", "api", "", "", "Claude Code · api"},
+		{"project and branch", "api", "release/1.2", "", "Claude Code · api (release/1.2)"},
+		{"template", "api", "main", "[{project}@{branch}] {event}", "[api@main] stop"},
+		{"template without context", "", "", "{project}{branch}ccbell", "ccbell"},
+		{"hostname", "api", "", "{hostname}: {project}", "devbox: api"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := NewMessage("stop")
+			msg.Hostname = "devbox"
+			msg.SetContext(tt.project, tt.branch, tt.template)
+			if msg.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", msg.Title, tt.wantTitle)
+			}
+			if msg.Project != tt.project || msg.Branch != tt.branch {
+				t.Errorf("Project, Branch = %q, %q", msg.Project, msg.Branch)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Replaceable in tests.
+var (
+	execCommandContext = exec.CommandContext
+	execCommand        = exec.Command
+	lookPath           = exec.LookPath
+	goos               = runtime.GOOS
+)
+
+// FocusAuto selects the terminal app Claude Code is running in.
+const FocusAuto = "auto"
+
+// terminalApps maps $TERM_PROGRAM values to application names.
+var terminalApps = map[string]string{
+	"iTerm.app":      "iTerm",
+	"Apple_Terminal": "Terminal",
+	"vscode":         "Visual Studio Code",
+	"WezTerm":        "WezTerm",
+	"ghostty":        "Ghostty",
+}
+
+// focusScript shows a notification with a default action and waits (in a
+// detached process) for a click, then raises the window of app $1. The
+// remaining arguments are passed to notify-send.
+const focusScript = `app=$1; shift; a=$(notify-send --action=default=Focus --wait "$@") && [ "$a" = default ] && { wmctrl -xa "$app" 2>/dev/null || xdotool search --class "$app" windowactivate 2>/dev/null; }`
+
+// Desktop shows native desktop notifications (notify-send on Linux,
+// osascript on macOS).
+type Desktop struct {
+	timeout   time.Duration
+	focusApp  string
+	shown     func(msg *Message, id uint32)
+	bypassDND bool
+}
+
+// NewDesktop creates a desktop notification channel. Critical messages
+// break through do-not-disturb unless SetBypassDND(false) is called.
+func NewDesktop(timeout time.Duration) *Desktop {
+	return &Desktop{timeout: timeout, bypassDND: true}
+}
+
+// SetBypassDND sets whether critical messages break through
+// do-not-disturb: with urgency=critical on Linux, and on macOS with
+// terminal-notifier's -ignoreDnD when it is installed.
+func (d *Desktop) SetBypassDND(bypass bool) {
+	d.bypassDND = bypass
+}
+
+// breaksThrough reports whether msg should break through do-not-disturb.
+func (d *Desktop) breaksThrough(msg *Message) bool {
+	return d.bypassDND && msg.Severity == SeverityCritical
+}
+
+// SetFocusApp makes clicking the notification activate app. FocusAuto
+// detects the terminal from the environment; empty disables click actions.
+func (d *Desktop) SetFocusApp(app string) {
+	if app == FocusAuto {
+		app = DetectTerminalApp()
+	}
+	d.focusApp = app
+}
+
+// SetShown makes the channel report each notification shown, with the ID
+// the notification server assigned (0 where unknown), so a later message
+// can replace it through ReplaceID. On macOS, terminal-notifier then groups
+// notifications per event instead of in one "ccbell" group.
+func (d *Desktop) SetShown(shown func(msg *Message, id uint32)) {
+	d.shown = shown
+}
+
+// DetectTerminalApp returns the application hosting the current terminal,
+// or "" if unknown.
+func DetectTerminalApp() string {
+	if app, ok := terminalApps[os.Getenv("TERM_PROGRAM")]; ok {
+		return app
+	}
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "":
+		return "kitty"
+	case os.Getenv("ALACRITTY_WINDOW_ID") != "":
+		return "Alacritty"
+	}
+	return ""
+}
+
+// Name returns the channel name.
+func (d *Desktop) Name() string { return "desktop" }
+
+// Timeout returns the delivery timeout.
+func (d *Desktop) Timeout() time.Duration { return d.timeout }
+
+// Send shows the notification.
+func (d *Desktop) Send(ctx context.Context, msg *Message) error {
+	var cmd *exec.Cmd
+	switch goos {
+	case "darwin":
+		if _, err := lookPath("terminal-notifier"); (d.focusApp != "" || d.breaksThrough(msg)) && err == nil {
+			// terminal-notifier supports click actions and ignoring
+			// do-not-disturb; osascript doesn't
+			cmd = execCommandContext(ctx, "terminal-notifier", d.terminalNotifierArgs(msg)...)
+			break
+		}
+		// Pass text as arguments so quotes in messages can't break the script
+		cmd = execCommandContext(ctx, "osascript",
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			msg.Title, msg.Body)
+	case "linux":
+		if _, err := lookPath("notify-send"); err != nil {
+			return fmt.Errorf("notify-send not found; install libnotify-bin or libnotify")
+		}
+		if d.focusApp != "" {
+			return d.sendFocusable(msg)
+		}
+		if d.shown != nil {
+			return d.sendReplaceable(ctx, msg)
+		}
+		cmd = execCommandContext(ctx, "notify-send", d.notifySendArgs(msg)...)
+	default:
+		return fmt.Errorf("desktop notifications not supported on %s", goos)
+	}
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", cmd.Args[0], err)
+	}
+	d.report(msg, 0)
+	return nil
+}
+
+// report passes a shown notification to the SetShown callback, if any.
+func (d *Desktop) report(msg *Message, id uint32) {
+	if d.shown != nil {
+		d.shown(msg, id)
+	}
+}
+
+// terminalNotifierArgs returns the terminal-notifier arguments showing msg.
+func (d *Desktop) terminalNotifierArgs(msg *Message) []string {
+	group := "ccbell"
+	if d.shown != nil {
+		group += "-" + msg.Event
+	}
+	args := []string{"-title", msg.Title, "-message", msg.Body, "-group", group}
+	if d.breaksThrough(msg) {
+		args = append(args, "-ignoreDnD")
+	}
+	if d.focusApp != "" {
+		args = append(args, "-execute", "open -a "+shellQuote(d.focusApp))
+	}
+	return args
+}
+
+// notifySendArgs returns the notify-send arguments showing msg, with its
+// urgency and expiry.
+func (d *Desktop) notifySendArgs(msg *Message) []string {
+	args := []string{"--app-name=ccbell"}
+	urgency := msg.Urgency
+	if urgency == "" && d.breaksThrough(msg) {
+		urgency = "critical"
+	}
+	if urgency != "" {
+		args = append(args, "--urgency="+urgency)
+	}
+	if msg.Expire != nil {
+		args = append(args, fmt.Sprintf("--expire-time=%d", msg.Expire.Milliseconds()))
+	}
+	if msg.ReplaceID != 0 {
+		args = append(args, fmt.Sprintf("--replace-id=%d", msg.ReplaceID))
+	}
+	return append(args, msg.Title, msg.Body)
+}
+
+// sendReplaceable shows the notification with notify-send --print-id
+// (libnotify 0.7.10 or later), reporting the ID it prints.
+func (d *Desktop) sendReplaceable(ctx context.Context, msg *Message) error {
+	cmd := execCommandContext(ctx, "notify-send", append([]string{"--print-id"}, d.notifySendArgs(msg)...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("notify-send failed: %w", err)
+	}
+	id, _ := strconv.ParseUint(strings.TrimSpace(string(out)), 10, 32)
+	d.report(msg, uint32(id))
+	return nil
+}
+
+// sendFocusable starts a detached notify-send that waits for a click, so the
+// hook doesn't block until the notification is dismissed.
+func (d *Desktop) sendFocusable(msg *Message) error {
+	args := append([]string{"-c", focusScript, "ccbell-notify", d.focusApp}, d.notifySendArgs(msg)...)
+	cmd := execCommand("sh", args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("notify-send failed: %w", err)
+	}
+	go func() { _ = cmd.Wait() }()
+	// The server keeps the ID of a notification it replaces
+	d.report(msg, msg.ReplaceID)
+	return nil
+}
+
+// shellQuote quotes s for use as a single sh word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,299 @@
+package notify
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakeExecCommandContext records the command and runs the test helper process.
+func fakeExecCommandContext(record *[]string) func(ctx context.Context, name string, args ...string) *exec.Cmd {
+	return func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		*record = append([]string{name}, args...)
+		cmd := exec.CommandContext(ctx, os.Args[0], "-test.run=TestHelperProcess")
+		cmd.Env = append(os.Environ(), "GO_WANT_HELPER_PROCESS=1")
+		return cmd
+	}
+}
+
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	os.Stdout.WriteString(os.Getenv("HELPER_OUTPUT"))
+	os.Exit(0)
+}
+
+// fakeDesktopEnv sets the target OS and which tools are installed.
+func fakeDesktopEnv(t *testing.T, osName string, installed ...string) {
+	t.Helper()
+	origOS, origLookPath := goos, lookPath
+	goos = osName
+	lookPath = func(file string) (string, error) {
+		for _, name := range installed {
+			if name == file {
+				return "/usr/bin/" + file, nil
+			}
+		}
+		return "", exec.ErrNotFound
+	}
+	t.Cleanup(func() { goos, lookPath = origOS, origLookPath })
+}
+
+func TestDesktopSend(t *testing.T) {
+	tests := []struct {
+		name      string
+		goos      string
+		installed []string
+		wantCmd   string
+	}{
+		{"macos", "darwin", nil, "osascript"},
+		{"linux", "linux", []string{"notify-send"}, "notify-send"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeDesktopEnv(t, tt.goos, tt.installed...)
+			var args []string
+			orig := execCommandContext
+			execCommandContext = fakeExecCommandContext(&args)
+			defer func() { execCommandContext = orig }()
+
+			d := NewDesktop(time.Second)
+			if d.Name() != "desktop" {
+				t.Errorf("Name() = %q, want desktop", d.Name())
+			}
+			msg := NewMessage("stop")
+			if err := d.Send(context.Background(), msg); err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+
+			if len(args) < 3 || args[0] != tt.wantCmd {
+				t.Fatalf("unexpected command: %v", args)
+			}
+			if args[len(args)-2] != msg.Title || args[len(args)-1] != msg.Body {
+				t.Errorf("command args = %v, want title and body last", args)
+			}
+		})
+	}
+}
+
+func TestDesktopSendCritical(t *testing.T) {
+	fakeDesktopEnv(t, "linux", "notify-send")
+	var args []string
+	orig := execCommandContext
+	execCommandContext = fakeExecCommandContext(&args)
+	defer func() { execCommandContext = orig }()
+
+	msg := NewMessage("permission_prompt")
+	msg.Severity = SeverityCritical
+	d := NewDesktop(time.Second)
+	if err := d.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if !slices.Contains(args, "--urgency=critical") {
+		t.Errorf("command args = %v, want --urgency=critical", args)
+	}
+
+	// terminal-notifier is used on macOS even without a focus app
+	fakeDesktopEnv(t, "darwin", "terminal-notifier")
+	if err := d.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if args[0] != "terminal-notifier" || !slices.Contains(args, "-ignoreDnD") {
+		t.Errorf("command = %v, want terminal-notifier -ignoreDnD", args)
+	}
+
+	d.SetBypassDND(false)
+	if err := d.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if args[0] != "osascript" {
+		t.Errorf("command = %v, want osascript without the bypass", args)
+	}
+	fakeDesktopEnv(t, "linux", "notify-send")
+	if err := d.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if slices.Contains(args, "--urgency=critical") {
+		t.Errorf("command args = %v, want no critical urgency without the bypass", args)
+	}
+}
+
+func TestNotifySendArgs(t *testing.T) {
+	sticky, short := time.Duration(0), 8*time.Second
+	tests := []struct {
+		name     string
+		severity string
+		urgency  string
+		expire   *time.Duration
+		want     []string
+	}{
+		{"default", SeverityInfo, "", nil, []string{"--app-name=ccbell"}},
+		{"critical severity", SeverityCritical, "", nil, []string{"--app-name=ccbell", "--urgency=critical"}},
+		{"urgency overrides severity", SeverityCritical, "low", nil, []string{"--app-name=ccbell", "--urgency=low"}},
+		{"sticky", SeverityWarning, "", &sticky, []string{"--app-name=ccbell", "--expire-time=0"}},
+		{"timeout", SeverityInfo, "normal", &short, []string{"--app-name=ccbell", "--urgency=normal", "--expire-time=8000"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := NewMessage("stop")
+			msg.Severity, msg.Urgency, msg.Expire = tt.severity, tt.urgency, tt.expire
+			want := append(tt.want, msg.Title, msg.Body)
+			if got := NewDesktop(time.Second).notifySendArgs(msg); !slices.Equal(got, want) {
+				t.Errorf("notifySendArgs() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestDesktopSendReplaceable(t *testing.T) {
+	fakeDesktopEnv(t, "linux", "notify-send")
+	t.Setenv("HELPER_OUTPUT", "42\n")
+	var args []string
+	orig := execCommandContext
+	execCommandContext = fakeExecCommandContext(&args)
+	defer func() { execCommandContext = orig }()
+
+	var shownID uint32
+	d := NewDesktop(time.Second)
+	d.SetShown(func(_ *Message, id uint32) { shownID = id })
+	msg := NewMessage("subagent")
+	msg.ReplaceID = 7
+	if err := d.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if !slices.Contains(args, "--print-id") || !slices.Contains(args, "--replace-id=7") {
+		t.Errorf("command args = %v, want --print-id and --replace-id=7", args)
+	}
+	if shownID != 42 {
+		t.Errorf("shown ID = %d, want 42", shownID)
+	}
+
+	// terminal-notifier replaces per event instead of one shared group
+	fakeDesktopEnv(t, "darwin", "terminal-notifier")
+	d.SetFocusApp("iTerm")
+	if err := d.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if i := slices.Index(args, "-group"); i < 0 || args[i+1] != "ccbell-subagent" {
+		t.Errorf("command args = %v, want -group ccbell-subagent", args)
+	}
+	if shownID != 0 {
+		t.Errorf("shown ID = %d, want 0 on macOS", shownID)
+	}
+}
+
+func TestDesktopSendErrors(t *testing.T) {
+	fakeDesktopEnv(t, "linux")
+	if err := NewDesktop(time.Second).Send(context.Background(), NewMessage("stop")); err == nil {
+		t.Error("expected error without notify-send")
+	}
+
+	fakeDesktopEnv(t, "windows")
+	if err := NewDesktop(time.Second).Send(context.Background(), NewMessage("stop")); err == nil {
+		t.Error("expected error on unsupported OS")
+	}
+}
+
+func TestDesktopFocusMacOS(t *testing.T) {
+	var args []string
+	orig := execCommandContext
+	execCommandContext = fakeExecCommandContext(&args)
+	defer func() { execCommandContext = orig }()
+
+	d := NewDesktop(time.Second)
+	d.SetFocusApp("iTerm")
+
+	// Falls back to osascript without terminal-notifier
+	fakeDesktopEnv(t, "darwin")
+	if err := d.Send(context.Background(), NewMessage("stop")); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if args[0] != "osascript" {
+		t.Errorf("command = %v, want osascript", args)
+	}
+
+	fakeDesktopEnv(t, "darwin", "terminal-notifier")
+	if err := d.Send(context.Background(), NewMessage("stop")); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if args[0] != "terminal-notifier" || args[len(args)-1] != "open -a 'iTerm'" {
+		t.Errorf("command = %v, want terminal-notifier activating iTerm", args)
+	}
+}
+
+func TestDesktopFocusLinux(t *testing.T) {
+	fakeDesktopEnv(t, "linux", "notify-send")
+	var args []string
+	orig := execCommand
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		args = append([]string{name}, arg...)
+		cmd := exec.Command(os.Args[0], "-test.run=TestHelperProcess")
+		cmd.Env = append(os.Environ(), "GO_WANT_HELPER_PROCESS=1")
+		return cmd
+	}
+	defer func() { execCommand = orig }()
+
+	d := NewDesktop(time.Second)
+	d.SetFocusApp("kitty")
+	msg := NewMessage("stop")
+	if err := d.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	want := []string{"sh", "-c", focusScript, "ccbell-notify", "kitty", "--app-name=ccbell", msg.Title, msg.Body}
+	if len(args) != len(want) {
+		t.Fatalf("command = %v, want %v", args, want)
+	}
+	for i := range want {
+		if args[i] != want[i] {
+			t.Errorf("arg %d = %q, want %q", i, args[i], want[i])
+		}
+	}
+}
+
+func TestDetectTerminalApp(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"iterm", map[string]string{"TERM_PROGRAM": "iTerm.app"}, "iTerm"},
+		{"vscode", map[string]string{"TERM_PROGRAM": "vscode"}, "Visual Studio Code"},
+		{"kitty", map[string]string{"KITTY_WINDOW_ID": "1"}, "kitty"},
+		{"unknown", nil, ""},
+	}
+
+	keys := []string{"TERM_PROGRAM", "KITTY_WINDOW_ID", "ALACRITTY_WINDOW_ID"}
+	for _, key := range keys {
+		orig := os.Getenv(key)
+		defer os.Setenv(key, orig)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range keys {
+				os.Setenv(key, tt.env[key])
+			}
+			if got := DetectTerminalApp(); got != tt.want {
+				t.Errorf("DetectTerminalApp() = %q, want %q", got, tt.want)
+			}
+
+			d := NewDesktop(time.Second)
+			d.SetFocusApp(FocusAuto)
+			if d.focusApp != tt.want {
+				t.Errorf("SetFocusApp(auto) = %q, want %q", d.focusApp, tt.want)
+			}
+		})
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got := shellQuote("it's"); got != `'it'\''s'` {
+		t.Errorf("shellQuote() = %q", got)
+	}
+}
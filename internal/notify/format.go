@@ -0,0 +1,55 @@
+package notify
+
+import "strings"
+
+// Notification severities. They color the text channel's line and set the
+// urgency of Linux desktop notifications.
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// DefaultIcon is the {icon} of events without their own.
+const DefaultIcon = "🔔"
+
+// defaultIcons holds each built-in event's {icon}.
+var defaultIcons = map[string]string{
+	"stop":              "✅",
+	"permission_prompt": "🔐",
+	"idle_prompt":       "⏳",
+	"subagent":          "🤖",
+}
+
+// defaultSeverities holds each built-in event's severity; others are info.
+var defaultSeverities = map[string]string{
+	"permission_prompt": SeverityWarning,
+}
+
+// severityColors are the ANSI colors of text channel lines by severity.
+var severityColors = map[string]string{
+	SeverityWarning:  "\x1b[33m", // Yellow
+	SeverityCritical: "\x1b[1;31m",
+}
+
+// ApplyFormat renders the body from template, which may reference
+// {message} (the current body), {icon}, {severity}, {project}, {branch},
+// {hostname} and {event}. An empty template keeps the body.
+func (m *Message) ApplyFormat(template string) {
+	if template == "" {
+		return
+	}
+	m.Body = m.replacer("{message}", m.Body).Replace(template)
+}
+
+// replacer substitutes the message's placeholders, plus extra pairs.
+func (m *Message) replacer(extra ...string) *strings.Replacer {
+	return strings.NewReplacer(append([]string{
+		"{project}", m.Project,
+		"{branch}", m.Branch,
+		"{hostname}", m.Hostname,
+		"{event}", m.Event,
+		"{icon}", m.Icon,
+		"{severity}", m.Severity,
+	}, extra...)...)
+}
@@ -0,0 +1,27 @@
+package notify
+
+import "testing"
+
+func TestMessageFormat(t *testing.T) {
+	msg := NewMessage("permission_prompt")
+	if msg.Icon != "🔐" || msg.Severity != SeverityWarning {
+		t.Errorf("permission_prompt icon, severity = %q, %q", msg.Icon, msg.Severity)
+	}
+	custom := NewMessage("deploy_done")
+	if custom.Icon != DefaultIcon || custom.Severity != SeverityInfo {
+		t.Errorf("custom event icon, severity = %q, %q", custom.Icon, custom.Severity)
+	}
+
+	msg.SetContext("api", "main", "{icon} {project}")
+	if msg.Title != "🔐 api" {
+		t.Errorf("Title = %q, want icon and project", msg.Title)
+	}
+	msg.ApplyFormat("")
+	if msg.Body != "Claude needs your permission" {
+		t.Errorf("empty template changed body to %q", msg.Body)
+	}
+	msg.ApplyFormat("{icon} [{severity}] {message} on {branch}")
+	if want := "🔐 [warning] Claude needs your permission on main"; msg.Body != want {
+		t.Errorf("Body = %q, want %q", msg.Body, want)
+	}
+}
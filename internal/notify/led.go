@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DefaultLEDBlinks is how many times the LED blinks per notification.
+const DefaultLEDBlinks = 3
+
+// defaultLEDColors are the LED colors for each built-in event.
+var defaultLEDColors = map[string]string{
+	"stop":              "#00ff00",
+	"permission_prompt": "#ff4000",
+	"idle_prompt":       "#0060ff",
+	"subagent":          "#a000ff",
+}
+
+// LED blinks a blink(1) USB LED through blink1-tool, for silent feedback
+// where sounds would disturb others.
+type LED struct {
+	colors  map[string]string
+	blinks  int
+	timeout time.Duration
+}
+
+// NewLED creates an LED channel. colors overrides the per-event colors
+// (#rrggbb); blinks <= 0 uses DefaultLEDBlinks.
+func NewLED(colors map[string]string, blinks int, timeout time.Duration) *LED {
+	if blinks <= 0 {
+		blinks = DefaultLEDBlinks
+	}
+	return &LED{colors: colors, blinks: blinks, timeout: timeout}
+}
+
+// Name returns the channel name.
+func (l *LED) Name() string { return "led" }
+
+// Timeout returns the delivery timeout.
+func (l *LED) Timeout() time.Duration { return l.timeout }
+
+// Send blinks the LED in the event's color.
+func (l *LED) Send(ctx context.Context, msg *Message) error {
+	if _, err := lookPath("blink1-tool"); err != nil {
+		return fmt.Errorf("blink1-tool not found; install it from https://github.com/todbot/blink1-tool")
+	}
+	cmd := execCommandContext(ctx, "blink1-tool", "-q",
+		"--rgb="+l.color(msg.Event), "--blink", strconv.Itoa(l.blinks))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("blink1-tool failed: %w: %s", err, out)
+	}
+	return nil
+}
+
+// color returns the configured color for an event, falling back to the
+// default and then white.
+func (l *LED) color(eventType string) string {
+	if color, ok := l.colors[eventType]; ok {
+		return color
+	}
+	if color, ok := defaultLEDColors[eventType]; ok {
+		return color
+	}
+	return "#ffffff"
+}
@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLEDSend(t *testing.T) {
+	tests := []struct {
+		name   string
+		colors map[string]string
+		blinks int
+		event  string
+		want   []string
+	}{
+		{"default color", nil, 0, "permission_prompt", []string{"blink1-tool", "-q", "--rgb=#ff4000", "--blink", "3"}},
+		{"configured color", map[string]string{"stop": "#112233"}, 5, "stop", []string{"blink1-tool", "-q", "--rgb=#112233", "--blink", "5"}},
+		{"unknown event", nil, 1, "custom", []string{"blink1-tool", "-q", "--rgb=#ffffff", "--blink", "1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeDesktopEnv(t, "linux", "blink1-tool")
+			var args []string
+			orig := execCommandContext
+			execCommandContext = fakeExecCommandContext(&args)
+			defer func() { execCommandContext = orig }()
+
+			led := NewLED(tt.colors, tt.blinks, time.Second)
+			if led.Name() != "led" {
+				t.Errorf("Name() = %q, want led", led.Name())
+			}
+			if err := led.Send(context.Background(), NewMessage(tt.event)); err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+			if !slices.Equal(args, tt.want) {
+				t.Errorf("command = %v, want %v", args, tt.want)
+			}
+		})
+	}
+}
+
+func TestLEDSendNotInstalled(t *testing.T) {
+	fakeDesktopEnv(t, "linux")
+	if err := NewLED(nil, 0, time.Second).Send(context.Background(), NewMessage("stop")); err == nil {
+		t.Error("expected error without blink1-tool")
+	}
+}
@@ -0,0 +1,19 @@
+package notify
+
+import "github.com/mpolatcan/ccbell/internal/locale"
+
+// Built-in notification text, translated for the "language" setting.
+func init() {
+	locale.Register("tr", map[string]string{
+		"Claude finished responding":                 "Claude yanıtını tamamladı",
+		"Claude needs your permission":               "Claude izninizi bekliyor",
+		"Claude is waiting for your input":           "Claude girdinizi bekliyor",
+		"A subagent completed":                       "Bir alt ajan tamamlandı",
+		"Event: %s":                                  "Olay: %s",
+		"%d subagents finished":                      "%d alt ajan tamamlandı",
+		"%s (%d times)":                              "%s (%d kez)",
+		"All done: Claude and 1 subagent finished":   "Hepsi tamam: Claude ve 1 alt ajan tamamlandı",
+		"All done: Claude and %d subagents finished": "Hepsi tamam: Claude ve %d alt ajan tamamlandı",
+		"%s (unacknowledged for %s)":                 "%s (%s boyunca onaylanmadı)",
+	})
+}
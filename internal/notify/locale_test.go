@@ -0,0 +1,37 @@
+package notify
+
+import (
+	"testing"
+
+	"github.com/mpolatcan/ccbell/internal/locale"
+)
+
+func TestLocale(t *testing.T) {
+	tests := []struct {
+		language string
+		event    string
+		want     string
+	}{
+		{"", "stop", "Claude finished responding"},
+		{"en-US", "permission_prompt", "Claude needs your permission"},
+		{"tr", "permission_prompt", "Claude izninizi bekliyor"},
+		{"tr_TR", "idle_prompt", "Claude girdinizi bekliyor"},
+		{"TR-tr", "deploy_done", "Olay: deploy_done"},
+		{"de", "subagent", "A subagent completed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language+"/"+tt.event, func(t *testing.T) {
+			if got := NewLocalizedMessage(tt.event, locale.New(tt.language)).Body; got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := locale.New("tr").Sprintf("%d subagents finished", 3); got != "3 alt ajan tamamlandı" {
+		t.Errorf("Sprintf() = %q", got)
+	}
+	if got := locale.New("tr").Sprintf("untranslated %d", 1); got != "untranslated 1" {
+		t.Errorf("Sprintf() without a translation = %q", got)
+	}
+}
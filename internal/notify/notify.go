@@ -0,0 +1,169 @@
+// Package notify delivers ccbell notifications over multiple channels
+// (sound, desktop, terminal, webhook) concurrently.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/locale"
+)
+
+// DefaultTimeout is the per-channel delivery timeout when none is configured.
+const DefaultTimeout = 5 * time.Second
+
+// DefaultTitle is the notification title used by text channels.
+const DefaultTitle = "Claude Code"
+
+// defaultBodies holds the notification text for each built-in event.
+var defaultBodies = map[string]string{
+	"stop":              "Claude finished responding",
+	"permission_prompt": "Claude needs your permission",
+	"idle_prompt":       "Claude is waiting for your input",
+	"subagent":          "A subagent completed",
+}
+
+// Message describes a notification to deliver.
+type Message struct {
+	Event     string    `json:"event"`
+	Title     string    `json:"title"`
+	Body      string    `json:"message"`
+	Project   string    `json:"project,omitempty"`  // Project directory name
+	Branch    string    `json:"branch,omitempty"`   // Git branch
+	Hostname  string    `json:"hostname,omitempty"` // Machine the event came from
+	Icon      string    `json:"icon,omitempty"`     // Emoji for {icon} in templates
+	Severity  string    `json:"severity,omitempty"` // SeverityInfo, SeverityWarning or SeverityCritical
+	Timestamp time.Time `json:"timestamp"`
+
+	// Payload is the raw hook payload, for webhook and push templates. It
+	// isn't part of the default webhook body.
+	Payload json.RawMessage `json:"-"`
+
+	// SessionID is the Claude session that raised the event, for
+	// per-session sounds.
+	SessionID string `json:"-"`
+
+	// Urgency overrides the desktop notification urgency implied by
+	// Severity: "low", "normal" or "critical".
+	Urgency string `json:"-"`
+
+	// Expire is how long a desktop notification stays up; 0 keeps it until
+	// dismissed, nil leaves it to the notification server.
+	Expire *time.Duration `json:"-"`
+
+	// Count is how many events the message stands for; 0 means one.
+	Count int `json:"-"`
+
+	// ReplaceID is the notification server ID of a desktop notification
+	// this one replaces, or 0.
+	ReplaceID uint32 `json:"-"`
+}
+
+// NewMessage creates a message with the default title, body, icon and
+// severity for an event.
+func NewMessage(eventType string) *Message {
+	return NewLocalizedMessage(eventType, locale.Locale{})
+}
+
+// NewLocalizedMessage is NewMessage with the body in the locale's
+// language.
+func NewLocalizedMessage(eventType string, l locale.Locale) *Message {
+	body, ok := defaultBodies[eventType]
+	if ok {
+		body = l.Sprintf(body)
+	} else {
+		body = l.Sprintf("Event: %s", eventType)
+	}
+	icon, ok := defaultIcons[eventType]
+	if !ok {
+		icon = DefaultIcon
+	}
+	severity, ok := defaultSeverities[eventType]
+	if !ok {
+		severity = SeverityInfo
+	}
+	return &Message{
+		Event:     eventType,
+		Title:     DefaultTitle,
+		Body:      body,
+		Icon:      icon,
+		Severity:  severity,
+		Timestamp: time.Now(),
+	}
+}
+
+// Channel is a notification delivery mechanism.
+type Channel interface {
+	// Name returns the channel name used in config and logs.
+	Name() string
+	// Timeout returns the maximum time allowed for a single delivery.
+	Timeout() time.Duration
+	// Send delivers the message, honoring ctx cancellation where possible.
+	Send(ctx context.Context, msg *Message) error
+}
+
+// funcChannel adapts a function to the Channel interface.
+type funcChannel struct {
+	name    string
+	timeout time.Duration
+	send    func(ctx context.Context, msg *Message) error
+}
+
+// Func returns a Channel that delivers messages by calling send.
+func Func(name string, timeout time.Duration, send func(ctx context.Context, msg *Message) error) Channel {
+	return &funcChannel{name: name, timeout: timeout, send: send}
+}
+
+func (f *funcChannel) Name() string           { return f.name }
+func (f *funcChannel) Timeout() time.Duration { return f.timeout }
+func (f *funcChannel) Send(ctx context.Context, msg *Message) error {
+	return f.send(ctx, msg)
+}
+
+// Dispatch sends msg to all channels concurrently, each bounded by its own
+// timeout. It waits for every channel to finish or time out and returns the
+// aggregated errors (nil if all deliveries succeeded).
+func Dispatch(ctx context.Context, msg *Message, channels []Channel) error {
+	errs := make([]error, len(channels))
+	var wg sync.WaitGroup
+
+	for i, ch := range channels {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := send(ctx, msg, ch); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", ch.Name(), err)
+			}
+		}()
+	}
+
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// send delivers msg on a single channel, giving up once its timeout expires
+// even if the channel ignores context cancellation.
+func send(ctx context.Context, msg *Message, ch Channel) error {
+	timeout := ch.Timeout()
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- ch.Send(ctx, msg)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+}
@@ -0,0 +1,128 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventType string
+		wantBody  string
+	}{
+		{"stop", "stop", "Claude finished responding"},
+		{"permission prompt", "permission_prompt", "Claude needs your permission"},
+		{"unknown event", "custom", "Event: custom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := NewMessage(tt.eventType)
+			if msg.Event != tt.eventType {
+				t.Errorf("Event = %q, want %q", msg.Event, tt.eventType)
+			}
+			if msg.Title != DefaultTitle {
+				t.Errorf("Title = %q, want %q", msg.Title, DefaultTitle)
+			}
+			if msg.Body != tt.wantBody {
+				t.Errorf("Body = %q, want %q", msg.Body, tt.wantBody)
+			}
+			if msg.Timestamp.IsZero() {
+				t.Error("Timestamp should be set")
+			}
+		})
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	msg := NewMessage("stop")
+
+	t.Run("no channels", func(t *testing.T) {
+		if err := Dispatch(context.Background(), msg, nil); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("all channels succeed", func(t *testing.T) {
+		var calls atomic.Int32
+		ok := func(_ context.Context, _ *Message) error {
+			calls.Add(1)
+			return nil
+		}
+		channels := []Channel{Func("a", time.Second, ok), Func("b", time.Second, ok)}
+		if err := Dispatch(context.Background(), msg, channels); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if calls.Load() != 2 {
+			t.Errorf("calls = %d, want 2", calls.Load())
+		}
+	})
+
+	t.Run("channels run concurrently", func(t *testing.T) {
+		// Each channel waits for the other; sequential dispatch would deadlock
+		// until the timeout fires.
+		started := make(chan struct{}, 2)
+		wait := func(ctx context.Context, _ *Message) error {
+			started <- struct{}{}
+			for len(started) < 2 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(time.Millisecond):
+				}
+			}
+			return nil
+		}
+		channels := []Channel{Func("a", time.Second, wait), Func("b", time.Second, wait)}
+		if err := Dispatch(context.Background(), msg, channels); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("slow channel times out without blocking others", func(t *testing.T) {
+		var fastDone atomic.Bool
+		slow := func(_ context.Context, _ *Message) error {
+			time.Sleep(time.Second) // ignores ctx on purpose
+			return nil
+		}
+		fast := func(_ context.Context, _ *Message) error {
+			fastDone.Store(true)
+			return nil
+		}
+		channels := []Channel{Func("slow", 20*time.Millisecond, slow), Func("fast", time.Second, fast)}
+
+		start := time.Now()
+		err := Dispatch(context.Background(), msg, channels)
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("Dispatch took %v, want it bounded by the slow channel timeout", elapsed)
+		}
+		if err == nil || !strings.Contains(err.Error(), "slow: timed out") {
+			t.Errorf("error = %v, want slow channel timeout", err)
+		}
+		if !fastDone.Load() {
+			t.Error("fast channel should have been notified")
+		}
+	})
+
+	t.Run("errors are aggregated", func(t *testing.T) {
+		errA := errors.New("a failed")
+		errB := errors.New("b failed")
+		channels := []Channel{
+			Func("a", time.Second, func(_ context.Context, _ *Message) error { return errA }),
+			Func("b", time.Second, func(_ context.Context, _ *Message) error { return errB }),
+			Func("c", time.Second, func(_ context.Context, _ *Message) error { return nil }),
+		}
+		err := Dispatch(context.Background(), msg, channels)
+		if !errors.Is(err, errA) || !errors.Is(err, errB) {
+			t.Errorf("error = %v, want both channel errors", err)
+		}
+		if strings.Contains(err.Error(), "c:") {
+			t.Errorf("error = %v, should not mention successful channel", err)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy bounds redelivery of a failed notification.
+type RetryPolicy struct {
+	Attempts int           // Total attempts, including the first
+	Backoff  time.Duration // Delay before the first retry, doubled after each
+	Budget   time.Duration // Time allowed for all attempts together
+}
+
+// DefaultRetryPolicy retries remote deliveries briefly, so a flaky network
+// doesn't drop a notification but a dead endpoint doesn't stall the hook.
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts: 3,
+	Backoff:  500 * time.Millisecond,
+	Budget:   10 * time.Second,
+}
+
+// permanentError marks a failure that retrying won't fix.
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying, e.g. a rejected request or a
+// missing secret.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// statusError returns err for an HTTP error status, marked permanent for
+// client errors other than timeouts and rate limiting.
+func statusError(err error, status int) error {
+	if status >= 400 && status < 500 && status != http.StatusRequestTimeout && status != http.StatusTooManyRequests {
+		return Permanent(err)
+	}
+	return err
+}
+
+// retryChannel redelivers failed messages with exponential backoff.
+type retryChannel struct {
+	ch     Channel
+	policy RetryPolicy
+	logf   func(format string, args ...any)
+}
+
+// WithRetry wraps a channel so failed deliveries are retried under policy.
+// Each attempt keeps the channel's own timeout; the wrapper's timeout is
+// the policy's budget, or one attempt's timeout if that is longer. The
+// final outcome of a retried delivery is reported to logf.
+func WithRetry(ch Channel, policy RetryPolicy, logf func(format string, args ...any)) Channel {
+	if policy.Attempts <= 1 {
+		return ch
+	}
+	return &retryChannel{ch: ch, policy: policy, logf: logf}
+}
+
+func (r *retryChannel) Name() string           { return r.ch.Name() }
+func (r *retryChannel) Timeout() time.Duration { return max(r.policy.Budget, r.ch.Timeout()) }
+
+// Send delivers msg, retrying failures that aren't permanent while
+// attempts and budget remain.
+func (r *retryChannel) Send(ctx context.Context, msg *Message) error {
+	delay := r.policy.Backoff
+	for attempt := 1; ; attempt++ {
+		err := send(ctx, msg, r.ch)
+		if err == nil {
+			if attempt > 1 {
+				r.logf("%s delivered on attempt %d", r.ch.Name(), attempt)
+			}
+			return nil
+		}
+		if IsPermanent(err) {
+			return err
+		}
+		if attempt >= r.policy.Attempts || !r.wait(ctx, delay) {
+			r.logf("%s failed after %d attempts: %v", r.ch.Name(), attempt, err)
+			return err
+		}
+		r.logf("%s attempt %d failed: %v, retrying in %s", r.ch.Name(), attempt, err, delay)
+		delay *= 2
+	}
+}
+
+// wait sleeps for delay, reporting false if ctx ends first or its deadline
+// leaves no time for another attempt.
+func (r *retryChannel) wait(ctx context.Context, delay time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+		return false
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
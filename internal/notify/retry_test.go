@@ -0,0 +1,110 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithRetry(t *testing.T) {
+	policy := RetryPolicy{Attempts: 3, Backoff: time.Millisecond, Budget: time.Second}
+	tests := []struct {
+		name         string
+		failures     int
+		err          error
+		wantAttempts int
+		wantErr      bool
+	}{
+		{"first attempt succeeds", 0, nil, 1, false},
+		{"retried until success", 2, errors.New("connection refused"), 3, false},
+		{"attempts exhausted", 5, errors.New("connection refused"), 3, true},
+		{"permanent failure", 5, Permanent(errors.New("status 400")), 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			ch := Func("webhook", time.Second, func(context.Context, *Message) error {
+				attempts++
+				if attempts <= tt.failures {
+					return tt.err
+				}
+				return nil
+			})
+			var logs []string
+			logf := func(format string, args ...any) { logs = append(logs, fmt.Sprintf(format, args...)) }
+
+			err := WithRetry(ch, policy, logf).Send(context.Background(), NewMessage("stop"))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			if tt.wantAttempts > 1 && len(logs) == 0 {
+				t.Error("retried delivery should log its outcome")
+			}
+		})
+	}
+}
+
+func TestWithRetryBudget(t *testing.T) {
+	attempts := 0
+	ch := Func("bark", time.Second, func(context.Context, *Message) error {
+		attempts++
+		return errors.New("connection refused")
+	})
+	policy := RetryPolicy{Attempts: 5, Backoff: 40 * time.Millisecond, Budget: 100 * time.Millisecond}
+	retry := WithRetry(ch, policy, func(string, ...any) {})
+	if retry.Timeout() != time.Second {
+		t.Errorf("Timeout() = %s, want the channel's 1s", retry.Timeout())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), policy.Budget)
+	defer cancel()
+	if err := retry.Send(ctx, NewMessage("stop")); err == nil {
+		t.Fatal("Send() should fail")
+	}
+	// Waits of 40ms and 80ms don't both fit in 100ms
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+}
+
+func TestWithRetrySingleAttempt(t *testing.T) {
+	ch := Func("webhook", time.Second, func(context.Context, *Message) error { return nil })
+	if got := WithRetry(ch, RetryPolicy{Attempts: 1}, nil); got != ch {
+		t.Error("a single attempt should not wrap the channel")
+	}
+}
+
+func TestWebhookRetryStatus(t *testing.T) {
+	tests := []struct {
+		status       int
+		wantAttempts int32
+	}{
+		{http.StatusServiceUnavailable, 3},
+		{http.StatusTooManyRequests, 3},
+		{http.StatusNotFound, 1},
+	}
+	for _, tt := range tests {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(tt.status)
+		}))
+		policy := RetryPolicy{Attempts: 3, Backoff: time.Millisecond, Budget: time.Second}
+		err := WithRetry(NewWebhook(server.URL, nil, time.Second), policy, func(string, ...any) {}).Send(context.Background(), NewMessage("stop"))
+		server.Close()
+		if err == nil {
+			t.Errorf("status %d: Send() should fail", tt.status)
+		}
+		if got := attempts.Load(); got != tt.wantAttempts {
+			t.Errorf("status %d: attempts = %d, want %d", tt.status, got, tt.wantAttempts)
+		}
+	}
+}
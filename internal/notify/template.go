@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"text/template"
+	"time"
+)
+
+// templateData is what webhook and push templates render.
+type templateData struct {
+	Event     string
+	Title     string
+	Message   string
+	Project   string
+	Branch    string
+	Hostname  string
+	Icon      string
+	Severity  string
+	Timestamp time.Time
+	Payload   map[string]any // Hook payload fields, e.g. .Payload.session_id
+}
+
+// render executes a message template. A payload that isn't a JSON object
+// leaves .Payload empty.
+func render(tmpl *template.Template, msg *Message) ([]byte, error) {
+	data := templateData{
+		Event:     msg.Event,
+		Title:     msg.Title,
+		Message:   msg.Body,
+		Project:   msg.Project,
+		Branch:    msg.Branch,
+		Hostname:  msg.Hostname,
+		Icon:      msg.Icon,
+		Severity:  msg.Severity,
+		Timestamp: msg.Timestamp,
+	}
+	if len(msg.Payload) > 0 {
+		_ = json.Unmarshal(msg.Payload, &data.Payload)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,107 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Terminal notification escape sequences.
+const (
+	SequenceOSC9   = "osc9"   // iTerm2, kitty, WezTerm, Windows Terminal
+	SequenceOSC777 = "osc777" // foot, rxvt-unicode, Ghostty, VTE
+)
+
+// openTTY opens the controlling terminal. Replaceable in tests.
+var openTTY = func() (io.WriteCloser, error) {
+	return os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+}
+
+// Terminal writes notification escape sequences to the controlling terminal,
+// so terminals (including over SSH) show their own native notification.
+type Terminal struct {
+	sequence string
+	timeout  time.Duration
+}
+
+// NewTerminal creates a terminal channel emitting the given sequence
+// (SequenceOSC9 if empty).
+func NewTerminal(sequence string, timeout time.Duration) *Terminal {
+	if sequence == "" {
+		sequence = SequenceOSC9
+	}
+	return &Terminal{sequence: sequence, timeout: timeout}
+}
+
+// Name returns the channel name.
+func (t *Terminal) Name() string { return "terminal" }
+
+// Timeout returns the delivery timeout.
+func (t *Terminal) Timeout() time.Duration { return t.timeout }
+
+// Send writes the escape sequence to the terminal.
+func (t *Terminal) Send(_ context.Context, msg *Message) error {
+	seq, err := terminalSequence(t.sequence, msg)
+	if err != nil {
+		return err
+	}
+	return writeTTY(seq)
+}
+
+// terminalSequence builds the escape sequence for msg.
+func terminalSequence(sequence string, msg *Message) (string, error) {
+	title, body := stripControl(msg.Title), stripControl(msg.Body)
+	switch sequence {
+	case SequenceOSC9:
+		return "\x1b]9;" + title + ": " + body + "\x07", nil
+	case SequenceOSC777:
+		// Fields are ';'-separated, so the title can't contain one
+		return "\x1b]777;notify;" + strings.ReplaceAll(title, ";", ",") + ";" + body + "\x07", nil
+	default:
+		return "", fmt.Errorf("unknown terminal sequence: %s", sequence)
+	}
+}
+
+// writeTTY writes seq to the controlling terminal, wrapping it for tmux
+// passthrough when running inside tmux.
+func writeTTY(seq string) error {
+	if os.Getenv("TMUX") != "" {
+		seq = "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+	tty, err := openTTY()
+	if err != nil {
+		return fmt.Errorf("no controlling terminal: %w", err)
+	}
+	defer tty.Close()
+	if _, err := io.WriteString(tty, seq); err != nil {
+		return fmt.Errorf("failed to write to terminal: %w", err)
+	}
+	return nil
+}
+
+// Bell rings the controlling terminal's bell.
+func Bell() error {
+	tty, err := openTTY()
+	if err != nil {
+		return fmt.Errorf("no controlling terminal: %w", err)
+	}
+	defer tty.Close()
+	if _, err := io.WriteString(tty, "\a"); err != nil {
+		return fmt.Errorf("failed to write to terminal: %w", err)
+	}
+	return nil
+}
+
+// stripControl removes control characters that would end or corrupt an
+// escape sequence.
+func stripControl(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || (r >= 0x80 && r < 0xa0) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+// nopCloser adapts a buffer to io.WriteCloser.
+type nopCloser struct{ *bytes.Buffer }
+
+func (nopCloser) Close() error { return nil }
+
+// fakeTTY captures terminal output for the duration of a test.
+func fakeTTY(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	orig := openTTY
+	openTTY = func() (io.WriteCloser, error) { return nopCloser{buf}, nil }
+	t.Cleanup(func() { openTTY = orig })
+
+	origTmux := os.Getenv("TMUX")
+	os.Unsetenv("TMUX")
+	t.Cleanup(func() { os.Setenv("TMUX", origTmux) })
+	return buf
+}
+
+func TestTerminalSend(t *testing.T) {
+	msg := &Message{Event: "stop", Title: "Claude; Code", Body: "done\x1b]0;evil\x07"}
+	tests := []struct {
+		sequence string
+		want     string
+	}{
+		{"", "\x1b]9;Claude; Code: done]0;evil\x07"},
+		{SequenceOSC9, "\x1b]9;Claude; Code: done]0;evil\x07"},
+		{SequenceOSC777, "\x1b]777;notify;Claude, Code;done]0;evil\x07"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sequence, func(t *testing.T) {
+			buf := fakeTTY(t)
+			term := NewTerminal(tt.sequence, time.Second)
+			if term.Name() != "terminal" {
+				t.Errorf("Name() = %q, want terminal", term.Name())
+			}
+			if err := term.Send(context.Background(), msg); err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerminalSendTmux(t *testing.T) {
+	buf := fakeTTY(t)
+	os.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+
+	if err := NewTerminal(SequenceOSC9, time.Second).Send(context.Background(), &Message{Title: "T", Body: "B"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	want := "\x1bPtmux;\x1b\x1b]9;T: B\x07\x1b\\"
+	if got := buf.String(); got != want {
+		t.Errorf("wrote %q, want %q", got, want)
+	}
+}
+
+func TestTerminalSendErrors(t *testing.T) {
+	fakeTTY(t)
+	if err := NewTerminal("osc99", time.Second).Send(context.Background(), NewMessage("stop")); err == nil {
+		t.Error("expected error for unknown sequence")
+	}
+
+	openTTY = func() (io.WriteCloser, error) { return nil, errors.New("no tty") }
+	if err := NewTerminal(SequenceOSC9, time.Second).Send(context.Background(), NewMessage("stop")); err == nil {
+		t.Error("expected error without a terminal")
+	}
+}
+
+func TestBell(t *testing.T) {
+	buf := fakeTTY(t)
+	if err := Bell(); err != nil {
+		t.Fatalf("Bell() error = %v", err)
+	}
+	if buf.String() != "\a" {
+		t.Errorf("wrote %q, want BEL", buf.String())
+	}
+}
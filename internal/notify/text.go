@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SpeakTimeout bounds the text channel when it also speaks the message.
+const SpeakTimeout = 15 * time.Second
+
+// Text writes a concise plain-text status line to the terminal, for screen
+// readers, and can speak it through the platform's speech service (say on
+// macOS, speech-dispatcher's spd-say on Linux).
+type Text struct {
+	speak   bool
+	timeout time.Duration
+}
+
+// NewText creates a text channel.
+func NewText(speak bool, timeout time.Duration) *Text {
+	return &Text{speak: speak, timeout: timeout}
+}
+
+// Name returns the channel name.
+func (t *Text) Name() string { return "text" }
+
+// Timeout returns the delivery timeout.
+func (t *Text) Timeout() time.Duration { return t.timeout }
+
+// Send writes the status line and speaks it if enabled.
+func (t *Text) Send(ctx context.Context, msg *Message) error {
+	line := stripControl(msg.Title + ": " + msg.Body)
+	if color, ok := severityColors[msg.Severity]; ok {
+		line = color + line + "\x1b[0m"
+	}
+	if err := writeTTY(fmt.Sprintf("\r\n[%s] %s\r\n", msg.Timestamp.Format("15:04"), line)); err != nil {
+		return err
+	}
+	if !t.speak {
+		return nil
+	}
+	return speak(ctx, stripControl(msg.Body))
+}
+
+// speak reads text aloud with the platform's speech command.
+func speak(ctx context.Context, text string) error {
+	var name string
+	switch goos {
+	case "darwin":
+		name = "say"
+	case "linux":
+		name = "spd-say"
+	default:
+		return fmt.Errorf("speech not supported on %s", goos)
+	}
+	if _, err := lookPath(name); err != nil {
+		return fmt.Errorf("%s not found", name)
+	}
+	args := []string{text}
+	if name == "spd-say" {
+		args = []string{"--wait", text} // Return once spoken, like say
+	}
+	if err := execCommandContext(ctx, name, args...).Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTextSend(t *testing.T) {
+	tests := []struct {
+		event    string
+		severity string
+		want     string
+	}{
+		{"stop", "", "\r\n[09:05] Claude Code: Claude finished responding\r\n"},
+		{"permission_prompt", "", "\r\n[09:05] \x1b[33mClaude Code: Claude needs your permission\x1b[0m\r\n"},
+		{"idle_prompt", SeverityCritical, "\r\n[09:05] \x1b[1;31mClaude Code: Claude is waiting for your input\x1b[0m\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.event, func(t *testing.T) {
+			buf := fakeTTY(t)
+			msg := NewMessage(tt.event)
+			msg.Timestamp = time.Date(2026, 1, 2, 9, 5, 0, 0, time.Local)
+			if tt.severity != "" {
+				msg.Severity = tt.severity
+			}
+
+			text := NewText(false, time.Second)
+			if text.Name() != "text" {
+				t.Errorf("Name() = %q, want text", text.Name())
+			}
+			if err := text.Send(context.Background(), msg); err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextSpeak(t *testing.T) {
+	tests := []struct {
+		goos string
+		tool string
+		want []string
+	}{
+		{"darwin", "say", []string{"say", "Claude finished responding"}},
+		{"linux", "spd-say", []string{"spd-say", "--wait", "Claude finished responding"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			fakeTTY(t)
+			fakeDesktopEnv(t, tt.goos, tt.tool)
+			var args []string
+			orig := execCommandContext
+			execCommandContext = fakeExecCommandContext(&args)
+			defer func() { execCommandContext = orig }()
+
+			if err := NewText(true, time.Second).Send(context.Background(), NewMessage("stop")); err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+			if !slices.Equal(args, tt.want) {
+				t.Errorf("command = %v, want %v", args, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextSpeakNotInstalled(t *testing.T) {
+	fakeTTY(t)
+	fakeDesktopEnv(t, "linux")
+	if err := NewText(true, time.Second).Send(context.Background(), NewMessage("stop")); err == nil {
+		t.Error("expected error without spd-say")
+	}
+}
@@ -0,0 +1,57 @@
+package notify
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DefaultUserVar is the terminal user variable set to the event type.
+const DefaultUserVar = "ccbell_event"
+
+// UserVar sets a terminal user variable to the event type so terminal
+// configs can react to it (tab color, badge, etc.). kitty is updated through
+// remote control; other terminals get an OSC 1337 SetUserVar sequence
+// (WezTerm, iTerm2).
+type UserVar struct {
+	name    string
+	timeout time.Duration
+}
+
+// NewUserVar creates a user-var channel for variable name (DefaultUserVar if
+// empty).
+func NewUserVar(name string, timeout time.Duration) *UserVar {
+	if name == "" {
+		name = DefaultUserVar
+	}
+	return &UserVar{name: name, timeout: timeout}
+}
+
+// Name returns the channel name.
+func (u *UserVar) Name() string { return "uservar" }
+
+// Timeout returns the delivery timeout.
+func (u *UserVar) Timeout() time.Duration { return u.timeout }
+
+// Send sets the user variable to the message's event type.
+func (u *UserVar) Send(ctx context.Context, msg *Message) error {
+	if windowID := os.Getenv("KITTY_WINDOW_ID"); windowID != "" {
+		if _, err := lookPath("kitten"); err == nil {
+			cmd := execCommandContext(ctx, "kitten", "@", "set-user-vars",
+				"--match", "id:"+windowID, u.name+"="+msg.Event)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				return fmt.Errorf("kitten @ set-user-vars failed (is allow_remote_control enabled?): %w: %s", err, out)
+			}
+			return nil
+		}
+	}
+	return writeTTY(userVarSequence(u.name, msg.Event))
+}
+
+// userVarSequence builds an OSC 1337 SetUserVar sequence; the value is
+// base64-encoded as the protocol requires.
+func userVarSequence(name, value string) string {
+	return "\x1b]1337;SetUserVar=" + stripControl(name) + "=" + base64.StdEncoding.EncodeToString([]byte(value)) + "\x07"
+}
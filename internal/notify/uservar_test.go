@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestUserVarSend(t *testing.T) {
+	buf := fakeTTY(t)
+	origKitty := os.Getenv("KITTY_WINDOW_ID")
+	os.Unsetenv("KITTY_WINDOW_ID")
+	defer os.Setenv("KITTY_WINDOW_ID", origKitty)
+
+	u := NewUserVar("", time.Second)
+	if u.Name() != "uservar" {
+		t.Errorf("Name() = %q, want uservar", u.Name())
+	}
+	if err := u.Send(context.Background(), NewMessage("stop")); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	want := "\x1b]1337;SetUserVar=ccbell_event=c3RvcA==\x07"
+	if got := buf.String(); got != want {
+		t.Errorf("wrote %q, want %q", got, want)
+	}
+}
+
+func TestUserVarSendKitty(t *testing.T) {
+	buf := fakeTTY(t)
+	fakeDesktopEnv(t, "linux", "kitten")
+	origKitty := os.Getenv("KITTY_WINDOW_ID")
+	os.Setenv("KITTY_WINDOW_ID", "7")
+	defer os.Setenv("KITTY_WINDOW_ID", origKitty)
+
+	var args []string
+	orig := execCommandContext
+	execCommandContext = fakeExecCommandContext(&args)
+	defer func() { execCommandContext = orig }()
+
+	if err := NewUserVar("claude", time.Second).Send(context.Background(), NewMessage("permission_prompt")); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	want := []string{"kitten", "@", "set-user-vars", "--match", "id:7", "claude=permission_prompt"}
+	if len(args) != len(want) {
+		t.Fatalf("command = %v, want %v", args, want)
+	}
+	for i := range want {
+		if args[i] != want[i] {
+			t.Errorf("arg %d = %q, want %q", i, args[i], want[i])
+		}
+	}
+	if buf.Len() != 0 {
+		t.Errorf("unexpected terminal output %q", buf.String())
+	}
+}
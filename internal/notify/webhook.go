@@ -0,0 +1,166 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"text/template"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/secret"
+)
+
+// Webhook payload presets.
+const (
+	PresetIFTTT  = "ifttt"  // IFTTT Webhooks: value1..value3
+	PresetZapier = "zapier" // Zapier catch hook: flat fields incl. one-line text
+)
+
+// Webhook POSTs notifications as JSON to an HTTP endpoint.
+type Webhook struct {
+	url     string
+	headers map[string]string
+	preset  string
+	body    *template.Template
+	timeout time.Duration
+	client  *http.Client
+}
+
+// NewWebhook creates a webhook channel. The URL and header values may be
+// "secret:<name>" references resolved from the OS keychain at send time.
+func NewWebhook(url string, headers map[string]string, timeout time.Duration) *Webhook {
+	return &Webhook{
+		url:     url,
+		headers: headers,
+		timeout: timeout,
+		client:  &http.Client{},
+	}
+}
+
+// SetPreset selects the payload shape expected by a service (PresetIFTTT,
+// PresetZapier); empty sends the message as-is.
+func (w *Webhook) SetPreset(preset string) {
+	w.preset = preset
+}
+
+// SetTemplate sets a template rendering the JSON request body, replacing
+// the preset's shape. Nil restores the preset.
+func (w *Webhook) SetTemplate(tmpl *template.Template) {
+	w.body = tmpl
+}
+
+// Name returns the channel name.
+func (w *Webhook) Name() string { return "webhook" }
+
+// Timeout returns the delivery timeout.
+func (w *Webhook) Timeout() time.Duration { return w.timeout }
+
+// Send POSTs the message as JSON.
+func (w *Webhook) Send(ctx context.Context, msg *Message) error {
+	target, err := secret.Resolve(w.url)
+	if err != nil {
+		return Permanent(fmt.Errorf("webhook url: %w", err))
+	}
+
+	payload, err := w.payload(msg)
+	if err != nil {
+		return Permanent(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(payload))
+	if err != nil {
+		return Permanent(fmt.Errorf("invalid webhook request: %w", withoutURL(err)))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ccbell")
+	for key, value := range w.headers {
+		resolved, err := secret.Resolve(value)
+		if err != nil {
+			return Permanent(fmt.Errorf("webhook header %s: %w", key, err))
+		}
+		req.Header.Set(key, resolved)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request to %s failed: %w", redactURL(target), withoutURL(err))
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return statusError(fmt.Errorf("webhook returned status %d", resp.StatusCode), resp.StatusCode)
+	}
+	return nil
+}
+
+// withoutURL strips the URL from a request error. Webhook URLs often
+// carry a token, possibly resolved from the keychain, and errors end up in
+// the log and on stderr.
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+// redactURL returns just the scheme and host of rawURL, or a placeholder
+// if it doesn't parse.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "the configured URL"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// payload returns the request body: the rendered template, or the preset's
+// JSON encoding of msg.
+func (w *Webhook) payload(msg *Message) ([]byte, error) {
+	if w.body == nil {
+		payload, err := json.Marshal(webhookPayload(w.preset, msg))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode payload: %w", err)
+		}
+		return payload, nil
+	}
+	payload, err := render(w.body, msg)
+	if err != nil {
+		return nil, fmt.Errorf("webhook template: %w", err)
+	}
+	if !json.Valid(payload) {
+		return nil, fmt.Errorf("webhook template produced invalid JSON: %s", payload)
+	}
+	return payload, nil
+}
+
+// webhookPayload returns the request body for a preset.
+func webhookPayload(preset string, msg *Message) any {
+	switch preset {
+	case PresetIFTTT:
+		return map[string]string{
+			"value1": msg.Title,
+			"value2": msg.Body,
+			"value3": msg.Event,
+		}
+	case PresetZapier:
+		return map[string]string{
+			"event":     msg.Event,
+			"title":     msg.Title,
+			"message":   msg.Body,
+			"text":      msg.Title + ": " + msg.Body,
+			"hostname":  msg.Hostname,
+			"project":   msg.Project,
+			"branch":    msg.Branch,
+			"timestamp": msg.Timestamp.Format(time.RFC3339),
+		}
+	default:
+		return msg
+	}
+}
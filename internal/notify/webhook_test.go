@@ -0,0 +1,178 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+)
+
+func TestWebhookSend(t *testing.T) {
+	var got Message
+	var gotHeader, gotContentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		gotHeader = r.Header.Get("X-Token")
+		gotContentType = r.Header.Get("Content-Type")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	w := NewWebhook(server.URL, map[string]string{"X-Token": "abc"}, time.Second)
+	if w.Name() != "webhook" {
+		t.Errorf("Name() = %q, want webhook", w.Name())
+	}
+	if err := w.Send(context.Background(), NewMessage("stop")); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if got.Event != "stop" || got.Body != "Claude finished responding" {
+		t.Errorf("payload = %+v", got)
+	}
+	if gotHeader != "abc" {
+		t.Errorf("X-Token = %q, want abc", gotHeader)
+	}
+	if gotContentType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", gotContentType)
+	}
+}
+
+func TestWebhookSendErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	w := NewWebhook(server.URL, nil, time.Second)
+	err := w.Send(context.Background(), NewMessage("stop"))
+	if err == nil || !strings.Contains(err.Error(), "status 500") {
+		t.Errorf("Send() error = %v, want status 500", err)
+	}
+}
+
+func TestWebhookSendCanceled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	w := NewWebhook(server.URL, nil, time.Second)
+	if err := w.Send(ctx, NewMessage("stop")); err == nil {
+		t.Error("Send() should fail when the context expires")
+	}
+}
+
+func TestWebhookSendErrorHidesURL(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	addr := server.Listener.Addr().String()
+	server.Close() // Connections are refused
+
+	w := NewWebhook("http://user:pass@"+addr+"/hooks/t0ken?key=s3cret", nil, time.Second)
+	err := w.Send(context.Background(), NewMessage("stop"))
+	if err == nil {
+		t.Fatal("Send() should fail when the connection is refused")
+	}
+	for _, secret := range []string{"t0ken", "s3cret", "pass"} {
+		if strings.Contains(err.Error(), secret) {
+			t.Errorf("Send() error %q contains %q", err, secret)
+		}
+	}
+	if !strings.Contains(err.Error(), "http://"+addr) {
+		t.Errorf("Send() error %q doesn't name the host", err)
+	}
+}
+
+func TestWebhookPresets(t *testing.T) {
+	tests := []struct {
+		preset string
+		want   map[string]string
+	}{
+		{PresetIFTTT, map[string]string{
+			"value1": "Claude Code",
+			"value2": "Claude needs your permission",
+			"value3": "permission_prompt",
+		}},
+		{PresetZapier, map[string]string{
+			"event":    "permission_prompt",
+			"title":    "Claude Code",
+			"message":  "Claude needs your permission",
+			"text":     "Claude Code: Claude needs your permission",
+			"hostname": "devbox",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.preset, func(t *testing.T) {
+			var got map[string]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("invalid payload: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			w := NewWebhook(server.URL, nil, time.Second)
+			w.SetPreset(tt.preset)
+			msg := NewMessage("permission_prompt")
+			msg.Hostname = "devbox"
+			if err := w.Send(context.Background(), msg); err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+			for key, want := range tt.want {
+				if got[key] != want {
+					t.Errorf("%s = %q, want %q", key, got[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookTemplate(t *testing.T) {
+	var got []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	msg := NewMessage("stop")
+	msg.Project = `my "app"`
+	msg.Payload = json.RawMessage(`{"session_id": "abc123"}`)
+
+	tmpl, err := config.ParseTemplate("webhook.template", `{"text": {{json .Message}}, "project": {{json .Project}}, "session": "{{.Payload.session_id}}", "missing": {{json .Payload.nope}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWebhook(server.URL, nil, time.Second)
+	w.SetPreset(PresetIFTTT) // The template wins
+	w.SetTemplate(tmpl)
+	if err := w.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	want := `{"text": "Claude finished responding", "project": "my \"app\"", "session": "abc123", "missing": null}`
+	if string(got) != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+
+	// Output that isn't JSON is a configuration problem, not worth retrying
+	tmpl, _ = config.ParseTemplate("webhook.template", `text={{.Message}}`)
+	w.SetTemplate(tmpl)
+	if err := w.Send(context.Background(), msg); !IsPermanent(err) {
+		t.Errorf("Send() error = %v, want a permanent error", err)
+	}
+}
@@ -0,0 +1,51 @@
+package pack
+
+import (
+	"os"
+	"strings"
+)
+
+// MatchLanguage picks the best of the available languages for want: an exact
+// match ("pt-BR"), then the same base language ("pt"), then the first
+// available. Returns "" if none are available.
+func MatchLanguage(want string, available []string) string {
+	if len(available) == 0 {
+		return ""
+	}
+	want = normalizeLanguage(want)
+	base, _, _ := strings.Cut(want, "-")
+	for _, lang := range available {
+		if strings.EqualFold(lang, want) {
+			return lang
+		}
+	}
+	for _, lang := range available {
+		langBase, _, _ := strings.Cut(lang, "-")
+		if base != "" && strings.EqualFold(langBase, base) {
+			return lang
+		}
+	}
+	return available[0]
+}
+
+// SystemLanguage returns the user's language from the locale environment
+// (LC_ALL, LC_MESSAGES, LANG), e.g. "tr-TR", or "" if unset.
+func SystemLanguage() string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if lang := normalizeLanguage(os.Getenv(key)); lang != "" {
+			return lang
+		}
+	}
+	return ""
+}
+
+// normalizeLanguage converts a locale such as "tr_TR.UTF-8" to "tr-TR".
+// The C and POSIX locales carry no language.
+func normalizeLanguage(locale string) string {
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	if locale == "C" || locale == "POSIX" {
+		return ""
+	}
+	return strings.ReplaceAll(locale, "_", "-")
+}
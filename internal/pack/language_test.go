@@ -0,0 +1,58 @@
+package pack
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMatchLanguage(t *testing.T) {
+	tests := []struct {
+		want      string
+		available []string
+		expected  string
+	}{
+		{"pt-BR", []string{"pt-PT", "pt-BR"}, "pt-BR"},
+		{"pt_BR.UTF-8", []string{"en", "pt-PT"}, "pt-PT"},
+		{"tr", []string{"en", "TR"}, "TR"},
+		{"de", []string{"en", "tr"}, "en"},
+		{"", []string{"en"}, "en"},
+		{"en", nil, ""},
+	}
+
+	for _, tt := range tests {
+		if got := MatchLanguage(tt.want, tt.available); got != tt.expected {
+			t.Errorf("MatchLanguage(%q, %v) = %q, want %q", tt.want, tt.available, got, tt.expected)
+		}
+	}
+}
+
+func TestSystemLanguage(t *testing.T) {
+	keys := []string{"LC_ALL", "LC_MESSAGES", "LANG"}
+	for _, key := range keys {
+		orig := os.Getenv(key)
+		defer os.Setenv(key, orig)
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"lang", map[string]string{"LANG": "tr_TR.UTF-8"}, "tr-TR"},
+		{"lc_all wins", map[string]string{"LC_ALL": "de_DE", "LANG": "en_US.UTF-8"}, "de-DE"},
+		{"modifier", map[string]string{"LANG": "sr_RS@latin"}, "sr-RS"},
+		{"posix", map[string]string{"LC_ALL": "C", "LANG": "fr_FR.UTF-8"}, "fr-FR"},
+		{"unset", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range keys {
+				os.Setenv(key, tt.env[key])
+			}
+			if got := SystemLanguage(); got != tt.want {
+				t.Errorf("SystemLanguage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
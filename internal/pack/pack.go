@@ -0,0 +1,221 @@
+// Package pack loads sound packs: directories of sounds described by a
+// pack.json manifest, installed under ~/.claude/ccbell/packs/<id>.
+package pack
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ManifestFile is the manifest file name inside a pack directory.
+const ManifestFile = "pack.json"
+
+// SpecPrefix prefixes pack sound specs: "pack:<id>:<event>", or "pack:<id>"
+// for the pack's sound for whichever event fires.
+const SpecPrefix = "pack:"
+
+// ErrNotFound is returned when a pack or pack sound doesn't exist.
+var ErrNotFound = errors.New("not found")
+
+// idRegex validates pack IDs, which are also directory names.
+var idRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Manifest describes a pack.
+type Manifest struct {
+	Name        string `json:"name"`
+	Version     string `json:"version,omitempty"`
+	Author      string `json:"author,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Languages lists the languages of a voice pack; the first is the
+	// default when the preferred language isn't available.
+	Languages []string `json:"languages,omitempty"`
+
+	Sounds    map[string]*Sound            `json:"sounds"`              // Event -> sound
+	Localized map[string]map[string]*Sound `json:"localized,omitempty"` // Language -> event -> sound
+}
+
+// Sound is a pack sound file, relative to the pack directory.
+type Sound struct {
+	File string  `json:"file"`
+	Gain float64 `json:"gain,omitempty"` // dB
+}
+
+// UnmarshalJSON accepts a sound either as a file name or as an object.
+func (s *Sound) UnmarshalJSON(data []byte) error {
+	var file string
+	if err := json.Unmarshal(data, &file); err == nil {
+		s.File = file
+		return nil
+	}
+	type soundJSON Sound
+	return json.Unmarshal(data, (*soundJSON)(s))
+}
+
+// Pack is an installed pack.
+type Pack struct {
+	ID  string
+	Dir string
+	Manifest
+}
+
+// Dir returns the packs directory for a home directory.
+func Dir(homeDir string) string {
+	return filepath.Join(homeDir, ".claude", "ccbell", "packs")
+}
+
+// ParseSpec splits a "pack:<id>:<event>" sound spec. The event is empty for
+// a "pack:<id>" alias, which plays the sound for the event being notified.
+func ParseSpec(spec string) (id, event string, ok bool) {
+	rest, found := strings.CutPrefix(spec, SpecPrefix)
+	if !found {
+		return "", "", false
+	}
+	id, event, found = strings.Cut(rest, ":")
+	if id == "" || (found && event == "") {
+		return "", "", false
+	}
+	return id, event, true
+}
+
+// ValidID reports whether id is a valid pack ID.
+func ValidID(id string) bool {
+	return idRegex.MatchString(id)
+}
+
+// Spec returns the sound spec for an event of pack id.
+func Spec(id, event string) string {
+	return SpecPrefix + id + ":" + event
+}
+
+// Load reads and validates the pack id from packsDir.
+func Load(packsDir, id string) (*Pack, error) {
+	if !idRegex.MatchString(id) {
+		return nil, fmt.Errorf("invalid pack id: %s", id)
+	}
+	dir := filepath.Join(packsDir, id)
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("pack %s: %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to read pack %s: %w", id, err)
+	}
+
+	p := &Pack{ID: id, Dir: dir}
+	if err := json.Unmarshal(data, &p.Manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest for pack %s: %w", id, err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("pack %s: %w", id, err)
+	}
+	return p, nil
+}
+
+// Remove deletes the pack id from packsDir. The pack needn't have a valid
+// manifest, so broken packs can be removed too.
+func Remove(packsDir, id string) error {
+	if !idRegex.MatchString(id) {
+		return fmt.Errorf("invalid pack id: %s", id)
+	}
+	dir := filepath.Join(packsDir, id)
+	if _, err := os.Stat(dir); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("pack %s: %w", id, ErrNotFound)
+		}
+		return fmt.Errorf("failed to remove pack %s: %w", id, err)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove pack %s: %w", id, err)
+	}
+	return nil
+}
+
+// List returns the valid packs installed in packsDir, sorted by ID. A missing
+// directory yields no packs.
+func List(packsDir string) ([]*Pack, error) {
+	entries, err := os.ReadDir(packsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list packs: %w", err)
+	}
+
+	var packs []*Pack
+	for _, entry := range entries {
+		if !entry.IsDir() || !idRegex.MatchString(entry.Name()) {
+			continue
+		}
+		if p, err := Load(packsDir, entry.Name()); err == nil {
+			packs = append(packs, p)
+		}
+	}
+	sort.Slice(packs, func(i, j int) bool { return packs[i].ID < packs[j].ID })
+	return packs, nil
+}
+
+// Validate checks that sound files stay inside the pack directory and that
+// localized sounds use a declared language.
+func (m *Manifest) Validate() error {
+	if m.Name == "" {
+		return errors.New("manifest name is required")
+	}
+	for event, sound := range m.Sounds {
+		if err := sound.validate(); err != nil {
+			return fmt.Errorf("sound %s: %w", event, err)
+		}
+	}
+	for lang, sounds := range m.Localized {
+		if !slices.Contains(m.Languages, lang) {
+			return fmt.Errorf("localized sounds for undeclared language: %s", lang)
+		}
+		for event, sound := range sounds {
+			if err := sound.validate(); err != nil {
+				return fmt.Errorf("sound %s (%s): %w", event, lang, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks a sound's file path.
+func (s *Sound) validate() error {
+	if s == nil || s.File == "" {
+		return errors.New("file is required")
+	}
+	if !filepath.IsLocal(s.File) {
+		return fmt.Errorf("file must be a relative path inside the pack: %s", s.File)
+	}
+	return nil
+}
+
+// Resolve returns the file and gain of the pack's sound for an event in the
+// preferred language, falling back to the pack's default language and then
+// its language-independent sounds.
+func (p *Pack) Resolve(event, language string) (string, float64, error) {
+	sound := p.lookup(event, language)
+	if sound == nil {
+		return "", 0, fmt.Errorf("pack %s has no sound for %s: %w", p.ID, event, ErrNotFound)
+	}
+	return filepath.Join(p.Dir, sound.File), sound.Gain, nil
+}
+
+// lookup finds the sound for an event.
+func (p *Pack) lookup(event, language string) *Sound {
+	if len(p.Localized) > 0 {
+		if lang := MatchLanguage(language, p.Languages); lang != "" {
+			if sound := p.Localized[lang][event]; sound != nil {
+				return sound
+			}
+		}
+	}
+	return p.Sounds[event]
+}
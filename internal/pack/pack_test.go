@@ -0,0 +1,163 @@
+package pack
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writePack creates a pack directory with the given manifest.
+func writePack(t *testing.T, packsDir, id, manifest string) {
+	t.Helper()
+	dir := filepath.Join(packsDir, id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+const voiceManifest = `{
+	"name": "Voice",
+	"languages": ["en", "tr"],
+	"sounds": {"stop": "stop.wav", "subagent": {"file": "sub.wav", "gain": -3}},
+	"localized": {
+		"en": {"stop": "en/stop.mp3", "permission_prompt": "en/permission.mp3"},
+		"tr": {"stop": {"file": "tr/stop.mp3", "gain": 2}}
+	}
+}`
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		spec      string
+		wantID    string
+		wantEvent string
+		wantOK    bool
+	}{
+		{"pack:retro:stop", "retro", "stop", true},
+		{"pack:retro", "retro", "", true},
+		{"pack:retro:", "", "", false},
+		{"pack:", "", "", false},
+		{"pack::stop", "", "", false},
+		{"bundled:stop", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			id, event, ok := ParseSpec(tt.spec)
+			if id != tt.wantID || event != tt.wantEvent || ok != tt.wantOK {
+				t.Errorf("ParseSpec(%q) = %q, %q, %v", tt.spec, id, event, ok)
+			}
+		})
+	}
+
+	if got := Spec("retro", "stop"); got != "pack:retro:stop" {
+		t.Errorf("Spec() = %q", got)
+	}
+}
+
+func TestLoadAndResolve(t *testing.T) {
+	packsDir, err := os.MkdirTemp("", "ccbell-pack-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(packsDir)
+	writePack(t, packsDir, "voice", voiceManifest)
+
+	p, err := Load(packsDir, "voice")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		event    string
+		language string
+		wantFile string
+		wantGain float64
+	}{
+		{"exact language", "stop", "tr", "tr/stop.mp3", 2},
+		{"base language", "stop", "tr-TR", "tr/stop.mp3", 2},
+		{"default language", "permission_prompt", "de", "en/permission.mp3", 0},
+		{"language-independent fallback", "subagent", "tr", "sub.wav", -3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, gain, err := p.Resolve(tt.event, tt.language)
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if want := filepath.Join(packsDir, "voice", tt.wantFile); path != want || gain != tt.wantGain {
+				t.Errorf("Resolve() = %q, %v, want %q, %v", path, gain, want, tt.wantGain)
+			}
+		})
+	}
+
+	if _, _, err := p.Resolve("idle_prompt", "en"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Resolve(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	packsDir, err := os.MkdirTemp("", "ccbell-pack-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(packsDir)
+
+	writePack(t, packsDir, "escape", `{"name": "Escape", "sounds": {"stop": "../../secret.wav"}}`)
+	writePack(t, packsDir, "nameless", `{"sounds": {"stop": "stop.wav"}}`)
+	writePack(t, packsDir, "undeclared", `{"name": "U", "localized": {"fr": {"stop": "fr.wav"}}}`)
+	writePack(t, packsDir, "broken", `{`)
+
+	tests := []struct {
+		id       string
+		notFound bool
+	}{
+		{"missing", true},
+		{"../etc", false},
+		{"escape", false},
+		{"nameless", false},
+		{"undeclared", false},
+		{"broken", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			_, err := Load(packsDir, tt.id)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if errors.Is(err, ErrNotFound) != tt.notFound {
+				t.Errorf("Load() error = %v, notFound %v", err, tt.notFound)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	packsDir, err := os.MkdirTemp("", "ccbell-pack-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(packsDir)
+
+	if packs, err := List(filepath.Join(packsDir, "none")); err != nil || len(packs) != 0 {
+		t.Errorf("List(missing) = %v, %v", packs, err)
+	}
+
+	writePack(t, packsDir, "zen", `{"name": "Zen", "sounds": {"stop": "stop.wav"}}`)
+	writePack(t, packsDir, "retro", `{"name": "Retro", "sounds": {"stop": "stop.wav"}}`)
+	writePack(t, packsDir, "broken", `{`)
+
+	packs, err := List(packsDir)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(packs) != 2 || packs[0].ID != "retro" || packs[1].ID != "zen" {
+		t.Errorf("List() = %v, want retro and zen", packs)
+	}
+}
@@ -0,0 +1,143 @@
+// Package secret stores and retrieves ccbell secrets (webhook URLs, push
+// tokens) in the OS keychain instead of plaintext config.
+package secret
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// Service is the keychain service name under which ccbell secrets are stored.
+const Service = "ccbell"
+
+// RefPrefix marks a config string value as a reference to a stored secret.
+const RefPrefix = "secret:"
+
+// ErrNotFound is returned when a secret does not exist in the keychain.
+var ErrNotFound = errors.New("secret not found")
+
+// nameRegex validates secret names.
+var nameRegex = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// execCommand is the command constructor, replaceable in tests.
+var execCommand = exec.Command
+
+// ValidateName returns an error if the secret name is invalid.
+func ValidateName(name string) error {
+	if !nameRegex.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q: use letters, digits, '.', '_' or '-'", name)
+	}
+	return nil
+}
+
+// Set stores a secret value under name, replacing any existing value.
+func Set(name, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		// security's interactive mode reads the command from stdin, so the
+		// value never shows in the process list
+		if strings.ContainsAny(value, "\r\n") {
+			return errors.New("secret values can't contain line breaks on macOS")
+		}
+		cmd := execCommand("security", "-i")
+		cmd.Stdin = strings.NewReader(securityLine("add-generic-password", "-U", "-s", Service, "-a", name, "-w", value))
+		return runCommand(cmd)
+	case "linux":
+		cmd := execCommand("secret-tool", "store", "--label", Service+": "+name, "service", Service, "account", name)
+		cmd.Stdin = strings.NewReader(value)
+		return runCommand(cmd)
+	default:
+		return fmt.Errorf("keychain not supported on %s", runtime.GOOS)
+	}
+}
+
+// securityLine returns a command line for "security -i", each argument
+// double-quoted with backslashes and quotes escaped.
+func securityLine(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		arg = strings.ReplaceAll(arg, `\`, `\\`)
+		quoted[i] = `"` + strings.ReplaceAll(arg, `"`, `\"`) + `"`
+	}
+	return strings.Join(quoted, " ") + "\n"
+}
+
+// Get retrieves the secret stored under name.
+func Get(name string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = execCommand("security", "find-generic-password", "-s", Service, "-a", name, "-w")
+	case "linux":
+		cmd = execCommand("secret-tool", "lookup", "service", Service, "account", name)
+	default:
+		return "", fmt.Errorf("keychain not supported on %s", runtime.GOOS)
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+		return "", fmt.Errorf("keychain lookup failed: %w", err)
+	}
+
+	value := strings.TrimRight(string(out), "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return value, nil
+}
+
+// Delete removes the secret stored under name.
+func Delete(name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		return runCommand(execCommand("security", "delete-generic-password", "-s", Service, "-a", name))
+	case "linux":
+		return runCommand(execCommand("secret-tool", "clear", "service", Service, "account", name))
+	default:
+		return fmt.Errorf("keychain not supported on %s", runtime.GOOS)
+	}
+}
+
+// Resolve returns the secret value if value is a "secret:<name>" reference,
+// otherwise value unchanged.
+func Resolve(value string) (string, error) {
+	name, ok := strings.CutPrefix(value, RefPrefix)
+	if !ok {
+		return value, nil
+	}
+	return Get(name)
+}
+
+// runCommand runs cmd and includes its stderr in any error.
+func runCommand(cmd *exec.Cmd) error {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s failed: %s", cmd.Args[0], msg)
+		}
+		return fmt.Errorf("%s failed: %w", cmd.Args[0], err)
+	}
+	return nil
+}
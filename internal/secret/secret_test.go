@@ -0,0 +1,114 @@
+package secret
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+)
+
+// fakeCommand returns an execCommand replacement that runs TestHelperProcess.
+func fakeCommand(name string, args ...string) *exec.Cmd {
+	cs := append([]string{"-test.run=TestHelperProcess", "--", name}, args...)
+	cmd := exec.Command(os.Args[0], cs...)
+	cmd.Env = append(os.Environ(), "CCBELL_WANT_HELPER_PROCESS=1")
+	return cmd
+}
+
+// TestHelperProcess emulates the keychain tools; it is not a real test.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("CCBELL_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	args = args[1:]
+
+	for _, arg := range args {
+		if arg == "missing" {
+			os.Exit(44)
+		}
+	}
+	switch {
+	case len(args) > 1 && (args[1] == "lookup" || args[1] == "find-generic-password"):
+		fmt.Println("s3cret")
+	default:
+		// store, add, clear, delete succeed silently
+	}
+	os.Exit(0)
+}
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"simple", "webhook", false},
+		{"with punctuation", "bark.device-key_1", false},
+		{"empty", "", true},
+		{"space", "my token", true},
+		{"shell chars", "a;rm -rf", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateName(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateName(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolvePassthrough(t *testing.T) {
+	got, err := Resolve("https://example.com/hook")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "https://example.com/hook" {
+		t.Errorf("Resolve() = %q, want unchanged value", got)
+	}
+}
+
+func TestKeychainCommands(t *testing.T) {
+	if runtime.GOOS != "darwin" && runtime.GOOS != "linux" {
+		t.Skip("keychain only supported on macOS and Linux")
+	}
+
+	oldExec := execCommand
+	execCommand = fakeCommand
+	defer func() { execCommand = oldExec }()
+
+	if err := Set("token", "s3cret"); err != nil {
+		t.Errorf("Set() error = %v", err)
+	}
+
+	got, err := Resolve("secret:token")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if got != "s3cret" {
+		t.Errorf("Resolve() = %q, want %q", got, "s3cret")
+	}
+
+	if _, err := Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+
+	if err := Delete("token"); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+}
+
+func TestSecurityLine(t *testing.T) {
+	got := securityLine("add-generic-password", "-a", "token", "-w", `pa ss"w\rd`)
+	want := `"add-generic-password" "-a" "token" "-w" "pa ss\"w\\rd"` + "\n"
+	if got != want {
+		t.Errorf("securityLine() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,109 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Unacked is the last notification sent, until it is acknowledged with
+// "ccbell ack" or by the next event.
+type Unacked struct {
+	Event      string          `json:"event"`
+	Message    json.RawMessage `json:"message"`
+	Payload    json.RawMessage `json:"payload,omitempty"`    // Hook payload, for templates
+	Sent       int64           `json:"sent"`                 // Unix time
+	EscalateAt int64           `json:"escalateAt,omitempty"` // Unix time; 0 once escalated or if never
+}
+
+// unackedTTL is how long an unacknowledged notification is kept.
+const unackedTTL = 24 * 60 * 60
+
+// SetUnacked records the last notification sent, replacing (and so
+// acknowledging) the previous one.
+func (m *Manager) SetUnacked(u *Unacked) error {
+	if m.filePath == "" {
+		return errors.New("no state file available")
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := m.load()
+	if err != nil {
+		state = &State{LastTrigger: make(map[string]int64)}
+	}
+	if u.Sent == 0 {
+		u.Sent = time.Now().Unix()
+	}
+	state.Unacked = u
+
+	if err := m.save(state); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
+
+// Ack acknowledges the last notification, returning it, or nil if there
+// was nothing to acknowledge.
+func (m *Manager) Ack() (*Unacked, error) {
+	if m.filePath == "" {
+		return nil, nil
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	state, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+	if state.Unacked == nil {
+		return nil, nil
+	}
+
+	u := state.Unacked
+	state.Unacked = nil
+	if err := m.save(state); err != nil {
+		return nil, fmt.Errorf("failed to save state: %w", err)
+	}
+	return u, nil
+}
+
+// TakeDueEscalation returns the unacknowledged notification if its
+// escalation is due at now, and marks it escalated so it is sent once.
+// It stays unacknowledged. Returns nil if nothing is due.
+func (m *Manager) TakeDueEscalation(now time.Time) (*Unacked, error) {
+	if m.filePath == "" {
+		return nil, nil
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	state, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+	u := state.Unacked
+	if u == nil || u.EscalateAt == 0 || now.Unix() < u.EscalateAt {
+		return nil, nil
+	}
+
+	due := *u
+	u.EscalateAt = 0
+	if err := m.save(state); err != nil {
+		return nil, fmt.Errorf("failed to save state: %w", err)
+	}
+	return &due, nil
+}
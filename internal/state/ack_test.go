@@ -0,0 +1,56 @@
+package state
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestManager_Unacked(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-state-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	m := NewManager(tmpDir)
+	if u, err := m.Ack(); err != nil || u != nil {
+		t.Fatalf("Ack() with nothing sent = %+v, %v", u, err)
+	}
+
+	now := time.Now()
+	if err := m.SetUnacked(&Unacked{Event: "stop", Sent: now.Unix()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SetUnacked(&Unacked{Event: "permission_prompt", Sent: now.Unix(), EscalateAt: now.Unix() + 60}); err != nil {
+		t.Fatal(err)
+	}
+
+	if u, err := m.TakeDueEscalation(now); err != nil || u != nil {
+		t.Errorf("TakeDueEscalation() before due = %+v, %v", u, err)
+	}
+	later := now.Add(time.Minute)
+	if u, err := m.TakeDueEscalation(later); err != nil || u == nil || u.Event != "permission_prompt" {
+		t.Errorf("TakeDueEscalation() = %+v, %v, want permission_prompt", u, err)
+	}
+	if u, err := m.TakeDueEscalation(later); err != nil || u != nil {
+		t.Errorf("second TakeDueEscalation() = %+v, %v, want nil", u, err)
+	}
+
+	// Escalated notifications stay unacknowledged; the latest replaced stop
+	u, err := m.Ack()
+	if err != nil || u == nil || u.Event != "permission_prompt" {
+		t.Errorf("Ack() = %+v, %v, want permission_prompt", u, err)
+	}
+	if u, err := m.Ack(); err != nil || u != nil {
+		t.Errorf("second Ack() = %+v, %v, want nil", u, err)
+	}
+}
+
+func TestState_GCUnacked(t *testing.T) {
+	now := time.Now().Unix()
+	s := &State{Unacked: &Unacked{Event: "stop", Sent: now - unackedTTL}}
+	if removed := s.gc(now); removed != 1 || s.Unacked != nil {
+		t.Errorf("gc() = %d, unacked = %+v", removed, s.Unacked)
+	}
+}
@@ -0,0 +1,112 @@
+package state
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bootClockTimeout bounds querying the boot clock on macOS.
+const bootClockTimeout = 200 * time.Millisecond
+
+// bootClock returns an ID for the current boot and the time since it, to
+// measure intervals the wall clock can't be trusted for (NTP corrections,
+// manual clock changes). ok is false where unsupported. Replaceable in
+// tests.
+var bootClock = readBootClock
+
+// readBootClock reads the boot ID and uptime from /proc on Linux, or from
+// sysctl on macOS, whose kernel adjusts the boot time with the clock.
+func readBootClock() (id string, uptime time.Duration, ok bool) {
+	switch runtime.GOOS {
+	case "linux":
+		bootID, err := os.ReadFile("/proc/sys/kernel/random/boot_id")
+		if err != nil {
+			return "", 0, false
+		}
+		data, err := os.ReadFile("/proc/uptime")
+		if err != nil {
+			return "", 0, false
+		}
+		fields := strings.Fields(string(data))
+		if len(fields) == 0 {
+			return "", 0, false
+		}
+		secs, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return "", 0, false
+		}
+		return strings.TrimSpace(string(bootID)), time.Duration(secs * float64(time.Second)), true
+	case "darwin":
+		ctx, cancel := context.WithTimeout(context.Background(), bootClockTimeout)
+		defer cancel()
+		out, err := exec.CommandContext(ctx, "sysctl", "-n", "kern.bootsessionuuid", "kern.boottime").Output()
+		if err != nil {
+			return "", 0, false
+		}
+		return parseDarwinBootClock(string(out), time.Now())
+	}
+	return "", 0, false
+}
+
+// parseDarwinBootClock parses sysctl's boot session UUID and boot time
+// ("{ sec = 1700000000, usec = 0 } Tue Nov 14 22:13:20 2023").
+func parseDarwinBootClock(out string, now time.Time) (id string, uptime time.Duration, ok bool) {
+	lines := strings.SplitN(strings.TrimSpace(out), "\n", 2)
+	if len(lines) != 2 {
+		return "", 0, false
+	}
+	_, rest, found := strings.Cut(lines[1], "sec = ")
+	if !found {
+		return "", 0, false
+	}
+	secs, _, _ := strings.Cut(rest, ",")
+	sec, err := strconv.ParseInt(strings.TrimSpace(secs), 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return strings.TrimSpace(lines[0]), now.Sub(time.Unix(sec, 0)), true
+}
+
+// elapsedSince returns the seconds since an event's last trigger: by the
+// boot clock when it was recorded in this boot, else by the wall clock. A
+// trigger time in the future, left by a clock set back, counts as long ago.
+func (s *State) elapsedSince(eventType string, now int64, bootID string, uptime int64) int64 {
+	if stamp, ok := s.TriggerUptime[eventType]; ok && bootID != "" && s.BootID == bootID && stamp <= uptime {
+		return uptime - stamp
+	}
+	elapsed := now - s.LastTrigger[eventType]
+	if elapsed < 0 {
+		return now // Untrustworthy; treat as never triggered
+	}
+	return elapsed
+}
+
+// setTrigger records an event's trigger at now, and on the boot clock when
+// bootID is known. Boot clock times from an earlier boot are dropped.
+func (s *State) setTrigger(eventType string, now int64, bootID string, uptime int64) {
+	s.LastTrigger[eventType] = now
+	if bootID == "" {
+		delete(s.TriggerUptime, eventType)
+		return
+	}
+	if s.BootID != bootID || s.TriggerUptime == nil {
+		s.BootID = bootID
+		s.TriggerUptime = make(map[string]int64)
+	}
+	s.TriggerUptime[eventType] = uptime
+}
+
+// bootStamp returns the current boot ID and uptime in seconds, or "" and 0
+// without a boot clock.
+func bootStamp() (string, int64) {
+	id, uptime, ok := bootClock()
+	if !ok {
+		return "", 0
+	}
+	return id, int64(uptime / time.Second)
+}
@@ -0,0 +1,75 @@
+package state
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseDarwinBootClock(t *testing.T) {
+	now := time.Unix(1700003600, 0)
+	out := "8C1A2B3C-0000-4000-8000-1234567890AB\n{ sec = 1700000000, usec = 250000 } Tue Nov 14 22:13:20 2023\n"
+	id, uptime, ok := parseDarwinBootClock(out, now)
+	if !ok || id != "8C1A2B3C-0000-4000-8000-1234567890AB" || uptime != time.Hour {
+		t.Errorf("parseDarwinBootClock() = %q, %v, %v", id, uptime, ok)
+	}
+	for _, bad := range []string{"", "uuid-only", "uuid\n{ usec = 0 }", "uuid\n{ sec = soon, usec = 0 }"} {
+		if _, _, ok := parseDarwinBootClock(bad, now); ok {
+			t.Errorf("parseDarwinBootClock(%q) should fail", bad)
+		}
+	}
+}
+
+func TestManager_CheckCooldownClockChanges(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-state-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	orig := bootClock
+	defer func() { bootClock = orig }()
+	bootID, uptime, supported := "boot-1", time.Hour, true
+	bootClock = func() (string, time.Duration, bool) { return bootID, uptime, supported }
+
+	m := NewManager(tmpDir)
+	shiftTrigger := func(by time.Duration) {
+		state, err := m.load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.LastTrigger["stop"] += int64(by / time.Second)
+		if err := m.save(state); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if inCooldown, _ := m.CheckCooldown("stop", 60); inCooldown {
+		t.Fatal("first CheckCooldown() should pass")
+	}
+
+	// The wall clock jumped forward an hour; the boot clock didn't
+	shiftTrigger(-time.Hour)
+	if inCooldown, _ := m.CheckCooldown("stop", 60); !inCooldown {
+		t.Error("CheckCooldown() after a clock jump should still be in cooldown")
+	}
+
+	// The boot clock moved on past the cooldown
+	uptime += 2 * time.Minute
+	if inCooldown, _ := m.CheckCooldown("stop", 60); inCooldown {
+		t.Error("CheckCooldown() after the cooldown should pass")
+	}
+
+	// After a reboot, the wall clock decides; set back, it can't block
+	bootID, uptime = "boot-2", time.Minute
+	shiftTrigger(time.Hour)
+	if inCooldown, _ := m.CheckCooldown("stop", 60); inCooldown {
+		t.Error("CheckCooldown() with a trigger time in the future should pass")
+	}
+
+	// Without a boot clock, the wall clock decides
+	supported = false
+	if inCooldown, _ := m.CheckCooldown("stop", 60); !inCooldown {
+		t.Error("CheckCooldown() by the wall clock should be in cooldown")
+	}
+}
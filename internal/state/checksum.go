@@ -0,0 +1,93 @@
+package state
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// errCorrupt marks a state file that is unreadable or fails its checksum.
+var errCorrupt = errors.New("corrupted state file")
+
+// backupPath returns the path of the last good copy of the state file.
+func (m *Manager) backupPath() string {
+	return m.filePath + ".0"
+}
+
+// checksumPlaceholder stands in for the checksum while it is computed, so
+// it covers the file's bytes as written.
+var checksumPlaceholder = strings.Repeat("0", sha256.Size*2)
+
+// encodeState marshals state with a checksum of the encoded file.
+func encodeState(state *State) ([]byte, error) {
+	state.Checksum = checksumPlaceholder
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	data = append(data, '\n') // Trailing newline
+	state.Checksum = checksum(data)
+	return bytes.Replace(data, checksumField(checksumPlaceholder), checksumField(state.Checksum), 1), nil
+}
+
+// decodeState unmarshals a state file and verifies its checksum. Files
+// without a checksum, written by older versions, are accepted as is. The
+// raw bytes are checked, so fields this version doesn't know about, written
+// by a newer one, don't make the file look corrupted.
+func decodeState(data []byte) (*State, error) {
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("%w: %v", errCorrupt, err)
+	}
+	if want := state.Checksum; want != "" {
+		field := checksumField(want)
+		if !bytes.Contains(data, field) {
+			return nil, fmt.Errorf("%w: checksum mismatch", errCorrupt)
+		}
+		if checksum(bytes.Replace(data, field, checksumField(checksumPlaceholder), 1)) != want {
+			return nil, fmt.Errorf("%w: checksum mismatch", errCorrupt)
+		}
+	}
+	if state.LastTrigger == nil {
+		state.LastTrigger = make(map[string]int64)
+	}
+	return &state, nil
+}
+
+// checksumField returns the checksum field as encodeState writes it.
+func checksumField(sum string) []byte {
+	return []byte(`"checksum": "` + sum + `"`)
+}
+
+// readStateFile reads and verifies a state file.
+func readStateFile(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decodeState(data)
+}
+
+// rotateBackup keeps the current state file as the last good copy before it
+// is replaced. A corrupted file is not kept, so the previous good copy
+// survives. Errors are ignored: the backup is best effort.
+func (m *Manager) rotateBackup() {
+	if _, err := readStateFile(m.filePath); err != nil {
+		return
+	}
+	// A hard link keeps the old content once the new file is renamed over
+	// it, without a moment where no state file exists.
+	_ = os.Remove(m.backupPath())
+	_ = os.Link(m.filePath, m.backupPath())
+}
+
+// checksum returns the hex SHA-256 of data.
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
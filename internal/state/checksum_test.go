@@ -0,0 +1,120 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestEncodeDecodeState(t *testing.T) {
+	state := &State{
+		LastTrigger: map[string]int64{"stop": 1700000000},
+		Sessions:    map[string]*Session{"s1": {Subagents: 2, Updated: 1700000000}},
+	}
+	data, err := encodeState(state)
+	if err != nil {
+		t.Fatalf("encodeState error: %v", err)
+	}
+	if !strings.Contains(string(data), `"checksum"`) {
+		t.Errorf("encoded state has no checksum: %s", data)
+	}
+
+	decoded, err := decodeState(data)
+	if err != nil {
+		t.Fatalf("decodeState error: %v", err)
+	}
+	if decoded.LastTrigger["stop"] != 1700000000 || decoded.Sessions["s1"].Subagents != 2 {
+		t.Errorf("decoded = %+v", decoded)
+	}
+
+	tampered := strings.Replace(string(data), "1700000000", "1700000001", 1)
+	if _, err := decodeState([]byte(tampered)); !errors.Is(err, errCorrupt) {
+		t.Errorf("decodeState(tampered) error = %v, want errCorrupt", err)
+	}
+	if _, err := decodeState([]byte("{truncated")); !errors.Is(err, errCorrupt) {
+		t.Errorf("decodeState(truncated) error = %v, want errCorrupt", err)
+	}
+
+	// Fields added by newer versions are covered by the checksum as written
+	newer, err := encodeState(&State{LastTrigger: map[string]int64{"stop": 7}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(newer, &fields); err != nil {
+		t.Fatal(err)
+	}
+	sum := fields["checksum"].(string)
+	newer = []byte(strings.Replace(string(newer), "{\n", "{\n  \"future\": [1, 2],\n", 1))
+	newer = []byte(strings.Replace(string(newer), sum, checksumPlaceholder, 1))
+	newer = []byte(strings.Replace(string(newer), checksumPlaceholder, checksum(newer), 1))
+	if decoded, err := decodeState(newer); err != nil || decoded.LastTrigger["stop"] != 7 {
+		t.Errorf("decodeState(newer) = %+v, %v", decoded, err)
+	}
+
+	// Files from older versions have no checksum
+	legacy, err := decodeState([]byte(`{"lastTrigger": {"stop": 5}}`))
+	if err != nil || legacy.LastTrigger["stop"] != 5 {
+		t.Errorf("decodeState(legacy) = %+v, %v", legacy, err)
+	}
+}
+
+func TestManager_RecoverFromBackup(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-state-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	m := NewManager(tmpDir)
+
+	// Two saves leave the first as the last good copy
+	if _, err := m.CheckCooldown("stop", 3600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.CheckCooldown("subagent", 3600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(m.backupPath()); err != nil {
+		t.Fatalf("backup not written: %v", err)
+	}
+
+	// A damaged state file falls back to the backup instead of resetting
+	if err := os.WriteFile(m.filePath, []byte(`{"lastTrigger": {"stop": 1`), FileMode); err != nil {
+		t.Fatal(err)
+	}
+	inCooldown, err := m.CheckCooldown("stop", 3600)
+	if err != nil {
+		t.Fatalf("CheckCooldown error: %v", err)
+	}
+	if !inCooldown {
+		t.Error("cooldown should survive a corrupted state file")
+	}
+
+	// The corrupted file is never kept as the backup
+	if _, err := m.CheckCooldown("idle_prompt", 3600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(m.filePath, []byte("garbage"), FileMode); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.CheckCooldown("permission_prompt", 3600); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := readStateFile(m.backupPath())
+	if err != nil {
+		t.Fatalf("backup unreadable: %v", err)
+	}
+	if backup.LastTrigger["stop"] == 0 {
+		t.Errorf("backup lost cooldowns: %+v", backup.LastTrigger)
+	}
+
+	if err := m.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(m.backupPath()); !os.IsNotExist(err) {
+		t.Error("Clear should remove the backup")
+	}
+}
@@ -0,0 +1,85 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Circuit tracks consecutive delivery failures for a remote endpoint.
+type Circuit struct {
+	Failures  int   `json:"failures"`
+	OpenUntil int64 `json:"openUntil,omitempty"` // Unix time deliveries resume
+	Updated   int64 `json:"updated"`
+}
+
+// circuitTTL is how long a closed circuit's failure count is kept.
+const circuitTTL = 24 * 60 * 60
+
+// CircuitOpenUntil returns when deliveries to endpoint resume, or the zero
+// time if its circuit is closed.
+func (m *Manager) CircuitOpenUntil(endpoint string) (time.Time, error) {
+	if m.filePath == "" {
+		return time.Time{}, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, err := m.load()
+	if err != nil {
+		return time.Time{}, err
+	}
+	c := state.Circuits[endpoint]
+	if c == nil || c.OpenUntil <= time.Now().Unix() {
+		return time.Time{}, nil
+	}
+	return time.Unix(c.OpenUntil, 0), nil
+}
+
+// RecordDelivery updates endpoint's circuit with a delivery outcome. A
+// success closes it; the threshold-th consecutive failure opens it for
+// cooldown, and while the count stays at the threshold every further
+// failure opens it again. It reports whether the circuit opened.
+func (m *Manager) RecordDelivery(endpoint string, ok bool, threshold int, cooldown time.Duration) (bool, error) {
+	if m.filePath == "" {
+		return false, errors.New("no state file available")
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	state, err := m.load()
+	if err != nil {
+		state = &State{LastTrigger: make(map[string]int64)}
+	}
+	c := state.Circuits[endpoint]
+	if ok {
+		if c == nil {
+			return false, nil
+		}
+		delete(state.Circuits, endpoint)
+	} else {
+		if state.Circuits == nil {
+			state.Circuits = make(map[string]*Circuit)
+		}
+		if c == nil {
+			c = &Circuit{}
+			state.Circuits[endpoint] = c
+		}
+		now := time.Now()
+		c.Failures = min(c.Failures+1, threshold)
+		c.Updated = now.Unix()
+		if c.Failures >= threshold {
+			c.OpenUntil = now.Add(cooldown).Unix()
+		}
+	}
+
+	if err := m.save(state); err != nil {
+		return false, fmt.Errorf("failed to save state: %w", err)
+	}
+	return !ok && c.OpenUntil != 0, nil
+}
@@ -0,0 +1,58 @@
+package state
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestManager_Circuit(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-state-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	m := NewManager(tmpDir)
+	record := func(ok bool, cooldown time.Duration) bool {
+		t.Helper()
+		opened, err := m.RecordDelivery("webhook", ok, 2, cooldown)
+		if err != nil {
+			t.Fatalf("RecordDelivery error: %v", err)
+		}
+		return opened
+	}
+	openUntil := func() time.Time {
+		t.Helper()
+		until, err := m.CircuitOpenUntil("webhook")
+		if err != nil {
+			t.Fatalf("CircuitOpenUntil error: %v", err)
+		}
+		return until
+	}
+
+	if record(false, time.Minute) || !openUntil().IsZero() {
+		t.Error("one failure should not open the circuit")
+	}
+	if !record(false, time.Minute) || openUntil().IsZero() {
+		t.Error("second failure should open the circuit")
+	}
+	if until, _ := m.CircuitOpenUntil("bark"); !until.IsZero() {
+		t.Error("circuits are per endpoint")
+	}
+
+	// After the cooldown a single failure opens it again
+	if !record(false, -time.Second) || !openUntil().IsZero() {
+		t.Error("expired circuit should be closed")
+	}
+	if !record(false, time.Minute) {
+		t.Error("failure after the cooldown should reopen the circuit")
+	}
+
+	if record(true, time.Minute) || !openUntil().IsZero() {
+		t.Error("success should close the circuit")
+	}
+	if record(false, time.Minute) {
+		t.Error("success should reset the failure count")
+	}
+}
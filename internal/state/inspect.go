@@ -0,0 +1,64 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Read returns the current state for inspection. Changes to it aren't
+// saved.
+func (m *Manager) Read() (*State, error) {
+	if m.filePath == "" {
+		return &State{LastTrigger: make(map[string]int64)}, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.load()
+}
+
+// SinceTrigger returns how long ago an event last triggered, measured the
+// way cooldowns are, and false if it never did.
+func (s *State) SinceTrigger(eventType string, now time.Time) (time.Duration, bool) {
+	if _, ok := s.LastTrigger[eventType]; !ok {
+		return 0, false
+	}
+	bootID, uptime := bootStamp()
+	return time.Duration(s.elapsedSince(eventType, now.Unix(), bootID, uptime)) * time.Second, true
+}
+
+// ClearEvent removes an event's cooldown and its unacknowledged
+// notification, if any, and reports whether there was anything to remove.
+func (m *Manager) ClearEvent(eventType string) (bool, error) {
+	if m.filePath == "" {
+		return false, errors.New("no state file available")
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	state, err := m.load()
+	if err != nil {
+		return false, err
+	}
+	_, found := state.LastTrigger[eventType]
+	delete(state.LastTrigger, eventType)
+	delete(state.TriggerUptime, eventType)
+	if state.Unacked != nil && state.Unacked.Event == eventType {
+		state.Unacked = nil
+		found = true
+	}
+	if !found {
+		return false, nil
+	}
+
+	if err := m.save(state); err != nil {
+		return false, fmt.Errorf("failed to save state: %w", err)
+	}
+	return true, nil
+}
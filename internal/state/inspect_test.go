@@ -0,0 +1,58 @@
+package state
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestManager_ClearEvent(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-state-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	m := NewManager(tmpDir)
+	if st, err := m.Read(); err != nil || len(st.LastTrigger) != 0 {
+		t.Fatalf("Read() of missing state = %+v, %v", st, err)
+	}
+
+	now := time.Now()
+	for _, event := range []string{"stop", "subagent"} {
+		if err := m.RecordTrigger(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.SetUnacked(&Unacked{Event: "stop", Sent: now.Unix()}); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := m.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if since, ok := st.SinceTrigger("stop", now); !ok || since < 0 || since > 2*time.Second {
+		t.Errorf("SinceTrigger(stop) = %v, %v, want about 0", since, ok)
+	}
+	if _, ok := st.SinceTrigger("permission_prompt", now); ok {
+		t.Error("SinceTrigger(permission_prompt) should report never triggered")
+	}
+
+	if found, err := m.ClearEvent("stop"); err != nil || !found {
+		t.Fatalf("ClearEvent(stop) = %v, %v", found, err)
+	}
+	if found, err := m.ClearEvent("stop"); err != nil || found {
+		t.Errorf("second ClearEvent(stop) = %v, %v, want false", found, err)
+	}
+	st, err = m.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := st.LastTrigger["stop"]; ok || st.Unacked != nil {
+		t.Errorf("stop state remains: %+v, unacked %+v", st.LastTrigger, st.Unacked)
+	}
+	if _, ok := st.LastTrigger["subagent"]; !ok {
+		t.Error("ClearEvent(stop) removed subagent")
+	}
+}
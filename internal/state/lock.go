@@ -0,0 +1,37 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// lock serializes a read-modify-write of the state file with other
+// goroutines and, through a ".lock" file next to it, with other ccbell
+// processes such as concurrent hooks. Every method that saves the state
+// holds it from load to save, so no update is lost; methods that only
+// read needn't, since save renames a complete file into place. It returns
+// the function releasing both.
+func (m *Manager) lock() (unlock func(), err error) {
+	m.mu.Lock()
+	if err := os.MkdirAll(filepath.Dir(m.filePath), 0750); err != nil {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	f, err := os.OpenFile(m.filePath+".lock", os.O_CREATE|os.O_RDWR, FileMode)
+	if err != nil {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("failed to open state lock: %w", err)
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		m.mu.Unlock()
+		return nil, fmt.Errorf("failed to lock state: %w", err)
+	}
+	return func() {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+		m.mu.Unlock()
+	}, nil
+}
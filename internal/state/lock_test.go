@@ -0,0 +1,117 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"testing"
+)
+
+// Concurrent writers in TestManager_ConcurrentProcesses.
+const (
+	helperProcesses = 4
+	helperUpdates   = 12 // Fewer than maxQueued in all
+)
+
+// TestHelperProcess is run as a separate process by runHelpers, making
+// helperUpdates state changes of the kind named by HELPER_ACTION.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	m := NewManager(os.Getenv("HELPER_HOME"))
+	for range helperUpdates {
+		var err error
+		switch action := os.Getenv("HELPER_ACTION"); action {
+		case "record":
+			_, err = m.RecordSubagent()
+		case "mixed":
+			// Other writers mustn't drop subagents recorded meanwhile
+			if err = m.RecordTrigger("stop"); err == nil {
+				_, err = m.RecordSubagent()
+			}
+		case "mixed-queue":
+			if _, err = m.CheckDuplicate("fingerprint", 60); err == nil {
+				err = m.Queue(&Delivery{Channel: "webhook", Message: json.RawMessage(`{}`)})
+			}
+		case "hold":
+			err = m.HoldSubagent("session-1")
+		case "queue":
+			err = m.Queue(&Delivery{Channel: "webhook", Message: json.RawMessage(`{}`)})
+		default:
+			err = fmt.Errorf("unknown action %q", action)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	os.Exit(0)
+}
+
+// runHelpers runs helperProcesses processes doing action at once.
+func runHelpers(t *testing.T, homeDir, action string) {
+	t.Helper()
+	var wg sync.WaitGroup
+	errs := make(chan error, helperProcesses)
+	for range helperProcesses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$")
+			cmd.Env = append(os.Environ(), "GO_WANT_HELPER_PROCESS=1", "HELPER_HOME="+homeDir, "HELPER_ACTION="+action)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				errs <- fmt.Errorf("%v: %s", err, out)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("helper process failed: %v", err)
+	}
+}
+
+func TestManager_ConcurrentProcesses(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-state-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	m := NewManager(tmpDir)
+
+	runHelpers(t, tmpDir, "record")
+	state, err := m.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := helperProcesses * helperUpdates
+	if state.Subagents == nil || state.Subagents.Count != want || state.Subagents.Seq != int64(want) {
+		t.Errorf("subagents = %+v, want %d completions", state.Subagents, want)
+	}
+
+	runHelpers(t, tmpDir, "mixed")
+	if state, err = m.load(); err != nil {
+		t.Fatal(err)
+	}
+	if state.Subagents == nil || state.Subagents.Count != 2*want {
+		t.Errorf("subagents = %+v, want %d completions", state.Subagents, 2*want)
+	}
+
+	runHelpers(t, tmpDir, "hold")
+	if held, err := m.TakeHeldSubagents("session-1"); err != nil || held != want {
+		t.Errorf("TakeHeldSubagents() = %d, %v; want %d", held, err, want)
+	}
+
+	runHelpers(t, tmpDir, "queue")
+	if queued, err := m.TakeQueued(); err != nil || len(queued) != want {
+		t.Errorf("TakeQueued() = %d deliveries, %v; want %d", len(queued), err, want)
+	}
+
+	runHelpers(t, tmpDir, "mixed-queue")
+	if queued, err := m.TakeQueued(); err != nil || len(queued) != want {
+		t.Errorf("TakeQueued() = %d deliveries, %v; want %d", len(queued), err, want)
+	}
+}
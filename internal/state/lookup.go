@@ -0,0 +1,75 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Lookup is a cached PATH search for an executable.
+type Lookup struct {
+	Path    string `json:"path,omitempty"` // Empty if it wasn't found
+	Checked int64  `json:"checked"`
+}
+
+// lookupTTL is how long a cached PATH search is trusted, so a newly
+// installed or removed player is noticed within minutes.
+const lookupTTL = 10 * 60
+
+// CachedLookups returns the executable searches still fresh, as name to
+// path ("" if the executable wasn't found).
+func (m *Manager) CachedLookups() (map[string]string, error) {
+	if m.filePath == "" {
+		return nil, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	lookups := make(map[string]string, len(state.Lookups))
+	for name, l := range state.Lookups {
+		if now-l.Checked < lookupTTL {
+			lookups[name] = l.Path
+		}
+	}
+	return lookups, nil
+}
+
+// CacheLookups records executable searches, as name to path ("" if the
+// executable wasn't found).
+func (m *Manager) CacheLookups(lookups map[string]string) error {
+	if m.filePath == "" {
+		return errors.New("no state file available")
+	}
+	if len(lookups) == 0 {
+		return nil
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := m.load()
+	if err != nil {
+		state = &State{LastTrigger: make(map[string]int64)}
+	}
+	if state.Lookups == nil {
+		state.Lookups = make(map[string]*Lookup)
+	}
+	now := time.Now().Unix()
+	for name, path := range lookups {
+		state.Lookups[name] = &Lookup{Path: path, Checked: now}
+	}
+
+	if err := m.save(state); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package state
+
+import (
+	"maps"
+	"os"
+	"testing"
+)
+
+func TestManager_Lookups(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-state-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	m := NewManager(tmpDir)
+	if lookups, err := m.CachedLookups(); err != nil || len(lookups) != 0 {
+		t.Fatalf("CachedLookups() = %v, %v; want none", lookups, err)
+	}
+	want := map[string]string{"mpv": "", "paplay": "/usr/bin/paplay"}
+	if err := m.CacheLookups(want); err != nil {
+		t.Fatalf("CacheLookups error: %v", err)
+	}
+	if lookups, err := m.CachedLookups(); err != nil || !maps.Equal(lookups, want) {
+		t.Errorf("CachedLookups() = %v, %v; want %v", lookups, err, want)
+	}
+
+	// Expired searches are ignored and collected by the next save
+	state, err := m.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Lookups["mpv"].Checked -= lookupTTL
+	if err := m.save(state); err != nil {
+		t.Fatal(err)
+	}
+	if lookups, _ := m.CachedLookups(); !maps.Equal(lookups, map[string]string{"paplay": "/usr/bin/paplay"}) {
+		t.Errorf("CachedLookups() = %v, want only paplay", lookups)
+	}
+	if err := m.CacheLookups(map[string]string{"aplay": ""}); err != nil {
+		t.Fatal(err)
+	}
+	state, _ = m.load()
+	if _, ok := state.Lookups["mpv"]; ok {
+		t.Error("expired lookup should be removed")
+	}
+
+	if err := NewManager("").CacheLookups(want); err == nil {
+		t.Error("CacheLookups without a state file should fail")
+	}
+}
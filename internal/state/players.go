@@ -0,0 +1,100 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PlayerRank is the installed Linux audio players, fastest to start first.
+// Started is set while a process ranks them.
+type PlayerRank struct {
+	Order   []string `json:"order"`
+	Checked int64    `json:"checked"`
+	Started int64    `json:"started,omitempty"`
+}
+
+// playerRankTTL is how long a player ranking is trusted, so players
+// installed or updated since are measured again within a week.
+const playerRankTTL = 7 * 24 * 60 * 60
+
+// playerRankTimeout is how long a started ranking keeps others from
+// starting, so one that died is started again.
+const playerRankTimeout = 5 * 60
+
+// RankedPlayers returns the cached player ranking, or nil if there is no
+// fresh one.
+func (m *Manager) RankedPlayers() ([]string, error) {
+	if m.filePath == "" {
+		return nil, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+	if state.Players == nil || time.Now().Unix()-state.Players.Checked >= playerRankTTL {
+		return nil, nil
+	}
+	return state.Players.Order, nil
+}
+
+// StartRanking records that a player ranking is starting, reporting false
+// when there is a fresh ranking or another process started one within
+// playerRankTimeout, so a burst of notifications starts one ranking.
+func (m *Manager) StartRanking() (bool, error) {
+	if m.filePath == "" {
+		return false, errors.New("no state file available")
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	state, err := m.load()
+	if err != nil {
+		state = &State{LastTrigger: make(map[string]int64)}
+	}
+	now := time.Now().Unix()
+	if p := state.Players; p != nil && (now-p.Checked < playerRankTTL || now-p.Started < playerRankTimeout) {
+		return false, nil
+	}
+	if state.Players == nil {
+		state.Players = &PlayerRank{}
+	}
+	state.Players.Started = now
+
+	if err := m.save(state); err != nil {
+		return false, fmt.Errorf("failed to save state: %w", err)
+	}
+	return true, nil
+}
+
+// RankPlayers caches the players' order by startup latency.
+func (m *Manager) RankPlayers(order []string) error {
+	if m.filePath == "" {
+		return errors.New("no state file available")
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := m.load()
+	if err != nil {
+		state = &State{LastTrigger: make(map[string]int64)}
+	}
+	state.Players = &PlayerRank{Order: order, Checked: time.Now().Unix()}
+
+	if err := m.save(state); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package state
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestManager_RankPlayers(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-state-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	m := NewManager(tmpDir)
+	if order, err := m.RankedPlayers(); err != nil || order != nil {
+		t.Fatalf("RankedPlayers() = %v, %v; want none", order, err)
+	}
+	want := []string{"paplay", "aplay", "mpv"}
+	if err := m.RankPlayers(want); err != nil {
+		t.Fatalf("RankPlayers error: %v", err)
+	}
+	if order, err := m.RankedPlayers(); err != nil || !slices.Equal(order, want) {
+		t.Errorf("RankedPlayers() = %v, %v; want %v", order, err, want)
+	}
+
+	// An old ranking is collected by the next save
+	state, err := m.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Players.Checked -= playerRankTTL
+	if err := m.save(state); err != nil {
+		t.Fatal(err)
+	}
+	if state, _ = m.load(); state.Players != nil {
+		t.Errorf("players = %v, want nil", state.Players)
+	}
+	if order, _ := m.RankedPlayers(); order != nil {
+		t.Errorf("RankedPlayers() = %v, want none once expired", order)
+	}
+
+	if err := NewManager("").RankPlayers(want); err == nil {
+		t.Error("RankPlayers without a state file should fail")
+	}
+}
+
+func TestManager_StartRanking(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-state-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	m := NewManager(tmpDir)
+	if start, err := m.StartRanking(); err != nil || !start {
+		t.Fatalf("StartRanking() = %v, %v; want true", start, err)
+	}
+	// A burst of notifications starts one ranking
+	if start, err := m.StartRanking(); err != nil || start {
+		t.Errorf("StartRanking() = %v, %v while ranking; want false", start, err)
+	}
+	if order, err := m.RankedPlayers(); err != nil || order != nil {
+		t.Errorf("RankedPlayers() = %v, %v while ranking; want none", order, err)
+	}
+
+	// A ranking that never finished is started again
+	state, err := m.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Players.Started -= playerRankTimeout
+	if err := m.save(state); err != nil {
+		t.Fatal(err)
+	}
+	if start, err := m.StartRanking(); err != nil || !start {
+		t.Errorf("StartRanking() = %v, %v after the timeout; want true", start, err)
+	}
+
+	if err := m.RankPlayers([]string{"paplay"}); err != nil {
+		t.Fatal(err)
+	}
+	if start, err := m.StartRanking(); err != nil || start {
+		t.Errorf("StartRanking() = %v, %v with a fresh ranking; want false", start, err)
+	}
+
+	if _, err := NewManager("").StartRanking(); err == nil {
+		t.Error("StartRanking without a state file should fail")
+	}
+}
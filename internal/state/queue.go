@@ -0,0 +1,83 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Delivery is a failed remote notification awaiting redelivery.
+type Delivery struct {
+	Channel string          `json:"channel"`
+	Message json.RawMessage `json:"message"`
+	Payload json.RawMessage `json:"payload,omitempty"` // Hook payload, for templates
+	Queued  int64           `json:"queued"`            // Unix time of the first failure
+}
+
+// maxQueued bounds the queue; the oldest deliveries are dropped first.
+const maxQueued = 50
+
+// queueTTL is how long a delivery is kept. Older notifications are stale.
+const queueTTL = 24 * 60 * 60
+
+// Queue adds deliveries to the redelivery queue, keeping their order.
+func (m *Manager) Queue(deliveries ...*Delivery) error {
+	if m.filePath == "" {
+		return errors.New("no state file available")
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := m.load()
+	if err != nil {
+		state = &State{LastTrigger: make(map[string]int64)}
+	}
+	now := time.Now().Unix()
+	for _, d := range deliveries {
+		if d.Queued == 0 {
+			d.Queued = now
+		}
+	}
+	state.Queue = append(state.Queue, deliveries...)
+	if len(state.Queue) > maxQueued {
+		state.Queue = state.Queue[len(state.Queue)-maxQueued:]
+	}
+
+	if err := m.save(state); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
+
+// TakeQueued returns and clears the redelivery queue, oldest first.
+func (m *Manager) TakeQueued() ([]*Delivery, error) {
+	if m.filePath == "" {
+		return nil, nil
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	state, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+	if len(state.Queue) == 0 {
+		return nil, nil
+	}
+
+	queued := state.Queue
+	state.Queue = nil
+	if err := m.save(state); err != nil {
+		return nil, fmt.Errorf("failed to save state: %w", err)
+	}
+	return queued, nil
+}
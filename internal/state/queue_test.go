@@ -0,0 +1,45 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestManager_Queue(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-state-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	m := NewManager(tmpDir)
+	if queued, err := m.TakeQueued(); err != nil || len(queued) != 0 {
+		t.Fatalf("TakeQueued() on empty state = %v, %v", queued, err)
+	}
+
+	for i := 0; i < maxQueued+2; i++ {
+		msg := json.RawMessage(fmt.Sprintf(`"%d"`, i))
+		if err := m.Queue(&Delivery{Channel: "bark", Message: msg}); err != nil {
+			t.Fatalf("Queue error: %v", err)
+		}
+	}
+	queued, err := m.TakeQueued()
+	if err != nil {
+		t.Fatalf("TakeQueued error: %v", err)
+	}
+	if len(queued) != maxQueued {
+		t.Fatalf("queued %d deliveries, want %d", len(queued), maxQueued)
+	}
+	if string(queued[0].Message) != `"2"` || queued[0].Queued == 0 {
+		t.Errorf("oldest kept delivery = %+v, want message 2 with a queue time", queued[0])
+	}
+
+	if queued, err := m.TakeQueued(); err != nil || len(queued) != 0 {
+		t.Errorf("TakeQueued() should clear the queue, got %v, %v", queued, err)
+	}
+	if err := NewManager("").Queue(&Delivery{Channel: "bark"}); err == nil {
+		t.Error("Queue without a state file should fail")
+	}
+}
@@ -2,7 +2,7 @@
 package state
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -17,7 +17,41 @@ const (
 
 // State represents the cooldown state.
 type State struct {
-	LastTrigger map[string]int64 `json:"lastTrigger"`
+	LastTrigger   map[string]int64    `json:"lastTrigger"`
+	TriggerUptime map[string]int64    `json:"triggerUptime,omitempty"` // Event -> seconds since boot at the last trigger
+	BootID        string              `json:"bootID,omitempty"`        // Boot TriggerUptime was recorded in
+	Fingerprints  map[string]int64    `json:"fingerprints,omitempty"`  // Payload hash -> expiry
+	Subagents     *SubagentBatch      `json:"subagents,omitempty"`
+	Sessions      map[string]*Session `json:"sessions,omitempty"`    // Session ID -> pending subagents
+	PausedUntil   int64               `json:"pausedUntil,omitempty"` // Unix time notifications resume
+	LastEvent     int64               `json:"lastEvent,omitempty"`   // Unix time of the latest event of any type
+	Queue         []*Delivery         `json:"queue,omitempty"`       // Remote notifications awaiting redelivery
+	Circuits      map[string]*Circuit `json:"circuits,omitempty"`    // Remote endpoint -> recent failures
+	Lookups       map[string]*Lookup  `json:"lookups,omitempty"`     // Executable -> cached PATH search
+	Players       *PlayerRank         `json:"players,omitempty"`     // Linux players by startup latency
+	Unacked       *Unacked            `json:"unacked,omitempty"`     // Last notification, until acknowledged
+	Toasts        map[string]*Toast   `json:"toasts,omitempty"`      // Event -> desktop notification repeats replace
+	Checksum      string              `json:"checksum,omitempty"`    // SHA-256 of the file with this value zeroed
+}
+
+// Session tracks subagent completions held back until a session's stop event.
+type Session struct {
+	Subagents int   `json:"subagents"`
+	Updated   int64 `json:"updated"`
+}
+
+// sessionTTL is how long an idle session entry is kept.
+const sessionTTL = 24 * 60 * 60
+
+// lastTriggerTTL is how long a trigger time is kept. Cooldowns are far
+// shorter, so older entries (e.g. from events no longer configured) are
+// never consulted again.
+const lastTriggerTTL = 30 * 24 * 60 * 60
+
+// SubagentBatch tracks subagent completions awaiting a summary notification.
+type SubagentBatch struct {
+	Seq   int64 `json:"seq"`   // Incremented on every completion
+	Count int   `json:"count"` // Completions since the last summary
 }
 
 // Manager handles state file operations.
@@ -28,14 +62,18 @@ type Manager struct {
 
 // NewManager creates a new state manager.
 func NewManager(homeDir string) *Manager {
-	statePath := ""
-	if homeDir != "" {
-		statePath = filepath.Join(homeDir, ".claude", "ccbell.state")
+	return &Manager{
+		filePath: Path(homeDir),
 	}
+}
 
-	return &Manager{
-		filePath: statePath,
+// Path returns the state file path for a home directory, or "" if homeDir
+// is empty.
+func Path(homeDir string) string {
+	if homeDir == "" {
+		return ""
 	}
+	return filepath.Join(homeDir, ".claude", "ccbell.state")
 }
 
 // CheckCooldown checks if an event is in cooldown period.
@@ -46,8 +84,11 @@ func (m *Manager) CheckCooldown(eventType string, cooldownSecs int) (bool, error
 		return false, nil // No cooldown configured
 	}
 
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	unlock, err := m.lock()
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
 
 	state, err := m.load()
 	if err != nil {
@@ -55,16 +96,17 @@ func (m *Manager) CheckCooldown(eventType string, cooldownSecs int) (bool, error
 		state = &State{LastTrigger: make(map[string]int64)}
 	}
 
+	// The boot clock isn't affected by NTP jumps or clock changes
 	currentTime := time.Now().Unix()
-	lastTrigger := state.LastTrigger[eventType]
-	elapsed := currentTime - lastTrigger
+	bootID, uptime := bootStamp()
+	elapsed := state.elapsedSince(eventType, currentTime, bootID, uptime)
 
 	if elapsed < int64(cooldownSecs) {
 		return true, nil // In cooldown
 	}
 
 	// Update last trigger time
-	state.LastTrigger[eventType] = currentTime
+	state.setTrigger(eventType, currentTime, bootID, uptime)
 	if err := m.save(state); err != nil {
 		return false, fmt.Errorf("failed to save state: %w", err)
 	}
@@ -72,27 +114,408 @@ func (m *Manager) CheckCooldown(eventType string, cooldownSecs int) (bool, error
 	return false, nil
 }
 
-// load reads the state file.
-func (m *Manager) load() (*State, error) {
-	data, err := os.ReadFile(m.filePath)
+// RecordTrigger sets an event's last trigger time to now, starting its
+// cooldown without checking it.
+func (m *Manager) RecordTrigger(eventType string) error {
+	if m.filePath == "" {
+		return nil
+	}
+
+	unlock, err := m.lock()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return &State{LastTrigger: make(map[string]int64)}, nil
-		}
-		return nil, err
+		return err
 	}
+	defer unlock()
 
-	var state State
-	if err := json.Unmarshal(data, &state); err != nil {
-		// Corrupted state file - start fresh
-		return &State{LastTrigger: make(map[string]int64)}, nil
+	state, err := m.load()
+	if err != nil {
+		state = &State{LastTrigger: make(map[string]int64)}
+	}
+	bootID, uptime := bootStamp()
+	state.setTrigger(eventType, time.Now().Unix(), bootID, uptime)
+	if err := m.save(state); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
+
+// RecordEvent notes that an event arrived now and returns when the previous
+// one did, or the zero time if none is recorded.
+func (m *Manager) RecordEvent() (time.Time, error) {
+	if m.filePath == "" {
+		return time.Time{}, errors.New("no state file available")
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer unlock()
+
+	state, err := m.load()
+	if err != nil {
+		state = &State{LastTrigger: make(map[string]int64)}
+	}
+	previous := state.LastEvent
+	state.LastEvent = time.Now().Unix()
+	if err := m.save(state); err != nil {
+		return time.Time{}, fmt.Errorf("failed to save state: %w", err)
+	}
+	if previous == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(previous, 0), nil
+}
+
+// CheckDuplicate checks if a notification with the same payload fingerprint
+// was seen within windowSecs. Returns true if it is a duplicate (should skip
+// notification). Records the fingerprint otherwise.
+func (m *Manager) CheckDuplicate(fingerprint string, windowSecs int) (bool, error) {
+	if m.filePath == "" || windowSecs <= 0 || fingerprint == "" {
+		return false, nil // No dedupe configured
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	state, err := m.load()
+	if err != nil {
+		state = &State{LastTrigger: make(map[string]int64)}
+	}
+	if state.Fingerprints == nil {
+		state.Fingerprints = make(map[string]int64)
+	}
+
+	currentTime := time.Now().Unix()
+	if expiry, ok := state.Fingerprints[fingerprint]; ok && currentTime < expiry {
+		return true, nil // Duplicate
+	}
+
+	state.Fingerprints[fingerprint] = currentTime + int64(windowSecs)
+	if err := m.save(state); err != nil {
+		return false, fmt.Errorf("failed to save state: %w", err)
+	}
+
+	return false, nil
+}
+
+// RecordSubagent adds a subagent completion to the pending batch and returns
+// its sequence number.
+func (m *Manager) RecordSubagent() (int64, error) {
+	return m.RecordSubagents(1)
+}
+
+// RecordSubagents adds n subagent completions to the pending batch and
+// returns the batch's new sequence number.
+func (m *Manager) RecordSubagents(n int) (int64, error) {
+	if m.filePath == "" {
+		return 0, errors.New("no state file available")
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	state, err := m.load()
+	if err != nil {
+		state = &State{LastTrigger: make(map[string]int64)}
+	}
+	if state.Subagents == nil {
+		state.Subagents = &SubagentBatch{}
+	}
+	state.Subagents.Seq++
+	state.Subagents.Count += n
+
+	if err := m.save(state); err != nil {
+		return 0, fmt.Errorf("failed to save state: %w", err)
+	}
+	return state.Subagents.Seq, nil
+}
+
+// TakeSubagentBatch returns and clears the pending batch count if seq is
+// still the latest completion. Returns 0 if newer completions arrived since,
+// in which case a later flush owns the batch.
+func (m *Manager) TakeSubagentBatch(seq int64) (int, error) {
+	if m.filePath == "" {
+		return 0, nil
 	}
 
-	if state.LastTrigger == nil {
-		state.LastTrigger = make(map[string]int64)
+	unlock, err := m.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	state, err := m.load()
+	if err != nil {
+		return 0, err
+	}
+	if state.Subagents == nil || state.Subagents.Seq != seq || state.Subagents.Count == 0 {
+		return 0, nil
+	}
+
+	count := state.Subagents.Count
+	state.Subagents.Count = 0
+	if err := m.save(state); err != nil {
+		return 0, fmt.Errorf("failed to save state: %w", err)
 	}
+	return count, nil
+}
 
-	return &state, nil
+// HoldSubagent records a suppressed subagent completion for a session.
+func (m *Manager) HoldSubagent(sessionID string) error {
+	return m.HoldSubagents(sessionID, 1)
+}
+
+// HoldSubagents records n suppressed subagent completions for a session.
+func (m *Manager) HoldSubagents(sessionID string, n int) error {
+	if m.filePath == "" {
+		return errors.New("no state file available")
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := m.load()
+	if err != nil {
+		state = &State{LastTrigger: make(map[string]int64)}
+	}
+	if state.Sessions == nil {
+		state.Sessions = make(map[string]*Session)
+	}
+
+	currentTime := time.Now().Unix()
+	session := state.Sessions[sessionID]
+	if session == nil {
+		session = &Session{}
+		state.Sessions[sessionID] = session
+	}
+	session.Subagents += n
+	session.Updated = currentTime
+
+	if err := m.save(state); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
+
+// TakeHeldSubagents returns and clears the number of subagent completions
+// held for a session.
+func (m *Manager) TakeHeldSubagents(sessionID string) (int, error) {
+	if m.filePath == "" {
+		return 0, nil
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	state, err := m.load()
+	if err != nil {
+		return 0, err
+	}
+	session, ok := state.Sessions[sessionID]
+	if !ok {
+		return 0, nil
+	}
+
+	delete(state.Sessions, sessionID)
+	if err := m.save(state); err != nil {
+		return 0, fmt.Errorf("failed to save state: %w", err)
+	}
+	return session.Subagents, nil
+}
+
+// Pause suppresses notifications until the given time.
+func (m *Manager) Pause(until time.Time) error {
+	if m.filePath == "" {
+		return errors.New("no state file available")
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := m.load()
+	if err != nil {
+		state = &State{LastTrigger: make(map[string]int64)}
+	}
+	state.PausedUntil = until.Unix()
+	if err := m.save(state); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
+
+// Resume clears any pause.
+func (m *Manager) Resume() error {
+	if m.filePath == "" {
+		return nil
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := m.load()
+	if err != nil {
+		return err
+	}
+	if state.PausedUntil == 0 {
+		return nil
+	}
+	state.PausedUntil = 0
+	if err := m.save(state); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
+
+// PausedUntil returns the time notifications resume, or the zero time if
+// they are not paused. The returned time may be in the past when a pause has
+// expired but not yet been cleared.
+func (m *Manager) PausedUntil() (time.Time, error) {
+	if m.filePath == "" {
+		return time.Time{}, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, err := m.load()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if state.PausedUntil == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(state.PausedUntil, 0), nil
+}
+
+// GC removes stale entries from the state file and returns how many were
+// removed. Saves do the same opportunistically.
+func (m *Manager) GC() (int, error) {
+	if m.filePath == "" {
+		return 0, nil
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	state, err := m.load()
+	if err != nil {
+		return 0, err
+	}
+	removed := state.gc(time.Now().Unix())
+	if removed == 0 {
+		return 0, nil
+	}
+	if err := m.save(state); err != nil {
+		return 0, fmt.Errorf("failed to save state: %w", err)
+	}
+	return removed, nil
+}
+
+// gc removes entries that have expired at now and returns how many were
+// removed.
+func (s *State) gc(now int64) int {
+	removed := 0
+	for key, last := range s.LastTrigger {
+		if now-last >= lastTriggerTTL {
+			delete(s.LastTrigger, key)
+			delete(s.TriggerUptime, key)
+			removed++
+		}
+	}
+	for fp, expiry := range s.Fingerprints {
+		if now >= expiry {
+			delete(s.Fingerprints, fp)
+			removed++
+		}
+	}
+	// Sessions that never saw a stop event
+	for id, session := range s.Sessions {
+		if now-session.Updated >= sessionTTL {
+			delete(s.Sessions, id)
+			removed++
+		}
+	}
+	for endpoint, c := range s.Circuits {
+		if now-c.Updated >= circuitTTL && now >= c.OpenUntil {
+			delete(s.Circuits, endpoint)
+			removed++
+		}
+	}
+	for name, l := range s.Lookups {
+		if now-l.Checked >= lookupTTL {
+			delete(s.Lookups, name)
+			removed++
+		}
+	}
+	if s.Players != nil && now-s.Players.Checked >= playerRankTTL && now-s.Players.Started >= playerRankTimeout {
+		s.Players = nil
+		removed++
+	}
+	for event, t := range s.Toasts {
+		if now-t.Shown >= toastTTL {
+			delete(s.Toasts, event)
+			removed++
+		}
+	}
+	if s.Unacked != nil && now-s.Unacked.Sent >= unackedTTL {
+		s.Unacked = nil
+		removed++
+	}
+	queue := s.Queue[:0]
+	for _, d := range s.Queue {
+		if now-d.Queued < queueTTL {
+			queue = append(queue, d)
+		}
+	}
+	removed += len(s.Queue) - len(queue)
+	s.Queue = queue
+	if s.PausedUntil != 0 && now >= s.PausedUntil {
+		s.PausedUntil = 0
+		removed++
+	}
+	return removed
+}
+
+// load reads the state file. A corrupted file is replaced by the last good
+// copy, so cooldowns survive a torn or damaged write.
+func (m *Manager) load() (*State, error) {
+	state, err := readStateFile(m.filePath)
+	switch {
+	case err == nil:
+		return state, nil
+	case os.IsNotExist(err):
+		return &State{LastTrigger: make(map[string]int64)}, nil
+	case errors.Is(err, errCorrupt):
+		if backup, err := readStateFile(m.backupPath()); err == nil {
+			return backup, nil
+		}
+		// No good copy either - start fresh
+		return &State{LastTrigger: make(map[string]int64)}, nil
+	default:
+		return nil, err
+	}
 }
 
 // save writes the state file atomically.
@@ -103,12 +526,14 @@ func (m *Manager) save(state *State) error {
 		return fmt.Errorf("failed to create state directory: %w", err)
 	}
 
-	// Marshal JSON
-	data, err := json.MarshalIndent(state, "", "  ")
+	// Drop stale entries so the state file stays small
+	state.gc(time.Now().Unix())
+
+	// Marshal JSON with a checksum
+	data, err := encodeState(state)
 	if err != nil {
 		return err
 	}
-	data = append(data, '\n') // Trailing newline
 
 	// Write to temp file first (atomic)
 	tempFile, err := os.CreateTemp(dir, "ccbell.state.*.tmp")
@@ -141,7 +566,8 @@ func (m *Manager) save(state *State) error {
 		return fmt.Errorf("failed to close temp file: %w", err)
 	}
 
-	// Atomic rename
+	// Keep the current file as the last good copy, then atomic rename
+	m.rotateBackup()
 	if err := os.Rename(tempPath, m.filePath); err != nil {
 		return fmt.Errorf("failed to rename temp file: %w", err)
 	}
@@ -150,13 +576,22 @@ func (m *Manager) save(state *State) error {
 	return nil
 }
 
-// Clear removes the state file.
+// Clear removes the state file and its last good copy.
 func (m *Manager) Clear() error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	if m.filePath == "" {
+		return nil
+	}
 
-	if err := os.Remove(m.filePath); err != nil && !os.IsNotExist(err) {
+	unlock, err := m.lock()
+	if err != nil {
 		return err
 	}
+	defer unlock()
+
+	for _, path := range []string{m.filePath, m.backupPath()} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestNewManager(t *testing.T) {
@@ -234,3 +235,367 @@ func TestManager_AtomicSave(t *testing.T) {
 		t.Errorf("file mode = %v, want %v", info.Mode().Perm(), FileMode)
 	}
 }
+
+func TestManager_CheckDuplicate(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-state-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	t.Run("no dedupe when window is 0", func(t *testing.T) {
+		m := NewManager(tmpDir)
+		m.Clear()
+		for i := 0; i < 2; i++ {
+			dup, err := m.CheckDuplicate("abc", 0)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if dup {
+				t.Error("should not be duplicate when window is 0")
+			}
+		}
+	})
+
+	t.Run("repeated fingerprint within window", func(t *testing.T) {
+		m := NewManager(tmpDir)
+		m.Clear()
+
+		dup, err := m.CheckDuplicate("abc", 60)
+		if err != nil {
+			t.Fatalf("first check error: %v", err)
+		}
+		if dup {
+			t.Error("first notification should not be duplicate")
+		}
+
+		dup, err = m.CheckDuplicate("abc", 60)
+		if err != nil {
+			t.Fatalf("second check error: %v", err)
+		}
+		if !dup {
+			t.Error("repeated fingerprint should be duplicate")
+		}
+
+		dup, err = m.CheckDuplicate("def", 60)
+		if err != nil {
+			t.Fatalf("other check error: %v", err)
+		}
+		if dup {
+			t.Error("different fingerprint should not be duplicate")
+		}
+	})
+
+	t.Run("separate from cooldown", func(t *testing.T) {
+		m := NewManager(tmpDir)
+		m.Clear()
+
+		if _, err := m.CheckDuplicate("abc", 60); err != nil {
+			t.Fatalf("dedupe error: %v", err)
+		}
+		inCooldown, err := m.CheckCooldown("stop", 60)
+		if err != nil {
+			t.Fatalf("cooldown error: %v", err)
+		}
+		if inCooldown {
+			t.Error("dedupe should not trigger cooldown")
+		}
+	})
+
+	t.Run("expired fingerprints are pruned", func(t *testing.T) {
+		m := NewManager(tmpDir)
+		m.Clear()
+
+		old := &State{
+			LastTrigger:  map[string]int64{},
+			Fingerprints: map[string]int64{"stale": 1},
+		}
+		if err := m.save(old); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := m.CheckDuplicate("abc", 60); err != nil {
+			t.Fatalf("dedupe error: %v", err)
+		}
+		loaded, err := m.load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := loaded.Fingerprints["stale"]; ok {
+			t.Error("stale fingerprint should be pruned")
+		}
+	})
+}
+
+func TestManager_SubagentBatch(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-state-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	m := NewManager(tmpDir)
+
+	var last int64
+	for i := 0; i < 3; i++ {
+		seq, err := m.RecordSubagent()
+		if err != nil {
+			t.Fatalf("RecordSubagent error: %v", err)
+		}
+		if seq <= last {
+			t.Errorf("seq = %d, want > %d", seq, last)
+		}
+		last = seq
+	}
+
+	// A stale flush doesn't own the batch
+	count, err := m.TakeSubagentBatch(last - 1)
+	if err != nil {
+		t.Fatalf("TakeSubagentBatch error: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("stale flush count = %d, want 0", count)
+	}
+
+	count, err = m.TakeSubagentBatch(last)
+	if err != nil {
+		t.Fatalf("TakeSubagentBatch error: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("count = %d, want 3", count)
+	}
+
+	// An aggregated completion adds its count
+	if last, err = m.RecordSubagents(4); err != nil {
+		t.Fatalf("RecordSubagents error: %v", err)
+	}
+	if count, err = m.TakeSubagentBatch(last); err != nil || count != 4 {
+		t.Errorf("aggregated count = %d, %v, want 4", count, err)
+	}
+
+	// Batch is cleared after being taken
+	count, err = m.TakeSubagentBatch(last)
+	if err != nil {
+		t.Fatalf("TakeSubagentBatch error: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("count after take = %d, want 0", count)
+	}
+
+	t.Run("no state file", func(t *testing.T) {
+		if _, err := NewManager("").RecordSubagent(); err == nil {
+			t.Error("expected error without a state file")
+		}
+	})
+}
+
+func TestManager_HeldSubagents(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-state-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	m := NewManager(tmpDir)
+
+	for i := 0; i < 2; i++ {
+		if err := m.HoldSubagent("s1"); err != nil {
+			t.Fatalf("HoldSubagent error: %v", err)
+		}
+	}
+	if err := m.HoldSubagents("s2", 3); err != nil {
+		t.Fatalf("HoldSubagents error: %v", err)
+	}
+
+	count, err := m.TakeHeldSubagents("s1")
+	if err != nil {
+		t.Fatalf("TakeHeldSubagents error: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("s1 count = %d, want 2", count)
+	}
+
+	count, err = m.TakeHeldSubagents("s1")
+	if err != nil {
+		t.Fatalf("TakeHeldSubagents error: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("s1 count after take = %d, want 0", count)
+	}
+
+	count, err = m.TakeHeldSubagents("s2")
+	if err != nil {
+		t.Fatalf("TakeHeldSubagents error: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("s2 count = %d, want 3", count)
+	}
+}
+
+func TestManager_Pause(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-state-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	m := NewManager(tmpDir)
+
+	until, err := m.PausedUntil()
+	if err != nil || !until.IsZero() {
+		t.Fatalf("PausedUntil() = %v, %v, want zero", until, err)
+	}
+
+	want := time.Now().Add(45 * time.Minute).Truncate(time.Second)
+	if err := m.Pause(want); err != nil {
+		t.Fatalf("Pause error: %v", err)
+	}
+	until, err = m.PausedUntil()
+	if err != nil || !until.Equal(want) {
+		t.Errorf("PausedUntil() = %v, %v, want %v", until, err, want)
+	}
+
+	if err := m.Resume(); err != nil {
+		t.Fatalf("Resume error: %v", err)
+	}
+	until, err = m.PausedUntil()
+	if err != nil || !until.IsZero() {
+		t.Errorf("PausedUntil() after Resume = %v, %v, want zero", until, err)
+	}
+
+	if err := NewManager("").Pause(want); err == nil {
+		t.Error("Pause without a state file should fail")
+	}
+}
+
+func TestManager_RecordEvent(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-state-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	m := NewManager(tmpDir)
+	if last, err := m.RecordEvent(); err != nil || !last.IsZero() {
+		t.Fatalf("first RecordEvent() = %v, %v, want zero", last, err)
+	}
+	if last, err := m.RecordEvent(); err != nil || time.Since(last) > time.Minute {
+		t.Errorf("RecordEvent() = %v, %v, want the previous event", last, err)
+	}
+
+	// A recorded trigger starts the cooldown without checking it
+	if inCooldown, _ := m.CheckCooldown("stop", 60); inCooldown {
+		t.Fatal("first CheckCooldown() should pass")
+	}
+	if err := m.RecordTrigger("stop"); err != nil {
+		t.Fatal(err)
+	}
+	if inCooldown, _ := m.CheckCooldown("stop", 60); !inCooldown {
+		t.Error("CheckCooldown() after RecordTrigger() should be in cooldown")
+	}
+
+	if _, err := NewManager("").RecordEvent(); err == nil {
+		t.Error("RecordEvent without a state file should fail")
+	}
+}
+
+func TestManager_GC(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-state-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	m := NewManager(tmpDir)
+	now := time.Now().Unix()
+	stale := &State{
+		LastTrigger:  map[string]int64{"stop": now - 60, "retired_event": now - lastTriggerTTL - 1},
+		Fingerprints: map[string]int64{"live": now + 60, "expired": now - 1},
+		Sessions: map[string]*Session{
+			"active":    {Subagents: 1, Updated: now},
+			"abandoned": {Subagents: 2, Updated: now - sessionTTL},
+		},
+		PausedUntil: now - 1,
+		Circuits: map[string]*Circuit{
+			"bark":    {Failures: 1, Updated: now},
+			"webhook": {Failures: 1, Updated: now - circuitTTL},
+		},
+		Queue: []*Delivery{
+			{Channel: "bark", Message: json.RawMessage(`{}`), Queued: now - 60},
+			{Channel: "webhook", Message: json.RawMessage(`{}`), Queued: now - queueTTL},
+		},
+	}
+	data, err := json.Marshal(stale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(m.filePath), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(m.filePath, data, FileMode); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := m.GC()
+	if err != nil {
+		t.Fatalf("GC error: %v", err)
+	}
+	if removed != 6 {
+		t.Errorf("GC removed %d, want 6", removed)
+	}
+
+	state, err := m.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := state.LastTrigger["stop"]; !ok || len(state.LastTrigger) != 1 {
+		t.Errorf("lastTrigger = %v", state.LastTrigger)
+	}
+	if _, ok := state.Fingerprints["live"]; !ok || len(state.Fingerprints) != 1 {
+		t.Errorf("fingerprints = %v", state.Fingerprints)
+	}
+	if _, ok := state.Sessions["active"]; !ok || len(state.Sessions) != 1 {
+		t.Errorf("sessions = %v", state.Sessions)
+	}
+	if state.PausedUntil != 0 {
+		t.Errorf("pausedUntil = %d, want 0", state.PausedUntil)
+	}
+	if _, ok := state.Circuits["bark"]; !ok || len(state.Circuits) != 1 {
+		t.Errorf("circuits = %v", state.Circuits)
+	}
+	if len(state.Queue) != 1 || state.Queue[0].Channel != "bark" {
+		t.Errorf("queue = %v", state.Queue)
+	}
+
+	removed, err = m.GC()
+	if err != nil || removed != 0 {
+		t.Errorf("second GC = %d, %v, want 0", removed, err)
+	}
+}
+
+func TestManager_GCOnSave(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-state-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	m := NewManager(tmpDir)
+	if err := os.MkdirAll(filepath.Dir(m.filePath), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(m.filePath, []byte(`{"lastTrigger": {"retired_event": 1000}}`), FileMode); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.CheckCooldown("stop", 10); err != nil {
+		t.Fatalf("CheckCooldown error: %v", err)
+	}
+	state, err := m.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := state.LastTrigger["retired_event"]; ok {
+		t.Error("stale entry should be removed on save")
+	}
+}
@@ -0,0 +1,67 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Toast is the desktop notification last shown for an event, which repeats
+// of the event replace while it is recent.
+type Toast struct {
+	ID    uint32 `json:"id,omitempty"` // Notification server ID; 0 if unknown
+	Count int    `json:"count"`        // Events the notification stands for
+	Shown int64  `json:"shown"`        // Unix time
+}
+
+// toastTTL is how long a shown desktop notification is remembered.
+const toastTTL = 24 * 60 * 60
+
+// RecentToast returns the desktop notification shown for an event within
+// window, or nil if there is none.
+func (m *Manager) RecentToast(eventType string, window time.Duration) (*Toast, error) {
+	if m.filePath == "" {
+		return nil, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+	t := state.Toasts[eventType]
+	if t == nil || time.Since(time.Unix(t.Shown, 0)) >= window {
+		return nil, nil
+	}
+	return t, nil
+}
+
+// RecordToast records the desktop notification just shown for an event and
+// how many events it stands for.
+func (m *Manager) RecordToast(eventType string, id uint32, count int) error {
+	if m.filePath == "" {
+		return errors.New("no state file available")
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := m.load()
+	if err != nil {
+		state = &State{LastTrigger: make(map[string]int64)}
+	}
+	if state.Toasts == nil {
+		state.Toasts = make(map[string]*Toast)
+	}
+	state.Toasts[eventType] = &Toast{ID: id, Count: count, Shown: time.Now().Unix()}
+
+	if err := m.save(state); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package state
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestManager_Toast(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-state-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	m := NewManager(tmpDir)
+	if toast, err := m.RecentToast("subagent", time.Minute); err != nil || toast != nil {
+		t.Fatalf("RecentToast() = %v, %v, want none", toast, err)
+	}
+	if err := m.RecordToast("subagent", 42, 3); err != nil {
+		t.Fatalf("RecordToast() error = %v", err)
+	}
+	toast, err := m.RecentToast("subagent", time.Minute)
+	if err != nil || toast == nil || toast.ID != 42 || toast.Count != 3 {
+		t.Fatalf("RecentToast() = %+v, %v, want ID 42 for 3 events", toast, err)
+	}
+	if toast, _ := m.RecentToast("stop", time.Minute); toast != nil {
+		t.Errorf("RecentToast(stop) = %+v, want none", toast)
+	}
+	if toast, _ := m.RecentToast("subagent", 0); toast != nil {
+		t.Errorf("RecentToast() outside the window = %+v, want none", toast)
+	}
+
+	s := &State{Toasts: map[string]*Toast{"subagent": {ID: 1, Shown: 100}}}
+	if removed := s.gc(100 + toastTTL); removed != 1 || len(s.Toasts) != 0 {
+		t.Errorf("gc() removed %d, left %v", removed, s.Toasts)
+	}
+}
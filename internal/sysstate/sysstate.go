@@ -0,0 +1,106 @@
+// Package sysstate detects OS state that changes how a notification should
+// be delivered, such as accessibility preferences asking for fewer sounds,
+// a locked screen, or a call using the microphone.
+package sysstate
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// probeTimeout bounds each OS query, so detection never delays a hook much.
+const probeTimeout = 500 * time.Millisecond
+
+// Replaceable in tests.
+var (
+	goos          = runtime.GOOS
+	getenv        = os.Getenv
+	glob          = filepath.Glob
+	commandOutput = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		return exec.CommandContext(ctx, name, args...).Output()
+	}
+)
+
+// output runs a query command with the probe timeout and returns its
+// trimmed output.
+func output(ctx context.Context, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	out, err := commandOutput(ctx, name, args...)
+	return strings.TrimSpace(string(out)), err
+}
+
+// ReducedSound reports whether the OS asks applications for fewer sounds:
+// "Play user interface sound effects" turned off on macOS, or event sounds
+// turned off in GNOME. Settings that can't be read count as not reduced.
+func ReducedSound(ctx context.Context) bool {
+	switch goos {
+	case "darwin":
+		out, err := output(ctx, "defaults", "read", "-g", "com.apple.sound.uiaudio.enabled")
+		return err == nil && out == "0"
+	case "linux":
+		out, err := output(ctx, "gsettings", "get", "org.gnome.desktop.sound", "event-sounds")
+		return err == nil && out == "false"
+	}
+	return false
+}
+
+// ScreenLocked reports whether the user's screen is locked: the CGSession
+// lock flag on macOS, or logind's LockedHint for the current session on
+// Linux. A state that can't be read counts as unlocked.
+func ScreenLocked(ctx context.Context) bool {
+	switch goos {
+	case "darwin":
+		out, err := output(ctx, "ioreg", "-n", "Root", "-d1")
+		return err == nil && strings.Contains(out, `"CGSSessionScreenIsLocked"=Yes`)
+	case "linux":
+		session := getenv("XDG_SESSION_ID")
+		if session == "" {
+			session = "auto"
+		}
+		out, err := output(ctx, "loginctl", "show-session", session, "-p", "LockedHint", "--value")
+		return err == nil && out == "yes"
+	}
+	return false
+}
+
+// inUseScript asks AVFoundation whether another application is using a
+// camera or microphone.
+const inUseScript = `ObjC.import('AVFoundation');
+var inUse = false;
+[$.AVMediaTypeVideo, $.AVMediaTypeAudio].forEach(function (type) {
+	var devices = $.AVCaptureDevice.devicesWithMediaType(type);
+	for (var i = 0; i < devices.count; i++) {
+		if (devices.objectAtIndex(i).inUseByAnotherApplication) inUse = true;
+	}
+});
+inUse;`
+
+// InCall reports whether the microphone or camera is in use, as during a
+// video call: AVFoundation's in-use flags on macOS; on Linux, PulseAudio or
+// PipeWire streams recording from a source, or a process holding a
+// /dev/video device open. A state that can't be read counts as not in use.
+func InCall(ctx context.Context) bool {
+	switch goos {
+	case "darwin":
+		out, err := output(ctx, "osascript", "-l", "JavaScript", "-e", inUseScript)
+		return err == nil && out == "true"
+	case "linux":
+		if out, err := output(ctx, "pactl", "list", "short", "source-outputs"); err == nil && out != "" {
+			return true
+		}
+		devices, _ := glob("/dev/video*")
+		if len(devices) == 0 {
+			return false
+		}
+		// fuser lists the processes using the devices and fails when none do
+		out, err := output(ctx, "fuser", devices...)
+		return err == nil && out != ""
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package sysstate
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeCommands sets the target OS and the output of query commands, keyed
+// by the command line.
+func fakeCommands(t *testing.T, osName string, outputs map[string]string) {
+	t.Helper()
+	origOS, origOutput := goos, commandOutput
+	goos = osName
+	commandOutput = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		out, ok := outputs[strings.Join(append([]string{name}, args...), " ")]
+		if !ok {
+			return nil, errors.New("exit status 1")
+		}
+		return []byte(out + "\n"), nil
+	}
+	t.Cleanup(func() { goos, commandOutput = origOS, origOutput })
+}
+
+func TestReducedSound(t *testing.T) {
+	const macKey = "defaults read -g com.apple.sound.uiaudio.enabled"
+	const gnomeKey = "gsettings get org.gnome.desktop.sound event-sounds"
+	tests := []struct {
+		name    string
+		goos    string
+		outputs map[string]string
+		want    bool
+	}{
+		{"macos effects off", "darwin", map[string]string{macKey: "0"}, true},
+		{"macos effects on", "darwin", map[string]string{macKey: "1"}, false},
+		{"macos unset", "darwin", nil, false},
+		{"gnome event sounds off", "linux", map[string]string{gnomeKey: "false"}, true},
+		{"gnome event sounds on", "linux", map[string]string{gnomeKey: "true"}, false},
+		{"no gsettings", "linux", nil, false},
+		{"other os", "windows", map[string]string{macKey: "0", gnomeKey: "false"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeCommands(t, tt.goos, tt.outputs)
+			if got := ReducedSound(context.Background()); got != tt.want {
+				t.Errorf("ReducedSound() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScreenLocked(t *testing.T) {
+	const macKey = "ioreg -n Root -d1"
+	const logindKey = "loginctl show-session auto -p LockedHint --value"
+	macLocked := `+-o Root  <class IORegistryEntry>
+    "IOConsoleUsers" = ({"CGSSessionScreenIsLocked"=Yes,"kCGSSessionOnConsoleKey"=Yes})`
+	macUnlocked := `+-o Root  <class IORegistryEntry>
+    "IOConsoleUsers" = ({"kCGSSessionOnConsoleKey"=Yes})`
+	tests := []struct {
+		name    string
+		goos    string
+		session string
+		outputs map[string]string
+		want    bool
+	}{
+		{"macos locked", "darwin", "", map[string]string{macKey: macLocked}, true},
+		{"macos unlocked", "darwin", "", map[string]string{macKey: macUnlocked}, false},
+		{"logind locked", "linux", "", map[string]string{logindKey: "yes"}, true},
+		{"logind unlocked", "linux", "", map[string]string{logindKey: "no"}, false},
+		{"logind session id", "linux", "c2", map[string]string{"loginctl show-session c2 -p LockedHint --value": "yes"}, true},
+		{"no logind", "linux", "", nil, false},
+		{"other os", "windows", "", map[string]string{macKey: macLocked, logindKey: "yes"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeCommands(t, tt.goos, tt.outputs)
+			origGetenv := getenv
+			getenv = func(key string) string {
+				if key == "XDG_SESSION_ID" {
+					return tt.session
+				}
+				return ""
+			}
+			defer func() { getenv = origGetenv }()
+			if got := ScreenLocked(context.Background()); got != tt.want {
+				t.Errorf("ScreenLocked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInCall(t *testing.T) {
+	const pactlKey = "pactl list short source-outputs"
+	tests := []struct {
+		name    string
+		goos    string
+		devices []string
+		outputs map[string]string
+		want    bool
+	}{
+		{"macos in use", "darwin", nil, map[string]string{"osascript -l JavaScript -e " + inUseScript: "true"}, true},
+		{"macos idle", "darwin", nil, map[string]string{"osascript -l JavaScript -e " + inUseScript: "false"}, false},
+		{"linux recording", "linux", nil, map[string]string{pactlKey: "42\t1\t7\ts16le 1ch 48000Hz"}, true},
+		{"linux camera", "linux", []string{"/dev/video0"}, map[string]string{pactlKey: "", "fuser /dev/video0": "1234"}, true},
+		{"linux idle camera", "linux", []string{"/dev/video0"}, map[string]string{pactlKey: ""}, false},
+		{"linux idle", "linux", nil, map[string]string{pactlKey: ""}, false},
+		{"other os", "windows", nil, map[string]string{pactlKey: "42"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeCommands(t, tt.goos, tt.outputs)
+			origGlob := glob
+			glob = func(string) ([]string, error) { return tt.devices, nil }
+			defer func() { glob = origGlob }()
+			if got := InCall(context.Background()); got != tt.want {
+				t.Errorf("InCall() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package ccbell
+
+import (
+	"context"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/locale"
+	"github.com/mpolatcan/ccbell/internal/notify"
+)
+
+// flushSubagentBatch waits for the quiet period and returns the number of
+// batched completions, or 0 if a newer completion took over the batch.
+func (n *Notifier) flushSubagentBatch(ctx context.Context, seq int64, quietPeriod time.Duration) (int, error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-time.After(quietPeriod):
+	}
+	return n.state.TakeSubagentBatch(seq)
+}
+
+// subagentSummary sets the message body for a batch of completions.
+func subagentSummary(msg *notify.Message, count int, l locale.Locale) {
+	msg.Count = count
+	if count > 1 {
+		msg.Body = l.Sprintf("%d subagents finished", count)
+	}
+}
+
+// countSummary sets the message body for a request standing for several
+// occurrences of its event.
+func countSummary(msg *notify.Message, count int, l locale.Locale) {
+	if msg.Event == "subagent" {
+		subagentSummary(msg, count, l)
+		return
+	}
+	msg.Count = count
+	msg.Body = l.Sprintf("%s (%d times)", msg.Body, count)
+}
+
+// allDoneSummary returns the message body for a stop that ends a session
+// whose subagent completions were held back.
+func allDoneSummary(held int, l locale.Locale) string {
+	if held == 1 {
+		return l.Sprintf("All done: Claude and 1 subagent finished")
+	}
+	return l.Sprintf("All done: Claude and %d subagents finished", held)
+}
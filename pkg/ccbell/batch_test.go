@@ -0,0 +1,90 @@
+package ccbell
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/locale"
+	"github.com/mpolatcan/ccbell/internal/notify"
+)
+
+func TestFlushSubagentBatch(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-batch-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	n := New(DefaultConfig(), Options{HomeDir: tmpDir})
+	if _, err := n.state.RecordSubagent(); err != nil {
+		t.Fatal(err)
+	}
+	seq, err := n.state.RecordSubagent()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := n.flushSubagentBatch(context.Background(), seq-1, 0)
+	if err != nil {
+		t.Fatalf("flushSubagentBatch() error = %v", err)
+	}
+	if count != 0 {
+		t.Errorf("stale flush count = %d, want 0", count)
+	}
+
+	count, err = n.flushSubagentBatch(context.Background(), seq, 0)
+	if err != nil {
+		t.Fatalf("flushSubagentBatch() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("count = %d, want 2", count)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := n.flushSubagentBatch(ctx, seq, time.Hour); err == nil {
+		t.Error("flushSubagentBatch() should stop when the context is canceled")
+	}
+}
+
+func TestSubagentSummary(t *testing.T) {
+	msg := notify.NewMessage("subagent")
+	single := msg.Body
+	subagentSummary(msg, 1, locale.Locale{})
+	if msg.Body != single {
+		t.Errorf("single completion body = %q, want %q", msg.Body, single)
+	}
+
+	subagentSummary(msg, 4, locale.Locale{})
+	if msg.Body != "4 subagents finished" {
+		t.Errorf("body = %q, want %q", msg.Body, "4 subagents finished")
+	}
+}
+
+func TestCountSummary(t *testing.T) {
+	msg := notify.NewMessage("stop")
+	countSummary(msg, 3, locale.Locale{})
+	if msg.Body != "Claude finished responding (3 times)" {
+		t.Errorf("stop body = %q", msg.Body)
+	}
+
+	msg = notify.NewMessage("subagent")
+	countSummary(msg, 2, locale.Locale{})
+	if msg.Body != "2 subagents finished" {
+		t.Errorf("subagent body = %q", msg.Body)
+	}
+}
+
+func TestAllDoneSummary(t *testing.T) {
+	if got := allDoneSummary(1, locale.Locale{}); got != "All done: Claude and 1 subagent finished" {
+		t.Errorf("allDoneSummary(1) = %q", got)
+	}
+	if got := allDoneSummary(3, locale.Locale{}); got != "All done: Claude and 3 subagents finished" {
+		t.Errorf("allDoneSummary(3) = %q", got)
+	}
+	if got := allDoneSummary(3, locale.New("tr")); got != "Hepsi tamam: Claude ve 3 alt ajan tamamlandı" {
+		t.Errorf("allDoneSummary(3) in Turkish = %q", got)
+	}
+}
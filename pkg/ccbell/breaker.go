@@ -0,0 +1,42 @@
+package ccbell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/notify"
+)
+
+// withBreaker wraps a remote channel with its circuit breaker: deliveries
+// are skipped while the circuit is open, and each outcome updates it.
+func (n *Notifier) withBreaker(ch Channel) Channel {
+	failures, cooldownSecs := n.cfg.BreakerValues()
+	if failures <= 0 || n.opts.HomeDir == "" {
+		return ch
+	}
+	name := ch.Name()
+	cooldown := time.Duration(cooldownSecs) * time.Second
+	return notify.Func(name, ch.Timeout(), func(ctx context.Context, msg *notify.Message) error {
+		until, err := n.state.CircuitOpenUntil(name)
+		if err != nil {
+			n.log.Debug("Circuit check error: %v, delivering anyway", err)
+		} else if !until.IsZero() {
+			n.log.Debug("Circuit open for %s until %s, skipping delivery", name, until.Format(time.TimeOnly))
+			return fmt.Errorf("skipped after repeated failures, next attempt after %s", until.Format(time.TimeOnly))
+		}
+
+		err = ch.Send(ctx, msg)
+		if err != nil && errors.Is(context.Cause(ctx), errFlushCutOff) {
+			return err // Cut off by ccbell, not a failure of the endpoint
+		}
+		opened, stateErr := n.state.RecordDelivery(name, err == nil, failures, cooldown)
+		if stateErr != nil {
+			n.log.Debug("Could not record %s delivery: %v", name, stateErr)
+		} else if opened {
+			n.log.Warn("%s failed %d times in a row, pausing deliveries for %s", name, failures, cooldown)
+		}
+		return err
+	})
+}
@@ -0,0 +1,44 @@
+package ccbell
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+)
+
+func TestNotifyCircuitBreaker(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-breaker-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	one, two, queue := 1, 2, false
+	cfg := newTestConfig()
+	cfg.Webhook = &config.Webhook{URL: server.URL}
+	cfg.Retry = &config.Retry{Attempts: &one, Queue: &queue}
+	cfg.CircuitBreaker = &config.CircuitBreaker{Failures: &two}
+	cfg.Events["stop"].Channels = []string{config.ChannelWebhook}
+	n := New(cfg, Options{HomeDir: tmpDir})
+
+	for i := 0; i < 3; i++ {
+		if err := n.Notify(context.Background(), Request{Event: "stop"}); err == nil {
+			t.Errorf("Notify() %d should fail", i)
+		}
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("webhook received %d requests, want 2 before the circuit opened", got)
+	}
+}
@@ -0,0 +1,86 @@
+// Package ccbell exposes the ccbell notification pipeline so other Go tools
+// can load the same configuration and trigger the same notifications.
+package ccbell
+
+import (
+	"github.com/mpolatcan/ccbell/internal/audio"
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/logger"
+	"github.com/mpolatcan/ccbell/internal/notify"
+)
+
+// Core types, re-exported from the internal packages.
+type (
+	// Config is a loaded ccbell configuration.
+	Config = config.Config
+	// Event is the configuration for a single event type.
+	Event = config.Event
+	// Message is a notification delivered to channels.
+	Message = notify.Message
+	// Channel is a notification delivery mechanism.
+	Channel = notify.Channel
+	// Player plays sounds on the current platform.
+	Player = audio.Player
+	// Logger writes debug logs to ~/.claude/ccbell.log.
+	Logger = logger.Logger
+)
+
+// ConfigPath returns the default config file path for a home directory,
+// honoring $CCBELL_CONFIG.
+func ConfigPath(homeDir string) string {
+	return config.Path(homeDir)
+}
+
+// LoadConfig loads and merges the system, global and profile config layers
+// for the config file at path. It returns the config and the path it was
+// loaded from.
+func LoadConfig(path string) (*Config, string, error) {
+	return config.LoadFile(path)
+}
+
+// DefaultConfig returns the built-in configuration.
+func DefaultConfig() *Config {
+	return config.Default()
+}
+
+// ValidateEventType returns an error if eventType is not a known event.
+func ValidateEventType(eventType string) error {
+	return config.ValidateEventType(eventType)
+}
+
+// NewPlayer creates a sound player that looks for bundled sounds under
+// pluginRoot.
+func NewPlayer(pluginRoot string) *Player {
+	return audio.NewPlayer(pluginRoot)
+}
+
+// NewMessage creates a message with the default title and body for an event.
+func NewMessage(eventType string) *Message {
+	return notify.NewMessage(eventType)
+}
+
+// NewLogger creates a debug logger writing under homeDir.
+func NewLogger(enabled bool, homeDir string) *Logger {
+	return logger.New(enabled, homeDir)
+}
+
+func derefBool(ptr *bool, defaultVal bool) bool {
+	if ptr == nil {
+		return defaultVal
+	}
+	return *ptr
+}
+
+func derefFloat(ptr *float64, defaultVal float64) float64 {
+	if ptr == nil {
+		return defaultVal
+	}
+	return *ptr
+}
+
+func derefInt(ptr *int, defaultVal int) int {
+	if ptr == nil {
+		return defaultVal
+	}
+	return *ptr
+}
@@ -0,0 +1,36 @@
+package ccbell
+
+import "testing"
+
+func TestDerefFunctions(t *testing.T) {
+	// Test derefBool
+	trueVal := true
+	falseVal := false
+	if derefBool(nil, true) != true {
+		t.Error("derefBool with nil should return default")
+	}
+	if derefBool(&trueVal, false) != true {
+		t.Error("derefBool with true pointer should return true")
+	}
+	if derefBool(&falseVal, true) != false {
+		t.Error("derefBool with false pointer should return false")
+	}
+
+	// Test derefFloat
+	fval := 0.75
+	if derefFloat(nil, 0.5) != 0.5 {
+		t.Error("derefFloat with nil should return default")
+	}
+	if derefFloat(&fval, 0.1) != 0.75 {
+		t.Error("derefFloat with pointer should return value")
+	}
+
+	// Test derefInt
+	ival := 42
+	if derefInt(nil, 10) != 10 {
+		t.Error("derefInt with nil should return default")
+	}
+	if derefInt(&ival, 0) != 42 {
+		t.Error("derefInt with pointer should return value")
+	}
+}
@@ -0,0 +1,200 @@
+package ccbell
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/notify"
+)
+
+// soundChannelTimeout bounds the sound channel. It is generous because the
+// first run on Linux may install an audio player.
+const soundChannelTimeout = 30 * time.Second
+
+// ChannelFactory creates a channel for one notification of event. ctx is the
+// context passed to Notify; unlike the per-delivery context given to Send it
+// stays live after Send returns, so use it for work that continues in the
+// background (e.g. playback). A nil Channel skips delivery.
+type ChannelFactory func(ctx context.Context, n *Notifier, event *Event) (Channel, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]ChannelFactory{
+		config.ChannelSound:    newSoundChannel,
+		config.ChannelDesktop:  newDesktopChannel,
+		config.ChannelWebhook:  newWebhookChannel,
+		config.ChannelTerminal: newTerminalChannel,
+		config.ChannelUserVar:  newUserVarChannel,
+		config.ChannelBark:     newBarkChannel,
+		config.ChannelLED:      newLEDChannel,
+		config.ChannelText:     newTextChannel,
+	}
+)
+
+// RegisterChannel adds or replaces a channel so events can list it in
+// "channels". Call it before loading configuration.
+func RegisterChannel(name string, factory ChannelFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = factory
+	config.ValidChannels[name] = true
+}
+
+// buildChannels creates the channels for the given names, skipping unknown
+// or unconfigured ones. Remote channels retry failed deliveries, then queue
+// them for a later invocation, and pause after repeated failures.
+func (n *Notifier) buildChannels(ctx context.Context, names []string, event *Event) []Channel {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	channels := make([]Channel, 0, len(names))
+	for _, name := range names {
+		ch := n.buildChannel(ctx, name, event)
+		if ch == nil {
+			continue
+		}
+		if config.RemoteChannels[name] {
+			ch = n.withBreaker(notify.WithRetry(ch, n.retryPolicy(), n.log.Debug))
+			if n.cfg.QueuesFailedDeliveries() {
+				ch = n.queueOnFailure(ch)
+			}
+		}
+		channels = append(channels, ch)
+	}
+	return channels
+}
+
+// buildChannel creates one channel, or returns nil if it is unknown or
+// unconfigured. The caller holds registryMu.
+func (n *Notifier) buildChannel(ctx context.Context, name string, event *Event) Channel {
+	factory, ok := registry[name]
+	if !ok {
+		n.log.Debug("Unknown channel %s, skipping", name)
+		return nil
+	}
+	ch, err := factory(ctx, n, event)
+	if err != nil {
+		n.log.Debug("Channel %s unavailable: %v", name, err)
+		return nil
+	}
+	return ch
+}
+
+// retryPolicy returns the retry policy for remote channels.
+func (n *Notifier) retryPolicy() notify.RetryPolicy {
+	policy := notify.DefaultRetryPolicy
+	if r := n.cfg.Retry; r != nil {
+		if r.Attempts != nil {
+			policy.Attempts = *r.Attempts
+		}
+		if r.Budget != nil {
+			policy.Budget = time.Duration(*r.Budget) * time.Second
+		}
+	}
+	return policy
+}
+
+// newSoundChannel plays the event's sound, falling back to soundFallback
+// when it can't. Playback is bound to the Notify context so it isn't cut off
+// when the delivery timeout is released.
+func newSoundChannel(ctx context.Context, n *Notifier, event *Event) (Channel, error) {
+	return notify.Func(config.ChannelSound, soundChannelTimeout, func(sendCtx context.Context, msg *notify.Message) error {
+		if err := sendCtx.Err(); err != nil {
+			return err
+		}
+		if err := n.playSound(ctx, event, msg.Event, msg.SessionID); err != nil {
+			return n.soundFallback(sendCtx, event, msg, err)
+		}
+		return nil
+	}), nil
+}
+
+func newDesktopChannel(_ context.Context, n *Notifier, _ *Event) (Channel, error) {
+	desktop := notify.NewDesktop(notify.DefaultTimeout)
+	if n.cfg.Desktop != nil {
+		desktop.SetFocusApp(n.cfg.Desktop.FocusApp)
+	}
+	desktop.SetBypassDND(n.cfg.BypassesDND())
+	if _, ok := n.cfg.DesktopGroupWindow(); ok {
+		desktop.SetShown(func(msg *notify.Message, id uint32) {
+			if err := n.state.RecordToast(msg.Event, id, max(1, msg.Count)); err != nil {
+				n.log.Debug("Could not record desktop notification: %v", err)
+			}
+		})
+	}
+	return desktop, nil
+}
+
+func newTerminalChannel(_ context.Context, n *Notifier, _ *Event) (Channel, error) {
+	sequence := ""
+	if n.cfg.Terminal != nil {
+		sequence = n.cfg.Terminal.Sequence
+	}
+	return notify.NewTerminal(sequence, notify.DefaultTimeout), nil
+}
+
+func newUserVarChannel(_ context.Context, n *Notifier, _ *Event) (Channel, error) {
+	name := ""
+	if n.cfg.Terminal != nil {
+		name = n.cfg.Terminal.UserVar
+	}
+	return notify.NewUserVar(name, notify.DefaultTimeout), nil
+}
+
+func newWebhookChannel(_ context.Context, n *Notifier, _ *Event) (Channel, error) {
+	webhook := n.cfg.Webhook
+	if webhook == nil {
+		return nil, nil
+	}
+	timeout := notify.DefaultTimeout
+	if webhook.Timeout != nil {
+		timeout = time.Duration(*webhook.Timeout) * time.Second
+	}
+	channel := notify.NewWebhook(webhook.URL, webhook.Headers, timeout)
+	channel.SetPreset(webhook.Preset)
+	if webhook.Template != "" {
+		tmpl, err := config.ParseTemplate("webhook.template", webhook.Template)
+		if err != nil {
+			return nil, err
+		}
+		channel.SetTemplate(tmpl)
+	}
+	return channel, nil
+}
+
+func newBarkChannel(_ context.Context, n *Notifier, _ *Event) (Channel, error) {
+	bark := n.cfg.Bark
+	if bark == nil {
+		return nil, nil
+	}
+	timeout := notify.DefaultTimeout
+	if bark.Timeout != nil {
+		timeout = time.Duration(*bark.Timeout) * time.Second
+	}
+	channel := notify.NewBark(bark.Server, bark.DeviceKey, timeout)
+	if bark.Body != "" {
+		tmpl, err := config.ParseTemplate("bark.body", bark.Body)
+		if err != nil {
+			return nil, err
+		}
+		channel.SetBodyTemplate(tmpl)
+	}
+	return channel, nil
+}
+
+func newLEDChannel(_ context.Context, n *Notifier, _ *Event) (Channel, error) {
+	led := n.cfg.LED
+	if led == nil {
+		return notify.NewLED(nil, 0, notify.DefaultTimeout), nil
+	}
+	return notify.NewLED(led.Colors, derefInt(led.Blinks, 0), notify.DefaultTimeout), nil
+}
+
+func newTextChannel(_ context.Context, n *Notifier, _ *Event) (Channel, error) {
+	if n.cfg.Text != nil && derefBool(n.cfg.Text.Speak, false) {
+		return notify.NewText(true, notify.SpeakTimeout), nil
+	}
+	return notify.NewText(false, notify.DefaultTimeout), nil
+}
@@ -0,0 +1,79 @@
+package ccbell
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/notify"
+)
+
+func TestBuildChannels(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		cfg   *Config
+		want  []string
+	}{
+		{
+			name:  "sound only",
+			names: []string{"sound"},
+			cfg:   &Config{},
+			want:  []string{"sound"},
+		},
+		{
+			name:  "all channels",
+			names: []string{"sound", "desktop", "terminal", "uservar", "led", "text", "webhook"},
+			cfg:   &Config{Webhook: &config.Webhook{URL: "https://example.com"}},
+			want:  []string{"sound", "desktop", "terminal", "uservar", "led", "text", "webhook"},
+		},
+		{
+			name:  "webhook without config is skipped",
+			names: []string{"desktop", "webhook"},
+			cfg:   &Config{},
+			want:  []string{"desktop"},
+		},
+		{
+			name:  "bark with config",
+			names: []string{"bark"},
+			cfg:   &Config{Bark: &config.Bark{DeviceKey: "key"}},
+			want:  []string{"bark"},
+		},
+		{
+			name:  "bark without config is skipped",
+			names: []string{"bark", "sound"},
+			cfg:   &Config{},
+			want:  []string{"sound"},
+		},
+		{
+			name:  "unknown channel is skipped",
+			names: []string{"pager", "sound"},
+			cfg:   &Config{},
+			want:  []string{"sound"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := New(tt.cfg, Options{})
+			channels := n.buildChannels(context.Background(), tt.names, &Event{})
+			if len(channels) != len(tt.want) {
+				t.Fatalf("got %d channels, want %d", len(channels), len(tt.want))
+			}
+			for i, ch := range channels {
+				if ch.Name() != tt.want[i] {
+					t.Errorf("channel %d = %q, want %q", i, ch.Name(), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTextChannelSpeakTimeout(t *testing.T) {
+	speak := true
+	n := New(&Config{Text: &config.Text{Speak: &speak}}, Options{})
+	channels := n.buildChannels(context.Background(), []string{"text"}, &Event{})
+	if len(channels) != 1 || channels[0].Timeout() != notify.SpeakTimeout {
+		t.Errorf("text channel with speak should use SpeakTimeout, got %v", channels)
+	}
+}
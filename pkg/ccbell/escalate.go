@@ -0,0 +1,63 @@
+package ccbell
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/locale"
+	"github.com/mpolatcan/ccbell/internal/notify"
+	"github.com/mpolatcan/ccbell/internal/state"
+)
+
+// recordUnacked remembers a sent notification until it is acknowledged,
+// scheduling its escalation if the event has one.
+func (n *Notifier) recordUnacked(msg *notify.Message, event *Event) {
+	if n.opts.HomeDir == "" {
+		return
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		n.log.Debug("Could not record notification for acknowledgement: %v", err)
+		return
+	}
+	u := &state.Unacked{Event: msg.Event, Message: data, Payload: msg.Payload, Sent: msg.Timestamp.Unix()}
+	if event.Escalate != nil {
+		u.EscalateAt = u.Sent + int64(event.Escalate.After)
+		n.log.Debug("Escalating in %ds unless acknowledged", event.Escalate.After)
+	}
+	if err := n.state.SetUnacked(u); err != nil {
+		n.log.Debug("Could not record notification for acknowledgement: %v", err)
+	}
+}
+
+// Escalate re-sends the last notification to its escalation channels if it
+// is still unacknowledged when its escalation is due. Each notification is
+// escalated at most once. It reports whether one was sent.
+func (n *Notifier) Escalate(ctx context.Context) (bool, error) {
+	now := time.Now()
+	u, err := n.state.TakeDueEscalation(now)
+	if err != nil || u == nil {
+		return false, err
+	}
+
+	var msg notify.Message
+	if err := json.Unmarshal(u.Message, &msg); err != nil {
+		return false, fmt.Errorf("unreadable unacknowledged notification: %w", err)
+	}
+	msg.Payload = u.Payload
+	event := n.cfg.GetEventConfig(msg.Event)
+	if event.Escalate == nil {
+		n.log.Debug("Escalation for %s no longer configured, skipping", msg.Event)
+		return false, nil
+	}
+	msg.Body = locale.New(n.language()).Sprintf("%s (unacknowledged for %s)", msg.Body, now.Sub(time.Unix(u.Sent, 0)).Round(time.Minute))
+
+	names := n.cfg.EscalationChannels(event.Escalate)
+	n.log.Debug("Escalating unacknowledged %s notification to %v", msg.Event, names)
+	if err := notify.Dispatch(ctx, &msg, n.buildChannels(ctx, names, event)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,74 @@
+package ccbell
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+)
+
+func TestEscalate(t *testing.T) {
+	rec := &recordingChannel{}
+	RegisterChannel("recording", rec.factory)
+
+	tmpDir, err := os.MkdirTemp("", "ccbell-escalate-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg := newTestConfig()
+	cfg.Events["permission_prompt"].Escalate = &config.Escalation{After: 60, Channels: []string{"recording"}}
+	n := New(cfg, Options{HomeDir: tmpDir})
+	ctx := context.Background()
+
+	if err := n.Notify(ctx, Request{Event: "permission_prompt"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if sent, err := n.Escalate(ctx); err != nil || sent {
+		t.Errorf("Escalate() before it is due = %v, %v, want false", sent, err)
+	}
+
+	// Make the escalation due
+	u, err := n.state.Ack()
+	if err != nil || u == nil || u.EscalateAt != u.Sent+60 {
+		t.Fatalf("unacknowledged notification = %+v, %v", u, err)
+	}
+	u.EscalateAt = time.Now().Unix()
+	if err := n.state.SetUnacked(u); err != nil {
+		t.Fatal(err)
+	}
+	if sent, err := n.Escalate(ctx); err != nil || !sent {
+		t.Fatalf("Escalate() = %v, %v, want true", sent, err)
+	}
+	bodies := rec.bodies()
+	if len(bodies) != 2 || !strings.HasPrefix(bodies[1], "Claude needs your permission (unacknowledged") {
+		t.Errorf("delivered = %v", bodies)
+	}
+	// Escalated once only
+	if sent, err := n.Escalate(ctx); err != nil || sent {
+		t.Errorf("second Escalate() = %v, %v, want false", sent, err)
+	}
+
+	// The next event acknowledges the previous notification
+	if err := n.Notify(ctx, Request{Event: "permission_prompt"}); err != nil {
+		t.Fatal(err)
+	}
+	u, _ = n.state.Ack()
+	u.EscalateAt = time.Now().Unix()
+	if err := n.state.SetUnacked(u); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Notify(ctx, Request{Event: "stop"}); err != nil {
+		t.Fatal(err)
+	}
+	if sent, err := n.Escalate(ctx); err != nil || sent {
+		t.Errorf("Escalate() after the next event = %v, %v, want false", sent, err)
+	}
+	if got := len(rec.bodies()); got != 4 {
+		t.Errorf("delivered %d notifications, want 4", got)
+	}
+}
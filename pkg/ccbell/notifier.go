@@ -0,0 +1,445 @@
+package ccbell
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/gitinfo"
+	"github.com/mpolatcan/ccbell/internal/history"
+	"github.com/mpolatcan/ccbell/internal/hook"
+	"github.com/mpolatcan/ccbell/internal/journal"
+	"github.com/mpolatcan/ccbell/internal/locale"
+	"github.com/mpolatcan/ccbell/internal/logger"
+	"github.com/mpolatcan/ccbell/internal/notify"
+	"github.com/mpolatcan/ccbell/internal/state"
+	"github.com/mpolatcan/ccbell/internal/sysstate"
+)
+
+// OS state probes; replaceable in tests.
+var (
+	reducedSound = sysstate.ReducedSound
+	screenLocked = sysstate.ScreenLocked
+	inCall       = sysstate.InCall
+)
+
+// Options configures a Notifier.
+type Options struct {
+	HomeDir    string    // Location of state, journal and caches; empty disables them
+	PluginRoot string    // Plugin directory containing bundled sounds
+	Logger     *Logger   // Debug logger; nil disables logging
+	Warn       io.Writer // User-facing warnings; nil discards them
+
+	// Prompt asks the user a yes/no question, such as whether to install a
+	// missing audio player. Nil when not running in a terminal.
+	Prompt func(question string) bool
+
+	// SpawnFlush starts a process that later calls Notify with FlushBatch
+	// set to seq. Subagent batching is unavailable when nil.
+	SpawnFlush func(seq int64) error
+
+	// SpawnRank starts a process that times the Linux audio players and
+	// caches their ranking, so notifications don't wait for it. Players are
+	// tried in the default order until a ranking is cached; none is made
+	// when nil. It is called once for a burst of notifications, and again
+	// only if a ranking started five minutes ago hasn't been cached.
+	SpawnRank func() error
+}
+
+// Request describes one notification to process.
+type Request struct {
+	Event     string // Event type, e.g. "stop"
+	Payload   []byte // Raw hook payload JSON (optional)
+	NoJournal bool   // Don't record the payload (e.g. replays)
+
+	// Count is how many occurrences of the event the request stands for,
+	// for wrappers that coalesce events. Zero counts as one.
+	Count int
+
+	// FlushBatch, when non-zero, emits the subagent batch summary for this
+	// sequence after the quiet period instead of recording a completion.
+	FlushBatch int64
+}
+
+// Notifier runs the ccbell event pipeline: enable checks, quiet hours,
+// subagent handling, cooldown, dedupe, then parallel channel dispatch.
+type Notifier struct {
+	cfg   *Config
+	opts  Options
+	log   *Logger
+	state *state.Manager
+}
+
+// New creates a Notifier for a loaded configuration.
+func New(cfg *Config, opts Options) *Notifier {
+	log := opts.Logger
+	if log == nil {
+		log = logger.New(false, "")
+	}
+	return &Notifier{
+		cfg:   cfg,
+		opts:  opts,
+		log:   log,
+		state: state.NewManager(opts.HomeDir),
+	}
+}
+
+// Config returns the notifier's configuration.
+func (n *Notifier) Config() *Config {
+	return n.cfg
+}
+
+// Notify processes a single event. It returns nil when the event is
+// suppressed by configuration, and the aggregated channel errors otherwise.
+// Canceling ctx aborts in-flight deliveries and stops playback.
+func (n *Notifier) Notify(ctx context.Context, req Request) error {
+	cfg, log := n.cfg, n.log
+	eventType := req.Event
+
+	if err := config.ValidateEventType(eventType); err != nil {
+		return err
+	}
+
+	payload, err := hook.Parse(req.Payload)
+	if err != nil {
+		log.Debug("Hook payload unavailable: %v", err)
+	}
+
+	// === Acknowledge the previous notification ===
+	if req.FlushBatch == 0 {
+		if u, err := n.state.Ack(); err != nil {
+			log.Debug("Could not acknowledge the previous notification: %v", err)
+		} else if u != nil {
+			log.Debug("Next event acknowledged the %s notification", u.Event)
+		}
+	}
+
+	// === Journal the payload ===
+	if maxSizeKB, ok := cfg.JournalMaxSizeKB(); ok && !req.NoJournal && req.FlushBatch == 0 {
+		j := journal.New(n.opts.HomeDir, int64(maxSizeKB)*1024)
+		if id, err := j.Append(eventType, req.Payload); err != nil {
+			log.Warn("Journal write failed: %v", err)
+		} else {
+			log.Debug("Journaled event #%d to %s", id, j.Path())
+		}
+	}
+
+	// === Check global enable ===
+	if !cfg.Enabled {
+		log.Debug("Plugin disabled globally, exiting")
+		return nil
+	}
+
+	// === Check pause ===
+	if until, err := n.state.PausedUntil(); err != nil {
+		log.Warn("Pause check error: %v, proceeding with notification", err)
+	} else if !until.IsZero() {
+		if time.Now().Before(until) {
+			log.Debug("Paused until %s, suppressing notification", until.Format("2006-01-02 15:04"))
+			return nil
+		}
+		log.Debug("Pause ended at %s, resuming notifications", until.Format("2006-01-02 15:04"))
+		if err := n.state.Resume(); err != nil {
+			log.Debug("Could not clear expired pause: %v", err)
+		}
+	}
+
+	// === Detect a fresh start after a long idle ===
+	fresh := false
+	if idle, ok := cfg.FreshStartAfter(); ok && req.FlushBatch == 0 {
+		now := time.Now()
+		if last, err := n.state.RecordEvent(); err != nil {
+			log.Debug("Could not record event time: %v", err)
+		} else if !last.IsZero() && (now.Sub(last) >= idle || last.After(now)) {
+			// A last event in the future means the clock jumped back
+			log.Debug("No events since %s, fresh start", last.Format("2006-01-02 15:04"))
+			fresh = true
+		}
+	}
+
+	// === Get event configuration ===
+	eventCfg := cfg.GetEventConfig(eventType)
+	log.Debug("Active profile: %s", cfg.ActiveProfile)
+	log.Debug("Event config: enabled=%v, sound=%s, volume=%.2f, cooldown=%d",
+		derefBool(eventCfg.Enabled, true), eventCfg.Sound, derefFloat(eventCfg.Volume, 0.5), derefInt(eventCfg.Cooldown, 0))
+
+	// === Apply branch/worktree rules ===
+	repo := n.detectRepo(payload.Cwd)
+	if len(cfg.Branches) > 0 && repo.Worktree != "" {
+		eventCfg = cfg.ForBranch(eventType, eventCfg, repo.Branch, repo.Worktree)
+	}
+
+	// === Pick sound by response length ===
+	if class := eventCfg.ResponseLength.Classify(payload.Duration(), payload.OutputTokens()); class != "" {
+		log.Debug("Response is %s (duration=%s, tokens=%d)", class, payload.Duration(), payload.OutputTokens())
+		eventCfg = eventCfg.ForResponseLength(class)
+	}
+
+	// === Pick the session's sound variant ===
+	if len(eventCfg.SessionSounds) > 0 && payload.SessionID != "" {
+		eventCfg = eventCfg.ForSession(payload.SessionID)
+		log.Debug("Session %s plays sound variant %s", payload.SessionID, eventCfg.Sound)
+	}
+
+	// === Check event enable ===
+	if !derefBool(eventCfg.Enabled, true) {
+		log.Debug("Event '%s' is disabled, exiting", eventType)
+		return nil
+	}
+
+	// === Check quiet hours ===
+	if cfg.IsInQuietHours() {
+		qh := cfg.QuietHoursFor(time.Now())
+		log.Debug("In quiet hours (%s-%s), suppressing notification", qh.Start, qh.End)
+		return nil
+	}
+
+	locale := locale.New(n.language())
+	msg := notify.NewLocalizedMessage(eventType, locale)
+	msg.Payload = req.Payload
+	msg.SessionID = payload.SessionID
+	if cfg.SendsHostname() {
+		msg.Hostname, _ = os.Hostname()
+	}
+	if f := eventCfg.Format; f != nil {
+		if f.Icon != nil {
+			msg.Icon = *f.Icon
+		}
+		if f.Severity != "" {
+			msg.Severity = f.Severity
+		}
+	}
+	if d := eventCfg.Desktop; d != nil {
+		msg.Urgency = d.Urgency
+		if d.Timeout != nil {
+			expire := time.Duration(*d.Timeout) * time.Second
+			msg.Expire = &expire
+		}
+	}
+	msg.SetContext(projectName(payload.Cwd, repo), repo.Branch, cfg.Title)
+	count := max(1, req.Count)
+	if count > 1 {
+		log.Debug("Request stands for %d %s events", count, eventType)
+	}
+
+	// === Replace the event's recent desktop notification ===
+	replaced := 0
+	if window, ok := cfg.DesktopGroupWindow(); ok && slices.Contains(config.EventChannels(eventCfg), config.ChannelDesktop) {
+		toast, err := n.state.RecentToast(eventType, window)
+		if err != nil {
+			log.Debug("Could not read the last desktop notification: %v", err)
+		} else if toast != nil {
+			log.Debug("Replacing the desktop notification for %d earlier %s events", toast.Count, eventType)
+			msg.ReplaceID = toast.ID
+			replaced = toast.Count
+		}
+	}
+	if count+replaced > 1 {
+		countSummary(msg, count+replaced, locale)
+	}
+
+	// === Hold subagent completions until the session's stop ===
+	if cfg.WaitForSubagents() && payload.SessionID != "" {
+		switch eventType {
+		case "subagent":
+			if err := n.state.HoldSubagents(payload.SessionID, count); err != nil {
+				log.Debug("Could not hold subagent completion: %v, notifying immediately", err)
+			} else {
+				log.Debug("Holding subagent completion until session %s stops", payload.SessionID)
+				return nil
+			}
+		case "stop":
+			held, err := n.state.TakeHeldSubagents(payload.SessionID)
+			if err != nil {
+				log.Debug("Could not read held subagents: %v", err)
+			} else if held > 0 {
+				log.Debug("Session finished with %d subagents, playing all-done sound", held)
+				eventCfg = cfg.AllDoneEvent(eventCfg)
+				msg.Body = allDoneSummary(held, locale)
+			}
+		}
+	}
+
+	// === Batch subagent completions ===
+	if quietPeriod, ok := cfg.SubagentQuietPeriod(); ok && eventType == "subagent" {
+		if req.FlushBatch != 0 {
+			count, err := n.flushSubagentBatch(ctx, req.FlushBatch, time.Duration(quietPeriod)*time.Second)
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				log.Debug("Subagent batch taken over by a newer completion, exiting")
+				return nil
+			}
+			log.Debug("Flushing subagent batch of %d", count)
+			subagentSummary(msg, count+replaced, locale)
+		} else if n.opts.SpawnFlush != nil {
+			seq, err := n.state.RecordSubagents(count)
+			if err == nil {
+				if err = n.opts.SpawnFlush(seq); err != nil {
+					_, _ = n.state.TakeSubagentBatch(seq)
+				}
+			}
+			if err == nil {
+				log.Debug("Subagent completion %d batched, summary after %ds quiet", seq, quietPeriod)
+				return nil
+			}
+			log.Debug("Subagent batching unavailable: %v, notifying immediately", err)
+		}
+	}
+
+	// === Check cooldown ===
+	if fresh {
+		// Stale trigger times from before a sleep or clock jump don't count
+		if err := n.state.RecordTrigger(eventType); err != nil {
+			log.Debug("Could not record trigger time: %v", err)
+		}
+		full := 1.0
+		fullVolume := *eventCfg
+		fullVolume.Volume = &full
+		eventCfg = &fullVolume
+		log.Debug("Fresh start, bypassing cooldown at full volume")
+	} else {
+		inCooldown, err := n.state.CheckCooldown(eventType, derefInt(eventCfg.Cooldown, 0))
+		if err != nil {
+			log.Warn("Cooldown check error: %v, proceeding with notification", err)
+		} else if inCooldown {
+			log.Debug("In cooldown period (%ds), suppressing notification", derefInt(eventCfg.Cooldown, 0))
+			return nil
+		}
+	}
+
+	// === Check duplicate payload ===
+	if dedupeWindow := derefInt(eventCfg.DedupeWindow, 0); dedupeWindow > 0 && !payload.Empty() {
+		isDuplicate, err := n.state.CheckDuplicate(payload.Fingerprint(eventType), dedupeWindow)
+		if err != nil {
+			log.Warn("Duplicate check error: %v, proceeding with notification", err)
+		} else if isDuplicate {
+			log.Debug("Identical payload seen within %ds, suppressing notification", dedupeWindow)
+			return nil
+		}
+	}
+
+	log.Debug("All checks passed, proceeding to notify")
+
+	// === Route away from a locked screen ===
+	if cfg.ScreenLock != nil && screenLocked(ctx) {
+		eventCfg = cfg.ForLockedScreen(eventCfg)
+		if len(eventCfg.Channels) == 0 {
+			log.Debug("Screen locked and no remote channels, suppressing notification")
+			return nil
+		}
+		log.Debug("Screen locked, routing to remote channels")
+	}
+
+	// === Keep quiet during calls ===
+	if cfg.ActiveCall != nil && slices.Contains(config.EventChannels(eventCfg), config.ChannelSound) && inCall(ctx) {
+		eventCfg = cfg.ForActiveCall(eventCfg)
+		if len(eventCfg.Channels) == 0 {
+			log.Debug("Microphone or camera in use, suppressing notification")
+			return nil
+		}
+		log.Debug("Microphone or camera in use, notifying without sound")
+	}
+
+	// === Adapt to reduced-sound accessibility settings ===
+	if slices.Contains(config.EventChannels(eventCfg), config.ChannelSound) && cfg.FollowsSystemSound() && reducedSound(ctx) {
+		log.Debug("OS accessibility settings ask for fewer sounds, adapting notification")
+		eventCfg = cfg.ForReducedSound(eventCfg)
+	}
+
+	// === Redeliver queued remote notifications alongside this one ===
+	// Queued channels may take their whole timeout, so they mustn't hold
+	// up the local sound, and are cut off after queueFlushWait.
+	flushCtx, cancelFlush := context.WithTimeoutCause(ctx, queueFlushWait, errFlushCutOff)
+	defer cancelFlush()
+	flushed := make(chan struct{})
+	go func() {
+		defer close(flushed)
+		if _, err := n.FlushQueue(flushCtx); err != nil {
+			log.Warn("Could not flush queued deliveries: %v", err)
+		}
+	}()
+
+	// === Render the event's text template ===
+	if eventCfg.Format != nil {
+		msg.ApplyFormat(eventCfg.Format.Body)
+	}
+
+	// === Dispatch to channels ===
+	channelNames := config.EventChannels(eventCfg)
+	log.Debug("Channels: %v", channelNames)
+	channels := n.buildChannels(ctx, channelNames, eventCfg)
+	err = notify.Dispatch(ctx, msg, channels)
+	n.recordHistory(eventType, payload.SessionID, err)
+	n.recordUnacked(msg, eventCfg)
+	<-flushed
+	if err != nil {
+		log.Error("Notification failed: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// detectRepo returns the git checkout containing the hook's working
+// directory, or an empty Info when there is none.
+func (n *Notifier) detectRepo(cwd string) gitinfo.Info {
+	if cwd == "" {
+		return gitinfo.Info{}
+	}
+	repo, err := gitinfo.Detect(cwd)
+	if err != nil {
+		n.log.Debug("Git checkout not detected for %q: %v", cwd, err)
+		return gitinfo.Info{}
+	}
+	n.log.Debug("Git checkout: worktree=%s, branch=%s", repo.Worktree, repo.Branch)
+	return repo
+}
+
+// projectName returns the project directory name for notifications: the
+// worktree's top-level directory, or the working directory outside git.
+func projectName(cwd string, repo gitinfo.Info) string {
+	switch {
+	case repo.Worktree != "":
+		return filepath.Base(repo.Worktree)
+	case cwd != "":
+		return filepath.Base(cwd)
+	default:
+		return ""
+	}
+}
+
+// recordHistory adds a dispatched notification to the history database and
+// prunes records past the retention period, if history is enabled.
+func (n *Notifier) recordHistory(eventType, sessionID string, dispatchErr error) {
+	retentionDays, ok := n.cfg.HistoryRetentionDays()
+	if !ok || n.opts.HomeDir == "" {
+		return
+	}
+	store, err := history.Open(history.Path(n.opts.HomeDir))
+	if err != nil {
+		n.log.Warn("History unavailable: %v", err)
+		return
+	}
+	defer store.Close()
+
+	outcome := history.OutcomeDelivered
+	if dispatchErr != nil {
+		outcome = history.OutcomeFailed
+	}
+	now := time.Now()
+	if err := store.Add(history.Record{Time: now, Event: eventType, Outcome: outcome, SessionID: sessionID}); err != nil {
+		n.log.Warn("History write failed: %v", err)
+		return
+	}
+	if pruned, err := store.Prune(now.AddDate(0, 0, -retentionDays)); err != nil {
+		n.log.Warn("History prune failed: %v", err)
+	} else if pruned > 0 {
+		n.log.Debug("Pruned %d history records older than %d days", pruned, retentionDays)
+	}
+}
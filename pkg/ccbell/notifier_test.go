@@ -0,0 +1,603 @@
+package ccbell
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/audio"
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/history"
+	"github.com/mpolatcan/ccbell/internal/notify"
+	"github.com/mpolatcan/ccbell/internal/state"
+)
+
+// recordingChannel collects delivered messages.
+type recordingChannel struct {
+	mu       sync.Mutex
+	messages []*Message
+}
+
+func (r *recordingChannel) factory(_ context.Context, _ *Notifier, _ *Event) (Channel, error) {
+	return notify.Func("recording", time.Second, func(_ context.Context, msg *Message) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.messages = append(r.messages, msg)
+		return nil
+	}), nil
+}
+
+func (r *recordingChannel) bodies() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	bodies := make([]string, 0, len(r.messages))
+	for _, msg := range r.messages {
+		bodies = append(bodies, msg.Body)
+	}
+	return bodies
+}
+
+// newTestConfig returns a config that notifies the recording channel in
+// English, whatever the test environment's locale.
+func newTestConfig() *Config {
+	cfg := DefaultConfig()
+	cfg.Language = "en"
+	for _, event := range cfg.Events {
+		event.Channels = []string{"recording"}
+	}
+	return cfg
+}
+
+func TestNotify(t *testing.T) {
+	rec := &recordingChannel{}
+	RegisterChannel("recording", rec.factory)
+
+	tmpDir, err := os.MkdirTemp("", "ccbell-notifier-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	n := New(newTestConfig(), Options{HomeDir: tmpDir})
+	if err := n.Notify(context.Background(), Request{Event: "stop"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got := rec.bodies(); len(got) != 1 || got[0] != "Claude finished responding" {
+		t.Errorf("delivered = %v", got)
+	}
+
+	if err := n.Notify(context.Background(), Request{Event: "Bad-Event"}); err == nil {
+		t.Error("Notify() should reject invalid event types")
+	}
+}
+
+func TestNotifyFormat(t *testing.T) {
+	rec := &recordingChannel{}
+	RegisterChannel("recording", rec.factory)
+
+	tmpDir, err := os.MkdirTemp("", "ccbell-notifier-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg := newTestConfig()
+	cfg.Title = "{icon} Claude"
+	icon := "🚨"
+	cfg.Events["permission_prompt"].Format = &config.Format{Icon: &icon, Severity: "critical", Body: "{icon} {message}!"}
+	n := New(cfg, Options{HomeDir: tmpDir})
+	for _, event := range []string{"stop", "permission_prompt"} {
+		if err := n.Notify(context.Background(), Request{Event: event}); err != nil {
+			t.Fatalf("Notify(%s) error = %v", event, err)
+		}
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.messages) != 2 {
+		t.Fatalf("delivered %d messages, want 2", len(rec.messages))
+	}
+	if stop := rec.messages[0]; stop.Title != "✅ Claude" || stop.Body != "Claude finished responding" || stop.Severity != notify.SeverityInfo {
+		t.Errorf("stop = %q, %q, %q, want the default icon and plain body", stop.Title, stop.Body, stop.Severity)
+	}
+	if perm := rec.messages[1]; perm.Title != "🚨 Claude" || perm.Body != "🚨 Claude needs your permission!" || perm.Severity != notify.SeverityCritical {
+		t.Errorf("permission_prompt = %q, %q, %q", perm.Title, perm.Body, perm.Severity)
+	}
+}
+
+func TestNotifyDesktopPersistence(t *testing.T) {
+	rec := &recordingChannel{}
+	RegisterChannel("recording", rec.factory)
+
+	tmpDir, err := os.MkdirTemp("", "ccbell-notifier-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg := newTestConfig()
+	sticky, brief := 0, 5
+	cfg.Events["permission_prompt"].Desktop = &config.EventDesktop{Timeout: &sticky, Urgency: "critical"}
+	cfg.Events["stop"].Desktop = &config.EventDesktop{Timeout: &brief}
+	n := New(cfg, Options{HomeDir: tmpDir})
+	for _, event := range []string{"permission_prompt", "stop", "idle_prompt"} {
+		if err := n.Notify(context.Background(), Request{Event: event}); err != nil {
+			t.Fatalf("Notify(%s) error = %v", event, err)
+		}
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.messages) != 3 {
+		t.Fatalf("delivered %d messages, want 3", len(rec.messages))
+	}
+	if perm := rec.messages[0]; perm.Expire == nil || *perm.Expire != 0 || perm.Urgency != "critical" {
+		t.Errorf("permission_prompt expire, urgency = %v, %q, want sticky and critical", perm.Expire, perm.Urgency)
+	}
+	if stop := rec.messages[1]; stop.Expire == nil || *stop.Expire != 5*time.Second || stop.Urgency != "" {
+		t.Errorf("stop expire, urgency = %v, %q, want 5s", stop.Expire, stop.Urgency)
+	}
+	if idle := rec.messages[2]; idle.Expire != nil || idle.Urgency != "" {
+		t.Errorf("idle_prompt expire, urgency = %v, %q, want server defaults", idle.Expire, idle.Urgency)
+	}
+}
+
+func TestNotifyReplacesDesktopNotification(t *testing.T) {
+	rec := &recordingChannel{}
+	registryMu.Lock()
+	origDesktop := registry[config.ChannelDesktop]
+	registryMu.Unlock()
+	RegisterChannel(config.ChannelDesktop, rec.factory)
+	defer RegisterChannel(config.ChannelDesktop, origDesktop)
+
+	tmpDir, err := os.MkdirTemp("", "ccbell-notifier-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg := newTestConfig()
+	group := 60
+	cfg.Desktop = &config.Desktop{Group: &group}
+	cfg.Events["subagent"].Channels = []string{config.ChannelDesktop}
+	cfg.Events["stop"].Channels = []string{config.ChannelDesktop}
+	n := New(cfg, Options{HomeDir: tmpDir})
+	if err := n.state.RecordToast("subagent", 7, 2); err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range []string{"subagent", "stop"} {
+		if err := n.Notify(context.Background(), Request{Event: event}); err != nil {
+			t.Fatalf("Notify(%s) error = %v", event, err)
+		}
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.messages) != 2 {
+		t.Fatalf("delivered %d messages, want 2", len(rec.messages))
+	}
+	if sub := rec.messages[0]; sub.ReplaceID != 7 || sub.Count != 3 || sub.Body != "3 subagents finished" {
+		t.Errorf("subagent = %d, %d, %q, want it to replace notification 7 for 3 events", sub.ReplaceID, sub.Count, sub.Body)
+	}
+	if stop := rec.messages[1]; stop.ReplaceID != 0 || stop.Body != "Claude finished responding" {
+		t.Errorf("stop = %d, %q, want a new notification", stop.ReplaceID, stop.Body)
+	}
+}
+
+func TestNotifyLanguage(t *testing.T) {
+	rec := &recordingChannel{}
+	RegisterChannel("recording", rec.factory)
+
+	tmpDir, err := os.MkdirTemp("", "ccbell-notifier-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg := newTestConfig()
+	cfg.Language = "tr-TR"
+	n := New(cfg, Options{HomeDir: tmpDir})
+	if err := n.Notify(context.Background(), Request{Event: "stop", Count: 2}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got := rec.bodies(); len(got) != 1 || got[0] != "Claude yanıtını tamamladı (2 kez)" {
+		t.Errorf("delivered = %v, want Turkish text", got)
+	}
+}
+
+func TestNotifyDisabled(t *testing.T) {
+	rec := &recordingChannel{}
+	RegisterChannel("recording", rec.factory)
+
+	cfg := newTestConfig()
+	cfg.Enabled = false
+	n := New(cfg, Options{})
+	if err := n.Notify(context.Background(), Request{Event: "stop"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got := rec.bodies(); len(got) != 0 {
+		t.Errorf("disabled config should not notify, got %v", got)
+	}
+}
+
+func TestNotifyBranchRule(t *testing.T) {
+	rec := &recordingChannel{}
+	RegisterChannel("recording", rec.factory)
+
+	tmpDir, err := os.MkdirTemp("", "ccbell-notifier-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	repo := filepath.Join(tmpDir, "repo")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, ".git", "HEAD"), []byte("ref: refs/heads/wip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	disabled := false
+	cfg := newTestConfig()
+	cfg.Branches = []*config.BranchRule{
+		{Branch: "wip", Events: map[string]*Event{"stop": {Enabled: &disabled}}},
+	}
+	n := New(cfg, Options{HomeDir: tmpDir})
+
+	payload := []byte(`{"cwd": "` + repo + `"}`)
+	if err := n.Notify(context.Background(), Request{Event: "stop", Payload: payload}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got := rec.bodies(); len(got) != 0 {
+		t.Errorf("branch rule should disable stop, got %v", got)
+	}
+
+	if err := n.Notify(context.Background(), Request{Event: "stop"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got := rec.bodies(); len(got) != 1 {
+		t.Errorf("stop outside the repo should notify, got %v", got)
+	}
+
+	payload = []byte(`{"cwd": "` + repo + `"}`)
+	if err := n.Notify(context.Background(), Request{Event: "permission_prompt", Payload: payload}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if last := rec.messages[len(rec.messages)-1]; last.Title != "Claude Code · repo (wip)" {
+		t.Errorf("Title = %q, want project and branch", last.Title)
+	}
+}
+
+func TestNotifySubagentBatch(t *testing.T) {
+	rec := &recordingChannel{}
+	RegisterChannel("recording", rec.factory)
+
+	tmpDir, err := os.MkdirTemp("", "ccbell-notifier-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg := newTestConfig()
+	enabled, quiet := true, 1
+	cfg.SubagentBatch = &config.SubagentBatch{Enabled: &enabled, QuietPeriod: &quiet}
+
+	var spawned []int64
+	n := New(cfg, Options{
+		HomeDir:    tmpDir,
+		SpawnFlush: func(seq int64) error { spawned = append(spawned, seq); return nil },
+	})
+
+	for i := 0; i < 3; i++ {
+		if err := n.Notify(context.Background(), Request{Event: "subagent"}); err != nil {
+			t.Fatalf("Notify() error = %v", err)
+		}
+	}
+	if len(rec.bodies()) != 0 {
+		t.Fatal("batched completions should not notify immediately")
+	}
+	if len(spawned) != 3 {
+		t.Fatalf("spawned %d flushes, want 3", len(spawned))
+	}
+
+	// Only the last flush owns the batch
+	if err := n.Notify(context.Background(), Request{Event: "subagent", FlushBatch: spawned[0]}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if err := n.Notify(context.Background(), Request{Event: "subagent", FlushBatch: spawned[2]}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got := rec.bodies(); len(got) != 1 || got[0] != "3 subagents finished" {
+		t.Errorf("delivered = %v, want one summary", got)
+	}
+}
+
+func TestNotifySoundWithMockBackend(t *testing.T) {
+	old := os.Getenv(audio.BackendEnvVar)
+	os.Setenv(audio.BackendEnvVar, "mock")
+	defer os.Setenv(audio.BackendEnvVar, old)
+	defer setReducedSound(false)()
+
+	tmpDir, err := os.MkdirTemp("", "ccbell-notifier-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	soundsDir := filepath.Join(tmpDir, "sounds")
+	if err := os.MkdirAll(soundsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(soundsDir, "stop.aiff"), []byte("FORM"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	spawned := 0
+	n := New(DefaultConfig(), Options{
+		HomeDir:    tmpDir,
+		PluginRoot: tmpDir,
+		SpawnRank:  func() error { spawned++; return nil },
+	})
+	if err := n.Notify(context.Background(), Request{Event: "stop"}); err != nil {
+		t.Errorf("Notify() error = %v", err)
+	}
+	// Players are ranked by another process, not while notifying
+	if order, err := state.NewManager(tmpDir).RankedPlayers(); err != nil || order != nil {
+		t.Errorf("RankedPlayers() = %v, %v; want none", order, err)
+	}
+	if want := runtime.GOOS == "linux"; (spawned == 1) != want {
+		t.Errorf("SpawnRank called %d times on %s", spawned, runtime.GOOS)
+	}
+
+	// Notifications while it ranks don't start another ranking
+	spawned = 0
+	if err := n.Notify(context.Background(), Request{Event: "stop"}); err != nil {
+		t.Errorf("Notify() error = %v", err)
+	}
+	if spawned != 0 {
+		t.Errorf("SpawnRank called %d times while ranking", spawned)
+	}
+
+	// A cached ranking is used without ranking again
+	if err := state.NewManager(tmpDir).RankPlayers([]string{"paplay"}); err != nil {
+		t.Fatal(err)
+	}
+	spawned = 0
+	if err := n.Notify(context.Background(), Request{Event: "stop"}); err != nil {
+		t.Errorf("Notify() error = %v", err)
+	}
+	if spawned != 0 {
+		t.Errorf("SpawnRank called %d times with a cached ranking", spawned)
+	}
+}
+
+// setReducedSound fakes the OS reduced-sound preference, returning a
+// function that restores detection.
+func setReducedSound(reduced bool) func() {
+	orig := reducedSound
+	reducedSound = func(context.Context) bool { return reduced }
+	return func() { reducedSound = orig }
+}
+
+func TestNotifyReducedSound(t *testing.T) {
+	rec := &recordingChannel{}
+	registryMu.Lock()
+	origDesktop := registry[config.ChannelDesktop]
+	registryMu.Unlock()
+	RegisterChannel(config.ChannelDesktop, rec.factory)
+	defer RegisterChannel(config.ChannelDesktop, origDesktop)
+	defer setReducedSound(true)()
+
+	cfg := newTestConfig()
+	cfg.Events["stop"].Channels = []string{config.ChannelSound}
+	n := New(cfg, Options{})
+	if err := n.Notify(context.Background(), Request{Event: "stop"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got := rec.bodies(); len(got) != 1 {
+		t.Errorf("reduced sound should show a desktop notification, got %v", got)
+	}
+
+	// Events without the sound channel are left alone
+	other := &recordingChannel{}
+	RegisterChannel("recording", other.factory)
+	cfg.Events["stop"].Channels = []string{"recording"}
+	if err := n.Notify(context.Background(), Request{Event: "stop"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if len(rec.bodies()) != 1 || len(other.bodies()) != 1 {
+		t.Errorf("desktop = %v, recording = %v", rec.bodies(), other.bodies())
+	}
+}
+
+func TestNotifyScreenLocked(t *testing.T) {
+	rec := &recordingChannel{}
+	RegisterChannel("recording", rec.factory)
+	origLocked := screenLocked
+	screenLocked = func(context.Context) bool { return true }
+	defer func() { screenLocked = origLocked }()
+
+	cfg := newTestConfig()
+	cfg.Events["stop"].Channels = []string{config.ChannelSound}
+	cfg.ScreenLock = &config.ScreenLock{Channels: []string{"recording"}}
+	n := New(cfg, Options{})
+	if err := n.Notify(context.Background(), Request{Event: "stop"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got := rec.bodies(); len(got) != 1 {
+		t.Errorf("locked screen should route to screenLock.channels, got %v", got)
+	}
+
+	// Without remote channels there is nowhere to route
+	cfg.ScreenLock = &config.ScreenLock{}
+	if err := n.Notify(context.Background(), Request{Event: "stop"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got := rec.bodies(); len(got) != 1 {
+		t.Errorf("locked screen without remote channels should suppress, got %v", got)
+	}
+}
+
+func TestNotifyActiveCall(t *testing.T) {
+	rec := &recordingChannel{}
+	registryMu.Lock()
+	origDesktop := registry[config.ChannelDesktop]
+	registryMu.Unlock()
+	RegisterChannel(config.ChannelDesktop, rec.factory)
+	defer RegisterChannel(config.ChannelDesktop, origDesktop)
+	origInCall := inCall
+	inCall = func(context.Context) bool { return true }
+	defer func() { inCall = origInCall }()
+
+	cfg := newTestConfig()
+	cfg.Events["stop"].Channels = []string{config.ChannelSound}
+	cfg.ActiveCall = &config.ActiveCall{}
+	n := New(cfg, Options{})
+	if err := n.Notify(context.Background(), Request{Event: "stop"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got := rec.bodies(); len(got) != 1 {
+		t.Errorf("call in progress should show a desktop notification, got %v", got)
+	}
+
+	cfg.ActiveCall.Mode = config.ActiveCallMute
+	if err := n.Notify(context.Background(), Request{Event: "stop"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got := rec.bodies(); len(got) != 1 {
+		t.Errorf("mute mode should suppress a sound-only event, got %v", got)
+	}
+}
+
+func TestNotifyPaused(t *testing.T) {
+	rec := &recordingChannel{}
+	RegisterChannel("recording", rec.factory)
+
+	tmpDir, err := os.MkdirTemp("", "ccbell-notifier-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	n := New(newTestConfig(), Options{HomeDir: tmpDir})
+	if err := n.state.Pause(time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Notify(context.Background(), Request{Event: "stop"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got := rec.bodies(); len(got) != 0 {
+		t.Errorf("paused notifier should not notify, got %v", got)
+	}
+
+	// An expired pause resumes automatically and is cleared
+	if err := n.state.Pause(time.Now().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Notify(context.Background(), Request{Event: "stop"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got := rec.bodies(); len(got) != 1 {
+		t.Errorf("expired pause should notify, got %v", got)
+	}
+	if until, _ := n.state.PausedUntil(); !until.IsZero() {
+		t.Errorf("expired pause not cleared: %v", until)
+	}
+}
+
+func TestNotifyRecordsHistory(t *testing.T) {
+	rec := &recordingChannel{}
+	RegisterChannel("recording", rec.factory)
+
+	tmpDir, err := os.MkdirTemp("", "ccbell-notifier-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg := newTestConfig()
+	enabled := true
+	cfg.History = &config.History{Enabled: &enabled}
+	n := New(cfg, Options{HomeDir: tmpDir})
+	for i := 0; i < 2; i++ {
+		if err := n.Notify(context.Background(), Request{Event: "stop"}); err != nil {
+			t.Fatalf("Notify() error = %v", err)
+		}
+	}
+
+	store, err := history.Open(history.Path(tmpDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	counts, err := store.Counts(time.Now().Add(-time.Hour), "stop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(counts) != 1 || counts[0].Count != 2 || counts[0].Outcome != history.OutcomeDelivered {
+		t.Errorf("history counts = %+v", counts)
+	}
+}
+
+func TestNotifyFreshStart(t *testing.T) {
+	var volumes []float64
+	RegisterChannel("volume", func(_ context.Context, _ *Notifier, event *Event) (Channel, error) {
+		return notify.Func("volume", time.Second, func(context.Context, *Message) error {
+			volumes = append(volumes, derefFloat(event.Volume, 0.5))
+			return nil
+		}), nil
+	})
+
+	tmpDir, err := os.MkdirTemp("", "ccbell-notifier-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	statePath := filepath.Join(tmpDir, ".claude", "ccbell.state")
+	if err := os.MkdirAll(filepath.Dir(statePath), 0750); err != nil {
+		t.Fatal(err)
+	}
+	writeState := func(lastTrigger, lastEvent time.Time) {
+		data := fmt.Sprintf(`{"lastTrigger": {"stop": %d}, "lastEvent": %d}`, lastTrigger.Unix(), lastEvent.Unix())
+		if err := os.WriteFile(statePath, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cooldown, freshStart, volume := 60, 3600, 0.3
+	cfg := newTestConfig()
+	cfg.FreshStart = &freshStart
+	cfg.Events["stop"].Channels = []string{"volume"}
+	cfg.Events["stop"].Cooldown = &cooldown
+	cfg.Events["stop"].Volume = &volume
+	n := New(cfg, Options{HomeDir: tmpDir})
+	ctx := context.Background()
+
+	// In cooldown, but the last event was two hours ago
+	now := time.Now()
+	writeState(now, now.Add(-2*time.Hour))
+	if err := n.Notify(ctx, Request{Event: "stop"}); err != nil {
+		t.Fatal(err)
+	}
+	// Not idle any more: the cooldown applies
+	if err := n.Notify(ctx, Request{Event: "stop"}); err != nil {
+		t.Fatal(err)
+	}
+	// The clock jumped back, leaving times in the future
+	writeState(now.Add(2*time.Hour), now.Add(2*time.Hour))
+	if err := n.Notify(ctx, Request{Event: "stop"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(volumes) != 2 || volumes[0] != 1 || volumes[1] != 1 {
+		t.Errorf("delivered at volumes %v, want two at full volume", volumes)
+	}
+}
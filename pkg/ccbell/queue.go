@@ -0,0 +1,98 @@
+package ccbell
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/notify"
+	"github.com/mpolatcan/ccbell/internal/state"
+)
+
+// queueFlushWait is how long a notification lets queued deliveries run;
+// those not delivered by then stay queued. Replaceable in tests.
+var queueFlushWait = 2 * time.Second
+
+// errFlushCutOff is the cause of canceling a flush after queueFlushWait.
+var errFlushCutOff = errors.New("queue flush cut off")
+
+// queueOnFailure wraps a remote channel so deliveries that still fail after
+// retrying are queued in the state file for FlushQueue.
+func (n *Notifier) queueOnFailure(ch Channel) Channel {
+	return notify.Func(ch.Name(), ch.Timeout(), func(ctx context.Context, msg *notify.Message) error {
+		err := ch.Send(ctx, msg)
+		if err == nil || notify.IsPermanent(err) {
+			return err
+		}
+		data, jsonErr := json.Marshal(msg)
+		if jsonErr == nil {
+			jsonErr = n.state.Queue(&state.Delivery{Channel: ch.Name(), Message: data, Payload: msg.Payload})
+		}
+		if jsonErr != nil {
+			n.log.Warn("Could not queue %s delivery: %v", ch.Name(), jsonErr)
+		} else {
+			n.log.Debug("Queued %s delivery for the next invocation", ch.Name())
+		}
+		return err
+	})
+}
+
+// FlushQueue redelivers remote notifications that failed earlier, trying
+// each once. Those that fail again stay queued, and once a channel fails
+// the rest of its deliveries wait for the next flush, as do all of them
+// once ctx is done. It returns how many were delivered.
+func (n *Notifier) FlushQueue(ctx context.Context) (int, error) {
+	queued, err := n.state.TakeQueued()
+	if err != nil || len(queued) == 0 {
+		return 0, err
+	}
+	n.log.Debug("Flushing %d queued deliveries", len(queued))
+
+	var requeue []*state.Delivery
+	down := make(map[string]bool)
+	sent := 0
+	for _, d := range queued {
+		if down[d.Channel] || ctx.Err() != nil {
+			requeue = append(requeue, d)
+			continue
+		}
+		var msg notify.Message
+		if err := json.Unmarshal(d.Message, &msg); err != nil {
+			n.log.Warn("Dropping unreadable queued delivery: %v", err)
+			continue
+		}
+		msg.Payload = d.Payload
+		event := n.cfg.Events[msg.Event]
+		if event == nil {
+			event = &Event{}
+		}
+		registryMu.RLock()
+		ch := n.buildChannel(ctx, d.Channel, event)
+		registryMu.RUnlock()
+		if ch == nil {
+			n.log.Debug("Dropping queued %s delivery, channel no longer configured", d.Channel)
+			continue
+		}
+
+		err := notify.Dispatch(ctx, &msg, []Channel{n.withBreaker(ch)})
+		switch {
+		case err == nil:
+			sent++
+		case notify.IsPermanent(err):
+			n.log.Warn("Dropping queued delivery: %v", err)
+		default:
+			n.log.Debug("Queued delivery failed again: %v", err)
+			down[d.Channel] = true
+			requeue = append(requeue, d)
+		}
+	}
+
+	if len(requeue) > 0 {
+		if err := n.state.Queue(requeue...); err != nil {
+			return sent, err
+		}
+	}
+	n.log.Debug("Delivered %d queued notifications, %d still queued", sent, len(requeue))
+	return sent, nil
+}
@@ -0,0 +1,192 @@
+package ccbell
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/state"
+)
+
+func TestFlushQueue(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-queue-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var up atomic.Bool
+	var received atomic.Int32
+	var body atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		received.Add(1)
+		data, _ := io.ReadAll(r.Body)
+		body.Store(string(data))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	one := 1
+	cfg := newTestConfig()
+	cfg.Webhook = &config.Webhook{URL: server.URL, Template: `{"session": {{json .Payload.session_id}}}`}
+	cfg.Retry = &config.Retry{Attempts: &one}
+	cfg.Events["stop"].Channels = []string{config.ChannelWebhook}
+	n := New(cfg, Options{HomeDir: tmpDir})
+
+	// The webhook is down: the delivery fails and is queued
+	if err := n.Notify(context.Background(), Request{Event: "stop", Payload: []byte(`{"session_id": "abc123"}`)}); err == nil {
+		t.Fatal("Notify() should report the failed delivery")
+	}
+	if sent, err := n.FlushQueue(context.Background()); err != nil || sent != 0 {
+		t.Errorf("FlushQueue() while down = %d, %v, want 0", sent, err)
+	}
+
+	// Back up: the queued delivery is sent once
+	up.Store(true)
+	if sent, err := n.FlushQueue(context.Background()); err != nil || sent != 1 {
+		t.Errorf("FlushQueue() = %d, %v, want 1", sent, err)
+	}
+	if sent, err := n.FlushQueue(context.Background()); err != nil || sent != 0 {
+		t.Errorf("second FlushQueue() = %d, %v, want 0", sent, err)
+	}
+	if got := received.Load(); got != 1 {
+		t.Errorf("webhook received %d deliveries, want 1", got)
+	}
+	// The payload is queued too, for the template
+	if got := body.Load(); got != `{"session": "abc123"}` {
+		t.Errorf("redelivered body = %v", got)
+	}
+}
+
+func TestFlushQueueDisabled(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-queue-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	one, queue := 1, false
+	cfg := newTestConfig()
+	cfg.Webhook = &config.Webhook{URL: server.URL}
+	cfg.Retry = &config.Retry{Attempts: &one, Queue: &queue}
+	cfg.Events["stop"].Channels = []string{config.ChannelWebhook}
+	n := New(cfg, Options{HomeDir: tmpDir})
+
+	if err := n.Notify(context.Background(), Request{Event: "stop"}); err == nil {
+		t.Fatal("Notify() should report the failed delivery")
+	}
+	if queued, err := n.state.TakeQueued(); err != nil || len(queued) != 0 {
+		t.Errorf("queue disabled, got %v, %v", queued, err)
+	}
+}
+
+func TestNotifyDoesNotWaitForFlush(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-queue-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	rec := &recordingChannel{}
+	RegisterChannel("recording", rec.factory)
+	cfg := newTestConfig()
+	cfg.Webhook = &config.Webhook{URL: server.URL}
+	cfg.Events["stop"].Channels = []string{"recording"}
+	n := New(cfg, Options{HomeDir: tmpDir})
+	queued, err := json.Marshal(&Message{Event: "stop", Body: "Earlier"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.state.Queue(&state.Delivery{Channel: config.ChannelWebhook, Message: queued}); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- n.Notify(context.Background(), Request{Event: "stop"}) }()
+	// The queued webhook hangs, but the event is delivered meanwhile
+	for deadline := time.Now().Add(5 * time.Second); len(rec.bodies()) == 0; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			close(release)
+			t.Fatal("the event waited for the queued delivery")
+		}
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("Notify() error = %v", err)
+	}
+	if queued, _ := n.state.TakeQueued(); len(queued) != 0 {
+		t.Errorf("queued delivery wasn't flushed: %d left", len(queued))
+	}
+}
+
+func TestNotifyCutsOffQueueFlush(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-queue-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	defer func(wait time.Duration) { queueFlushWait = wait }(queueFlushWait)
+	queueFlushWait = 100 * time.Millisecond
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release) // Before closing the server, which waits for handlers
+
+	rec := &recordingChannel{}
+	RegisterChannel("recording", rec.factory)
+	cfg := newTestConfig()
+	cfg.Webhook = &config.Webhook{URL: server.URL}
+	cfg.Events["stop"].Channels = []string{"recording"}
+	failures := 1
+	cfg.CircuitBreaker = &config.CircuitBreaker{Failures: &failures}
+	n := New(cfg, Options{HomeDir: tmpDir})
+	queued, err := json.Marshal(&Message{Event: "stop", Body: "Earlier"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.state.Queue(&state.Delivery{Channel: config.ChannelWebhook, Message: queued}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The hanging webhook doesn't hold up the hook past the cutoff
+	start := time.Now()
+	if err := n.Notify(context.Background(), Request{Event: "stop"}); err != nil {
+		t.Errorf("Notify() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Notify() took %v waiting for the queue", elapsed)
+	}
+	if queued, _ := n.state.TakeQueued(); len(queued) != 1 {
+		t.Errorf("%d deliveries queued after the cutoff, want 1", len(queued))
+	}
+	// Being cut off isn't the endpoint failing
+	if until, err := n.state.CircuitOpenUntil(config.ChannelWebhook); err != nil || !until.IsZero() {
+		t.Errorf("CircuitOpenUntil() = %v, %v; want closed", until, err)
+	}
+}
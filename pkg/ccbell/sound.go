@@ -0,0 +1,233 @@
+package ccbell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/mpolatcan/ccbell/internal/audio"
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/notify"
+	"github.com/mpolatcan/ccbell/internal/pack"
+)
+
+// Sound fallback deliveries; replaceable in tests.
+var (
+	fallbackDesktop = newDesktopChannel
+	ringBell        = notify.Bell
+)
+
+// soundFallback delivers msg another way after the event's sound failed
+// with playErr, so the event isn't lost. It returns playErr, noting the
+// fallback used.
+func (n *Notifier) soundFallback(ctx context.Context, event *Event, msg *notify.Message, playErr error) error {
+	fallback := n.cfg.SoundFallback
+	if fallback == "" {
+		fallback = config.SoundFallbackDesktop
+	}
+	if fallback == config.SoundFallbackNone || slices.Contains(config.EventChannels(event), fallback) {
+		return playErr
+	}
+
+	var err error
+	switch fallback {
+	case config.SoundFallbackDesktop:
+		var ch Channel
+		if ch, err = fallbackDesktop(ctx, n, event); err == nil {
+			err = ch.Send(ctx, msg)
+		}
+	case config.SoundFallbackBell:
+		err = ringBell()
+	}
+	if err != nil {
+		n.log.Debug("Sound fallback %s failed: %v", fallback, err)
+		return playErr
+	}
+	n.log.Debug("Sound failed, notified with %s instead", fallback)
+	return fmt.Errorf("%w (%s fallback used)", playErr, fallback)
+}
+
+// playSound resolves and plays the configured sound for an event. A
+// per-session variation uses sessionID, when known.
+func (n *Notifier) playSound(ctx context.Context, event *Event, eventType, sessionID string) error {
+	cfg, log := n.cfg, n.log
+
+	// === Resolve sound path ===
+	player := audio.NewPlayer(n.opts.PluginRoot)
+	player.SetSearchPaths(cfg.SoundPaths)
+	player.SetSymlinkPolicy(audio.SymlinkPolicy(cfg.AllowSymlinks))
+	player.SetPermissionPolicy(audio.PermissionPolicy(cfg.SoundPerms))
+	player.SetAllowedDirs(cfg.AllowedDirs)
+	if cfg.PlayerTimeout != nil {
+		player.SetTimeout(time.Duration(*cfg.PlayerTimeout) * time.Second)
+	}
+	player.SetPlayerOrder(cfg.Players)
+	player.SetAutoInstall(cfg.AutoInstallsPlayer())
+	if n.opts.Prompt != nil {
+		player.SetInstallPrompt(n.opts.Prompt)
+	}
+	if cfg.MaxVolume != nil {
+		player.SetMaxVolume(*cfg.MaxVolume)
+		log.Debug("Maximum volume: %.2f", *cfg.MaxVolume)
+	}
+	maxSizeKB, maxDurationSecs := cfg.SoundLimitValues()
+	player.SetLimits(audio.Limits{
+		MaxSize:     int64(maxSizeKB) * 1024,
+		MaxDuration: time.Duration(maxDurationSecs) * time.Second,
+	})
+	if event.Variation != nil {
+		player.SetVariation(event.Variation.Mode, event.Variation.Amount)
+		if event.Variation.PerSession && sessionID != "" {
+			step := config.SessionVariant(sessionID, audio.VariationSteps)
+			player.SetVariationStep(step)
+			log.Debug("Session %s variation step: %d of %d", sessionID, step+1, audio.VariationSteps)
+		}
+	}
+	if a := event.AFPlay; a != nil {
+		quality := -1
+		if a.Quality != nil {
+			quality = *a.Quality
+		}
+		player.SetAFPlayOptions(derefFloat(a.Rate, 1), quality)
+	}
+	if n.opts.HomeDir != "" {
+		player.SetCacheDir(filepath.Join(n.opts.HomeDir, ".claude", "ccbell", "cache", "transcoded"))
+		player.SetDownloadDir(filepath.Join(n.opts.HomeDir, ".claude", "ccbell", "cache", "downloads"))
+	}
+	log.Debug("Detected platform: %s", player.Platform())
+	if n.opts.HomeDir != "" {
+		// Saves probing PATH for every player and package manager per hook
+		if lookups, err := n.state.CachedLookups(); err == nil {
+			player.SetLookupCache(lookups)
+			defer func() {
+				if err := n.state.CacheLookups(player.NewLookups()); err != nil {
+					log.Warn("Failed to cache player lookups: %v", err)
+				}
+			}()
+		}
+	}
+	if len(cfg.SoundPaths) > 0 {
+		log.Debug("Sound search paths: %v", cfg.SoundPaths)
+	}
+
+	// === Ensure audio player is available ===
+	if player.Platform() == audio.PlatformLinux {
+		if len(cfg.Players) == 0 && n.opts.HomeDir != "" {
+			n.rankPlayers(player)
+		}
+		audioPlayer, err := player.EnsureAudioPlayer()
+		if err != nil {
+			log.Error("Audio player check failed: %v", err)
+			return fmt.Errorf("no audio player available: %w", err)
+		}
+		log.Debug("Using audio player: %s", audioPlayer)
+	}
+
+	soundSpec := event.SoundFor(string(player.Platform()))
+	if soundSpec != event.Sound {
+		log.Debug("Using %s-specific sound: %s", player.Platform(), soundSpec)
+	}
+	gain := derefFloat(event.Gain, 0)
+	var soundPath string
+	var err error
+	if id, packEvent, ok := pack.ParseSpec(soundSpec); ok {
+		if packEvent == "" {
+			packEvent = eventType
+		}
+		var packGain float64
+		soundPath, packGain, err = n.resolvePackSound(player, id, packEvent)
+		gain += packGain
+	} else {
+		soundPath, err = player.ResolveSoundPathContext(ctx, soundSpec, eventType)
+	}
+	if err != nil {
+		log.Warn("Sound resolution failed: %v, trying fallbacks", err)
+		if errors.Is(err, audio.ErrLimitExceeded) && n.opts.Warn != nil {
+			fmt.Fprintf(n.opts.Warn, "ccbell: %v (see soundLimits in config)\n", err)
+		}
+		soundPath = player.GetFallbackPath(eventType)
+		gain = derefFloat(event.Gain, 0)
+		if soundPath == "" {
+			return fmt.Errorf("no playable sound found")
+		}
+	}
+	log.Debug("Final sound path: %s", soundPath)
+
+	// === Play sound ===
+	player.SetGain(gain)
+	if err := player.Play(ctx, soundPath, derefFloat(event.Volume, 0.5)); err != nil {
+		log.Error("Sound playback failed: %v", err)
+		return fmt.Errorf("sound playback failed: %w", err)
+	}
+
+	log.Debug("Sound playback initiated successfully")
+	return nil
+}
+
+// language returns the configured language, or the locale's.
+func (n *Notifier) language() string {
+	if n.cfg.Language != "" {
+		return n.cfg.Language
+	}
+	return pack.SystemLanguage()
+}
+
+// rankPlayers makes the player try the Linux players that start fastest
+// first, using the ranking cached in the state file. Without a fresh one,
+// the default order is kept and a process is started to time the players,
+// playing silence, so the sound isn't held up.
+func (n *Notifier) rankPlayers(player *audio.Player) {
+	log := n.log
+	order, err := n.state.RankedPlayers()
+	if err != nil {
+		log.Warn("Failed to read player ranking: %v", err)
+		return
+	}
+	if order == nil {
+		if n.opts.SpawnRank == nil {
+			return
+		}
+		// Only one of a burst of notifications starts a ranking
+		if start, err := n.state.StartRanking(); err != nil || !start {
+			if err != nil {
+				log.Warn("Failed to record player ranking: %v", err)
+			}
+			return
+		}
+		if err := n.opts.SpawnRank(); err != nil {
+			log.Warn("Failed to start ranking audio players: %v", err)
+		}
+		return
+	}
+	log.Debug("Using ranked audio players: %v", order)
+	player.SetPlayerOrder(order)
+}
+
+// resolvePackSound resolves a pack's sound for an event in the configured
+// language, returning its path and gain.
+func (n *Notifier) resolvePackSound(player *audio.Player, id, event string) (string, float64, error) {
+	if n.opts.HomeDir == "" {
+		return "", 0, errors.New("pack sounds require a home directory")
+	}
+	p, err := pack.Load(pack.Dir(n.opts.HomeDir), id)
+	if err != nil {
+		return "", 0, err
+	}
+
+	language := n.language()
+	file, gain, err := p.Resolve(event, language)
+	if err != nil {
+		return "", 0, err
+	}
+	n.log.Debug("Pack %s sound for %s (language %q): %s", id, event, language, file)
+
+	// Pack files get the same checks as custom sounds
+	path, err := player.ResolveSoundPath("custom:"+file, event)
+	if err != nil {
+		return "", 0, err
+	}
+	return path, gain, nil
+}
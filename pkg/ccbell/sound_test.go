@@ -0,0 +1,107 @@
+package ccbell
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mpolatcan/ccbell/internal/audio"
+	"github.com/mpolatcan/ccbell/internal/config"
+	"github.com/mpolatcan/ccbell/internal/pack"
+)
+
+func TestResolvePackSound(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ccbell-sound-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	packDir := filepath.Join(pack.Dir(tmpDir), "voice")
+	for _, file := range []string{"en/stop.wav", "tr/stop.wav"} {
+		path := filepath.Join(packDir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("RIFF"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manifest := `{"name": "Voice", "languages": ["en", "tr"], "localized": {
+		"en": {"stop": "en/stop.wav"},
+		"tr": {"stop": {"file": "tr/stop.wav", "gain": -4}}
+	}}`
+	if err := os.WriteFile(filepath.Join(packDir, pack.ManifestFile), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		language string
+		wantFile string
+		wantGain float64
+	}{
+		{"tr", "tr/stop.wav", -4},
+		{"de", "en/stop.wav", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Language = tt.language
+			n := New(cfg, Options{HomeDir: tmpDir})
+
+			path, gain, err := n.resolvePackSound(audio.NewPlayer(tmpDir), "voice", "stop")
+			if err != nil {
+				t.Fatalf("resolvePackSound() error = %v", err)
+			}
+			if path != filepath.Join(packDir, tt.wantFile) || gain != tt.wantGain {
+				t.Errorf("resolvePackSound() = %q, %v, want %s, %v", path, gain, tt.wantFile, tt.wantGain)
+			}
+		})
+	}
+
+	n := New(DefaultConfig(), Options{HomeDir: tmpDir})
+	if _, _, err := n.resolvePackSound(audio.NewPlayer(tmpDir), "missing", "stop"); err == nil {
+		t.Error("expected error for a missing pack")
+	}
+}
+
+func TestSoundFallback(t *testing.T) {
+	origDesktop, origBell := fallbackDesktop, ringBell
+	bells := 0
+	ringBell = func() error { bells++; return nil }
+	defer func() { fallbackDesktop, ringBell = origDesktop, origBell }()
+
+	playErr := errors.New("no audio player available")
+	tests := []struct {
+		name        string
+		fallback    string
+		channels    []string
+		wantDesktop int
+		wantBells   int
+	}{
+		{"desktop by default", "", nil, 1, 0},
+		{"bell", config.SoundFallbackBell, nil, 0, 1},
+		{"none", config.SoundFallbackNone, nil, 0, 0},
+		{"desktop already notified", "", []string{config.ChannelSound, config.ChannelDesktop}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desktop := &recordingChannel{}
+			fallbackDesktop, bells = desktop.factory, 0
+			cfg := DefaultConfig()
+			cfg.SoundFallback = tt.fallback
+			n := New(cfg, Options{})
+
+			err := n.soundFallback(context.Background(), &Event{Channels: tt.channels}, NewMessage("stop"), playErr)
+			if !errors.Is(err, playErr) {
+				t.Errorf("soundFallback() error = %v, want the playback error", err)
+			}
+			if len(desktop.bodies()) != tt.wantDesktop || bells != tt.wantBells {
+				t.Errorf("desktop = %d, bells = %d, want %d, %d", len(desktop.bodies()), bells, tt.wantDesktop, tt.wantBells)
+			}
+		})
+	}
+}